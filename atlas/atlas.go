@@ -0,0 +1,67 @@
+// Package atlas provides texture atlases (sprite sheets). Atlas is a single
+// image containing many named sprites. Drawing many sprites from the same
+// atlas is faster than drawing them from separate images, because the texture
+// does not have to be switched.
+//
+// Atlas can be built from separate selections:
+//
+//	builder := atlas.NewBuilder(gl)
+//	builder.SetMaxSize(gl.Context().Capabilities().MaxTextureSize())
+//	builder.Add("player", playerImage.WholeImageSelection())
+//	builder.Add("enemy", enemyImage.WholeImageSelection())
+//	spriteSheet, err := builder.Build()
+//	...
+//	player, _ := spriteSheet.Selection("player")
+//
+// or loaded from existing image and JSON metadata generated by tools such as
+// TexturePacker or Aseprite (see DecodeJSON).
+package atlas
+
+import (
+	"sort"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+// Rectangle is a position and size of the sprite in the atlas image.
+type Rectangle struct {
+	X, Y, Width, Height int
+}
+
+// Atlas is an image containing many named sprites.
+type Atlas struct {
+	image   *image.Image
+	sprites map[string]Rectangle
+}
+
+// Image returns the image containing all sprites.
+func (a *Atlas) Image() *image.Image {
+	return a.image
+}
+
+// Selection returns the selection of the atlas image containing the sprite with
+// given name. False is returned when there is no such sprite.
+func (a *Atlas) Selection(name string) (image.Selection, bool) {
+	rect, ok := a.sprites[name]
+	if !ok {
+		return image.Selection{}, false
+	}
+	return a.image.Selection(rect.X, rect.Y).WithSize(rect.Width, rect.Height), true
+}
+
+// Rectangle returns the position and size of the sprite with given name. False
+// is returned when there is no such sprite.
+func (a *Atlas) Rectangle(name string) (Rectangle, bool) {
+	rect, ok := a.sprites[name]
+	return rect, ok
+}
+
+// Names returns sorted names of all sprites.
+func (a *Atlas) Names() []string {
+	names := make([]string, 0, len(a.sprites))
+	for name := range a.sprites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
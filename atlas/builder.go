@@ -0,0 +1,183 @@
+package atlas
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/elgopher/pixiq/blend"
+	"github.com/elgopher/pixiq/image"
+)
+
+// ImageFactory creates a new image with given dimensions.
+//
+// *glfw.OpenGL instance can be used as an ImageFactory implementation.
+type ImageFactory interface {
+	NewImage(width, height int) *image.Image
+}
+
+// NewBuilder creates a Builder which packs selections into a new atlas image
+// created by given ImageFactory. By default the maximum size of the atlas is
+// 2048x2048 and there is no padding between sprites.
+func NewBuilder(imageFactory ImageFactory) *Builder {
+	if imageFactory == nil {
+		panic("nil imageFactory")
+	}
+	return &Builder{
+		imageFactory: imageFactory,
+		maxSize:      2048,
+		sprites:      map[string]image.Selection{},
+		copier:       blend.NewSource(),
+	}
+}
+
+// Builder packs many selections into a single atlas image.
+type Builder struct {
+	imageFactory ImageFactory
+	maxSize      int
+	padding      int
+	sprites      map[string]image.Selection
+	copier       *blend.Source
+}
+
+// SetMaxSize sets the maximum width and height of the atlas image. Usually
+// it should be set to the maximum texture size supported by the video card,
+// which can be obtained using gl.Context.Capabilities().MaxTextureSize().
+//
+// Will panic when size is not positive.
+func (b *Builder) SetMaxSize(size int) {
+	if size <= 0 {
+		panic("size is not positive")
+	}
+	b.maxSize = size
+}
+
+// SetPadding sets number of transparent pixels between sprites. Padding
+// prevents bleeding of adjacent sprites when atlas is drawn with scaling or
+// filtering. Will panic when padding is negative.
+func (b *Builder) SetPadding(padding int) {
+	if padding < 0 {
+		panic("negative padding")
+	}
+	b.padding = padding
+}
+
+// Add adds a sprite with given name. Pixels are copied from the selection
+// during Build. Will panic when sprite with such name was already added or
+// selection has zero width or height.
+func (b *Builder) Add(name string, selection image.Selection) {
+	if _, ok := b.sprites[name]; ok {
+		panic("sprite " + name + " already added")
+	}
+	if selection.Width() <= 0 || selection.Height() <= 0 {
+		panic("selection size is not positive")
+	}
+	b.sprites[name] = selection
+}
+
+// Build packs all added sprites into a new atlas image. Error is returned when
+// no sprites were added or sprites do not fit into the maximum size.
+func (b *Builder) Build() (*Atlas, error) {
+	if len(b.sprites) == 0 {
+		return nil, errors.New("no sprites added")
+	}
+	items := b.sortedItems()
+	width, height, ok := b.pack(items)
+	if !ok {
+		return nil, fmt.Errorf("sprites do not fit into %dx%d atlas", b.maxSize, b.maxSize)
+	}
+	img := b.imageFactory.NewImage(width, height)
+	atlas := &Atlas{
+		image:   img,
+		sprites: make(map[string]Rectangle, len(items)),
+	}
+	for _, item := range items {
+		target := img.Selection(item.rect.X, item.rect.Y)
+		b.copier.BlendSourceToTarget(b.sprites[item.name], target)
+		atlas.sprites[item.name] = item.rect
+	}
+	return atlas, nil
+}
+
+type item struct {
+	name string
+	rect Rectangle
+}
+
+// sortedItems returns items sorted by height, then width and name, which gives
+// good and deterministic results for shelf packing
+func (b *Builder) sortedItems() []item {
+	items := make([]item, 0, len(b.sprites))
+	for name, selection := range b.sprites {
+		items = append(items, item{
+			name: name,
+			rect: Rectangle{Width: selection.Width(), Height: selection.Height()},
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i].rect, items[j].rect
+		if a.Height != b.Height {
+			return a.Height > b.Height
+		}
+		if a.Width != b.Width {
+			return a.Width > b.Width
+		}
+		return items[i].name < items[j].name
+	})
+	return items
+}
+
+// pack places items using the shelf algorithm. It starts with the smallest
+// power of two width which could fit all items and doubles it until items
+// fit into maxSize. Returns the size of the atlas.
+func (b *Builder) pack(items []item) (width, height int, ok bool) {
+	area, widest := 0, 0
+	for _, it := range items {
+		area += (it.rect.Width + b.padding) * (it.rect.Height + b.padding)
+		if it.rect.Width > widest {
+			widest = it.rect.Width
+		}
+	}
+	if widest > b.maxSize {
+		return 0, 0, false
+	}
+	shelfWidth := 1
+	for shelfWidth < widest || shelfWidth*shelfWidth < area {
+		shelfWidth *= 2
+	}
+	for {
+		if shelfWidth > b.maxSize {
+			shelfWidth = b.maxSize
+		}
+		width, height = b.packShelves(items, shelfWidth)
+		if height <= b.maxSize {
+			return width, height, true
+		}
+		if shelfWidth == b.maxSize {
+			return 0, 0, false
+		}
+		shelfWidth *= 2
+	}
+}
+
+// packShelves places items in rows (shelves) not wider than maxWidth.
+func (b *Builder) packShelves(items []item, maxWidth int) (width, height int) {
+	x, y, shelfHeight := 0, 0, 0
+	for i := range items {
+		rect := &items[i].rect
+		if x > 0 && x+rect.Width > maxWidth {
+			x = 0
+			y += shelfHeight + b.padding
+			shelfHeight = 0
+		}
+		rect.X, rect.Y = x, y
+		x += rect.Width + b.padding
+		if rect.Width+rect.X > width {
+			width = rect.Width + rect.X
+		}
+		if rect.Height > shelfHeight {
+			shelfHeight = rect.Height
+		}
+	}
+	return width, y + shelfHeight
+}
@@ -0,0 +1,232 @@
+package atlas_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/atlas"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+)
+
+func TestNewBuilder(t *testing.T) {
+	t.Run("should panic for nil ImageFactory", func(t *testing.T) {
+		assert.Panics(t, func() {
+			atlas.NewBuilder(nil)
+		})
+	})
+	t.Run("should create builder", func(t *testing.T) {
+		builder := atlas.NewBuilder(fakeImageFactory{})
+		assert.NotNil(t, builder)
+	})
+}
+
+func TestBuilder_SetMaxSize(t *testing.T) {
+	t.Run("should panic for non positive size", func(t *testing.T) {
+		builder := atlas.NewBuilder(fakeImageFactory{})
+		assert.Panics(t, func() {
+			builder.SetMaxSize(0)
+		})
+	})
+}
+
+func TestBuilder_SetPadding(t *testing.T) {
+	t.Run("should panic for negative padding", func(t *testing.T) {
+		builder := atlas.NewBuilder(fakeImageFactory{})
+		assert.Panics(t, func() {
+			builder.SetPadding(-1)
+		})
+	})
+}
+
+func TestBuilder_Add(t *testing.T) {
+	t.Run("should panic when name was already added", func(t *testing.T) {
+		builder := atlas.NewBuilder(fakeImageFactory{})
+		builder.Add("a", newSprite(1, 1, color(1)))
+		assert.Panics(t, func() {
+			builder.Add("a", newSprite(1, 1, color(1)))
+		})
+	})
+	t.Run("should panic for empty selection", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(1, 1))
+		builder := atlas.NewBuilder(fakeImageFactory{})
+		assert.Panics(t, func() {
+			builder.Add("a", img.Selection(0, 0).WithSize(0, 1))
+		})
+		assert.Panics(t, func() {
+			builder.Add("a", img.Selection(0, 0).WithSize(1, 0))
+		})
+	})
+}
+
+func TestBuilder_Build(t *testing.T) {
+	t.Run("should return error when no sprites were added", func(t *testing.T) {
+		builder := atlas.NewBuilder(fakeImageFactory{})
+		a, err := builder.Build()
+		assert.Error(t, err)
+		assert.Nil(t, a)
+	})
+	t.Run("should return error when sprites do not fit", func(t *testing.T) {
+		tests := map[string][][2]int{
+			"too wide":      {{5, 1}},
+			"too high":      {{1, 5}},
+			"too many":      {{4, 4}, {1, 1}},
+			"with too many": {{2, 2}, {2, 2}, {2, 2}, {2, 2}, {1, 1}},
+		}
+		for name, sizes := range tests {
+			t.Run(name, func(t *testing.T) {
+				builder := atlas.NewBuilder(fakeImageFactory{})
+				builder.SetMaxSize(4)
+				for i, size := range sizes {
+					builder.Add(fmt.Sprintf("%d", i), newSprite(size[0], size[1], color(1)))
+				}
+				// when
+				a, err := builder.Build()
+				// then
+				assert.Error(t, err)
+				assert.Nil(t, a)
+			})
+		}
+	})
+	t.Run("should build atlas with one sprite", func(t *testing.T) {
+		builder := atlas.NewBuilder(fakeImageFactory{})
+		sprite := newSprite(2, 3, color(7))
+		builder.Add("sprite", sprite)
+		// when
+		a, err := builder.Build()
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, a.Image().Width())
+		assert.Equal(t, 3, a.Image().Height())
+		assert.Equal(t, []string{"sprite"}, a.Names())
+		rect, ok := a.Rectangle("sprite")
+		require.True(t, ok)
+		assert.Equal(t, atlas.Rectangle{Width: 2, Height: 3}, rect)
+		selection, ok := a.Selection("sprite")
+		require.True(t, ok)
+		assertSameColors(t, sprite, selection)
+	})
+	t.Run("should pack sprites without overlapping", func(t *testing.T) {
+		tests := map[string]struct {
+			maxSize int
+			padding int
+			sizes   [][2]int
+		}{
+			"two sprites": {
+				maxSize: 16,
+				sizes:   [][2]int{{2, 2}, {3, 1}},
+			},
+			"exactly fitting": {
+				maxSize: 4,
+				sizes:   [][2]int{{2, 2}, {2, 2}, {2, 2}, {2, 2}},
+			},
+			"with padding": {
+				maxSize: 16,
+				padding: 1,
+				sizes:   [][2]int{{2, 2}, {2, 2}, {2, 2}, {2, 2}},
+			},
+			"many different sprites": {
+				maxSize: 64,
+				padding: 2,
+				sizes:   [][2]int{{5, 7}, {3, 3}, {10, 1}, {1, 10}, {8, 8}, {2, 5}, {6, 4}, {1, 1}},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				builder := atlas.NewBuilder(fakeImageFactory{})
+				builder.SetMaxSize(test.maxSize)
+				builder.SetPadding(test.padding)
+				sprites := map[string]image.Selection{}
+				for i, size := range test.sizes {
+					name := fmt.Sprintf("sprite%d", i)
+					sprites[name] = newSprite(size[0], size[1], color(i+1))
+					builder.Add(name, sprites[name])
+				}
+				// when
+				a, err := builder.Build()
+				// then
+				require.NoError(t, err)
+				assert.LessOrEqual(t, a.Image().Width(), test.maxSize)
+				assert.LessOrEqual(t, a.Image().Height(), test.maxSize)
+				assert.Len(t, a.Names(), len(sprites))
+				for name, sprite := range sprites {
+					selection, ok := a.Selection(name)
+					require.True(t, ok)
+					assertSameColors(t, sprite, selection)
+				}
+				assertNoOverlapping(t, a, test.padding)
+			})
+		}
+	})
+	t.Run("should build many atlases", func(t *testing.T) {
+		builder := atlas.NewBuilder(fakeImageFactory{})
+		builder.Add("a", newSprite(1, 1, color(1)))
+		first, _ := builder.Build()
+		builder.Add("b", newSprite(1, 1, color(2)))
+		// when
+		second, err := builder.Build()
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, first.Names())
+		assert.Equal(t, []string{"a", "b"}, second.Names())
+	})
+}
+
+func TestAtlas_Selection(t *testing.T) {
+	t.Run("should return false for missing sprite", func(t *testing.T) {
+		builder := atlas.NewBuilder(fakeImageFactory{})
+		builder.Add("a", newSprite(1, 1, color(1)))
+		a, _ := builder.Build()
+		// when
+		_, ok := a.Selection("missing")
+		// then
+		assert.False(t, ok)
+	})
+}
+
+func color(i int) image.Color {
+	return image.RGBA(byte(i), byte(i*2), byte(i*3), 255)
+}
+
+func newSprite(width, height int, color image.Color) image.Selection {
+	img := image.New(fake.NewAcceleratedImage(width, height))
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, color)
+		}
+	}
+	return selection
+}
+
+func assertSameColors(t *testing.T, expected, actual image.Selection) {
+	require.Equal(t, expected.Width(), actual.Width())
+	require.Equal(t, expected.Height(), actual.Height())
+	for y := 0; y < expected.Height(); y++ {
+		for x := 0; x < expected.Width(); x++ {
+			assert.Equal(t, expected.Color(x, y), actual.Color(x, y), "position(%d,%d)", x, y)
+		}
+	}
+}
+
+func assertNoOverlapping(t *testing.T, a *atlas.Atlas, padding int) {
+	names := a.Names()
+	for i, name1 := range names {
+		for _, name2 := range names[i+1:] {
+			r1, _ := a.Rectangle(name1)
+			r2, _ := a.Rectangle(name2)
+			separated := r1.X+r1.Width+padding <= r2.X || r2.X+r2.Width+padding <= r1.X ||
+				r1.Y+r1.Height+padding <= r2.Y || r2.Y+r2.Height+padding <= r1.Y
+			assert.True(t, separated, "%s %v and %s %v overlap", name1, r1, name2, r2)
+		}
+	}
+}
+
+type fakeImageFactory struct{}
+
+func (i fakeImageFactory) NewImage(width, height int) *image.Image {
+	return image.New(fake.NewAcceleratedImage(width, height))
+}
@@ -0,0 +1,162 @@
+package atlas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+type jsonRectangle struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type jsonSize struct {
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type jsonFrame struct {
+	Filename         string        `json:"filename,omitempty"`
+	Frame            jsonRectangle `json:"frame"`
+	Rotated          bool          `json:"rotated"`
+	Trimmed          bool          `json:"trimmed"`
+	SpriteSourceSize jsonRectangle `json:"spriteSourceSize"`
+	SourceSize       jsonSize      `json:"sourceSize"`
+}
+
+type jsonMeta struct {
+	App    string   `json:"app,omitempty"`
+	Image  string   `json:"image"`
+	Size   jsonSize `json:"size"`
+	Format string   `json:"format"`
+	Scale  string   `json:"scale"`
+}
+
+type jsonAtlas struct {
+	Frames map[string]jsonFrame `json:"frames"`
+	Meta   jsonMeta             `json:"meta"`
+}
+
+// DecodeJSON creates an Atlas from existing image and JSON metadata in the
+// format used by TexturePacker and Aseprite. Both "hash" and "array" variants
+// of frames are supported. Rotated frames are not supported. For trimmed
+// frames only the trimmed rectangle is used.
+//
+// Error is returned when JSON is invalid, frames are rotated or frames are
+// outside the image.
+func DecodeJSON(img *image.Image, reader io.Reader) (*Atlas, error) {
+	if img == nil {
+		panic("nil image")
+	}
+	if reader == nil {
+		panic("nil reader")
+	}
+	var raw struct {
+		Frames json.RawMessage `json:"frames"`
+	}
+	if err := json.NewDecoder(reader).Decode(&raw); err != nil {
+		return nil, err
+	}
+	frames, err := decodeFrames(raw.Frames)
+	if err != nil {
+		return nil, err
+	}
+	atlas := &Atlas{
+		image:   img,
+		sprites: make(map[string]Rectangle, len(frames)),
+	}
+	for name, frame := range frames {
+		if frame.Rotated {
+			return nil, fmt.Errorf("frame %s: rotated frames are not supported", name)
+		}
+		rect := Rectangle{X: frame.Frame.X, Y: frame.Frame.Y, Width: frame.Frame.W, Height: frame.Frame.H}
+		if rect.X < 0 || rect.Y < 0 || rect.Width < 0 || rect.Height < 0 ||
+			rect.X+rect.Width > img.Width() || rect.Y+rect.Height > img.Height() {
+			return nil, fmt.Errorf("frame %s is outside the image", name)
+		}
+		atlas.sprites[name] = rect
+	}
+	return atlas, nil
+}
+
+func decodeFrames(data json.RawMessage) (map[string]jsonFrame, error) {
+	if len(data) == 0 {
+		return nil, errors.New("missing frames")
+	}
+	var hash map[string]jsonFrame
+	if err := json.Unmarshal(data, &hash); err == nil {
+		return hash, nil
+	}
+	var array []jsonFrame
+	if err := json.Unmarshal(data, &array); err != nil {
+		return nil, err
+	}
+	frames := make(map[string]jsonFrame, len(array))
+	for _, frame := range array {
+		if frame.Filename == "" {
+			return nil, errors.New("frame without filename")
+		}
+		frames[frame.Filename] = frame
+	}
+	return frames, nil
+}
+
+// DecodeJSONFile creates an Atlas from existing image and JSON metadata file.
+// See DecodeJSON.
+func DecodeJSONFile(img *image.Image, fileName string) (*Atlas, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return DecodeJSON(img, file)
+}
+
+// EncodeJSON writes atlas metadata in the TexturePacker "hash" format, which
+// can be read by DecodeJSON and many game engines. imageFileName is the name
+// of the file to which the atlas image is saved.
+func (a *Atlas) EncodeJSON(writer io.Writer, imageFileName string) error {
+	if writer == nil {
+		panic("nil writer")
+	}
+	atlas := jsonAtlas{
+		Frames: make(map[string]jsonFrame, len(a.sprites)),
+		Meta: jsonMeta{
+			App:    "https://github.com/elgopher/pixiq",
+			Image:  imageFileName,
+			Size:   jsonSize{W: a.image.Width(), H: a.image.Height()},
+			Format: "RGBA8888",
+			Scale:  "1",
+		},
+	}
+	for name, rect := range a.sprites {
+		atlas.Frames[name] = jsonFrame{
+			Frame:            jsonRectangle{X: rect.X, Y: rect.Y, W: rect.Width, H: rect.Height},
+			SpriteSourceSize: jsonRectangle{W: rect.Width, H: rect.Height},
+			SourceSize:       jsonSize{W: rect.Width, H: rect.Height},
+		}
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(atlas)
+}
+
+// EncodeJSONFile writes atlas metadata to a file. See EncodeJSON.
+func (a *Atlas) EncodeJSONFile(fileName, imageFileName string) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err = a.EncodeJSON(file, imageFileName); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
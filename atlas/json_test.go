@@ -0,0 +1,164 @@
+package atlas_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/atlas"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	img := image.New(fake.NewAcceleratedImage(16, 8))
+
+	t.Run("should panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = atlas.DecodeJSON(nil, strings.NewReader("{}"))
+		})
+		assert.Panics(t, func() {
+			_, _ = atlas.DecodeJSON(img, nil)
+		})
+	})
+	t.Run("should return error", func(t *testing.T) {
+		tests := map[string]string{
+			"invalid json":       "{",
+			"missing frames":     "{}",
+			"invalid frames":     `{"frames": 1}`,
+			"rotated frame":      `{"frames": {"a": {"frame": {"x":0,"y":0,"w":1,"h":1}, "rotated": true}}}`,
+			"outside image":      `{"frames": {"a": {"frame": {"x":15,"y":0,"w":2,"h":1}}}}`,
+			"negative position":  `{"frames": {"a": {"frame": {"x":-1,"y":0,"w":1,"h":1}}}}`,
+			"missing filename":   `{"frames": [{"frame": {"x":0,"y":0,"w":1,"h":1}}]}`,
+			"frame not a object": `{"frames": {"a": 1}}`,
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				a, err := atlas.DecodeJSON(img, strings.NewReader(data))
+				assert.Error(t, err)
+				assert.Nil(t, a)
+			})
+		}
+	})
+	t.Run("should decode", func(t *testing.T) {
+		tests := map[string]string{
+			"hash": `{
+				"frames": {
+					"player.png": {"frame": {"x":1,"y":2,"w":3,"h":4}, "rotated": false, "trimmed": false,
+						"spriteSourceSize": {"x":0,"y":0,"w":3,"h":4}, "sourceSize": {"w":3,"h":4}},
+					"enemy.png": {"frame": {"x":8,"y":0,"w":8,"h":8}}
+				},
+				"meta": {"image": "atlas.png", "size": {"w":16,"h":8}}
+			}`,
+			"array": `{
+				"frames": [
+					{"filename": "player.png", "frame": {"x":1,"y":2,"w":3,"h":4}, "duration": 100},
+					{"filename": "enemy.png", "frame": {"x":8,"y":0,"w":8,"h":8}, "duration": 100}
+				],
+				"meta": {"app": "http://www.aseprite.org/", "image": "atlas.png"}
+			}`,
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				// when
+				a, err := atlas.DecodeJSON(img, strings.NewReader(data))
+				// then
+				require.NoError(t, err)
+				assert.Same(t, img, a.Image())
+				assert.Equal(t, []string{"enemy.png", "player.png"}, a.Names())
+				rect, _ := a.Rectangle("player.png")
+				assert.Equal(t, atlas.Rectangle{X: 1, Y: 2, Width: 3, Height: 4}, rect)
+				selection, ok := a.Selection("enemy.png")
+				require.True(t, ok)
+				assert.Equal(t, 8, selection.ImageX())
+				assert.Equal(t, 0, selection.ImageY())
+				assert.Equal(t, 8, selection.Width())
+				assert.Equal(t, 8, selection.Height())
+			})
+		}
+	})
+}
+
+func TestDecodeJSONFile(t *testing.T) {
+	t.Run("should return error when file does not exist", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(1, 1))
+		a, err := atlas.DecodeJSONFile(img, "missing.json")
+		assert.Error(t, err)
+		assert.Nil(t, a)
+	})
+}
+
+func TestAtlas_EncodeJSON(t *testing.T) {
+	t.Run("should panic for nil writer", func(t *testing.T) {
+		a := buildAtlas(t)
+		assert.Panics(t, func() {
+			_ = a.EncodeJSON(nil, "atlas.png")
+		})
+	})
+	t.Run("should encode and decode the same atlas", func(t *testing.T) {
+		a := buildAtlas(t)
+		buffer := &bytes.Buffer{}
+		// when
+		err := a.EncodeJSON(buffer, "atlas.png")
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, buffer.String(), `"image": "atlas.png"`)
+		decoded, err := atlas.DecodeJSON(a.Image(), buffer)
+		require.NoError(t, err)
+		assertSameRectangles(t, a, decoded)
+	})
+}
+
+func TestAtlas_EncodeJSONFile(t *testing.T) {
+	t.Run("should encode and decode the same atlas", func(t *testing.T) {
+		a := buildAtlas(t)
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+		fileName := filepath.Join(dir, "atlas.json")
+		// when
+		err := a.EncodeJSONFile(fileName, "atlas.png")
+		// then
+		require.NoError(t, err)
+		decoded, err := atlas.DecodeJSONFile(a.Image(), fileName)
+		require.NoError(t, err)
+		assertSameRectangles(t, a, decoded)
+	})
+	t.Run("should return error when file cannot be created", func(t *testing.T) {
+		a := buildAtlas(t)
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+		fileName := filepath.Join(dir, "missing", "atlas.json")
+		err := a.EncodeJSONFile(fileName, "atlas.png")
+		assert.Error(t, err)
+	})
+}
+
+func buildAtlas(t *testing.T) *atlas.Atlas {
+	builder := atlas.NewBuilder(fakeImageFactory{})
+	builder.Add("a", newSprite(2, 3, color(1)))
+	builder.Add("b", newSprite(4, 1, color(2)))
+	a, err := builder.Build()
+	require.NoError(t, err)
+	return a
+}
+
+func assertSameRectangles(t *testing.T, expected, actual *atlas.Atlas) {
+	require.Equal(t, expected.Names(), actual.Names())
+	for _, name := range expected.Names() {
+		expectedRect, _ := expected.Rectangle(name)
+		actualRect, _ := actual.Rectangle(name)
+		assert.Equal(t, expectedRect, actualRect)
+	}
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "atlas")
+	require.NoError(t, err)
+	return dir
+}
@@ -6,6 +6,7 @@ package blend
 
 import (
 	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/internal/clip"
 )
 
 // ColorBlender blends source and target colors together. It is executed by Tool
@@ -32,13 +33,28 @@ func NewSource() *Source {
 
 // Source is a blending tool which replaces target selection with source
 // colors. It is like coping of source selection colors into target.
-type Source struct{}
+type Source struct {
+	transform Transform
+}
+
+// SetTransform sets the transform (flip or rotation) applied to the source
+// before blending. Will panic when transform is invalid.
+func (s *Source) SetTransform(transform Transform) {
+	validateTransform(transform)
+	s.transform = transform
+}
 
 // BlendSourceToTarget blends source into target selection.
 // Only position of the target Selection is used and the source is not clamped by
 // the target size.
 func (s *Source) BlendSourceToTarget(source, target image.Selection) {
-	source = clampSourceToTargetImage(source, target)
+	if s.transform != NoTransform {
+		blendTransformed(source, target, s.transform, func(source, _ image.Color) image.Color {
+			return source
+		})
+		return
+	}
+	source = clip.SourceToTargetImage(source, target)
 	target = target.WithSize(source.Width(), source.Height())
 	var (
 		sourceLines   = source.Lines()
@@ -75,18 +91,6 @@ func (s *Source) BlendSourceToTarget(source, target image.Selection) {
 	}
 }
 
-func clampSourceToTargetImage(source image.Selection, target image.Selection) image.Selection {
-	width := source.Width()
-	if width+target.ImageX() > target.Image().Width() {
-		width = target.Image().Width() - target.ImageX()
-	}
-	height := source.Height()
-	if height+target.ImageY() > target.Image().Height() {
-		height = target.Image().Height() - target.ImageY()
-	}
-	return source.WithSize(width, height)
-}
-
 // NewSourceOver creates a new blending tool which blends together source and target
 // taking into account alpha channel of both. Source-over means that source will be
 // painted on top of the target.
@@ -97,14 +101,46 @@ func NewSourceOver() *SourceOver {
 // SourceOver (aka Normal) is a blending tool which blends together source and target
 // taking into account alpha channel of both. Source-over means that source will be
 // painted on top of the target.
-type SourceOver struct{}
+//
+// Source colors can be multiplied by opacity and tint before blending. This can
+// be used for fading sprites in and out, damage flashes or ghost effects.
+type SourceOver struct {
+	modulation modulation
+	transform  Transform
+}
+
+// SetTransform sets the transform (flip or rotation) applied to the source
+// before blending. Will panic when transform is invalid.
+func (s *SourceOver) SetTransform(transform Transform) {
+	validateTransform(transform)
+	s.transform = transform
+}
+
+// SetOpacity sets the global opacity multiplied with all source colors.
+// 0 means fully transparent and 1 (the default) fully opaque source. Will panic
+// when opacity is outside range [0,1].
+func (s *SourceOver) SetOpacity(opacity float64) {
+	s.modulation.setOpacity(opacity)
+}
+
+// SetTint sets the color multiplied with all source colors. Each component is
+// multiplied separately, for example image.RGBA(255, 0, 0, 255) keeps only red
+// component of the source. The default is opaque white which does not modify
+// source colors.
+func (s *SourceOver) SetTint(tint image.Color) {
+	s.modulation.setTint(tint)
+}
 
 // BlendSourceToTarget blends source into target selection. Results will be stored
 // in the image pointed by target selection
 // Only position of the target Selection is used and the source is not clamped by
 // the target size.
 func (s *SourceOver) BlendSourceToTarget(source, target image.Selection) {
-	source = clampSourceToTargetImage(source, target)
+	if s.transform != NoTransform {
+		blendTransformed(source, target, s.transform, s.blendColor)
+		return
+	}
+	source = clip.SourceToTargetImage(source, target)
 	target = target.WithSize(source.Width(), source.Height())
 	var (
 		sourceLines   = source.Lines()
@@ -125,6 +161,13 @@ func (s *SourceOver) BlendSourceToTarget(source, target image.Selection) {
 	for y := startY; y < height; y++ {
 		sourceLine := sourceLines.LineForRead(y - sourceYOffset)
 		targetLine := targetLines.LineForWrite(y - targetYOffset)
+		if s.modulation.enabled {
+			for x := targetXOffset + sourceXOffset; x < len(sourceLine); x++ {
+				i := x - targetXOffset
+				targetLine[i] = s.modulation.sourceOver(sourceLine[x-sourceXOffset], targetLine[i])
+			}
+			continue
+		}
 		for x := targetXOffset + sourceXOffset; x < len(sourceLine); x++ {
 			// blend source with target color (following block of code is inlined to improve performance)
 			source := sourceLine[x-sourceXOffset]
@@ -141,6 +184,21 @@ func (s *SourceOver) BlendSourceToTarget(source, target image.Selection) {
 	}
 }
 
+func (s *SourceOver) blendColor(source, target image.Color) image.Color {
+	if s.modulation.enabled {
+		return s.modulation.sourceOver(source, target)
+	}
+	srcR, srcG, srcB, srcA := source.RGBAi()
+	dstR, dstG, dstB, dstA := target.RGBAi()
+	dstFactor := 255 - srcA
+	return image.RGBAi(
+		srcR+mul(dstR, dstFactor),
+		srcG+mul(dstG, dstFactor),
+		srcB+mul(dstB, dstFactor),
+		srcA+mul(dstA, dstFactor),
+	)
+}
+
 // mul is an optimized version of round(a * b / 255)
 func mul(a, b int) int {
 	t := a*b + 0x80
@@ -149,8 +207,33 @@ func mul(a, b int) int {
 
 // Tool is a customizable blending tool which blends together two selections. It uses
 // ColorBlender implementation for actual blending of two pixel colors.
+//
+// Source colors can be multiplied by opacity and tint before blending.
 type Tool struct {
 	colorBlender ColorBlender
+	modulation   modulation
+	transform    Transform
+}
+
+// SetTransform sets the transform (flip or rotation) applied to the source
+// before blending. Will panic when transform is invalid.
+func (t *Tool) SetTransform(transform Transform) {
+	validateTransform(transform)
+	t.transform = transform
+}
+
+// SetOpacity sets the global opacity multiplied with all source colors.
+// 0 means fully transparent and 1 (the default) fully opaque source. Will panic
+// when opacity is outside range [0,1].
+func (t *Tool) SetOpacity(opacity float64) {
+	t.modulation.setOpacity(opacity)
+}
+
+// SetTint sets the color multiplied with all source colors. Each component is
+// multiplied separately. The default is opaque white which does not modify
+// source colors.
+func (t *Tool) SetTint(tint image.Color) {
+	t.modulation.setTint(tint)
 }
 
 // BlendSourceToTarget blends source into target selection. Results will be stored
@@ -158,12 +241,21 @@ type Tool struct {
 // Only position of the target Selection is used and the source is not clamped by
 // the target size.
 func (t *Tool) BlendSourceToTarget(source, target image.Selection) {
+	if t.transform != NoTransform {
+		blendTransformed(source, target, t.transform, t.blendColor)
+		return
+	}
 	for y := 0; y < source.Height(); y++ {
 		for x := 0; x < source.Width(); x++ {
-			sourceColor := source.Color(x, y)
-			targetColor := target.Color(x, y)
-			color := t.colorBlender.BlendSourceToTargetColor(sourceColor, targetColor)
+			color := t.blendColor(source.Color(x, y), target.Color(x, y))
 			target.SetColor(x, y, color)
 		}
 	}
 }
+
+func (t *Tool) blendColor(source, target image.Color) image.Color {
+	if t.modulation.enabled {
+		source = t.modulation.apply(source)
+	}
+	return t.colorBlender.BlendSourceToTargetColor(source, target)
+}
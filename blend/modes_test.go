@@ -0,0 +1,126 @@
+package blend_test
+
+import (
+	"testing"
+
+	"github.com/elgopher/pixiq/blend"
+	"github.com/elgopher/pixiq/image"
+)
+
+func TestPorterDuff(t *testing.T) {
+	var (
+		source = image.RGBA(100, 0, 0, 200)
+		target = image.RGBA(0, 60, 0, 120)
+	)
+	tests := map[string]struct {
+		tool     *blend.Tool
+		expected image.Color
+	}{
+		"Clear":           {tool: blend.NewClear(), expected: image.Transparent},
+		"Destination":     {tool: blend.NewDestination(), expected: target},
+		"DestinationOver": {tool: blend.NewDestinationOver(), expected: image.RGBA(53, 60, 0, 226)},
+		"SourceIn":        {tool: blend.NewSourceIn(), expected: image.RGBA(47, 0, 0, 94)},
+		"DestinationIn":   {tool: blend.NewDestinationIn(), expected: image.RGBA(0, 47, 0, 94)},
+		"SourceOut":       {tool: blend.NewSourceOut(), expected: image.RGBA(53, 0, 0, 106)},
+		"DestinationOut":  {tool: blend.NewDestinationOut(), expected: image.RGBA(0, 13, 0, 26)},
+		"SourceAtop":      {tool: blend.NewSourceAtop(), expected: image.RGBA(47, 13, 0, 120)},
+		"DestinationAtop": {tool: blend.NewDestinationAtop(), expected: image.RGBA(53, 47, 0, 200)},
+		"Xor":             {tool: blend.NewXor(), expected: image.RGBA(53, 13, 0, 132)},
+		"Add":             {tool: blend.NewAdd(), expected: image.RGBA(100, 60, 0, 255)},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sourceImage := newImage([][]image.Color{{source}})
+			targetImage := newImage([][]image.Color{{target}})
+			// when
+			test.tool.BlendSourceToTarget(sourceImage.WholeImageSelection(), targetImage.WholeImageSelection())
+			// then
+			assertColors(t, targetImage, [][]image.Color{{test.expected}})
+		})
+	}
+}
+
+func TestSeparable(t *testing.T) {
+	var (
+		opaqueSource = image.RGB(255, 128, 0)
+		opaqueTarget = image.RGB(192, 192, 192)
+		// semitransparent source is blended with opaque target
+		semiSource = image.NRGBA(255, 128, 0, 128)
+		semiTarget = image.RGB(192, 64, 64)
+	)
+	tests := map[string]struct {
+		tool                    *blend.Tool
+		expectedOpaque          image.Color
+		expectedSemitransparent image.Color
+	}{
+		"Multiply": {
+			tool:                    blend.NewMultiply(),
+			expectedOpaque:          image.RGB(192, 96, 0),
+			expectedSemitransparent: image.RGB(192, 48, 32),
+		},
+		"Screen": {
+			tool:                    blend.NewScreen(),
+			expectedOpaque:          image.RGB(255, 224, 192),
+			expectedSemitransparent: image.RGB(224, 112, 64),
+		},
+		"Overlay": {
+			tool:                    blend.NewOverlay(),
+			expectedOpaque:          image.RGB(255, 192, 129),
+			expectedSemitransparent: image.RGB(224, 64, 32),
+		},
+		"Subtract": {
+			tool:                    blend.NewSubtract(),
+			expectedOpaque:          image.RGB(0, 64, 192),
+			expectedSemitransparent: image.RGB(96, 32, 64),
+		},
+		"Darken": {
+			tool:                    blend.NewDarken(),
+			expectedOpaque:          image.RGB(192, 128, 0),
+			expectedSemitransparent: image.RGB(192, 64, 32),
+		},
+		"Lighten": {
+			tool:                    blend.NewLighten(),
+			expectedOpaque:          image.RGB(255, 192, 192),
+			expectedSemitransparent: image.RGB(224, 96, 64),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should blend opaque colors", func(t *testing.T) {
+				sourceImage := newImage([][]image.Color{{opaqueSource}})
+				targetImage := newImage([][]image.Color{{opaqueTarget}})
+				// when
+				test.tool.BlendSourceToTarget(sourceImage.WholeImageSelection(), targetImage.WholeImageSelection())
+				// then
+				assertColors(t, targetImage, [][]image.Color{{test.expectedOpaque}})
+			})
+
+			t.Run("should blend semitransparent source", func(t *testing.T) {
+				sourceImage := newImage([][]image.Color{{semiSource}})
+				targetImage := newImage([][]image.Color{{semiTarget}})
+				// when
+				test.tool.BlendSourceToTarget(sourceImage.WholeImageSelection(), targetImage.WholeImageSelection())
+				// then
+				assertColors(t, targetImage, [][]image.Color{{test.expectedSemitransparent}})
+			})
+
+			t.Run("should preserve target when source is transparent", func(t *testing.T) {
+				sourceImage := newImage([][]image.Color{{image.Transparent}})
+				targetImage := newImage([][]image.Color{{semiTarget}})
+				// when
+				test.tool.BlendSourceToTarget(sourceImage.WholeImageSelection(), targetImage.WholeImageSelection())
+				// then
+				assertColors(t, targetImage, [][]image.Color{{semiTarget}})
+			})
+
+			t.Run("should copy source when target is transparent", func(t *testing.T) {
+				sourceImage := newImage([][]image.Color{{semiSource}})
+				targetImage := newImage([][]image.Color{{image.Transparent}})
+				// when
+				test.tool.BlendSourceToTarget(sourceImage.WholeImageSelection(), targetImage.WholeImageSelection())
+				// then
+				assertColors(t, targetImage, [][]image.Color{{semiSource}})
+			})
+		})
+	}
+}
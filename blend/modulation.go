@@ -0,0 +1,73 @@
+package blend
+
+import (
+	"math"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+// modulation multiplies source colors by tint and opacity. Zero value does
+// not modify colors.
+type modulation struct {
+	enabled bool
+	tint    image.Color
+	opacity float64
+	// r, g, b, a are tint components multiplied by opacity in range [0,1]
+	r, g, b, a float64
+}
+
+func (m *modulation) setOpacity(opacity float64) {
+	if opacity < 0 || opacity > 1 || math.IsNaN(opacity) {
+		panic("opacity outside range [0,1]")
+	}
+	if !m.enabled {
+		m.tint = image.RGBA(255, 255, 255, 255)
+	}
+	m.opacity = opacity
+	m.update()
+}
+
+func (m *modulation) setTint(tint image.Color) {
+	if !m.enabled {
+		m.opacity = 1
+	}
+	m.tint = tint
+	m.update()
+}
+
+func (m *modulation) update() {
+	r, g, b, a := m.tint.RGBAi()
+	m.r = float64(r) / 255 * m.opacity
+	m.g = float64(g) / 255 * m.opacity
+	m.b = float64(b) / 255 * m.opacity
+	m.a = float64(a) / 255 * m.opacity
+	m.enabled = m.r != 1 || m.g != 1 || m.b != 1 || m.a != 1
+}
+
+func (m *modulation) apply(color image.Color) image.Color {
+	r, g, b, a := color.RGBAi()
+	return image.RGBA(
+		roundByte(float64(r)*m.r),
+		roundByte(float64(g)*m.g),
+		roundByte(float64(b)*m.b),
+		roundByte(float64(a)*m.a),
+	)
+}
+
+func roundByte(v float64) byte {
+	return byte(math.Round(v))
+}
+
+// sourceOver blends modulated source with target. Modulated source is not
+// rounded before blending, so results are the same as for video card.
+func (m *modulation) sourceOver(source, target image.Color) image.Color {
+	srcR, srcG, srcB, srcA := source.RGBAi()
+	dstR, dstG, dstB, dstA := target.RGBAi()
+	dstFactor := 1 - float64(srcA)*m.a/255
+	return image.RGBA(
+		roundByte(float64(srcR)*m.r+float64(dstR)*dstFactor),
+		roundByte(float64(srcG)*m.g+float64(dstG)*dstFactor),
+		roundByte(float64(srcB)*m.b+float64(dstB)*dstFactor),
+		roundByte(float64(srcA)*m.a+float64(dstA)*dstFactor),
+	)
+}
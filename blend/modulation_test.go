@@ -0,0 +1,160 @@
+package blend_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/blend"
+	"github.com/elgopher/pixiq/image"
+)
+
+func TestSourceOver_SetOpacity(t *testing.T) {
+	t.Run("should panic when opacity is outside range", func(t *testing.T) {
+		for _, opacity := range []float64{-0.1, 1.1, math.NaN()} {
+			assert.Panics(t, func() {
+				blend.NewSourceOver().SetOpacity(opacity)
+			})
+		}
+	})
+
+	t.Run("should blend with opacity", func(t *testing.T) {
+		tests := map[string]struct {
+			opacity  float64
+			expected image.Color
+		}{
+			"0":   {opacity: 0, expected: image.RGBA(0, 0, 100, 255)},
+			"0.5": {opacity: 0.5, expected: image.RGBA(100, 50, 86, 255)},
+			"1":   {opacity: 1, expected: image.RGBA(200, 100, 72, 255)},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				source := newImage([][]image.Color{{image.RGBA(200, 100, 50, 200)}})
+				target := newImage([][]image.Color{{image.RGBA(0, 0, 100, 255)}})
+				tool := blend.NewSourceOver()
+				tool.SetOpacity(test.opacity)
+				// when
+				tool.BlendSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+				// then
+				assertColors(t, target, [][]image.Color{{test.expected}})
+			})
+		}
+	})
+
+	t.Run("should blend many pixels", func(t *testing.T) {
+		source := newImage([][]image.Color{
+			{image.RGBA(200, 100, 50, 255), image.RGBA(20, 40, 60, 100)},
+		})
+		target := newImage([][]image.Color{
+			{image.RGBA(10, 20, 30, 255), image.RGBA(0, 0, 100, 255), image.RGBA(1, 2, 3, 4)},
+		})
+		tool := blend.NewSourceOver()
+		tool.SetOpacity(0.5)
+		// when
+		tool.BlendSourceToTarget(source.WholeImageSelection(), target.Selection(1, 0))
+		// then
+		assertColors(t, target, [][]image.Color{
+			{image.RGBA(10, 20, 30, 255), image.RGBA(100, 50, 75, 255), image.RGBA(11, 22, 32, 53)},
+		})
+	})
+}
+
+func TestSourceOver_SetTint(t *testing.T) {
+	tests := map[string]struct {
+		tint     image.Color
+		opacity  float64
+		expected image.Color
+	}{
+		"white": {
+			tint:     image.RGBA(255, 255, 255, 255),
+			opacity:  1,
+			expected: image.RGBA(200, 100, 50, 255),
+		},
+		"red": {
+			tint:     image.RGBA(255, 0, 0, 255),
+			opacity:  1,
+			expected: image.RGBA(200, 0, 0, 255),
+		},
+		"semitransparent white": {
+			tint:     image.NRGBA(255, 255, 255, 128),
+			opacity:  1,
+			expected: image.RGBA(100, 50, 75, 255),
+		},
+		"red with opacity": {
+			tint:     image.RGBA(255, 0, 0, 255),
+			opacity:  0.5,
+			expected: image.RGBA(100, 0, 50, 255),
+		},
+		"transparent": {
+			tint:     image.Transparent,
+			opacity:  1,
+			expected: image.RGBA(0, 0, 100, 255),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			source := newImage([][]image.Color{{image.RGBA(200, 100, 50, 255)}})
+			target := newImage([][]image.Color{{image.RGBA(0, 0, 100, 255)}})
+			tool := blend.NewSourceOver()
+			tool.SetTint(test.tint)
+			tool.SetOpacity(test.opacity)
+			// when
+			tool.BlendSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+			// then
+			assertColors(t, target, [][]image.Color{{test.expected}})
+		})
+	}
+
+	t.Run("should tint using zero value", func(t *testing.T) {
+		source := newImage([][]image.Color{{image.RGBA(200, 100, 50, 255)}})
+		target := newImage([][]image.Color{{image.RGBA(0, 0, 100, 255)}})
+		tool := &blend.SourceOver{}
+		tool.SetTint(image.RGBA(0, 255, 0, 255))
+		// when
+		tool.BlendSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+		// then
+		assertColors(t, target, [][]image.Color{{image.RGBA(0, 100, 0, 255)}})
+	})
+}
+
+func TestTool_SetOpacity(t *testing.T) {
+	t.Run("should panic when opacity is outside range", func(t *testing.T) {
+		for _, opacity := range []float64{-0.1, 1.1, math.NaN()} {
+			assert.Panics(t, func() {
+				blend.New(sourceColor{}).SetOpacity(opacity)
+			})
+		}
+	})
+
+	t.Run("should pass source color multiplied by opacity to ColorBlender", func(t *testing.T) {
+		source := newImage([][]image.Color{{image.RGBA(200, 100, 50, 200)}})
+		target := newImage([][]image.Color{{image.RGBA(0, 0, 100, 255)}})
+		tool := blend.New(sourceColor{})
+		tool.SetOpacity(0.5)
+		// when
+		tool.BlendSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+		// then
+		assertColors(t, target, [][]image.Color{{image.RGBA(100, 50, 25, 100)}})
+	})
+}
+
+func TestTool_SetTint(t *testing.T) {
+	t.Run("should pass source color multiplied by tint to ColorBlender", func(t *testing.T) {
+		source := newImage([][]image.Color{{image.RGBA(200, 100, 50, 200)}})
+		target := newImage([][]image.Color{{image.RGBA(0, 0, 100, 255)}})
+		tool := blend.New(sourceColor{})
+		tool.SetTint(image.RGBA(0, 255, 0, 255))
+		// when
+		tool.BlendSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+		// then
+		assertColors(t, target, [][]image.Color{{image.RGBA(0, 100, 0, 200)}})
+	})
+}
+
+// sourceColor replaces target color with source color
+type sourceColor struct{}
+
+func (c sourceColor) BlendSourceToTargetColor(source, _ image.Color) image.Color {
+	return source
+}
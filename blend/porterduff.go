@@ -0,0 +1,131 @@
+package blend
+
+import (
+	"github.com/elgopher/pixiq/image"
+)
+
+// Porter-Duff operators compose source and target using formula:
+//
+//	R = S*Fs + D*Fd
+//
+// where S is a source color component, D is a target (destination) color
+// component and Fs, Fd are factors depending on the source and target alpha.
+// All colors are premultiplied by alpha.
+//
+// See https://www.w3.org/TR/compositing-1/#porterduffcompositingoperators
+
+// NewClear creates a new blending tool which clears the target (Fs=0, Fd=0).
+func NewClear() *Tool {
+	return New(porterDuff{source: zero, target: zero})
+}
+
+// NewDestination creates a new blending tool which preserves the target
+// (Fs=0, Fd=1).
+func NewDestination() *Tool {
+	return New(porterDuff{source: zero, target: one})
+}
+
+// NewDestinationOver creates a new blending tool which paints the target on
+// top of the source (Fs=1-Da, Fd=1).
+func NewDestinationOver() *Tool {
+	return New(porterDuff{source: oneMinusTargetAlpha, target: one})
+}
+
+// NewSourceIn creates a new blending tool which shows the source only where
+// the target is (Fs=Da, Fd=0). The target is cleared elsewhere.
+func NewSourceIn() *Tool {
+	return New(porterDuff{source: targetAlpha, target: zero})
+}
+
+// NewDestinationIn creates a new blending tool which preserves the target only
+// where the source is (Fs=0, Fd=Sa). It can be used for masking.
+func NewDestinationIn() *Tool {
+	return New(porterDuff{source: zero, target: sourceAlpha})
+}
+
+// NewSourceOut creates a new blending tool which shows the source only where
+// the target is not (Fs=1-Da, Fd=0). The target is cleared elsewhere.
+func NewSourceOut() *Tool {
+	return New(porterDuff{source: oneMinusTargetAlpha, target: zero})
+}
+
+// NewDestinationOut creates a new blending tool which preserves the target
+// only where the source is not (Fs=0, Fd=1-Sa). It can be used for erasing.
+func NewDestinationOut() *Tool {
+	return New(porterDuff{source: zero, target: oneMinusSourceAlpha})
+}
+
+// NewSourceAtop creates a new blending tool which paints the source on top
+// of the target, but only where the target is (Fs=Da, Fd=1-Sa).
+func NewSourceAtop() *Tool {
+	return New(porterDuff{source: targetAlpha, target: oneMinusSourceAlpha})
+}
+
+// NewDestinationAtop creates a new blending tool which paints the target on
+// top of the source, but only where the source is (Fs=1-Da, Fd=Sa).
+func NewDestinationAtop() *Tool {
+	return New(porterDuff{source: oneMinusTargetAlpha, target: sourceAlpha})
+}
+
+// NewXor creates a new blending tool which shows the source where the target
+// is not and the target where the source is not (Fs=1-Da, Fd=1-Sa).
+func NewXor() *Tool {
+	return New(porterDuff{source: oneMinusTargetAlpha, target: oneMinusSourceAlpha})
+}
+
+// NewAdd creates a new blending tool which adds source and target colors
+// (Fs=1, Fd=1), aka Plus or Linear Dodge. Results are clamped. It can be used
+// for lighting.
+func NewAdd() *Tool {
+	return New(porterDuff{source: one, target: one})
+}
+
+// factor returns the Porter-Duff factor in range 0 to 255
+type factor func(sourceAlpha, targetAlpha int) int
+
+func zero(int, int) int {
+	return 0
+}
+
+func one(int, int) int {
+	return 255
+}
+
+func sourceAlpha(sourceAlpha, _ int) int {
+	return sourceAlpha
+}
+
+func oneMinusSourceAlpha(sourceAlpha, _ int) int {
+	return 255 - sourceAlpha
+}
+
+func targetAlpha(_, targetAlpha int) int {
+	return targetAlpha
+}
+
+func oneMinusTargetAlpha(_, targetAlpha int) int {
+	return 255 - targetAlpha
+}
+
+// porterDuff is a ColorBlender for Porter-Duff operators
+type porterDuff struct {
+	source, target factor
+}
+
+func (p porterDuff) BlendSourceToTargetColor(source, target image.Color) image.Color {
+	srcR, srcG, srcB, srcA := source.RGBAi()
+	dstR, dstG, dstB, dstA := target.RGBAi()
+	fs := p.source(srcA, dstA)
+	fd := p.target(srcA, dstA)
+	// the sum is rounded once, the same way as done by video card
+	return image.RGBAi(
+		div255(srcR*fs+dstR*fd),
+		div255(srcG*fs+dstG*fd),
+		div255(srcB*fs+dstB*fd),
+		div255(srcA*fs+dstA*fd))
+}
+
+// div255 is round(a / 255)
+func div255(a int) int {
+	return (a + 127) / 255
+}
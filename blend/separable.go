@@ -0,0 +1,99 @@
+package blend
+
+import (
+	"math"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+// Separable blend modes mix source and target colors using a function applied
+// to each color component separately. The result is painted on top of the
+// target the same way as SourceOver does:
+//
+//	R = S*(1-Da) + D*(1-Sa) + Sa*Da*B(D/Da, S/Sa)
+//	Ra = Sa + Da - Sa*Da
+//
+// where B is a blending function, S is a source color component, D is a target
+// (destination) color component, Sa and Da are source and target alpha.
+//
+// See https://www.w3.org/TR/compositing-1/#blending
+
+// NewMultiply creates a new blending tool which multiplies source and target
+// colors. The result is always darker. It can be used for shadows and lighting
+// (with light map as a source).
+func NewMultiply() *Tool {
+	return New(separable(func(d, s float64) float64 {
+		return d * s
+	}))
+}
+
+// NewScreen creates a new blending tool which multiplies complements of source
+// and target colors. The result is always lighter. It is the opposite of
+// Multiply.
+func NewScreen() *Tool {
+	return New(separable(screen))
+}
+
+// NewOverlay creates a new blending tool which multiplies or screens colors,
+// depending on the target color. Dark target colors get darker, light target
+// colors get lighter.
+func NewOverlay() *Tool {
+	return New(separable(func(d, s float64) float64 {
+		if d <= 0.5 {
+			return s * 2 * d
+		}
+		return screen(2*d-1, s)
+	}))
+}
+
+// NewSubtract creates a new blending tool which subtracts source color from
+// the target. Results are clamped.
+func NewSubtract() *Tool {
+	return New(separable(func(d, s float64) float64 {
+		return math.Max(0, d-s)
+	}))
+}
+
+// NewDarken creates a new blending tool which selects darker of source and
+// target color components.
+func NewDarken() *Tool {
+	return New(separable(math.Min))
+}
+
+// NewLighten creates a new blending tool which selects lighter of source and
+// target color components.
+func NewLighten() *Tool {
+	return New(separable(math.Max))
+}
+
+func screen(d, s float64) float64 {
+	return d + s - d*s
+}
+
+// separable is a ColorBlender for separable blend modes. Function is called
+// with target and source color components not premultiplied by alpha.
+type separable func(d, s float64) float64
+
+func (f separable) BlendSourceToTargetColor(source, target image.Color) image.Color {
+	srcR, srcG, srcB, srcA := source.RGBAi()
+	dstR, dstG, dstB, dstA := target.RGBAi()
+	sa := float64(srcA) / 255
+	da := float64(dstA) / 255
+	mix := func(s, d int) int {
+		sp, dp := float64(s)/255, float64(d)/255
+		var blended float64
+		if sa > 0 && da > 0 {
+			blended = sa * da * f(dp/da, sp/sa)
+		}
+		return toByte(sp*(1-da) + dp*(1-sa) + blended)
+	}
+	return image.RGBAi(
+		mix(srcR, dstR),
+		mix(srcG, dstG),
+		mix(srcB, dstB),
+		toByte(sa+da-sa*da))
+}
+
+func toByte(v float64) int {
+	return int(math.Round(v * 255))
+}
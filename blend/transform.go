@@ -0,0 +1,81 @@
+package blend
+
+import (
+	"github.com/elgopher/pixiq/image"
+)
+
+// Transform changes orientation of the source before blending. Only flips and
+// rotations by multiple of 90 degrees are supported, so pixels are never
+// resampled.
+type Transform int
+
+const (
+	// NoTransform blends source as-is. This is the default.
+	NoTransform Transform = iota
+	// FlipHorizontal mirrors source horizontally (left becomes right).
+	FlipHorizontal
+	// FlipVertical mirrors source vertically (top becomes bottom).
+	FlipVertical
+	// Rotate90 rotates source clockwise by 90 degrees.
+	Rotate90
+	// Rotate180 rotates source by 180 degrees.
+	Rotate180
+	// Rotate270 rotates source clockwise by 270 degrees (counterclockwise by 90).
+	Rotate270
+)
+
+// Size returns the size of the transformed source with given dimensions.
+// Width and height are swapped for Rotate90 and Rotate270.
+func (t Transform) Size(width, height int) (int, int) {
+	if t == Rotate90 || t == Rotate270 {
+		return height, width
+	}
+	return width, height
+}
+
+// SourcePosition returns position of the source pixel which after transformation
+// is placed at position x, y. Width and height are dimensions of the source.
+func (t Transform) SourcePosition(x, y, width, height int) (int, int) {
+	switch t {
+	case FlipHorizontal:
+		return width - 1 - x, y
+	case FlipVertical:
+		return x, height - 1 - y
+	case Rotate90:
+		return y, height - 1 - x
+	case Rotate180:
+		return width - 1 - x, height - 1 - y
+	case Rotate270:
+		return width - 1 - y, x
+	default:
+		return x, y
+	}
+}
+
+func validateTransform(transform Transform) {
+	if transform < NoTransform || transform > Rotate270 {
+		panic("invalid transform")
+	}
+}
+
+// blendTransformed blends transformed source into target pixel by pixel
+// using given function.
+func blendTransformed(source, target image.Selection, transform Transform,
+	blend func(source, target image.Color) image.Color) {
+	sourceWidth, sourceHeight := source.Width(), source.Height()
+	if sourceWidth <= 0 || sourceHeight <= 0 {
+		return
+	}
+	lines := target.WithSize(transform.Size(sourceWidth, sourceHeight)).Lines()
+	var (
+		xOffset = lines.XOffset()
+		yOffset = lines.YOffset()
+	)
+	for y := 0; y < lines.Length(); y++ {
+		line := lines.LineForWrite(y)
+		for x := range line {
+			sourceX, sourceY := transform.SourcePosition(x+xOffset, y+yOffset, sourceWidth, sourceHeight)
+			line[x] = blend(source.Color(sourceX, sourceY), line[x])
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package blend_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/blend"
+	"github.com/elgopher/pixiq/image"
+)
+
+func TestTransform_Size(t *testing.T) {
+	tests := map[blend.Transform]struct {
+		expectedWidth, expectedHeight int
+	}{
+		blend.NoTransform:    {expectedWidth: 3, expectedHeight: 2},
+		blend.FlipHorizontal: {expectedWidth: 3, expectedHeight: 2},
+		blend.FlipVertical:   {expectedWidth: 3, expectedHeight: 2},
+		blend.Rotate90:       {expectedWidth: 2, expectedHeight: 3},
+		blend.Rotate180:      {expectedWidth: 3, expectedHeight: 2},
+		blend.Rotate270:      {expectedWidth: 2, expectedHeight: 3},
+	}
+	for transform, test := range tests {
+		// when
+		width, height := transform.Size(3, 2)
+		// then
+		assert.Equal(t, test.expectedWidth, width)
+		assert.Equal(t, test.expectedHeight, height)
+	}
+}
+
+type transformableTool interface {
+	SetTransform(blend.Transform)
+	BlendSourceToTarget(source, target image.Selection)
+}
+
+func TestSetTransform(t *testing.T) {
+	var (
+		a = image.RGB(10, 0, 0)
+		b = image.RGB(20, 0, 0)
+		c = image.RGB(30, 0, 0)
+		d = image.RGB(40, 0, 0)
+		e = image.RGB(50, 0, 0)
+		f = image.RGB(60, 0, 0)
+		x = image.RGB(0, 0, 1) // target color
+		o = image.Transparent
+	)
+	tools := map[string]struct {
+		newTool func() transformableTool
+		// outside is expected target color when source pixel is outside
+		// the source image
+		outside image.Color
+	}{
+		"Source": {
+			newTool: func() transformableTool { return blend.NewSource() },
+			outside: o,
+		},
+		"SourceOver": {
+			newTool: func() transformableTool { return blend.NewSourceOver() },
+			outside: x,
+		},
+		"Tool": {
+			newTool: func() transformableTool { return blend.New(sourceColor{}) },
+			outside: o,
+		},
+	}
+	for name, tool := range tools {
+		newTool := tool.newTool
+		outside := tool.outside
+		t.Run(name, func(t *testing.T) {
+			t.Run("should panic when transform is invalid", func(t *testing.T) {
+				for _, transform := range []blend.Transform{-1, blend.Rotate270 + 1} {
+					assert.Panics(t, func() {
+						newTool().SetTransform(transform)
+					})
+				}
+			})
+
+			t.Run("should blend transformed source", func(t *testing.T) {
+				tests := map[blend.Transform][][]image.Color{
+					blend.NoTransform: {
+						{a, b, c},
+						{d, e, f},
+						{x, x, x},
+					},
+					blend.FlipHorizontal: {
+						{c, b, a},
+						{f, e, d},
+						{x, x, x},
+					},
+					blend.FlipVertical: {
+						{d, e, f},
+						{a, b, c},
+						{x, x, x},
+					},
+					blend.Rotate90: {
+						{d, a, x},
+						{e, b, x},
+						{f, c, x},
+					},
+					blend.Rotate180: {
+						{f, e, d},
+						{c, b, a},
+						{x, x, x},
+					},
+					blend.Rotate270: {
+						{c, f, x},
+						{b, e, x},
+						{a, d, x},
+					},
+				}
+				for transform, expected := range tests {
+					source := newImage([][]image.Color{
+						{a, b, c},
+						{d, e, f},
+					})
+					target := newImage([][]image.Color{
+						{x, x, x},
+						{x, x, x},
+						{x, x, x},
+					})
+					tool := newTool()
+					tool.SetTransform(transform)
+					// when
+					tool.BlendSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+					// then
+					assertColors(t, target, expected)
+				}
+			})
+
+			t.Run("should blend transformed source into target partially outside image", func(t *testing.T) {
+				source := newImage([][]image.Color{
+					{a, b, c},
+					{d, e, f},
+				})
+				target := newImage([][]image.Color{
+					{x, x},
+					{x, x},
+				})
+				tool := newTool()
+				tool.SetTransform(blend.Rotate90)
+				// when
+				tool.BlendSourceToTarget(source.WholeImageSelection(), target.Selection(1, -1))
+				// then
+				assertColors(t, target, [][]image.Color{
+					{x, e},
+					{x, f},
+				})
+			})
+
+			t.Run("should blend transformed source partially outside image", func(t *testing.T) {
+				source := newImage([][]image.Color{
+					{a, b},
+					{d, e},
+				})
+				target := newImage([][]image.Color{
+					{x, x, x},
+					{x, x, x},
+				})
+				tool := newTool()
+				tool.SetTransform(blend.FlipHorizontal)
+				// when
+				tool.BlendSourceToTarget(source.Selection(0, 0).WithSize(3, 2), target.WholeImageSelection())
+				// then
+				assertColors(t, target, [][]image.Color{
+					{outside, b, a},
+					{outside, e, d},
+				})
+			})
+		})
+	}
+}
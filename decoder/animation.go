@@ -0,0 +1,117 @@
+package decoder
+
+import (
+	stdimage "image"
+	"image/draw"
+	"image/gif"
+	"os"
+	"time"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+// Animation is a decoded sequence of frames.
+type Animation struct {
+	// Frames are fully composed frames ready to be displayed. All frames have
+	// the same size.
+	Frames []Frame
+	// LoopCount controls the number of times an animation will be
+	// restarted during display. 0 means to loop forever, -1 means to play
+	// the animation only once, n > 0 means to play it n+1 times.
+	LoopCount int
+}
+
+// Frame is a single frame of the Animation.
+type Frame struct {
+	Image *image.Image
+	// Delay is the time the frame should be displayed
+	Delay time.Duration
+}
+
+// Duration returns the total duration of a single loop of the animation.
+func (a *Animation) Duration() time.Duration {
+	var duration time.Duration
+	for _, frame := range a.Frames {
+		duration += frame.Delay
+	}
+	return duration
+}
+
+// DecodeAnimation decodes all frames of animated GIF. Each frame is a new
+// *image.Image created by ImageFactory. Frames are composed the same way web
+// browsers do it: disposal methods are taken into account and each frame
+// contains the whole picture, not only the changed part.
+//
+// Not animated GIFs are decoded as single frame animations. Other formats
+// are not supported.
+func (d *Decoder) DecodeAnimation(reader Reader) (*Animation, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	g, err := gif.DecodeAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	canvasBounds := stdimage.Rect(0, 0, g.Config.Width, g.Config.Height)
+	for _, frame := range g.Image {
+		canvasBounds = canvasBounds.Union(frame.Bounds())
+	}
+	var (
+		canvas    = stdimage.NewRGBA(canvasBounds)
+		previous  = stdimage.NewRGBA(canvasBounds)
+		animation = &Animation{
+			Frames:    make([]Frame, 0, len(g.Image)),
+			LoopCount: g.LoopCount,
+		}
+	)
+	for i, frame := range g.Image {
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			copy(previous.Pix, canvas.Pix)
+		}
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		var delay time.Duration
+		if i < len(g.Delay) {
+			delay = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+		animation.Frames = append(animation.Frames, Frame{
+			Image: d.newFrameImage(canvas),
+			Delay: delay,
+		})
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), stdimage.Transparent, stdimage.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, previous.Pix)
+		}
+	}
+	return animation, nil
+}
+
+// newFrameImage creates a new *image.Image filled with colors of the canvas.
+// Top-left corner of the canvas becomes (0,0) of the frame.
+func (d *Decoder) newFrameImage(canvas *stdimage.RGBA) *image.Image {
+	bounds := canvas.Bounds()
+	newImage := d.imageFactory.NewImage(bounds.Dx(), bounds.Dy())
+	target := newImage.WholeImageSelection()
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := canvas.RGBAAt(x+bounds.Min.X, y+bounds.Min.Y)
+			target.SetColor(x, y, image.RGBA(c.R, c.G, c.B, c.A))
+		}
+	}
+	return newImage
+}
+
+// DecodeAnimationFile decodes all frames of animated GIF file. See DecodeAnimation.
+func (d *Decoder) DecodeAnimationFile(fileName string) (*Animation, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return d.DecodeAnimation(file)
+}
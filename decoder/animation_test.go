@@ -0,0 +1,234 @@
+package decoder_test
+
+import (
+	"bytes"
+	"errors"
+	stdimage "image"
+	"image/color"
+	"image/gif"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/decoder"
+	"github.com/elgopher/pixiq/image"
+)
+
+var (
+	gifPalette = color.Palette{
+		color.RGBA{},
+		color.RGBA{R: 255, A: 255},
+		color.RGBA{G: 255, A: 255},
+		color.RGBA{B: 255, A: 255},
+	}
+	transparent = image.Transparent
+	red         = image.RGB(255, 0, 0)
+	green       = image.RGB(0, 255, 0)
+	blue        = image.RGB(0, 0, 255)
+)
+
+func TestDecoder_DecodeAnimation(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		assert.Panics(t, func() {
+			_, _ = imageDecoder.DecodeAnimation(nil)
+		})
+	})
+	t.Run("should return error", func(t *testing.T) {
+		tests := map[string]decoder.Reader{
+			"reader error":   &erroneousReader{error: errors.New("read failed")},
+			"invalid format": strings.NewReader("invalid"),
+			"png":            bytes.NewReader(png1x2().data),
+		}
+		for name, reader := range tests {
+			t.Run(name, func(t *testing.T) {
+				imageDecoder := decoder.New(fakeImageFactory{})
+				// when
+				animation, err := imageDecoder.DecodeAnimation(reader)
+				// then
+				assert.Error(t, err)
+				assert.Nil(t, animation)
+			})
+		}
+	})
+	t.Run("should decode not animated GIF", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		// when
+		animation, err := imageDecoder.DecodeAnimation(bytes.NewReader(gif1x2().data))
+		// then
+		require.NoError(t, err)
+		require.Len(t, animation.Frames, 1)
+		assertImageColors(t, gif1x2().expectedColors, animation.Frames[0].Image)
+	})
+	t.Run("should decode frames", func(t *testing.T) {
+		tests := map[string]struct {
+			frames   []gifFrame
+			expected [][][]image.Color
+		}{
+			"full frames": {
+				frames: []gifFrame{
+					{bounds: stdimage.Rect(0, 0, 2, 1), pixels: []uint8{1, 2}},
+					{bounds: stdimage.Rect(0, 0, 2, 1), pixels: []uint8{3, 1}},
+				},
+				expected: [][][]image.Color{
+					{{red, green}},
+					{{blue, red}},
+				},
+			},
+			"partial frame": {
+				frames: []gifFrame{
+					{bounds: stdimage.Rect(0, 0, 2, 1), pixels: []uint8{1, 2}},
+					{bounds: stdimage.Rect(1, 0, 2, 1), pixels: []uint8{3}},
+				},
+				expected: [][][]image.Color{
+					{{red, green}},
+					{{red, blue}},
+				},
+			},
+			"transparent pixel": {
+				frames: []gifFrame{
+					{bounds: stdimage.Rect(0, 0, 2, 1), pixels: []uint8{1, 2}},
+					{bounds: stdimage.Rect(0, 0, 2, 1), pixels: []uint8{0, 3}},
+				},
+				expected: [][][]image.Color{
+					{{red, green}},
+					{{red, blue}},
+				},
+			},
+			"disposal none": {
+				frames: []gifFrame{
+					{bounds: stdimage.Rect(0, 0, 2, 1), pixels: []uint8{1, 2}, disposal: gif.DisposalNone},
+					{bounds: stdimage.Rect(1, 0, 2, 1), pixels: []uint8{3}},
+				},
+				expected: [][][]image.Color{
+					{{red, green}},
+					{{red, blue}},
+				},
+			},
+			"disposal background": {
+				frames: []gifFrame{
+					{bounds: stdimage.Rect(0, 0, 2, 1), pixels: []uint8{1, 2}},
+					{bounds: stdimage.Rect(1, 0, 2, 1), pixels: []uint8{3}, disposal: gif.DisposalBackground},
+					{bounds: stdimage.Rect(0, 0, 1, 1), pixels: []uint8{3}},
+				},
+				expected: [][][]image.Color{
+					{{red, green}},
+					{{red, blue}},
+					{{blue, transparent}},
+				},
+			},
+			"disposal previous": {
+				frames: []gifFrame{
+					{bounds: stdimage.Rect(0, 0, 2, 1), pixels: []uint8{1, 2}},
+					{bounds: stdimage.Rect(1, 0, 2, 1), pixels: []uint8{3}, disposal: gif.DisposalPrevious},
+					{bounds: stdimage.Rect(0, 0, 1, 1), pixels: []uint8{3}},
+				},
+				expected: [][][]image.Color{
+					{{red, green}},
+					{{red, blue}},
+					{{blue, green}},
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				imageDecoder := decoder.New(fakeImageFactory{})
+				data := encodeGIF(t, test.frames, 0)
+				// when
+				animation, err := imageDecoder.DecodeAnimation(bytes.NewReader(data))
+				// then
+				require.NoError(t, err)
+				require.Len(t, animation.Frames, len(test.expected))
+				for i, expected := range test.expected {
+					assertImageColors(t, expected, animation.Frames[i].Image)
+				}
+			})
+		}
+	})
+	t.Run("should decode timing", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		frames := []gifFrame{
+			{bounds: stdimage.Rect(0, 0, 1, 1), pixels: []uint8{1}, delay: 10},
+			{bounds: stdimage.Rect(0, 0, 1, 1), pixels: []uint8{2}, delay: 25},
+		}
+		data := encodeGIF(t, frames, 3)
+		// when
+		animation, err := imageDecoder.DecodeAnimation(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, animation.LoopCount)
+		assert.Equal(t, 100*time.Millisecond, animation.Frames[0].Delay)
+		assert.Equal(t, 250*time.Millisecond, animation.Frames[1].Delay)
+		assert.Equal(t, 350*time.Millisecond, animation.Duration())
+	})
+}
+
+func TestDecoder_DecodeAnimationFile(t *testing.T) {
+	t.Run("should return error when file does not exist", func(t *testing.T) {
+		imageDecoder := decoder.New(fakeImageFactory{})
+		animation, err := imageDecoder.DecodeAnimationFile("not-existing-file")
+		assert.Error(t, err)
+		assert.Nil(t, animation)
+	})
+	t.Run("should decode file", func(t *testing.T) {
+		file, err := ioutil.TempFile("", "TestDecoder_DecodeAnimationFile")
+		require.NoError(t, err)
+		defer os.Remove(file.Name())
+		_, err = file.Write(encodeGIF(t, []gifFrame{
+			{bounds: stdimage.Rect(0, 0, 1, 1), pixels: []uint8{1}},
+			{bounds: stdimage.Rect(0, 0, 1, 1), pixels: []uint8{2}},
+		}, 0))
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		imageDecoder := decoder.New(fakeImageFactory{})
+		// when
+		animation, err := imageDecoder.DecodeAnimationFile(file.Name())
+		// then
+		require.NoError(t, err)
+		assert.Len(t, animation.Frames, 2)
+	})
+}
+
+type gifFrame struct {
+	bounds   stdimage.Rectangle
+	pixels   []uint8
+	delay    int
+	disposal byte
+}
+
+func encodeGIF(t *testing.T, frames []gifFrame, loopCount int) []byte {
+	g := &gif.GIF{
+		LoopCount: loopCount,
+		Config: stdimage.Config{
+			ColorModel: gifPalette,
+			Width:      frames[0].bounds.Dx(),
+			Height:     frames[0].bounds.Dy(),
+		},
+	}
+	for _, frame := range frames {
+		paletted := stdimage.NewPaletted(frame.bounds, gifPalette)
+		copy(paletted.Pix, frame.pixels)
+		g.Image = append(g.Image, paletted)
+		g.Delay = append(g.Delay, frame.delay)
+		g.Disposal = append(g.Disposal, frame.disposal)
+	}
+	buffer := &bytes.Buffer{}
+	require.NoError(t, gif.EncodeAll(buffer, g))
+	return buffer.Bytes()
+}
+
+func assertImageColors(t *testing.T, expected [][]image.Color, img *image.Image) {
+	require.Equal(t, len(expected), img.Height())
+	require.Equal(t, len(expected[0]), img.Width())
+	selection := img.WholeImageSelection()
+	for y, line := range expected {
+		for x, expectedColor := range line {
+			assertColor(t, expectedColor, selection.Color(x, y))
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Package aseprite provides a decoder of files created by Aseprite pixel art
+// editor (.ase and .aseprite):
+//
+//	asepriteDecoder := aseprite.NewDecoder(openGL)
+//	file, err := asepriteDecoder.DecodeFile("player.aseprite")
+//	...
+//	firstFrame := file.Frames[0].Image
+//
+// Besides flattened frame images, the decoder exposes layers, cels, animation
+// tags and slices. Tilemap cels and external files are not supported.
+package aseprite
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/elgopher/pixiq/decoder"
+	"github.com/elgopher/pixiq/image"
+)
+
+// File is a decoded Aseprite file.
+type File struct {
+	Width, Height int
+	// Layers are ordered from the bottom to the top
+	Layers []Layer
+	Frames []Frame
+	Tags   []Tag
+	Slices []Slice
+}
+
+// Layer describes a layer. Pixels are stored in cels.
+type Layer struct {
+	Name    string
+	Visible bool
+	// Group is true for layers grouping other layers
+	Group bool
+	// Parent is an index of the parent group layer or -1 for top level layers
+	Parent    int
+	Opacity   byte
+	BlendMode BlendMode
+}
+
+// BlendMode is a layer blending mode.
+type BlendMode int
+
+// Blend modes supported by Aseprite.
+const (
+	Normal BlendMode = iota
+	Multiply
+	Screen
+	Overlay
+	Darken
+	Lighten
+	ColorDodge
+	ColorBurn
+	HardLight
+	SoftLight
+	Difference
+	Exclusion
+	Hue
+	Saturation
+	Color
+	Luminosity
+	Addition
+	Subtract
+	Divide
+)
+
+// Frame is a single frame of the sprite.
+type Frame struct {
+	Duration time.Duration
+	// Image is a flattened image of all visible layers
+	Image *image.Image
+	// Cels are non-empty layer images in this frame
+	Cels []Cel
+}
+
+// Cel is an image of a single layer in a single frame.
+type Cel struct {
+	// Layer is an index of the layer
+	Layer int
+	// X and Y is the position of the cel image in the frame
+	X, Y    int
+	Opacity byte
+	// Image is shared between linked cels
+	Image *image.Image
+}
+
+// Tag is a named range of frames used to define animations.
+type Tag struct {
+	Name string
+	// From and To are indexes of the first and the last frame (inclusive)
+	From, To  int
+	Direction Direction
+	// Repeat is the number of times the animation is played. 0 means infinity.
+	Repeat int
+}
+
+// Direction is a direction in which tag frames are played.
+type Direction int
+
+const (
+	// Forward plays frames from the first to the last
+	Forward Direction = iota
+	// Reverse plays frames from the last to the first
+	Reverse
+	// PingPong plays frames forward and then in reverse
+	PingPong
+	// PingPongReverse plays frames in reverse and then forward
+	PingPongReverse
+)
+
+// Slice is a named region of the sprite. The region can change between frames.
+type Slice struct {
+	Name string
+	Keys []SliceKey
+}
+
+// SliceKey is the region of the slice starting from given frame.
+type SliceKey struct {
+	Frame  int
+	Bounds Rectangle
+	// Center is a center of the 9-patch slice relative to the bounds. Nil
+	// when slice is not 9-patch.
+	Center *Rectangle
+	// Pivot is a pivot point relative to the bounds. Nil when slice has no pivot.
+	Pivot *Point
+}
+
+// Rectangle is a position and size of a region.
+type Rectangle struct {
+	X, Y, Width, Height int
+}
+
+// Point is a position.
+type Point struct {
+	X, Y int
+}
+
+// NewDecoder creates a Decoder instance which can be used many times for
+// decoding Aseprite files. Images are created using ImageFactory.
+func NewDecoder(imageFactory decoder.ImageFactory) *Decoder {
+	if imageFactory == nil {
+		panic("nil imageFactory")
+	}
+	return &Decoder{imageFactory: imageFactory}
+}
+
+// Decoder decodes Aseprite files.
+type Decoder struct {
+	imageFactory decoder.ImageFactory
+}
+
+// Decode decodes Aseprite file. Frame images are created by flattening
+// all visible layers using their opacity and blend modes, approximately the
+// same way Aseprite does it.
+func (d *Decoder) Decode(reader io.Reader) (*File, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	sprite, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return d.newFile(sprite), nil
+}
+
+// DecodeFile decodes Aseprite file with given name.
+func (d *Decoder) DecodeFile(fileName string) (*File, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return d.Decode(file)
+}
+
+func (d *Decoder) newFile(s *sprite) *File {
+	file := &File{
+		Width:  s.width,
+		Height: s.height,
+		Layers: s.layers,
+		Tags:   s.tags,
+		Slices: s.slices,
+		Frames: make([]Frame, len(s.frames)),
+	}
+	celImages := map[*pixels]*image.Image{}
+	for i, f := range s.frames {
+		frame := Frame{
+			Duration: time.Duration(f.duration) * time.Millisecond,
+			Cels:     make([]Cel, 0, len(f.cels)),
+		}
+		for _, c := range f.cels {
+			img, ok := celImages[c.pixels]
+			if !ok {
+				img = d.newImage(c.pixels.width, c.pixels.height, c.pixels.colors)
+				celImages[c.pixels] = img
+			}
+			frame.Cels = append(frame.Cels, Cel{
+				Layer:   c.layer,
+				X:       c.x,
+				Y:       c.y,
+				Opacity: c.opacity,
+				Image:   img,
+			})
+		}
+		frame.Image = d.newImage(s.width, s.height, flatten(s, f))
+		file.Frames[i] = frame
+	}
+	return file
+}
+
+func (d *Decoder) newImage(width, height int, colors []nrgba) *image.Image {
+	img := d.imageFactory.NewImage(width, height)
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := colors[y*width+x]
+			selection.SetColor(x, y, image.NRGBA(c.r, c.g, c.b, c.a))
+		}
+	}
+	return img
+}
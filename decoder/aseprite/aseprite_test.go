@@ -0,0 +1,554 @@
+package aseprite_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/decoder/aseprite"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+)
+
+var (
+	red   = image.RGB(255, 0, 0)
+	green = image.RGB(0, 255, 0)
+	blue  = image.RGB(0, 0, 255)
+)
+
+func TestNewDecoder(t *testing.T) {
+	t.Run("should panic for nil ImageFactory", func(t *testing.T) {
+		assert.Panics(t, func() {
+			aseprite.NewDecoder(nil)
+		})
+	})
+	t.Run("should create decoder", func(t *testing.T) {
+		assert.NotNil(t, aseprite.NewDecoder(fakeImageFactory{}))
+	})
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		decoder := aseprite.NewDecoder(fakeImageFactory{})
+		assert.Panics(t, func() {
+			_, _ = decoder.Decode(nil)
+		})
+	})
+	t.Run("should return error", func(t *testing.T) {
+		validFile := newFile(1, 1, 32).frame(100, layerChunk("layer", 1, 0, 0, 0, 255), rawCelChunk(0, 0, 0, 255, 1, 1, []byte{1, 2, 3, 4}))
+		tests := map[string][]byte{
+			"empty":                 {},
+			"invalid magic number":  make([]byte, 128),
+			"truncated header":      validFile.bytes()[:100],
+			"truncated frame":       validFile.bytes()[:140],
+			"unsupported depth":     newFile(1, 1, 24).bytes(),
+			"invalid frame magic":   corrupt(validFile.bytes(), 132),
+			"too few pixels":        newFile(2, 1, 32).frame(100, rawCelChunk(0, 0, 0, 255, 2, 1, []byte{1, 2, 3, 4})).bytes(),
+			"invalid compression":   newFile(1, 1, 32).frame(100, celChunk(0, 0, 0, 255, 2, []byte{1, 0, 1, 0, 1, 2, 3})).bytes(),
+			"missing linked cel":    newFile(1, 1, 32).frame(100, linkedCelChunk(0, 0)).bytes(),
+			"invalid chunk size":    newFile(1, 1, 32).frame(100, chunk{typ: 0x2004, size: 2}).bytes(),
+			"truncated layer chunk": newFile(1, 1, 32).frame(100, chunk{typ: 0x2004, data: []byte{1}}).bytes(),
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				decoder := aseprite.NewDecoder(fakeImageFactory{})
+				// when
+				file, err := decoder.Decode(bytes.NewReader(data))
+				// then
+				assert.Error(t, err)
+				assert.Nil(t, file)
+			})
+		}
+	})
+	t.Run("should return error when reader returned error", func(t *testing.T) {
+		decoder := aseprite.NewDecoder(fakeImageFactory{})
+		file, err := decoder.Decode(erroneousReader{})
+		assert.Error(t, err)
+		assert.Nil(t, file)
+	})
+	t.Run("should decode frames", func(t *testing.T) {
+		data := newFile(2, 1, 32).
+			frame(100,
+				layerChunk("layer", 1, 0, 0, 0, 255),
+				rawCelChunk(0, 0, 0, 255, 2, 1, []byte{255, 0, 0, 255, 0, 255, 0, 255}),
+			).
+			frame(250,
+				compressedCelChunk(0, 1, 0, 255, 1, 1, []byte{0, 0, 255, 255}),
+			).
+			frame(50,
+				linkedCelChunk(0, 0),
+			).
+			bytes()
+		decoder := aseprite.NewDecoder(fakeImageFactory{})
+		// when
+		file, err := decoder.Decode(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, file.Width)
+		assert.Equal(t, 1, file.Height)
+		require.Len(t, file.Frames, 3)
+		assert.Equal(t, 100*time.Millisecond, file.Frames[0].Duration)
+		assert.Equal(t, 250*time.Millisecond, file.Frames[1].Duration)
+		assert.Equal(t, 50*time.Millisecond, file.Frames[2].Duration)
+		assertColors(t, []image.Color{red, green}, file.Frames[0].Image)
+		assertColors(t, []image.Color{image.Transparent, blue}, file.Frames[1].Image)
+		assertColors(t, []image.Color{red, green}, file.Frames[2].Image)
+		// and
+		require.Len(t, file.Frames[1].Cels, 1)
+		cel := file.Frames[1].Cels[0]
+		assert.Equal(t, 0, cel.Layer)
+		assert.Equal(t, 1, cel.X)
+		assert.Equal(t, 0, cel.Y)
+		assert.Equal(t, byte(255), cel.Opacity)
+		assertColors(t, []image.Color{blue}, cel.Image)
+		// and
+		assert.Same(t, file.Frames[0].Cels[0].Image, file.Frames[2].Cels[0].Image)
+	})
+	t.Run("should decode grayscale and indexed pixels", func(t *testing.T) {
+		tests := map[string]struct {
+			file     *file
+			expected []image.Color
+		}{
+			"grayscale": {
+				file: newFile(2, 1, 16).frame(100,
+					layerChunk("layer", 1, 0, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 255, 2, 1, []byte{100, 255, 200, 0}),
+				),
+				expected: []image.Color{image.RGB(100, 100, 100), image.Transparent},
+			},
+			"indexed": {
+				file: newFile(3, 1, 8).frame(100,
+					paletteChunk([]byte{0, 0, 0, 0}, []byte{255, 0, 0, 255}, []byte{0, 0, 255, 255}),
+					layerChunk("layer", 1, 0, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 255, 3, 1, []byte{2, 0, 1}),
+				),
+				expected: []image.Color{blue, image.Transparent, red},
+			},
+			"indexed with old palette": {
+				file: newFile(2, 1, 8).frame(100,
+					oldPaletteChunk([]byte{0, 0, 0}, []byte{0, 255, 0}),
+					layerChunk("layer", 1, 0, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 255, 2, 1, []byte{1, 0}),
+				),
+				expected: []image.Color{green, image.Transparent},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				decoder := aseprite.NewDecoder(fakeImageFactory{})
+				// when
+				file, err := decoder.Decode(bytes.NewReader(test.file.bytes()))
+				// then
+				require.NoError(t, err)
+				assertColors(t, test.expected, file.Frames[0].Image)
+			})
+		}
+	})
+	t.Run("should decode layers", func(t *testing.T) {
+		data := newFile(1, 1, 32).frame(100,
+			layerChunk("background", 1, 0, 0, 0, 255),
+			layerChunk("group", 1, 1, 0, 0, 255),
+			layerChunk("child", 0, 0, 1, 3, 128),
+			layerChunk("top", 1, 0, 0, 16, 100),
+		).bytes()
+		decoder := aseprite.NewDecoder(fakeImageFactory{})
+		// when
+		file, err := decoder.Decode(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []aseprite.Layer{
+			{Name: "background", Visible: true, Parent: -1, Opacity: 255, BlendMode: aseprite.Normal},
+			{Name: "group", Visible: true, Group: true, Parent: -1, Opacity: 255, BlendMode: aseprite.Normal},
+			{Name: "child", Visible: false, Parent: 1, Opacity: 128, BlendMode: aseprite.Overlay},
+			{Name: "top", Visible: true, Parent: -1, Opacity: 100, BlendMode: aseprite.Addition},
+		}, file.Layers)
+	})
+	t.Run("should ignore layer opacity when it is not valid", func(t *testing.T) {
+		f := newFile(1, 1, 32).frame(100, layerChunk("layer", 1, 0, 0, 0, 10))
+		f.flags = 0
+		decoder := aseprite.NewDecoder(fakeImageFactory{})
+		// when
+		file, err := decoder.Decode(bytes.NewReader(f.bytes()))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, byte(255), file.Layers[0].Opacity)
+	})
+	t.Run("should flatten layers", func(t *testing.T) {
+		half := image.RGBA(128, 0, 0, 128)
+		tests := map[string]struct {
+			file     *file
+			expected image.Color
+		}{
+			"top layer covers bottom": {
+				file: newFile(1, 1, 32).frame(100,
+					layerChunk("bottom", 1, 0, 0, 0, 255),
+					layerChunk("top", 1, 0, 0, 0, 255),
+					rawCelChunk(1, 0, 0, 255, 1, 1, []byte{0, 255, 0, 255}),
+					rawCelChunk(0, 0, 0, 255, 1, 1, []byte{255, 0, 0, 255}),
+				),
+				expected: green,
+			},
+			"invisible layer": {
+				file: newFile(1, 1, 32).frame(100,
+					layerChunk("bottom", 1, 0, 0, 0, 255),
+					layerChunk("top", 0, 0, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 255, 1, 1, []byte{255, 0, 0, 255}),
+					rawCelChunk(1, 0, 0, 255, 1, 1, []byte{0, 255, 0, 255}),
+				),
+				expected: red,
+			},
+			"layer in invisible group": {
+				file: newFile(1, 1, 32).frame(100,
+					layerChunk("group", 0, 1, 0, 0, 255),
+					layerChunk("child", 1, 0, 1, 0, 255),
+					rawCelChunk(1, 0, 0, 255, 1, 1, []byte{255, 0, 0, 255}),
+				),
+				expected: image.Transparent,
+			},
+			"layer opacity": {
+				file: newFile(1, 1, 32).frame(100,
+					layerChunk("layer", 1, 0, 0, 0, 128),
+					rawCelChunk(0, 0, 0, 255, 1, 1, []byte{255, 0, 0, 255}),
+				),
+				expected: half,
+			},
+			"cel opacity": {
+				file: newFile(1, 1, 32).frame(100,
+					layerChunk("layer", 1, 0, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 128, 1, 1, []byte{255, 0, 0, 255}),
+				),
+				expected: half,
+			},
+			"pixel alpha": {
+				file: newFile(1, 1, 32).frame(100,
+					layerChunk("layer", 1, 0, 0, 0, 255),
+					rawCelChunk(0, 0, 0, 255, 1, 1, []byte{255, 0, 0, 128}),
+				),
+				expected: half,
+			},
+			"multiply": {
+				file: newFile(1, 1, 32).frame(100,
+					layerChunk("bottom", 1, 0, 0, 0, 255),
+					layerChunk("top", 1, 0, 0, 1, 255),
+					rawCelChunk(0, 0, 0, 255, 1, 1, []byte{200, 100, 255, 255}),
+					rawCelChunk(1, 0, 0, 255, 1, 1, []byte{255, 51, 0, 255}),
+				),
+				expected: image.RGB(200, 20, 0),
+			},
+			"screen": {
+				file: newFile(1, 1, 32).frame(100,
+					layerChunk("bottom", 1, 0, 0, 0, 255),
+					layerChunk("top", 1, 0, 0, 2, 255),
+					rawCelChunk(0, 0, 0, 255, 1, 1, []byte{255, 0, 0, 255}),
+					rawCelChunk(1, 0, 0, 255, 1, 1, []byte{0, 0, 255, 255}),
+				),
+				expected: image.RGB(255, 0, 255),
+			},
+			"difference": {
+				file: newFile(1, 1, 32).frame(100,
+					layerChunk("bottom", 1, 0, 0, 0, 255),
+					layerChunk("top", 1, 0, 0, 10, 255),
+					rawCelChunk(0, 0, 0, 255, 1, 1, []byte{100, 200, 50, 255}),
+					rawCelChunk(1, 0, 0, 255, 1, 1, []byte{150, 100, 50, 255}),
+				),
+				expected: image.RGB(50, 100, 0),
+			},
+			"blend mode on transparent backdrop": {
+				file: newFile(1, 1, 32).frame(100,
+					layerChunk("top", 1, 0, 0, 1, 255),
+					rawCelChunk(0, 0, 0, 255, 1, 1, []byte{0, 0, 255, 255}),
+				),
+				expected: blue,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				decoder := aseprite.NewDecoder(fakeImageFactory{})
+				// when
+				file, err := decoder.Decode(bytes.NewReader(test.file.bytes()))
+				// then
+				require.NoError(t, err)
+				assertColors(t, []image.Color{test.expected}, file.Frames[0].Image)
+			})
+		}
+	})
+	t.Run("should clip cels to sprite", func(t *testing.T) {
+		data := newFile(1, 1, 32).frame(100,
+			layerChunk("layer", 1, 0, 0, 0, 255),
+			rawCelChunk(0, -1, -1, 255, 2, 2, []byte{
+				0, 0, 0, 255, 0, 0, 0, 255,
+				0, 0, 0, 255, 255, 0, 0, 255,
+			}),
+		).bytes()
+		decoder := aseprite.NewDecoder(fakeImageFactory{})
+		// when
+		file, err := decoder.Decode(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assertColors(t, []image.Color{red}, file.Frames[0].Image)
+	})
+	t.Run("should decode tags", func(t *testing.T) {
+		data := newFile(1, 1, 32).frame(100,
+			tagsChunk(
+				tag{name: "walk", from: 0, to: 3, direction: 0},
+				tag{name: "jump", from: 4, to: 6, direction: 2, repeat: 2},
+			),
+		).bytes()
+		decoder := aseprite.NewDecoder(fakeImageFactory{})
+		// when
+		file, err := decoder.Decode(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []aseprite.Tag{
+			{Name: "walk", From: 0, To: 3, Direction: aseprite.Forward},
+			{Name: "jump", From: 4, To: 6, Direction: aseprite.PingPong, Repeat: 2},
+		}, file.Tags)
+	})
+	t.Run("should decode slices", func(t *testing.T) {
+		data := newFile(1, 1, 32).frame(100,
+			sliceChunk("simple", 0, sliceKey{frame: 0, bounds: [4]int{1, 2, 3, 4}}),
+			sliceChunk("9patch with pivot", 3,
+				sliceKey{frame: 0, bounds: [4]int{-1, 0, 10, 10}, center: [4]int{2, 2, 6, 6}, pivot: [2]int{5, -5}},
+				sliceKey{frame: 2, bounds: [4]int{0, 0, 8, 8}, center: [4]int{1, 1, 6, 6}, pivot: [2]int{4, 4}},
+			),
+		).bytes()
+		decoder := aseprite.NewDecoder(fakeImageFactory{})
+		// when
+		file, err := decoder.Decode(bytes.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []aseprite.Slice{
+			{
+				Name: "simple",
+				Keys: []aseprite.SliceKey{
+					{Frame: 0, Bounds: aseprite.Rectangle{X: 1, Y: 2, Width: 3, Height: 4}},
+				},
+			},
+			{
+				Name: "9patch with pivot",
+				Keys: []aseprite.SliceKey{
+					{
+						Frame:  0,
+						Bounds: aseprite.Rectangle{X: -1, Y: 0, Width: 10, Height: 10},
+						Center: &aseprite.Rectangle{X: 2, Y: 2, Width: 6, Height: 6},
+						Pivot:  &aseprite.Point{X: 5, Y: -5},
+					},
+					{
+						Frame:  2,
+						Bounds: aseprite.Rectangle{X: 0, Y: 0, Width: 8, Height: 8},
+						Center: &aseprite.Rectangle{X: 1, Y: 1, Width: 6, Height: 6},
+						Pivot:  &aseprite.Point{X: 4, Y: 4},
+					},
+				},
+			},
+		}, file.Slices)
+	})
+}
+
+func TestDecoder_DecodeFile(t *testing.T) {
+	t.Run("should return error when file does not exist", func(t *testing.T) {
+		decoder := aseprite.NewDecoder(fakeImageFactory{})
+		file, err := decoder.DecodeFile("not-existing-file")
+		assert.Error(t, err)
+		assert.Nil(t, file)
+	})
+	t.Run("should decode file created by Aseprite", func(t *testing.T) {
+		decoder := aseprite.NewDecoder(fakeImageFactory{})
+		// when
+		file, err := decoder.DecodeFile("../../docs/pixiq-primitives.aseprite")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 156, file.Width)
+		assert.Equal(t, 120, file.Height)
+		require.Len(t, file.Layers, 5)
+		assert.Equal(t, "bg", file.Layers[0].Name)
+		assert.Equal(t, "selection fog", file.Layers[3].Name)
+		assert.Equal(t, byte(167), file.Layers[3].Opacity)
+		require.Len(t, file.Frames, 2)
+		assert.Equal(t, 500*time.Millisecond, file.Frames[0].Duration)
+		assert.Len(t, file.Frames[0].Cels, 5)
+	})
+}
+
+func assertColors(t *testing.T, expected []image.Color, img *image.Image) {
+	require.Equal(t, 1, img.Height())
+	require.Equal(t, len(expected), img.Width())
+	selection := img.WholeImageSelection()
+	for x, expectedColor := range expected {
+		assert.Equal(t, expectedColor, selection.Color(x, 0), "position(%d,0)", x)
+	}
+}
+
+type fakeImageFactory struct{}
+
+func (i fakeImageFactory) NewImage(width, height int) *image.Image {
+	return image.New(fake.NewAcceleratedImage(width, height))
+}
+
+type erroneousReader struct{}
+
+func (erroneousReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+// file is a builder of Aseprite files used in tests
+type file struct {
+	width, height, depth int
+	flags                uint32
+	frames               [][]chunk
+	durations            []int
+}
+
+type chunk struct {
+	typ  int
+	data []byte
+	// size overrides the size of the chunk when not 0
+	size int
+}
+
+func newFile(width, height, depth int) *file {
+	return &file{width: width, height: height, depth: depth, flags: 1}
+}
+
+func (f *file) frame(duration int, chunks ...chunk) *file {
+	f.frames = append(f.frames, chunks)
+	f.durations = append(f.durations, duration)
+	return f
+}
+
+func (f *file) bytes() []byte {
+	body := &bytes.Buffer{}
+	for i, chunks := range f.frames {
+		frame := &bytes.Buffer{}
+		for _, c := range chunks {
+			size := c.size
+			if size == 0 {
+				size = len(c.data) + 6
+			}
+			write(frame, uint32(size), uint16(c.typ), c.data)
+		}
+		write(body,
+			uint32(frame.Len()+16), uint16(0xF1FA), uint16(len(chunks)),
+			uint16(f.durations[i]), uint16(0), uint32(len(chunks)),
+			frame.Bytes())
+	}
+	header := &bytes.Buffer{}
+	write(header,
+		uint32(128+body.Len()), uint16(0xA5E0), uint16(len(f.frames)),
+		uint16(f.width), uint16(f.height), uint16(f.depth), f.flags,
+		uint16(100), uint32(0), uint32(0), byte(0), make([]byte, 3), uint16(0),
+		byte(1), byte(1), int16(0), int16(0), uint16(16), uint16(16), make([]byte, 84))
+	return append(header.Bytes(), body.Bytes()...)
+}
+
+func write(buffer *bytes.Buffer, values ...interface{}) {
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			_ = binary.Write(buffer, binary.LittleEndian, uint16(len(s)))
+			buffer.WriteString(s)
+			continue
+		}
+		_ = binary.Write(buffer, binary.LittleEndian, v)
+	}
+}
+
+func corrupt(data []byte, position int) []byte {
+	data[position] = 0
+	return data
+}
+
+func layerChunk(name string, flags, layerType, childLevel, blendMode int, opacity byte) chunk {
+	data := &bytes.Buffer{}
+	write(data, uint16(flags), uint16(layerType), uint16(childLevel), uint16(0), uint16(0),
+		uint16(blendMode), opacity, make([]byte, 3), name)
+	return chunk{typ: 0x2004, data: data.Bytes()}
+}
+
+func celChunk(layer, x, y int, opacity byte, celType int, rest []byte) chunk {
+	data := &bytes.Buffer{}
+	write(data, uint16(layer), int16(x), int16(y), opacity, uint16(celType), make([]byte, 7), rest)
+	return chunk{typ: 0x2005, data: data.Bytes()}
+}
+
+func rawCelChunk(layer, x, y int, opacity byte, width, height int, pixels []byte) chunk {
+	rest := &bytes.Buffer{}
+	write(rest, uint16(width), uint16(height), pixels)
+	return celChunk(layer, x, y, opacity, 0, rest.Bytes())
+}
+
+func compressedCelChunk(layer, x, y int, opacity byte, width, height int, pixels []byte) chunk {
+	compressed := &bytes.Buffer{}
+	writer := zlib.NewWriter(compressed)
+	_, _ = writer.Write(pixels)
+	_ = writer.Close()
+	rest := &bytes.Buffer{}
+	write(rest, uint16(width), uint16(height), compressed.Bytes())
+	return celChunk(layer, x, y, opacity, 2, rest.Bytes())
+}
+
+func linkedCelChunk(layer, frame int) chunk {
+	rest := &bytes.Buffer{}
+	write(rest, uint16(frame))
+	return celChunk(layer, 0, 0, 255, 1, rest.Bytes())
+}
+
+func paletteChunk(colors ...[]byte) chunk {
+	data := &bytes.Buffer{}
+	write(data, uint32(len(colors)), uint32(0), uint32(len(colors)-1), make([]byte, 8))
+	for _, c := range colors {
+		write(data, uint16(0), c)
+	}
+	return chunk{typ: 0x2019, data: data.Bytes()}
+}
+
+func oldPaletteChunk(colors ...[]byte) chunk {
+	data := &bytes.Buffer{}
+	write(data, uint16(1), byte(0), byte(len(colors)))
+	for _, c := range colors {
+		write(data, c)
+	}
+	return chunk{typ: 0x0004, data: data.Bytes()}
+}
+
+type tag struct {
+	name             string
+	from, to, repeat int
+	direction        byte
+}
+
+func tagsChunk(tags ...tag) chunk {
+	data := &bytes.Buffer{}
+	write(data, uint16(len(tags)), make([]byte, 8))
+	for _, t := range tags {
+		write(data, uint16(t.from), uint16(t.to), t.direction, uint16(t.repeat), make([]byte, 10), t.name)
+	}
+	return chunk{typ: 0x2018, data: data.Bytes()}
+}
+
+type sliceKey struct {
+	frame  int
+	bounds [4]int
+	center [4]int
+	pivot  [2]int
+}
+
+func sliceChunk(name string, flags int, keys ...sliceKey) chunk {
+	data := &bytes.Buffer{}
+	write(data, uint32(len(keys)), uint32(flags), uint32(0), name)
+	for _, k := range keys {
+		write(data, uint32(k.frame), int32(k.bounds[0]), int32(k.bounds[1]), uint32(k.bounds[2]), uint32(k.bounds[3]))
+		if flags&1 != 0 {
+			write(data, int32(k.center[0]), int32(k.center[1]), uint32(k.center[2]), uint32(k.center[3]))
+		}
+		if flags&2 != 0 {
+			write(data, int32(k.pivot[0]), int32(k.pivot[1]))
+		}
+	}
+	return chunk{typ: 0x2022, data: data.Bytes()}
+}
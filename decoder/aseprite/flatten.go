@@ -0,0 +1,261 @@
+package aseprite
+
+import (
+	"math"
+)
+
+// flatten composes all visible layers of the frame into a single image
+func flatten(s *sprite, f frame) []nrgba {
+	canvas := make([]rgbaf, s.width*s.height)
+	for layer := range s.layers {
+		if !s.visible(layer) || s.layers[layer].Group {
+			continue
+		}
+		for _, c := range f.cels {
+			if c.layer == layer {
+				drawCel(canvas, s.width, s.height, c, s.layers[layer])
+			}
+		}
+	}
+	colors := make([]nrgba, len(canvas))
+	for i, c := range canvas {
+		colors[i] = c.nrgba()
+	}
+	return colors
+}
+
+// visible returns true when layer and all its parents are visible
+func (s *sprite) visible(layer int) bool {
+	for ; layer >= 0; layer = s.layers[layer].Parent {
+		if !s.layers[layer].Visible {
+			return false
+		}
+	}
+	return true
+}
+
+// rgbaf is a color not premultiplied by alpha with components in range [0,1]
+type rgbaf struct {
+	r, g, b, a float64
+}
+
+func (c rgbaf) nrgba() nrgba {
+	return nrgba{
+		r: toByte(c.r),
+		g: toByte(c.g),
+		b: toByte(c.b),
+		a: toByte(c.a),
+	}
+}
+
+func toByte(v float64) byte {
+	return byte(math.Round(clamp(v) * 255))
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func drawCel(canvas []rgbaf, width, height int, c cel, layer Layer) {
+	opacity := float64(c.opacity) / 255 * float64(layer.Opacity) / 255
+	blend := blendFunctions[layer.BlendMode]
+	if blend == nil {
+		blend = blendFunctions[Normal]
+	}
+	for y := 0; y < c.pixels.height; y++ {
+		canvasY := c.y + y
+		if canvasY < 0 || canvasY >= height {
+			continue
+		}
+		for x := 0; x < c.pixels.width; x++ {
+			canvasX := c.x + x
+			if canvasX < 0 || canvasX >= width {
+				continue
+			}
+			p := c.pixels.colors[y*c.pixels.width+x]
+			if p.a == 0 {
+				continue
+			}
+			source := rgbaf{
+				r: float64(p.r) / 255,
+				g: float64(p.g) / 255,
+				b: float64(p.b) / 255,
+				a: float64(p.a) / 255 * opacity,
+			}
+			i := canvasY*width + canvasX
+			canvas[i] = composite(canvas[i], source, blend)
+		}
+	}
+}
+
+// composite blends source with backdrop and composes the result using
+// source-over as described in W3C Compositing and Blending specification.
+func composite(backdrop, source rgbaf, blend blendFunction) rgbaf {
+	outA := source.a + backdrop.a*(1-source.a)
+	if outA == 0 {
+		return rgbaf{}
+	}
+	blended := blend(backdrop, source)
+	mix := func(b, s, blended float64) float64 {
+		s = (1-backdrop.a)*s + backdrop.a*blended
+		return (source.a*s + backdrop.a*(1-source.a)*b) / outA
+	}
+	return rgbaf{
+		r: mix(backdrop.r, source.r, blended.r),
+		g: mix(backdrop.g, source.g, blended.g),
+		b: mix(backdrop.b, source.b, blended.b),
+		a: outA,
+	}
+}
+
+// blendFunction returns blended RGB components of backdrop and source
+type blendFunction func(backdrop, source rgbaf) rgbaf
+
+func separable(f func(b, s float64) float64) blendFunction {
+	return func(b, s rgbaf) rgbaf {
+		return rgbaf{r: f(b.r, s.r), g: f(b.g, s.g), b: f(b.b, s.b)}
+	}
+}
+
+var blendFunctions = map[BlendMode]blendFunction{
+	Normal:   separable(func(b, s float64) float64 { return s }),
+	Multiply: separable(multiply),
+	Screen:   separable(screen),
+	Overlay: separable(func(b, s float64) float64 {
+		return hardLight(s, b)
+	}),
+	Darken:  separable(math.Min),
+	Lighten: separable(math.Max),
+	ColorDodge: separable(func(b, s float64) float64 {
+		if b == 0 {
+			return 0
+		}
+		if s >= 1 {
+			return 1
+		}
+		return math.Min(1, b/(1-s))
+	}),
+	ColorBurn: separable(func(b, s float64) float64 {
+		if b >= 1 {
+			return 1
+		}
+		if s <= 0 {
+			return 0
+		}
+		return 1 - math.Min(1, (1-b)/s)
+	}),
+	HardLight: separable(hardLight),
+	SoftLight: separable(func(b, s float64) float64 {
+		if s <= 0.5 {
+			return b - (1-2*s)*b*(1-b)
+		}
+		d := math.Sqrt(b)
+		if b <= 0.25 {
+			d = ((16*b-12)*b + 4) * b
+		}
+		return b + (2*s-1)*(d-b)
+	}),
+	Difference: separable(func(b, s float64) float64 {
+		return math.Abs(b - s)
+	}),
+	Exclusion: separable(func(b, s float64) float64 {
+		return b + s - 2*b*s
+	}),
+	Hue: func(b, s rgbaf) rgbaf {
+		return setLum(setSat(s, sat(b)), lum(b))
+	},
+	Saturation: func(b, s rgbaf) rgbaf {
+		return setLum(setSat(b, sat(s)), lum(b))
+	},
+	Color: func(b, s rgbaf) rgbaf {
+		return setLum(s, lum(b))
+	},
+	Luminosity: func(b, s rgbaf) rgbaf {
+		return setLum(b, lum(s))
+	},
+	Addition: separable(func(b, s float64) float64 {
+		return math.Min(1, b+s)
+	}),
+	Subtract: separable(func(b, s float64) float64 {
+		return math.Max(0, b-s)
+	}),
+	Divide: separable(func(b, s float64) float64 {
+		if b == 0 {
+			return 0
+		}
+		if s == 0 {
+			return 1
+		}
+		return math.Min(1, b/s)
+	}),
+}
+
+func multiply(b, s float64) float64 {
+	return b * s
+}
+
+func screen(b, s float64) float64 {
+	return b + s - b*s
+}
+
+func hardLight(b, s float64) float64 {
+	if s <= 0.5 {
+		return multiply(b, 2*s)
+	}
+	return screen(b, 2*s-1)
+}
+
+func lum(c rgbaf) float64 {
+	return 0.3*c.r + 0.59*c.g + 0.11*c.b
+}
+
+func setLum(c rgbaf, l float64) rgbaf {
+	d := l - lum(c)
+	c.r += d
+	c.g += d
+	c.b += d
+	return clipColor(c)
+}
+
+func clipColor(c rgbaf) rgbaf {
+	l := lum(c)
+	n := math.Min(c.r, math.Min(c.g, c.b))
+	x := math.Max(c.r, math.Max(c.g, c.b))
+	if n < 0 {
+		c.r = l + (c.r-l)*l/(l-n)
+		c.g = l + (c.g-l)*l/(l-n)
+		c.b = l + (c.b-l)*l/(l-n)
+	}
+	if x > 1 {
+		c.r = l + (c.r-l)*(1-l)/(x-l)
+		c.g = l + (c.g-l)*(1-l)/(x-l)
+		c.b = l + (c.b-l)*(1-l)/(x-l)
+	}
+	return c
+}
+
+func sat(c rgbaf) float64 {
+	return math.Max(c.r, math.Max(c.g, c.b)) - math.Min(c.r, math.Min(c.g, c.b))
+}
+
+func setSat(c rgbaf, s float64) rgbaf {
+	components := []*float64{&c.r, &c.g, &c.b}
+	// sort pointers by value: min, mid, max
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2-i; j++ {
+			if *components[j] > *components[j+1] {
+				components[j], components[j+1] = components[j+1], components[j]
+			}
+		}
+	}
+	min, mid, max := components[0], components[1], components[2]
+	if *max > *min {
+		*mid = (*mid - *min) * s / (*max - *min)
+		*max = s
+	} else {
+		*mid = 0
+		*max = 0
+	}
+	*min = 0
+	return c
+}
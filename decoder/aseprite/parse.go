@@ -0,0 +1,425 @@
+package aseprite
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+const (
+	headerMagic = 0xA5E0
+	frameMagic  = 0xF1FA
+
+	oldPaletteChunk = 0x0004
+	layerChunk      = 0x2004
+	celChunk        = 0x2005
+	tagsChunk       = 0x2018
+	paletteChunk    = 0x2019
+	sliceChunk      = 0x2022
+
+	rawCel            = 0
+	linkedCel         = 1
+	compressedCel     = 2
+	layerOpacityValid = 1
+)
+
+// nrgba is a color not premultiplied by alpha, used by Aseprite
+type nrgba struct {
+	r, g, b, a byte
+}
+
+type pixels struct {
+	width, height int
+	colors        []nrgba
+}
+
+type cel struct {
+	layer   int
+	x, y    int
+	opacity byte
+	pixels  *pixels
+}
+
+type frame struct {
+	duration int
+	cels     []cel
+}
+
+type sprite struct {
+	width, height    int
+	depth            int
+	flags            uint32
+	transparentIndex byte
+	palette          []nrgba
+	layers           []Layer
+	frames           []frame
+	tags             []Tag
+	slices           []Slice
+}
+
+// reader reads little-endian values. After the first error all methods return
+// zero values.
+type reader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.pos+n > len(r.data) {
+		r.err = errors.New("unexpected end of data")
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *reader) byte() byte {
+	b := r.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *reader) word() int {
+	b := r.next(2)
+	if b == nil {
+		return 0
+	}
+	return int(binary.LittleEndian.Uint16(b))
+}
+
+func (r *reader) short() int {
+	return int(int16(r.word()))
+}
+
+func (r *reader) dword() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (r *reader) long() int {
+	return int(int32(r.dword()))
+}
+
+func (r *reader) string() string {
+	return string(r.next(r.word()))
+}
+
+func (r *reader) skip(n int) {
+	r.next(n)
+}
+
+func parse(data []byte) (*sprite, error) {
+	r := &reader{data: data}
+	r.skip(4) // file size
+	if r.word() != headerMagic && r.err == nil {
+		return nil, errors.New("not an Aseprite file: invalid magic number")
+	}
+	s := &sprite{}
+	frames := r.word()
+	s.width = r.word()
+	s.height = r.word()
+	s.depth = r.word()
+	s.flags = r.dword()
+	r.skip(2 + 4 + 4) // speed and reserved
+	s.transparentIndex = r.byte()
+	r.skip(3 + 2 + 1 + 1 + 2 + 2 + 2 + 2 + 84)
+	if r.err != nil {
+		return nil, r.err
+	}
+	if s.depth != 32 && s.depth != 16 && s.depth != 8 {
+		return nil, fmt.Errorf("unsupported color depth %d", s.depth)
+	}
+	for i := 0; i < frames; i++ {
+		if err := s.parseFrame(r); err != nil {
+			return nil, fmt.Errorf("frame %d: %s", i, err)
+		}
+	}
+	return s, nil
+}
+
+func (s *sprite) parseFrame(r *reader) error {
+	start := r.pos
+	size := int(r.dword())
+	if r.word() != frameMagic && r.err == nil {
+		return errors.New("invalid frame magic number")
+	}
+	oldChunks := r.word()
+	f := frame{duration: r.word()}
+	r.skip(2)
+	chunks := int(r.dword())
+	if chunks == 0 {
+		chunks = oldChunks
+	}
+	if r.err != nil {
+		return r.err
+	}
+	for i := 0; i < chunks; i++ {
+		chunkStart := r.pos
+		chunkSize := int(r.dword())
+		chunkType := r.word()
+		data := r.next(chunkSize - 6)
+		if r.err != nil {
+			return r.err
+		}
+		if err := s.parseChunk(&f, chunkType, &reader{data: data}); err != nil {
+			return fmt.Errorf("chunk 0x%04x at %d: %s", chunkType, chunkStart, err)
+		}
+	}
+	r.pos = start + size
+	s.frames = append(s.frames, f)
+	return nil
+}
+
+func (s *sprite) parseChunk(f *frame, chunkType int, r *reader) error {
+	switch chunkType {
+	case oldPaletteChunk:
+		if s.palette == nil {
+			s.parseOldPalette(r)
+		}
+	case paletteChunk:
+		s.parsePalette(r)
+	case layerChunk:
+		s.parseLayer(r)
+	case celChunk:
+		return s.parseCel(f, r)
+	case tagsChunk:
+		s.parseTags(r)
+	case sliceChunk:
+		s.parseSlice(r)
+	}
+	return r.err
+}
+
+func (s *sprite) parseOldPalette(r *reader) {
+	packets := r.word()
+	index := 0
+	for i := 0; i < packets && r.err == nil; i++ {
+		index += int(r.byte())
+		colors := int(r.byte())
+		if colors == 0 {
+			colors = 256
+		}
+		for j := 0; j < colors; j++ {
+			s.setPaletteColor(index, nrgba{r: r.byte(), g: r.byte(), b: r.byte(), a: 255})
+			index++
+		}
+	}
+}
+
+func (s *sprite) parsePalette(r *reader) {
+	r.skip(4) // new palette size
+	first := int(r.dword())
+	last := int(r.dword())
+	r.skip(8)
+	for i := first; i <= last && r.err == nil; i++ {
+		flags := r.word()
+		s.setPaletteColor(i, nrgba{r: r.byte(), g: r.byte(), b: r.byte(), a: r.byte()})
+		if flags&1 != 0 {
+			r.string() // color name
+		}
+	}
+}
+
+func (s *sprite) setPaletteColor(index int, color nrgba) {
+	if index >= 256 {
+		return
+	}
+	for len(s.palette) <= index {
+		s.palette = append(s.palette, nrgba{})
+	}
+	s.palette[index] = color
+}
+
+func (s *sprite) parseLayer(r *reader) {
+	flags := r.word()
+	layerType := r.word()
+	childLevel := r.word()
+	r.skip(4) // default width and height
+	blendMode := r.word()
+	opacity := r.byte()
+	r.skip(3)
+	name := r.string()
+	if s.flags&layerOpacityValid == 0 {
+		opacity = 255
+	}
+	s.layers = append(s.layers, Layer{
+		Name:      name,
+		Visible:   flags&1 != 0,
+		Group:     layerType == 1,
+		Parent:    s.parentOfNextLayer(childLevel),
+		Opacity:   opacity,
+		BlendMode: BlendMode(blendMode),
+	})
+}
+
+// parentOfNextLayer returns the index of last group layer with childLevel-1
+func (s *sprite) parentOfNextLayer(childLevel int) int {
+	if childLevel == 0 {
+		return -1
+	}
+	for i := len(s.layers) - 1; i >= 0; i-- {
+		if s.layers[i].Group && s.childLevel(i) == childLevel-1 {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *sprite) childLevel(layer int) int {
+	level := 0
+	for parent := s.layers[layer].Parent; parent >= 0; parent = s.layers[parent].Parent {
+		level++
+	}
+	return level
+}
+
+func (s *sprite) parseCel(f *frame, r *reader) error {
+	c := cel{
+		layer:   r.word(),
+		x:       r.short(),
+		y:       r.short(),
+		opacity: r.byte(),
+	}
+	celType := r.word()
+	r.skip(7) // z-index and reserved
+	switch celType {
+	case rawCel, compressedCel:
+		width, height := r.word(), r.word()
+		data := r.next(len(r.data) - r.pos)
+		if r.err != nil {
+			return r.err
+		}
+		if celType == compressedCel {
+			var err error
+			data, err = decompress(data)
+			if err != nil {
+				return err
+			}
+		}
+		colors, err := s.colors(data, width*height)
+		if err != nil {
+			return err
+		}
+		c.pixels = &pixels{width: width, height: height, colors: colors}
+	case linkedCel:
+		linkedFrame := r.word()
+		if r.err != nil {
+			return r.err
+		}
+		linked, ok := s.findCel(linkedFrame, c.layer)
+		if !ok {
+			return fmt.Errorf("linked cel in frame %d not found", linkedFrame)
+		}
+		c = linked
+	default:
+		// tilemaps are not supported
+		return nil
+	}
+	f.cels = append(f.cels, c)
+	return nil
+}
+
+func (s *sprite) findCel(frame, layer int) (cel, bool) {
+	if frame >= len(s.frames) {
+		return cel{}, false
+	}
+	for _, c := range s.frames[frame].cels {
+		if c.layer == layer {
+			return c, true
+		}
+	}
+	return cel{}, false
+}
+
+func decompress(data []byte) ([]byte, error) {
+	zlibReader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zlibReader.Close()
+	return ioutil.ReadAll(zlibReader)
+}
+
+func (s *sprite) colors(data []byte, count int) ([]nrgba, error) {
+	bytesPerPixel := s.depth / 8
+	if len(data) < count*bytesPerPixel {
+		return nil, errors.New("too few pixels")
+	}
+	colors := make([]nrgba, count)
+	for i := range colors {
+		switch s.depth {
+		case 32:
+			p := data[i*4:]
+			colors[i] = nrgba{r: p[0], g: p[1], b: p[2], a: p[3]}
+		case 16:
+			v, a := data[i*2], data[i*2+1]
+			colors[i] = nrgba{r: v, g: v, b: v, a: a}
+		case 8:
+			index := data[i]
+			if index != s.transparentIndex && int(index) < len(s.palette) {
+				colors[i] = s.palette[index]
+			}
+		}
+	}
+	return colors, nil
+}
+
+func (s *sprite) parseTags(r *reader) {
+	count := r.word()
+	r.skip(8)
+	for i := 0; i < count && r.err == nil; i++ {
+		tag := Tag{
+			From:      r.word(),
+			To:        r.word(),
+			Direction: Direction(r.byte()),
+			Repeat:    r.word(),
+		}
+		r.skip(6 + 3 + 1) // reserved, deprecated color and extra byte
+		tag.Name = r.string()
+		s.tags = append(s.tags, tag)
+	}
+}
+
+func (s *sprite) parseSlice(r *reader) {
+	keys := int(r.dword())
+	flags := r.dword()
+	r.skip(4)
+	slice := Slice{Name: r.string()}
+	for i := 0; i < keys && r.err == nil; i++ {
+		key := SliceKey{
+			Frame: int(r.dword()),
+			Bounds: Rectangle{
+				X:      r.long(),
+				Y:      r.long(),
+				Width:  int(r.dword()),
+				Height: int(r.dword()),
+			},
+		}
+		if flags&1 != 0 {
+			key.Center = &Rectangle{
+				X:      r.long(),
+				Y:      r.long(),
+				Width:  int(r.dword()),
+				Height: int(r.dword()),
+			}
+		}
+		if flags&2 != 0 {
+			key.Pivot = &Point{X: r.long(), Y: r.long()}
+		}
+		slice.Keys = append(slice.Keys, key)
+	}
+	s.slices = append(s.slices, slice)
+}
@@ -0,0 +1,150 @@
+package dither
+
+import (
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/palette"
+)
+
+// Kernel describes how quantization error is distributed to neighbouring
+// pixels. Each Cell receives Weight/Divisor of the error.
+type Kernel struct {
+	Cells   []Cell
+	Divisor int
+}
+
+// Cell is a neighbouring pixel receiving part of the quantization error.
+// X and Y are relative to the current pixel. Pixels are processed from left
+// to right, from top to bottom, therefore Y must not be negative and X must be
+// positive when Y is 0.
+type Cell struct {
+	X, Y, Weight int
+}
+
+// FloydSteinberg kernel. x is the current pixel:
+//
+//	   x  7
+//	3  5  1    (1/16)
+var FloydSteinberg = Kernel{
+	Cells: []Cell{
+		{X: 1, Weight: 7},
+		{X: -1, Y: 1, Weight: 3}, {Y: 1, Weight: 5}, {X: 1, Y: 1, Weight: 1},
+	},
+	Divisor: 16,
+}
+
+// Atkinson kernel. Only 3/4 of the error is distributed which gives more
+// contrast:
+//
+//	   x  1  1
+//	1  1  1        (1/8)
+//	   1
+var Atkinson = Kernel{
+	Cells: []Cell{
+		{X: 1, Weight: 1}, {X: 2, Weight: 1},
+		{X: -1, Y: 1, Weight: 1}, {Y: 1, Weight: 1}, {X: 1, Y: 1, Weight: 1},
+		{Y: 2, Weight: 1},
+	},
+	Divisor: 8,
+}
+
+// Sierra kernel (aka Sierra-3). x is the current pixel:
+//
+//	      x  5  3
+//	2  4  5  4  2    (1/32)
+//	   2  3  2
+var Sierra = Kernel{
+	Cells: []Cell{
+		{X: 1, Weight: 5}, {X: 2, Weight: 3},
+		{X: -2, Y: 1, Weight: 2}, {X: -1, Y: 1, Weight: 4}, {Y: 1, Weight: 5}, {X: 1, Y: 1, Weight: 4}, {X: 2, Y: 1, Weight: 2},
+		{X: -1, Y: 2, Weight: 2}, {Y: 2, Weight: 3}, {X: 1, Y: 2, Weight: 2},
+	},
+	Divisor: 32,
+}
+
+// NewErrorDiffusion creates a new error diffusion dithering tool. Will panic
+// when palette is empty or kernel is invalid.
+func NewErrorDiffusion(pal palette.Palette, kernel Kernel) *ErrorDiffusion {
+	if len(pal) == 0 {
+		panic("empty palette")
+	}
+	if kernel.Divisor <= 0 {
+		panic("kernel divisor must be positive")
+	}
+	if len(kernel.Cells) == 0 {
+		panic("empty kernel")
+	}
+	maxY := 0
+	for _, cell := range kernel.Cells {
+		if cell.Y < 0 || (cell.Y == 0 && cell.X <= 0) {
+			panic("kernel cell points to already processed pixel")
+		}
+		if cell.Y > maxY {
+			maxY = cell.Y
+		}
+	}
+	cells := make([]Cell, len(kernel.Cells))
+	copy(cells, kernel.Cells)
+	return &ErrorDiffusion{
+		palette: pal,
+		kernel:  Kernel{Cells: cells, Divisor: kernel.Divisor},
+		rows:    maxY + 1,
+	}
+}
+
+// ErrorDiffusion is a dithering tool which distributes the quantization error
+// (difference between original color and palette color) to neighbouring
+// pixels which are not processed yet.
+type ErrorDiffusion struct {
+	palette palette.Palette
+	kernel  Kernel
+	// rows is a number of rows covered by kernel, including the current one
+	rows int
+}
+
+// colorError is a quantization error of RGB components (premultiplied by alpha)
+type colorError struct {
+	r, g, b int
+}
+
+// Dither replaces colors of the selection with palette colors. Only pixels
+// inside the selection receive the quantization error.
+func (d *ErrorDiffusion) Dither(selection image.Selection) {
+	lines := selection.Lines()
+	if lines.Length() == 0 {
+		return
+	}
+	width := len(lines.LineForRead(0))
+	// errors of rows from current to current+rows-1, used as a ring buffer
+	errors := make([][]colorError, d.rows)
+	for i := range errors {
+		errors[i] = make([]colorError, width)
+	}
+	divisor := d.kernel.Divisor
+	for y := 0; y < lines.Length(); y++ {
+		line := lines.LineForWrite(y)
+		currentErrors := errors[y%d.rows]
+		for x, color := range line {
+			r, g, b, a := color.RGBAi()
+			e := currentErrors[x]
+			adjusted := image.RGBAi(r+e.r/divisor, g+e.g/divisor, b+e.b/divisor, a)
+			nearest := d.palette.NearestColor(adjusted)
+			line[x] = nearest
+			ar, ag, ab, _ := adjusted.RGBAi()
+			nr, ng, nb, _ := nearest.RGBAi()
+			diff := colorError{r: ar - nr, g: ag - ng, b: ab - nb}
+			for _, cell := range d.kernel.Cells {
+				cellX := x + cell.X
+				if cellX < 0 || cellX >= width {
+					continue
+				}
+				target := &errors[(y+cell.Y)%d.rows][cellX]
+				target.r += diff.r * cell.Weight
+				target.g += diff.g * cell.Weight
+				target.b += diff.b * cell.Weight
+			}
+		}
+		for x := range currentErrors {
+			currentErrors[x] = colorError{}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package dither_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/dither"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/palette"
+)
+
+var kernels = map[string]dither.Kernel{
+	"FloydSteinberg": dither.FloydSteinberg,
+	"Atkinson":       dither.Atkinson,
+	"Sierra":         dither.Sierra,
+}
+
+func TestNewErrorDiffusion(t *testing.T) {
+	t.Run("should panic", func(t *testing.T) {
+		tests := map[string]struct {
+			palette palette.Palette
+			kernel  dither.Kernel
+		}{
+			"empty palette": {kernel: dither.FloydSteinberg},
+			"zero divisor": {
+				palette: blackWhite,
+				kernel:  dither.Kernel{Cells: []dither.Cell{{X: 1, Weight: 1}}},
+			},
+			"negative divisor": {
+				palette: blackWhite,
+				kernel:  dither.Kernel{Cells: []dither.Cell{{X: 1, Weight: 1}}, Divisor: -1},
+			},
+			"empty kernel": {
+				palette: blackWhite,
+				kernel:  dither.Kernel{Divisor: 1},
+			},
+			"current pixel": {
+				palette: blackWhite,
+				kernel:  dither.Kernel{Cells: []dither.Cell{{Weight: 1}}, Divisor: 1},
+			},
+			"previous pixel": {
+				palette: blackWhite,
+				kernel:  dither.Kernel{Cells: []dither.Cell{{X: -1, Weight: 1}}, Divisor: 1},
+			},
+			"previous row": {
+				palette: blackWhite,
+				kernel:  dither.Kernel{Cells: []dither.Cell{{X: 1, Y: -1, Weight: 1}}, Divisor: 1},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				assert.Panics(t, func() {
+					dither.NewErrorDiffusion(test.palette, test.kernel)
+				})
+			})
+		}
+	})
+}
+
+func TestErrorDiffusion_Dither(t *testing.T) {
+	t.Run("should not change colors from palette", func(t *testing.T) {
+		for name, kernel := range kernels {
+			t.Run(name, func(t *testing.T) {
+				img := newFilledImage(3, 3, white)
+				img.WholeImageSelection().SetColor(1, 1, black)
+				tool := dither.NewErrorDiffusion(blackWhite, kernel)
+				// when
+				tool.Dither(img.WholeImageSelection())
+				// then
+				assertColors(t, img, [][]image.Color{
+					{white, white, white},
+					{white, black, white},
+					{white, white, white},
+				})
+			})
+		}
+	})
+
+	t.Run("should diffuse error to next pixels", func(t *testing.T) {
+		img := newFilledImage(4, 1, gray)
+		tool := dither.NewErrorDiffusion(blackWhite, dither.FloydSteinberg)
+		// when
+		tool.Dither(img.WholeImageSelection())
+		// then
+		assertColors(t, img, [][]image.Color{
+			{white, black, white, black},
+		})
+	})
+
+	t.Run("should preserve average brightness", func(t *testing.T) {
+		for name, kernel := range kernels {
+			t.Run(name, func(t *testing.T) {
+				img := newFilledImage(16, 16, gray)
+				tool := dither.NewErrorDiffusion(blackWhite, kernel)
+				// when
+				tool.Dither(img.WholeImageSelection())
+				// then
+				whites := 0
+				selection := img.WholeImageSelection()
+				for y := 0; y < 16; y++ {
+					for x := 0; x < 16; x++ {
+						if selection.Color(x, y) == white {
+							whites++
+						}
+					}
+				}
+				assert.InDelta(t, 128, whites, 24)
+			})
+		}
+	})
+
+	t.Run("should dither only pixels inside selection", func(t *testing.T) {
+		img := newFilledImage(3, 2, gray)
+		tool := dither.NewErrorDiffusion(blackWhite, dither.FloydSteinberg)
+		// when
+		tool.Dither(img.Selection(1, 0).WithSize(1, 1))
+		// then
+		assertColors(t, img, [][]image.Color{
+			{gray, white, gray},
+			{gray, gray, gray},
+		})
+	})
+
+	t.Run("should not modify kernel passed to constructor", func(t *testing.T) {
+		kernel := dither.Kernel{Cells: []dither.Cell{{X: 1, Weight: 1}}, Divisor: 1}
+		tool := dither.NewErrorDiffusion(blackWhite, kernel)
+		kernel.Cells[0].X = -1
+		img := newFilledImage(2, 1, gray)
+		// when
+		tool.Dither(img.WholeImageSelection())
+		// then
+		assertColors(t, img, [][]image.Color{
+			{white, black},
+		})
+	})
+}
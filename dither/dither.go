@@ -0,0 +1,138 @@
+// Package dither provides tools reducing colors of the image to a given
+// palette. Dithering creates an illusion of more colors by mixing available
+// palette colors in a pattern:
+//
+//	tool := dither.NewOrdered(palette, 4)
+//	tool.Dither(selection)
+//
+// Ordered dithering uses a Bayer threshold matrix and gives a regular,
+// retro-looking pattern. Error diffusion (see NewErrorDiffusion) spreads
+// the quantization error to neighbouring pixels and gives more natural results
+// for photos.
+package dither
+
+import (
+	"math"
+
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/palette"
+)
+
+// Bayer returns Bayer threshold matrix with given size. Matrix contains
+// values from 0 to size*size-1. Will panic when size is not 2, 4 or 8.
+func Bayer(size int) [][]int {
+	validateMatrixSize(size)
+	matrix := make([][]int, size)
+	for y := 0; y < size; y++ {
+		matrix[y] = make([]int, size)
+		for x := 0; x < size; x++ {
+			matrix[y][x] = bayerValue(x, y, size)
+		}
+	}
+	return matrix
+}
+
+func validateMatrixSize(size int) {
+	if size != 2 && size != 4 && size != 8 {
+		panic("matrix size must be 2, 4 or 8")
+	}
+}
+
+// bayerValue calculates the value of recursively defined matrix:
+//
+//	M(2n) = | 4*M(n)    4*M(n)+2 |
+//	        | 4*M(n)+3  4*M(n)+1 |
+func bayerValue(x, y, size int) int {
+	value, multiplier := 0, 1
+	for n := size / 2; n >= 1; n /= 2 {
+		bx, by := (x/n)%2, (y/n)%2
+		value += multiplier * (2*(bx^by) + by)
+		multiplier *= 4
+	}
+	return value
+}
+
+// NewOrdered creates a new ordered dithering tool using Bayer matrix with given
+// size. Will panic when palette is empty or matrixSize is not 2, 4 or 8.
+func NewOrdered(pal palette.Palette, matrixSize int) *Ordered {
+	if len(pal) == 0 {
+		panic("empty palette")
+	}
+	validateMatrixSize(matrixSize)
+	return &Ordered{
+		palette:   pal,
+		threshold: thresholds(matrixSize),
+		spread:    DefaultSpread,
+	}
+}
+
+// DefaultSpread is a default spread of Ordered tool.
+const DefaultSpread = 64
+
+// thresholds returns Bayer matrix normalized to range (-0.5,0.5)
+func thresholds(size int) [][]float64 {
+	matrix := Bayer(size)
+	normalized := make([][]float64, size)
+	for y, row := range matrix {
+		normalized[y] = make([]float64, size)
+		for x, v := range row {
+			normalized[y][x] = (float64(v)+0.5)/float64(size*size) - 0.5
+		}
+	}
+	return normalized
+}
+
+// Ordered is a tool for ordered dithering (aka Bayer dithering).
+type Ordered struct {
+	palette   palette.Palette
+	threshold [][]float64
+	spread    int
+}
+
+// SetSpread sets how much colors are changed by the threshold matrix before
+// the nearest palette color is found. The higher the value the more visible
+// the pattern is. Good value is 255 divided by the number of levels of each
+// color component in the palette. Will panic when spread is negative.
+func (o *Ordered) SetSpread(spread int) {
+	if spread < 0 {
+		panic("negative spread")
+	}
+	o.spread = spread
+}
+
+// Dither replaces colors of the selection with palette colors. Pattern is
+// aligned to the top-left corner of the selection.
+func (o *Ordered) Dither(selection image.Selection) {
+	size := len(o.threshold)
+	lines := selection.Lines()
+	var (
+		xOffset = lines.XOffset()
+		yOffset = lines.YOffset()
+		spread  = float64(o.spread)
+		cache   = map[image.Color]image.Color{}
+	)
+	for y := 0; y < lines.Length(); y++ {
+		line := lines.LineForWrite(y)
+		row := o.threshold[(y+yOffset)%size]
+		for x, color := range line {
+			r, g, b, a := color.RGBAi()
+			// offset is premultiplied by alpha
+			offset := spread * row[(x+xOffset)%size] * float64(a) / 255
+			adjusted := image.RGBAi(
+				round(float64(r)+offset),
+				round(float64(g)+offset),
+				round(float64(b)+offset),
+				a)
+			nearest, ok := cache[adjusted]
+			if !ok {
+				nearest = o.palette.NearestColor(adjusted)
+				cache[adjusted] = nearest
+			}
+			line[x] = nearest
+		}
+	}
+}
+
+func round(v float64) int {
+	return int(math.Round(v))
+}
@@ -0,0 +1,47 @@
+package dither_test
+
+import (
+	"testing"
+
+	"github.com/elgopher/pixiq/dither"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/palette"
+)
+
+var pal = palette.Palette{
+	image.RGB(0, 0, 0), image.RGB(255, 255, 255), image.RGB(255, 0, 0), image.RGB(0, 255, 0),
+	image.RGB(0, 0, 255), image.RGB(255, 255, 0), image.RGB(0, 255, 255), image.RGB(255, 0, 255),
+}
+
+func BenchmarkOrdered_Dither(b *testing.B) {
+	tool := dither.NewOrdered(pal, 4)
+	img := newGradientImage(320, 200)
+	selection := img.WholeImageSelection()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.Dither(selection)
+	}
+}
+
+func BenchmarkErrorDiffusion_Dither(b *testing.B) {
+	tool := dither.NewErrorDiffusion(pal, dither.FloydSteinberg)
+	img := newGradientImage(320, 200)
+	selection := img.WholeImageSelection()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.Dither(selection)
+	}
+}
+
+func newGradientImage(width, height int) *image.Image {
+	img := newFilledImage(width, height, image.Transparent)
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, image.RGB(uint8(x), uint8(y), uint8(x+y)))
+		}
+	}
+	return img
+}
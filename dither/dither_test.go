@@ -0,0 +1,173 @@
+package dither_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/dither"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+	"github.com/elgopher/pixiq/palette"
+)
+
+var (
+	black      = image.RGB(0, 0, 0)
+	white      = image.RGB(255, 255, 255)
+	gray       = image.RGB(128, 128, 128)
+	blackWhite = palette.Palette{black, white}
+)
+
+func TestBayer(t *testing.T) {
+	t.Run("should panic for invalid size", func(t *testing.T) {
+		for _, size := range []int{-1, 0, 1, 3, 16} {
+			assert.Panics(t, func() {
+				dither.Bayer(size)
+			})
+		}
+	})
+
+	t.Run("should return matrix", func(t *testing.T) {
+		tests := map[int][][]int{
+			2: {
+				{0, 2},
+				{3, 1},
+			},
+			4: {
+				{0, 8, 2, 10},
+				{12, 4, 14, 6},
+				{3, 11, 1, 9},
+				{15, 7, 13, 5},
+			},
+		}
+		for size, expected := range tests {
+			assert.Equal(t, expected, dither.Bayer(size))
+		}
+	})
+
+	t.Run("should return matrix with all values", func(t *testing.T) {
+		matrix := dither.Bayer(8)
+		values := map[int]bool{}
+		for _, row := range matrix {
+			for _, v := range row {
+				values[v] = true
+			}
+		}
+		for i := 0; i < 64; i++ {
+			assert.True(t, values[i], "missing value %d", i)
+		}
+	})
+}
+
+func TestNewOrdered(t *testing.T) {
+	t.Run("should panic", func(t *testing.T) {
+		tests := map[string]struct {
+			palette    palette.Palette
+			matrixSize int
+		}{
+			"empty palette":       {matrixSize: 2},
+			"invalid matrix size": {palette: blackWhite, matrixSize: 3},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				assert.Panics(t, func() {
+					dither.NewOrdered(test.palette, test.matrixSize)
+				})
+			})
+		}
+	})
+}
+
+func TestOrdered_SetSpread(t *testing.T) {
+	t.Run("should panic for negative spread", func(t *testing.T) {
+		tool := dither.NewOrdered(blackWhite, 2)
+		assert.Panics(t, func() {
+			tool.SetSpread(-1)
+		})
+	})
+}
+
+func TestOrdered_Dither(t *testing.T) {
+	t.Run("should dither gray color", func(t *testing.T) {
+		img := newFilledImage(4, 2, gray)
+		tool := dither.NewOrdered(blackWhite, 2)
+		// when
+		tool.Dither(img.WholeImageSelection())
+		// then
+		assertColors(t, img, [][]image.Color{
+			{black, white, black, white},
+			{white, black, white, black},
+		})
+	})
+
+	t.Run("should align pattern to selection", func(t *testing.T) {
+		img := newFilledImage(3, 2, gray)
+		tool := dither.NewOrdered(blackWhite, 2)
+		// when
+		tool.Dither(img.Selection(1, 0).WithSize(2, 2))
+		// then
+		assertColors(t, img, [][]image.Color{
+			{gray, black, white},
+			{gray, white, black},
+		})
+	})
+
+	t.Run("should dither selection partially outside the image", func(t *testing.T) {
+		img := newFilledImage(2, 2, gray)
+		tool := dither.NewOrdered(blackWhite, 2)
+		// when
+		tool.Dither(img.Selection(-1, -1).WithSize(3, 3))
+		// then
+		assertColors(t, img, [][]image.Color{
+			{black, white},
+			{white, black},
+		})
+	})
+
+	t.Run("should use nearest color when spread is 0", func(t *testing.T) {
+		img := newFilledImage(2, 2, gray)
+		tool := dither.NewOrdered(blackWhite, 2)
+		tool.SetSpread(0)
+		// when
+		tool.Dither(img.WholeImageSelection())
+		// then
+		assertColors(t, img, [][]image.Color{
+			{white, white},
+			{white, white},
+		})
+	})
+
+	t.Run("should not dither transparent pixels", func(t *testing.T) {
+		img := newFilledImage(2, 2, image.Transparent)
+		tool := dither.NewOrdered(palette.Palette{image.Transparent, black, white}, 2)
+		// when
+		tool.Dither(img.WholeImageSelection())
+		// then
+		assertColors(t, img, [][]image.Color{
+			{image.Transparent, image.Transparent},
+			{image.Transparent, image.Transparent},
+		})
+	})
+}
+
+func newFilledImage(width, height int, color image.Color) *image.Image {
+	img := image.New(fake.NewAcceleratedImage(width, height))
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, color)
+		}
+	}
+	return img
+}
+
+func assertColors(t *testing.T, img *image.Image, expectedColorLines [][]image.Color) {
+	selection := img.WholeImageSelection()
+	for y := 0; y < selection.Height(); y++ {
+		expectedColorLine := expectedColorLines[y]
+		for x := 0; x < selection.Width(); x++ {
+			color := selection.Color(x, y)
+			assert.Equal(t, expectedColorLine[x], color, "position (%d,%d)", x, y)
+		}
+	}
+}
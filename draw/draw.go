@@ -0,0 +1,371 @@
+// Package draw provides CPU tools for drawing primitives such as lines,
+// rectangles, ellipses and polygons.
+//
+//	tool := draw.New()
+//	tool.SetColor(colornames.White)
+//	tool.Line(screen, 0, 0, 10, 5)
+//
+// All coordinates are local to the image.Selection. Pixels outside the selection
+// (or outside the image) are not drawn.
+package draw
+
+import (
+	"sort"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+// New returns new instance of *draw.Tool. By default the tool draws with
+// transparent color and lines are 1 pixel thick.
+func New() *Tool {
+	return &Tool{thickness: 1}
+}
+
+// Tool is a drawing tool. It draws primitives into the image.Selection using
+// previously set color. Drawn pixels replace the original ones - there is no
+// blending and no anti-aliasing.
+//
+// Tool uses CPU.
+type Tool struct {
+	color     image.Color
+	thickness int
+	// reused between FilledPolygon calls
+	intersections []float64
+}
+
+// Point is a position of a pixel in the Selection.
+type Point struct {
+	X, Y int
+}
+
+// SetColor sets color which will be used by drawing methods
+func (t *Tool) SetColor(color image.Color) {
+	t.color = color
+}
+
+// SetThickness sets the thickness of lines in pixels. It is used by Line,
+// Rectangle, Circle, Ellipse and Polygon. Thickness lower than 1 is treated
+// as 1.
+//
+// Thick lines are drawn using a square brush centered on each pixel of the
+// 1-pixel line.
+func (t *Tool) SetThickness(thickness int) {
+	if thickness < 1 {
+		thickness = 1
+	}
+	t.thickness = thickness
+}
+
+// Point draws a single pixel (or a square brush when thickness is greater than 1)
+func (t *Tool) Point(selection image.Selection, x, y int) {
+	t.stamp(selection, x, y)
+}
+
+// Line draws a pixel-perfect line from (x1,y1) to (x2,y2). For each pixel on
+// the major axis (the one with a bigger distance between ends) a single pixel
+// nearest to the ideal line is drawn. Halfway cases are rounded away from the
+// start. Both ends are drawn and drawn pixels does not depend on the line
+// direction.
+func (t *Tool) Line(selection image.Selection, x1, y1, x2, y2 int) {
+	dx, dy := x2-x1, y2-y1
+	if abs(dx) >= abs(dy) {
+		if x1 > x2 {
+			x1, y1, x2, y2, dx, dy = x2, y2, x1, y1, -dx, -dy
+		}
+		for x := x1; x <= x2; x++ {
+			t.stamp(selection, x, y1+minorOffset(x-x1, dx, dy))
+		}
+		return
+	}
+	if y1 > y2 {
+		x1, y1, x2, y2, dx, dy = x2, y2, x1, y1, -dx, -dy
+	}
+	for y := y1; y <= y2; y++ {
+		t.stamp(selection, x1+minorOffset(y-y1, dy, dx), y)
+	}
+}
+
+// minorOffset returns the offset on the minor axis for a given step on the major
+// axis. The result is step*minor/major rounded half away from zero.
+func minorOffset(step, major, minor int) int {
+	if major == 0 {
+		return 0
+	}
+	offset := (2*step*abs(minor) + major) / (2 * major)
+	if minor < 0 {
+		return -offset
+	}
+	return offset
+}
+
+// Rectangle draws an outline of the rectangle with top-left corner at (x,y).
+// Thick outline is drawn inside the rectangle.
+func (t *Tool) Rectangle(selection image.Selection, x, y, width, height int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	thickness := t.thickness
+	if 2*thickness >= width || 2*thickness >= height {
+		t.FilledRectangle(selection, x, y, width, height)
+		return
+	}
+	t.FilledRectangle(selection, x, y, width, thickness)
+	t.FilledRectangle(selection, x, y+height-thickness, width, thickness)
+	t.FilledRectangle(selection, x, y+thickness, thickness, height-2*thickness)
+	t.FilledRectangle(selection, x+width-thickness, y+thickness, thickness, height-2*thickness)
+}
+
+// FilledRectangle draws a filled rectangle with top-left corner at (x,y).
+// Nothing is drawn when width or height is not positive.
+func (t *Tool) FilledRectangle(selection image.Selection, x, y, width, height int) {
+	for line := y; line < y+height; line++ {
+		t.horizontalLine(selection, x, x+width-1, line)
+	}
+}
+
+// Circle draws an outline of the circle using midpoint circle algorithm.
+func (t *Tool) Circle(selection image.Selection, centerX, centerY, radius int) {
+	if radius < 0 {
+		return
+	}
+	x, y := radius, 0
+	err := 1 - radius
+	for x >= y {
+		t.stamp(selection, centerX+x, centerY+y)
+		t.stamp(selection, centerX+y, centerY+x)
+		t.stamp(selection, centerX-y, centerY+x)
+		t.stamp(selection, centerX-x, centerY+y)
+		t.stamp(selection, centerX-x, centerY-y)
+		t.stamp(selection, centerX-y, centerY-x)
+		t.stamp(selection, centerX+y, centerY-x)
+		t.stamp(selection, centerX+x, centerY-y)
+		y++
+		if err < 0 {
+			err += 2*y + 1
+		} else {
+			x--
+			err += 2*(y-x) + 1
+		}
+	}
+}
+
+// FilledCircle draws a filled circle using midpoint circle algorithm. The shape
+// is the same as the one drawn by Circle with thickness 1.
+func (t *Tool) FilledCircle(selection image.Selection, centerX, centerY, radius int) {
+	if radius < 0 {
+		return
+	}
+	x, y := radius, 0
+	err := 1 - radius
+	for x >= y {
+		t.horizontalLine(selection, centerX-x, centerX+x, centerY+y)
+		t.horizontalLine(selection, centerX-x, centerX+x, centerY-y)
+		t.horizontalLine(selection, centerX-y, centerX+y, centerY+x)
+		t.horizontalLine(selection, centerX-y, centerX+y, centerY-x)
+		y++
+		if err < 0 {
+			err += 2*y + 1
+		} else {
+			x--
+			err += 2*(y-x) + 1
+		}
+	}
+}
+
+// Ellipse draws an outline of the axis-aligned ellipse using midpoint ellipse
+// algorithm.
+func (t *Tool) Ellipse(selection image.Selection, centerX, centerY, radiusX, radiusY int) {
+	ellipse(radiusX, radiusY, func(x, y int) {
+		t.stamp(selection, centerX+x, centerY+y)
+		t.stamp(selection, centerX-x, centerY+y)
+		t.stamp(selection, centerX+x, centerY-y)
+		t.stamp(selection, centerX-x, centerY-y)
+	})
+}
+
+// FilledEllipse draws a filled axis-aligned ellipse using midpoint ellipse
+// algorithm. The shape is the same as the one drawn by Ellipse with thickness 1.
+func (t *Tool) FilledEllipse(selection image.Selection, centerX, centerY, radiusX, radiusY int) {
+	ellipse(radiusX, radiusY, func(x, y int) {
+		t.horizontalLine(selection, centerX-x, centerX+x, centerY+y)
+		t.horizontalLine(selection, centerX-x, centerX+x, centerY-y)
+	})
+}
+
+// ellipse executes plot for each pixel of ellipse's quadrant
+func ellipse(radiusX, radiusY int, plot func(x, y int)) {
+	if radiusX < 0 || radiusY < 0 {
+		return
+	}
+	if radiusX == 0 || radiusY == 0 {
+		for x := 0; x <= radiusX; x++ {
+			for y := 0; y <= radiusY; y++ {
+				plot(x, y)
+			}
+		}
+		return
+	}
+	var (
+		rx2 = int64(radiusX) * int64(radiusX)
+		ry2 = int64(radiusY) * int64(radiusY)
+		x   = int64(0)
+		y   = int64(radiusY)
+		dx  = int64(0)
+		dy  = 2 * rx2 * y
+	)
+	// region 1 - slope > -1
+	p := 4*ry2 - 4*rx2*int64(radiusY) + rx2
+	for dx < dy {
+		plot(int(x), int(y))
+		x++
+		dx += 2 * ry2
+		if p < 0 {
+			p += 4 * (dx + ry2)
+		} else {
+			y--
+			dy -= 2 * rx2
+			p += 4 * (dx - dy + ry2)
+		}
+	}
+	// region 2 - slope <= -1
+	p = ry2*(2*x+1)*(2*x+1) + 4*rx2*(y-1)*(y-1) - 4*rx2*ry2
+	for y >= 0 {
+		plot(int(x), int(y))
+		y--
+		dy -= 2 * rx2
+		if p > 0 {
+			p += 4 * (rx2 - dy)
+		} else {
+			x++
+			dx += 2 * ry2
+			p += 4 * (dx - dy + rx2)
+		}
+	}
+}
+
+// Polygon draws an outline of the polygon. The last point is connected with
+// the first one.
+func (t *Tool) Polygon(selection image.Selection, points []Point) {
+	if len(points) == 0 {
+		return
+	}
+	for i := 0; i < len(points)-1; i++ {
+		t.Line(selection, points[i].X, points[i].Y, points[i+1].X, points[i+1].Y)
+	}
+	last := points[len(points)-1]
+	t.Line(selection, last.X, last.Y, points[0].X, points[0].Y)
+}
+
+// FilledPolygon draws a filled polygon, which may be convex or concave.
+// Polygon is filled using even-odd rule, therefore self-intersecting polygons
+// have holes. Edges of the polygon are always drawn, just like in Polygon with
+// thickness 1.
+func (t *Tool) FilledPolygon(selection image.Selection, points []Point) {
+	if len(points) == 0 {
+		return
+	}
+	minY, maxY := points[0].Y, points[0].Y
+	for _, point := range points {
+		if point.Y < minY {
+			minY = point.Y
+		}
+		if point.Y > maxY {
+			maxY = point.Y
+		}
+	}
+	if minY < 0 {
+		minY = 0
+	}
+	if maxY >= selection.Height() {
+		maxY = selection.Height() - 1
+	}
+	for y := minY; y <= maxY; y++ {
+		intersections := t.intersections[:0]
+		for i := range points {
+			p1 := points[i]
+			p2 := points[(i+1)%len(points)]
+			if p1.Y == p2.Y {
+				continue
+			}
+			if p1.Y > p2.Y {
+				p1, p2 = p2, p1
+			}
+			// half-open range, so that vertices are not counted twice
+			if y < p1.Y || y >= p2.Y {
+				continue
+			}
+			x := float64(p1.X) + float64(y-p1.Y)*float64(p2.X-p1.X)/float64(p2.Y-p1.Y)
+			intersections = append(intersections, x)
+		}
+		sort.Float64s(intersections)
+		for i := 0; i+1 < len(intersections); i += 2 {
+			t.horizontalLine(selection, ceil(intersections[i]), floor(intersections[i+1]), y)
+		}
+		t.intersections = intersections
+	}
+	thickness := t.thickness
+	t.thickness = 1
+	t.Polygon(selection, points)
+	t.thickness = thickness
+}
+
+// stamp draws a square brush of size thickness centered at (x,y)
+func (t *Tool) stamp(selection image.Selection, x, y int) {
+	if t.thickness == 1 {
+		if x < 0 || y < 0 || x >= selection.Width() || y >= selection.Height() {
+			return
+		}
+		selection.SetColor(x, y, t.color)
+		return
+	}
+	half := (t.thickness - 1) / 2
+	t.FilledRectangle(selection, x-half, y-half, t.thickness, t.thickness)
+}
+
+// horizontalLine draws pixels from x1 to x2 (inclusive) in line y. Nothing is
+// drawn when x1 > x2. Pixels outside the selection are not drawn.
+func (t *Tool) horizontalLine(selection image.Selection, x1, x2, y int) {
+	if y < 0 || y >= selection.Height() {
+		return
+	}
+	if x1 < 0 {
+		x1 = 0
+	}
+	if x2 >= selection.Width() {
+		x2 = selection.Width() - 1
+	}
+	if x1 > x2 {
+		return
+	}
+	lines := selection.Selection(x1, y).WithSize(x2-x1+1, 1).Lines()
+	if lines.Length() == 0 {
+		return
+	}
+	line := lines.LineForWrite(0)
+	for x := 0; x < len(line); x++ {
+		line[x] = t.color
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func ceil(v float64) int {
+	i := int(v)
+	if float64(i) < v {
+		i++
+	}
+	return i
+}
+
+func floor(v float64) int {
+	i := int(v)
+	if float64(i) > v {
+		i--
+	}
+	return i
+}
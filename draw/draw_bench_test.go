@@ -0,0 +1,52 @@
+package draw_test
+
+import (
+	"testing"
+
+	"github.com/elgopher/pixiq/draw"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+)
+
+func BenchmarkTool_Line(b *testing.B) {
+	var (
+		img       = image.New(fake.NewAcceleratedImage(1920, 1080))
+		selection = img.WholeImageSelection()
+		tool      = draw.New()
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.Line(selection, 0, 0, 1919, 1079)
+	}
+}
+
+func BenchmarkTool_FilledCircle(b *testing.B) {
+	var (
+		img       = image.New(fake.NewAcceleratedImage(1920, 1080))
+		selection = img.WholeImageSelection()
+		tool      = draw.New()
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.FilledCircle(selection, 960, 540, 500)
+	}
+}
+
+func BenchmarkTool_FilledPolygon(b *testing.B) {
+	var (
+		img       = image.New(fake.NewAcceleratedImage(1920, 1080))
+		selection = img.WholeImageSelection()
+		tool      = draw.New()
+		points    = []draw.Point{
+			{X: 100, Y: 100}, {X: 1800, Y: 200}, {X: 960, Y: 540},
+			{X: 1700, Y: 1000}, {X: 200, Y: 900},
+		}
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.FilledPolygon(selection, points)
+	}
+}
@@ -0,0 +1,689 @@
+package draw_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/draw"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+)
+
+var color = image.RGBA(10, 20, 30, 40)
+
+func TestNew(t *testing.T) {
+	t.Run("should create tool", func(t *testing.T) {
+		tool := draw.New()
+		assert.NotNil(t, tool)
+	})
+}
+
+func TestTool_Point(t *testing.T) {
+	t.Run("should draw point", func(t *testing.T) {
+		tests := map[string]struct {
+			x, y      int
+			thickness int
+			expected  []string
+		}{
+			"1 pixel": {
+				x: 1, y: 1, thickness: 1,
+				expected: []string{
+					"...",
+					".#.",
+					"...",
+				},
+			},
+			"thickness 0 is treated as 1": {
+				x: 1, y: 1, thickness: 0,
+				expected: []string{
+					"...",
+					".#.",
+					"...",
+				},
+			},
+			"thickness 2": {
+				x: 1, y: 1, thickness: 2,
+				expected: []string{
+					"...",
+					".##",
+					".##",
+				},
+			},
+			"thickness 3": {
+				x: 1, y: 1, thickness: 3,
+				expected: []string{
+					"###",
+					"###",
+					"###",
+				},
+			},
+			"outside selection": {
+				x: 3, y: -1, thickness: 1,
+				expected: []string{
+					"...",
+					"...",
+					"...",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := image.New(fake.NewAcceleratedImage(3, 3))
+				tool := newTool(test.thickness)
+				// when
+				tool.Point(img.WholeImageSelection(), test.x, test.y)
+				// then
+				assertPixels(t, img, test.expected)
+			})
+		}
+	})
+}
+
+func TestTool_Line(t *testing.T) {
+	t.Run("should draw line", func(t *testing.T) {
+		tests := map[string]struct {
+			x1, y1, x2, y2 int
+			thickness      int
+			expected       []string
+		}{
+			"single pixel": {
+				x1: 1, y1: 1, x2: 1, y2: 1,
+				expected: []string{
+					".....",
+					".#...",
+					".....",
+				},
+			},
+			"horizontal": {
+				x1: 0, y1: 1, x2: 4, y2: 1,
+				expected: []string{
+					".....",
+					"#####",
+					".....",
+				},
+			},
+			"vertical": {
+				x1: 2, y1: 0, x2: 2, y2: 2,
+				expected: []string{
+					"..#..",
+					"..#..",
+					"..#..",
+				},
+			},
+			"diagonal": {
+				x1: 0, y1: 0, x2: 2, y2: 2,
+				expected: []string{
+					"#....",
+					".#...",
+					"..#..",
+				},
+			},
+			"reversed diagonal": {
+				x1: 4, y1: 0, x2: 2, y2: 2,
+				expected: []string{
+					"....#",
+					"...#.",
+					"..#..",
+				},
+			},
+			"gentle slope": {
+				x1: 0, y1: 0, x2: 4, y2: 2,
+				expected: []string{
+					"#....",
+					".##..",
+					"...##",
+				},
+			},
+			"same pixels in both directions": {
+				x1: 4, y1: 2, x2: 0, y2: 0,
+				expected: []string{
+					"#....",
+					".##..",
+					"...##",
+				},
+			},
+			// halfway pixels are always rounded away from the start on the major
+			// axis, therefore a steep line looks the same no matter whether it goes
+			// left or right
+			"steep slope going right": {
+				x1: 0, y1: 0, x2: 1, y2: 2,
+				expected: []string{
+					"#....",
+					".#...",
+					".#...",
+				},
+			},
+			"steep slope going left": {
+				x1: 1, y1: 0, x2: 0, y2: 2,
+				expected: []string{
+					".#...",
+					"#....",
+					"#....",
+				},
+			},
+			"steep slope in reversed direction": {
+				x1: 0, y1: 2, x2: 1, y2: 0,
+				expected: []string{
+					".#...",
+					"#....",
+					"#....",
+				},
+			},
+			"clipped": {
+				x1: -2, y1: 1, x2: 10, y2: 1,
+				expected: []string{
+					".....",
+					"#####",
+					".....",
+				},
+			},
+			"thick horizontal": {
+				x1: 1, y1: 1, x2: 3, y2: 1, thickness: 3,
+				expected: []string{
+					"#####",
+					"#####",
+					"#####",
+				},
+			},
+			"thick vertical": {
+				x1: 2, y1: 0, x2: 2, y2: 2, thickness: 2,
+				expected: []string{
+					"..##.",
+					"..##.",
+					"..##.",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := image.New(fake.NewAcceleratedImage(5, 3))
+				tool := newTool(test.thickness)
+				// when
+				tool.Line(img.WholeImageSelection(), test.x1, test.y1, test.x2, test.y2)
+				// then
+				assertPixels(t, img, test.expected)
+			})
+		}
+	})
+	t.Run("should draw line using selection coordinates", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(5, 3))
+		selection := img.Selection(1, 1).WithSize(3, 1)
+		tool := newTool(1)
+		// when
+		tool.Line(selection, -1, 0, 5, 0)
+		// then
+		assertPixels(t, img, []string{
+			".....",
+			".###.",
+			".....",
+		})
+	})
+	t.Run("should not draw outside image", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(3, 3))
+		selection := img.Selection(-1, -1).WithSize(5, 5)
+		tool := newTool(1)
+		// when
+		tool.Line(selection, 0, 0, 4, 4)
+		// then
+		assertPixels(t, img, []string{
+			"#..",
+			".#.",
+			"..#",
+		})
+	})
+}
+
+func TestTool_Rectangle(t *testing.T) {
+	t.Run("should draw rectangle", func(t *testing.T) {
+		tests := map[string]struct {
+			x, y, width, height int
+			thickness           int
+			expected            []string
+		}{
+			"empty": {
+				x: 1, y: 1, width: 0, height: 3,
+				expected: []string{
+					".....",
+					".....",
+					".....",
+					".....",
+				},
+			},
+			"1x1": {
+				x: 1, y: 1, width: 1, height: 1,
+				expected: []string{
+					".....",
+					".#...",
+					".....",
+					".....",
+				},
+			},
+			"outline": {
+				x: 0, y: 0, width: 5, height: 4,
+				expected: []string{
+					"#####",
+					"#...#",
+					"#...#",
+					"#####",
+				},
+			},
+			"clipped": {
+				x: -1, y: 1, width: 4, height: 5,
+				expected: []string{
+					".....",
+					"###..",
+					"..#..",
+					"..#..",
+				},
+			},
+			"thick outline drawn inside": {
+				x: 0, y: 0, width: 5, height: 4, thickness: 2,
+				expected: []string{
+					"#####",
+					"#####",
+					"#####",
+					"#####",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := image.New(fake.NewAcceleratedImage(5, 4))
+				tool := newTool(test.thickness)
+				// when
+				tool.Rectangle(img.WholeImageSelection(), test.x, test.y, test.width, test.height)
+				// then
+				assertPixels(t, img, test.expected)
+			})
+		}
+	})
+	t.Run("should draw thick outline", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(6, 6))
+		tool := newTool(2)
+		// when
+		tool.Rectangle(img.WholeImageSelection(), 0, 0, 6, 5)
+		// then
+		assertPixels(t, img, []string{
+			"######",
+			"######",
+			"##..##",
+			"######",
+			"######",
+			"......",
+		})
+	})
+}
+
+func TestTool_FilledRectangle(t *testing.T) {
+	t.Run("should draw filled rectangle", func(t *testing.T) {
+		tests := map[string]struct {
+			x, y, width, height int
+			expected            []string
+		}{
+			"negative size": {
+				x: 1, y: 1, width: -1, height: -1,
+				expected: []string{
+					"....",
+					"....",
+					"....",
+				},
+			},
+			// rectangle with negative width is not mirrored
+			"negative width": {
+				x: 2, y: 1, width: -2, height: 2,
+				expected: []string{
+					"....",
+					"....",
+					"....",
+				},
+			},
+			"inside": {
+				x: 1, y: 1, width: 2, height: 2,
+				expected: []string{
+					"....",
+					".##.",
+					".##.",
+				},
+			},
+			"clipped": {
+				x: -1, y: -1, width: 3, height: 3,
+				expected: []string{
+					"##..",
+					"##..",
+					"....",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := image.New(fake.NewAcceleratedImage(4, 3))
+				tool := newTool(1)
+				// when
+				tool.FilledRectangle(img.WholeImageSelection(), test.x, test.y, test.width, test.height)
+				// then
+				assertPixels(t, img, test.expected)
+			})
+		}
+	})
+}
+
+func TestTool_Circle(t *testing.T) {
+	t.Run("should draw circle", func(t *testing.T) {
+		tests := map[string]struct {
+			radius   int
+			expected []string
+		}{
+			"negative radius": {
+				radius: -1,
+				expected: []string{
+					".......",
+					".......",
+					".......",
+					".......",
+					".......",
+					".......",
+					".......",
+				},
+			},
+			"radius 0": {
+				radius: 0,
+				expected: []string{
+					".......",
+					".......",
+					".......",
+					"...#...",
+					".......",
+					".......",
+					".......",
+				},
+			},
+			"radius 1": {
+				radius: 1,
+				expected: []string{
+					".......",
+					".......",
+					"...#...",
+					"..#.#..",
+					"...#...",
+					".......",
+					".......",
+				},
+			},
+			"radius 3": {
+				radius: 3,
+				expected: []string{
+					"..###..",
+					".#...#.",
+					"#.....#",
+					"#.....#",
+					"#.....#",
+					".#...#.",
+					"..###..",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := image.New(fake.NewAcceleratedImage(7, 7))
+				tool := newTool(1)
+				// when
+				tool.Circle(img.WholeImageSelection(), 3, 3, test.radius)
+				// then
+				assertPixels(t, img, test.expected)
+			})
+		}
+	})
+}
+
+func TestTool_FilledCircle(t *testing.T) {
+	t.Run("should draw filled circle", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(7, 7))
+		tool := newTool(1)
+		// when
+		tool.FilledCircle(img.WholeImageSelection(), 3, 3, 3)
+		// then
+		assertPixels(t, img, []string{
+			"..###..",
+			".#####.",
+			"#######",
+			"#######",
+			"#######",
+			".#####.",
+			"..###..",
+		})
+	})
+	t.Run("should clip filled circle", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(4, 4))
+		tool := newTool(1)
+		// when
+		tool.FilledCircle(img.WholeImageSelection(), 0, 0, 3)
+		// then
+		assertPixels(t, img, []string{
+			"####",
+			"####",
+			"###.",
+			"##..",
+		})
+	})
+}
+
+func TestTool_Ellipse(t *testing.T) {
+	t.Run("should draw ellipse", func(t *testing.T) {
+		tests := map[string]struct {
+			radiusX, radiusY int
+			expected         []string
+		}{
+			"zero radiusY": {
+				radiusX: 3, radiusY: 0,
+				expected: []string{
+					".......",
+					".......",
+					"#######",
+					".......",
+					".......",
+				},
+			},
+			"zero radiusX": {
+				radiusX: 0, radiusY: 2,
+				expected: []string{
+					"...#...",
+					"...#...",
+					"...#...",
+					"...#...",
+					"...#...",
+				},
+			},
+			"wide": {
+				radiusX: 3, radiusY: 2,
+				expected: []string{
+					"..###..",
+					".#...#.",
+					"#.....#",
+					".#...#.",
+					"..###..",
+				},
+			},
+			"same as circle when radii are equal": {
+				radiusX: 1, radiusY: 1,
+				expected: []string{
+					".......",
+					"...#...",
+					"..#.#..",
+					"...#...",
+					".......",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := image.New(fake.NewAcceleratedImage(7, 5))
+				tool := newTool(1)
+				// when
+				tool.Ellipse(img.WholeImageSelection(), 3, 2, test.radiusX, test.radiusY)
+				// then
+				assertPixels(t, img, test.expected)
+			})
+		}
+	})
+}
+
+func TestTool_FilledEllipse(t *testing.T) {
+	t.Run("should draw filled ellipse", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(7, 5))
+		tool := newTool(1)
+		// when
+		tool.FilledEllipse(img.WholeImageSelection(), 3, 2, 3, 2)
+		// then
+		assertPixels(t, img, []string{
+			"..###..",
+			".#####.",
+			"#######",
+			".#####.",
+			"..###..",
+		})
+	})
+}
+
+func TestTool_Polygon(t *testing.T) {
+	t.Run("should not draw anything for empty polygon", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(2, 2))
+		tool := newTool(1)
+		// when
+		tool.Polygon(img.WholeImageSelection(), nil)
+		// then
+		assertPixels(t, img, []string{
+			"..",
+			"..",
+		})
+	})
+	t.Run("should draw polygon outline", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(5, 5))
+		tool := newTool(1)
+		// when
+		tool.Polygon(img.WholeImageSelection(), []draw.Point{
+			{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 4},
+		})
+		// then
+		assertPixels(t, img, []string{
+			"#####",
+			"#..#.",
+			"#.#..",
+			"##...",
+			"#....",
+		})
+	})
+}
+
+func TestTool_FilledPolygon(t *testing.T) {
+	t.Run("should draw filled polygon", func(t *testing.T) {
+		tests := map[string]struct {
+			points   []draw.Point
+			expected []string
+		}{
+			"empty": {
+				points: nil,
+				expected: []string{
+					".....",
+					".....",
+					".....",
+					".....",
+					".....",
+				},
+			},
+			"single point": {
+				points: []draw.Point{{X: 1, Y: 1}},
+				expected: []string{
+					".....",
+					".#...",
+					".....",
+					".....",
+					".....",
+				},
+			},
+			"triangle": {
+				points: []draw.Point{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 4}},
+				expected: []string{
+					"#####",
+					"####.",
+					"###..",
+					"##...",
+					"#....",
+				},
+			},
+			"concave": {
+				points: []draw.Point{
+					{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4},
+					{X: 3, Y: 4}, {X: 3, Y: 1}, {X: 1, Y: 1},
+					{X: 1, Y: 4}, {X: 0, Y: 4},
+				},
+				expected: []string{
+					"#####",
+					"#####",
+					"##.##",
+					"##.##",
+					"##.##",
+				},
+			},
+			"thin": {
+				points: []draw.Point{{X: 0, Y: 0}, {X: 2, Y: 5}, {X: 1, Y: 5}},
+				expected: []string{
+					"#....",
+					"#....",
+					"##...",
+					".#...",
+					".##..",
+				},
+			},
+			"clipped": {
+				points: []draw.Point{{X: -5, Y: -5}, {X: 2, Y: -5}, {X: 2, Y: 2}, {X: -5, Y: 2}},
+				expected: []string{
+					"###..",
+					"###..",
+					"###..",
+					".....",
+					".....",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := image.New(fake.NewAcceleratedImage(5, 5))
+				tool := newTool(1)
+				// when
+				tool.FilledPolygon(img.WholeImageSelection(), test.points)
+				// then
+				assertPixels(t, img, test.expected)
+			})
+		}
+	})
+}
+
+func newTool(thickness int) *draw.Tool {
+	tool := draw.New()
+	tool.SetColor(color)
+	tool.SetThickness(thickness)
+	return tool
+}
+
+// assertPixels compares image pixels with expected lines, where '#' is
+// a pixel with color and '.' is a transparent one.
+func assertPixels(t *testing.T, img *image.Image, expected []string) {
+	selection := img.WholeImageSelection()
+	actual := make([]string, img.Height())
+	for y := 0; y < img.Height(); y++ {
+		line := make([]byte, img.Width())
+		for x := 0; x < img.Width(); x++ {
+			switch selection.Color(x, y) {
+			case color:
+				line[x] = '#'
+			case image.Transparent:
+				line[x] = '.'
+			default:
+				line[x] = '?'
+			}
+		}
+		actual[y] = string(line)
+	}
+	assert.Equal(t, expected, actual)
+}
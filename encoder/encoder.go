@@ -0,0 +1,121 @@
+// Package encoder provides functionality of encoding image selections into
+// compressed formats such as PNG and GIF:
+//
+//	pngEncoder := encoder.New(encoder.Zoom(2))
+//	err := pngEncoder.EncodeFile("screenshot.png", screen.Image().WholeImageSelection())
+package encoder
+
+import (
+	"fmt"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elgopher/pixiq/goimage"
+	"github.com/elgopher/pixiq/image"
+)
+
+// Format is a compressed image format.
+type Format int
+
+const (
+	// PNG is a lossless format supporting all colors and alpha channel.
+	PNG Format = iota
+	// GIF is a format supporting up to 256 colors and one fully transparent
+	// color.
+	GIF
+)
+
+// Option is an encoding option
+type Option func(opts) opts
+
+// Zoom increases the image during encoding. Zoom <= 0 is treated as zoom 1.
+func Zoom(zoom int) Option {
+	return func(o opts) opts {
+		if zoom > 0 {
+			o.zoom = zoom
+		} else {
+			o.zoom = 1
+		}
+		return o
+	}
+}
+
+// WithFormat sets the format used by Encode. PNG is used by default.
+func WithFormat(format Format) Option {
+	return func(o opts) opts {
+		o.format = format
+		return o
+	}
+}
+
+type opts struct {
+	zoom   int
+	format Format
+}
+
+// New creates an Encoder instance which can be used many times for encoding
+// selections. Will panic for unsupported format.
+func New(options ...Option) *Encoder {
+	opts := opts{
+		zoom:   1,
+		format: PNG,
+	}
+	for _, option := range options {
+		opts = option(opts)
+	}
+	if opts.format != PNG && opts.format != GIF {
+		panic("unsupported format")
+	}
+	return &Encoder{opts: opts}
+}
+
+// Encoder encodes selections into compressed images, such as PNGs and GIFs.
+type Encoder struct {
+	opts opts
+}
+
+// Encode encodes selection and writes it to writer. Pixels outside the image
+// are transparent. Error is returned when selection is empty or writer returned
+// error.
+func (e *Encoder) Encode(writer io.Writer, selection image.Selection) error {
+	if writer == nil {
+		panic("nil writer")
+	}
+	return e.encode(writer, selection, e.opts.format)
+}
+
+func (e *Encoder) encode(writer io.Writer, selection image.Selection, format Format) error {
+	if selection.Width() <= 0 || selection.Height() <= 0 {
+		return fmt.Errorf("cannot encode empty selection %dx%d", selection.Width(), selection.Height())
+	}
+	if format == GIF {
+		return encodeGIF(writer, selection, e.opts.zoom)
+	}
+	img := goimage.FromSelection(selection, goimage.Zoom(e.opts.zoom))
+	return png.Encode(writer, img)
+}
+
+// EncodeFile encodes selection and saves it to a file. Format is determined by
+// the file extension (".png" or ".gif"). For other extensions the format
+// passed to New is used.
+func (e *Encoder) EncodeFile(fileName string, selection image.Selection) error {
+	format := e.opts.format
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".png":
+		format = PNG
+	case ".gif":
+		format = GIF
+	}
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err = e.encode(file, selection, format); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
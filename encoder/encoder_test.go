@@ -0,0 +1,258 @@
+package encoder_test
+
+import (
+	"bytes"
+	"errors"
+	stdimage "image"
+	"image/gif"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/encoder"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+)
+
+var (
+	red   = image.RGB(255, 0, 0)
+	green = image.RGB(0, 255, 0)
+	blue  = image.RGB(0, 0, 255)
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should create encoder", func(t *testing.T) {
+		assert.NotNil(t, encoder.New())
+	})
+	t.Run("should panic for unsupported format", func(t *testing.T) {
+		assert.Panics(t, func() {
+			encoder.New(encoder.WithFormat(-1))
+		})
+	})
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	t.Run("should panic for nil writer", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(1, 1))
+		assert.Panics(t, func() {
+			_ = encoder.New().Encode(nil, img.WholeImageSelection())
+		})
+	})
+	t.Run("should return error for empty selection", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(1, 1))
+		tests := map[string]image.Selection{
+			"zero width":  img.Selection(0, 0).WithSize(0, 1),
+			"zero height": img.Selection(0, 0).WithSize(1, 0),
+		}
+		for name, selection := range tests {
+			t.Run(name, func(t *testing.T) {
+				err := encoder.New().Encode(&bytes.Buffer{}, selection)
+				assert.Error(t, err)
+			})
+		}
+	})
+	t.Run("should return error when writer returned error", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(1, 1))
+		formats := map[string]encoder.Format{
+			"png": encoder.PNG,
+			"gif": encoder.GIF,
+		}
+		for name, format := range formats {
+			t.Run(name, func(t *testing.T) {
+				err := encoder.New(encoder.WithFormat(format)).Encode(erroneousWriter{}, img.WholeImageSelection())
+				assert.Error(t, err)
+			})
+		}
+	})
+	t.Run("should encode PNG", func(t *testing.T) {
+		semiTransparent := image.RGBA(40, 0, 40, 40)
+		img := newImage([][]image.Color{
+			{red, green},
+			{blue, semiTransparent},
+		})
+		buffer := &bytes.Buffer{}
+		// when
+		err := encoder.New().Encode(buffer, img.WholeImageSelection())
+		// then
+		require.NoError(t, err)
+		decoded, err := png.Decode(buffer)
+		require.NoError(t, err)
+		assertColors(t, [][]image.Color{
+			{red, green},
+			{blue, semiTransparent},
+		}, decoded)
+	})
+	t.Run("should encode part of image", func(t *testing.T) {
+		img := newImage([][]image.Color{
+			{red, green},
+			{blue, red},
+		})
+		buffer := &bytes.Buffer{}
+		// when
+		err := encoder.New().Encode(buffer, img.Selection(1, 1).WithSize(2, 1))
+		// then
+		require.NoError(t, err)
+		decoded, err := png.Decode(buffer)
+		require.NoError(t, err)
+		assertColors(t, [][]image.Color{
+			{red, image.Transparent},
+		}, decoded)
+	})
+	t.Run("should encode zoomed image", func(t *testing.T) {
+		formats := map[string]encoder.Format{
+			"png": encoder.PNG,
+			"gif": encoder.GIF,
+		}
+		for name, format := range formats {
+			t.Run(name, func(t *testing.T) {
+				img := newImage([][]image.Color{
+					{red, green},
+				})
+				buffer := &bytes.Buffer{}
+				// when
+				err := encoder.New(encoder.WithFormat(format), encoder.Zoom(2)).
+					Encode(buffer, img.WholeImageSelection())
+				// then
+				require.NoError(t, err)
+				decoded, _, err := stdimage.Decode(buffer)
+				require.NoError(t, err)
+				assertColors(t, [][]image.Color{
+					{red, red, green, green},
+					{red, red, green, green},
+				}, decoded)
+			})
+		}
+	})
+	t.Run("should encode GIF", func(t *testing.T) {
+		img := newImage([][]image.Color{
+			{red, green, image.Transparent},
+			{blue, image.RGBA(0, 0, 0, 127), image.RGBA(64, 0, 0, 128)},
+		})
+		buffer := &bytes.Buffer{}
+		// when
+		err := encoder.New(encoder.WithFormat(encoder.GIF)).Encode(buffer, img.WholeImageSelection())
+		// then
+		require.NoError(t, err)
+		decoded, err := gif.Decode(buffer)
+		require.NoError(t, err)
+		assertColors(t, [][]image.Color{
+			{red, green, image.Transparent},
+			// semi-transparent colors are opaque in GIF, components are
+			// unpremultiplied with rounding, see image.Color.NRGBA
+			{blue, image.Transparent, image.RGB(128, 0, 0)},
+		}, decoded)
+	})
+	t.Run("should encode GIF with more than 256 colors", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(300, 1))
+		selection := img.WholeImageSelection()
+		for x := 0; x < 300; x++ {
+			selection.SetColor(x, 0, image.RGB(byte(x), 0, 0))
+		}
+		selection.SetColor(0, 0, image.Transparent)
+		selection.SetColor(299, 0, image.RGB(255, 255, 255))
+		buffer := &bytes.Buffer{}
+		// when
+		err := encoder.New(encoder.WithFormat(encoder.GIF)).Encode(buffer, selection)
+		// then
+		require.NoError(t, err)
+		decoded, err := gif.Decode(buffer)
+		require.NoError(t, err)
+		assertColor(t, image.Transparent, decoded.At(0, 0))
+		assertColor(t, image.RGB(255, 0, 0), decoded.At(255, 0))
+		assertColor(t, image.RGB(255, 255, 255), decoded.At(299, 0))
+	})
+}
+
+func TestEncoder_EncodeFile(t *testing.T) {
+	img := newImage([][]image.Color{
+		{red, green},
+	})
+
+	t.Run("should encode file using format based on extension", func(t *testing.T) {
+		tests := map[string]struct {
+			options        []encoder.Option
+			expectedFormat string
+		}{
+			"image.png": {expectedFormat: "png"},
+			"image.PNG": {expectedFormat: "png", options: []encoder.Option{encoder.WithFormat(encoder.GIF)}},
+			"image.gif": {expectedFormat: "gif"},
+			"image":     {expectedFormat: "png"},
+			"image.bmp": {expectedFormat: "gif", options: []encoder.Option{encoder.WithFormat(encoder.GIF)}},
+		}
+		for fileName, test := range tests {
+			t.Run(fileName, func(t *testing.T) {
+				dir := tempDir(t)
+				defer os.RemoveAll(dir)
+				path := filepath.Join(dir, fileName)
+				// when
+				err := encoder.New(test.options...).EncodeFile(path, img.WholeImageSelection())
+				// then
+				require.NoError(t, err)
+				file, err := os.Open(path)
+				require.NoError(t, err)
+				defer file.Close()
+				decoded, format, err := stdimage.Decode(file)
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedFormat, format)
+				assertColors(t, [][]image.Color{
+					{red, green},
+				}, decoded)
+			})
+		}
+	})
+	t.Run("should return error when file cannot be created", func(t *testing.T) {
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+		err := encoder.New().EncodeFile(filepath.Join(dir, "missing", "image.png"), img.WholeImageSelection())
+		assert.Error(t, err)
+	})
+	t.Run("should return error for empty selection", func(t *testing.T) {
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+		err := encoder.New().EncodeFile(filepath.Join(dir, "image.png"), img.Selection(0, 0))
+		assert.Error(t, err)
+	})
+}
+
+func newImage(colors [][]image.Color) *image.Image {
+	img := image.New(fake.NewAcceleratedImage(len(colors[0]), len(colors)))
+	selection := img.WholeImageSelection()
+	for y, line := range colors {
+		for x, color := range line {
+			selection.SetColor(x, y, color)
+		}
+	}
+	return img
+}
+
+func assertColors(t *testing.T, expected [][]image.Color, actual stdimage.Image) {
+	require.Equal(t, len(expected), actual.Bounds().Dy())
+	require.Equal(t, len(expected[0]), actual.Bounds().Dx())
+	for y, line := range expected {
+		for x, color := range line {
+			assertColor(t, color, actual.At(x, y))
+		}
+	}
+}
+
+func assertColor(t *testing.T, expected image.Color, actual interface{ RGBA() (r, g, b, a uint32) }) {
+	r, g, b, a := actual.RGBA()
+	assert.Equal(t, expected, image.RGBA(byte(r>>8), byte(g>>8), byte(b>>8), byte(a>>8)))
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "encoder")
+	require.NoError(t, err)
+	return dir
+}
+
+type erroneousWriter struct{}
+
+func (erroneousWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
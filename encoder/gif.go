@@ -0,0 +1,85 @@
+package encoder
+
+import (
+	stdimage "image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"io"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+// encodeGIF encodes selection using a palette extracted from the selection.
+// When selection has more than 256 colors, the web-safe palette is used
+// instead and colors are replaced by the nearest ones. Pixels with alpha lower
+// than 128 are transparent, others are fully opaque.
+func encodeGIF(writer io.Writer, selection image.Selection, zoom int) error {
+	var (
+		width   = selection.Width()
+		height  = selection.Height()
+		indexes = make([]uint8, width*height)
+		pal     = extractPalette(selection, indexes)
+		target  = stdimage.NewPaletted(stdimage.Rect(0, 0, width*zoom, height*zoom), pal)
+	)
+	for y := 0; y < target.Rect.Dy(); y++ {
+		row := target.Pix[y*target.Stride:]
+		for x := 0; x < target.Rect.Dx(); x++ {
+			row[x] = indexes[(y/zoom)*width+x/zoom]
+		}
+	}
+	return gif.Encode(writer, target, nil)
+}
+
+// extractPalette returns a palette of selection colors and fills indexes with
+// palette index of each pixel.
+func extractPalette(selection image.Selection, indexes []uint8) color.Palette {
+	var (
+		width    = selection.Width()
+		pal      color.Palette
+		colorMap = map[color.RGBA]uint8{}
+	)
+	for y := 0; y < selection.Height(); y++ {
+		for x := 0; x < width; x++ {
+			c := gifColor(selection.Color(x, y))
+			index, ok := colorMap[c]
+			if !ok {
+				if len(pal) == 256 {
+					return nearestPalette(selection, indexes)
+				}
+				index = uint8(len(pal))
+				colorMap[c] = index
+				pal = append(pal, c)
+			}
+			indexes[y*width+x] = index
+		}
+	}
+	return pal
+}
+
+func nearestPalette(selection image.Selection, indexes []uint8) color.Palette {
+	var (
+		width = selection.Width()
+		pal   = append(color.Palette{color.RGBA{}}, palette.WebSafe...)
+	)
+	for y := 0; y < selection.Height(); y++ {
+		for x := 0; x < width; x++ {
+			c := gifColor(selection.Color(x, y))
+			index := 0
+			if c.A != 0 {
+				// skip transparent color
+				index = pal[1:].Index(c) + 1
+			}
+			indexes[y*width+x] = uint8(index)
+		}
+	}
+	return pal
+}
+
+func gifColor(c image.Color) color.RGBA {
+	if c.A() < 128 {
+		return color.RGBA{}
+	}
+	r, g, b, _ := c.NRGBA()
+	return color.RGBA{R: r, G: g, B: b, A: 255}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/elgopher/pixiq/clear"
+	"github.com/elgopher/pixiq/colornames"
+	"github.com/elgopher/pixiq/draw"
+	"github.com/elgopher/pixiq/gldraw"
+	"github.com/elgopher/pixiq/glfw"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/keyboard"
+)
+
+type drawTool interface {
+	SetColor(image.Color)
+	SetThickness(int)
+	Line(selection image.Selection, x1, y1, x2, y2 int)
+	Rectangle(selection image.Selection, x, y, width, height int)
+	FilledRectangle(selection image.Selection, x, y, width, height int)
+}
+
+func main() {
+	glfw.RunOrDie(func(openGL *glfw.OpenGL) {
+		window, err := openGL.OpenWindow(80, 40, glfw.Zoom(8))
+		if err != nil {
+			panic(err)
+		}
+		gpuTool, err := gldraw.New(openGL.Context())
+		if err != nil {
+			panic(err)
+		}
+		cpuTool := draw.New()
+		tools := []drawTool{
+			cpuTool, // CPU one
+			gpuTool, // GPU one
+		}
+		currentTool := 0
+		clearTool := clear.New()
+		keys := keyboard.New(window)
+		screen := window.Screen()
+		for {
+			clearTool.Clear(screen)
+			tool := tools[currentTool]
+			tool.SetThickness(1)
+			tool.SetColor(colornames.White)
+			tool.Line(screen, 2, 2, 30, 12)
+			tool.SetColor(colornames.Hotpink)
+			tool.Rectangle(screen, 34, 2, 12, 10)
+			tool.SetColor(colornames.Cornflowerblue)
+			tool.FilledRectangle(screen, 52, 2, 12, 10)
+			tool.SetThickness(3)
+			tool.SetColor(colornames.Orange)
+			tool.Line(screen, 4, 36, 20, 18)
+			// shapes below are supported only by the CPU tool
+			cpuTool.SetThickness(1)
+			cpuTool.SetColor(colornames.Yellow)
+			cpuTool.Ellipse(screen, 36, 28, 10, 6)
+			cpuTool.SetColor(colornames.Lightgreen)
+			cpuTool.FilledPolygon(screen, []draw.Point{
+				{X: 54, Y: 20}, {X: 68, Y: 24}, {X: 62, Y: 28}, {X: 68, Y: 36}, {X: 52, Y: 34},
+			})
+			keys.Update()
+			if keys.JustReleased(keyboard.Space) {
+				currentTool++
+				currentTool = currentTool % len(tools)
+			}
+			window.Draw()
+			if window.ShouldClose() {
+				break
+			}
+		}
+	})
+}
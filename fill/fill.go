@@ -0,0 +1,296 @@
+// Package fill provides CPU tools for filling areas of similar colors,
+// also known as flood fill or bucket fill.
+package fill
+
+import (
+	"github.com/elgopher/pixiq/image"
+)
+
+// Connectivity defines which pixels are neighbours.
+type Connectivity int
+
+const (
+	// Four means that only pixels on the left, right, top and bottom are neighbours.
+	Four Connectivity = 4
+	// Eight means that diagonal pixels are also neighbours.
+	Eight Connectivity = 8
+)
+
+// Mode defines which pixels are filled.
+type Mode int
+
+const (
+	// Contiguous fills only pixels connected with the starting pixel.
+	Contiguous Mode = iota
+	// Global fills all pixels in the selection matching the starting pixel color.
+	Global
+)
+
+// New returns new instance of *fill.Tool. By default the tool fills
+// contiguous area of exactly the same color with transparent color using
+// 4-connectivity.
+func New() *Tool {
+	return &Tool{
+		connectivity: Four,
+		mode:         Contiguous,
+	}
+}
+
+// Tool is a flood fill tool. It replaces colors of all pixels similar to the
+// starting one with the previously set color (or pattern). There is no blending.
+//
+// Tool uses CPU.
+type Tool struct {
+	color        image.Color
+	pattern      []image.Color
+	patternWidth int
+	connectivity Connectivity
+	mode         Mode
+	tolerance    int
+	// reused between Fill calls
+	visited []bool
+	stack   []point
+	rows    [][]image.Color
+}
+
+type point struct {
+	x, y int
+}
+
+// SetColor sets color which will be used for filling. It replaces the pattern
+// set by SetPattern.
+func (t *Tool) SetColor(color image.Color) {
+	t.color = color
+	t.pattern = nil
+}
+
+// SetPattern sets pattern which will be used for filling instead of a color.
+// The pattern is repeated over the whole filled selection, starting from its
+// top-left corner. Pattern colors are copied, therefore later changes to
+// the pattern selection are not used.
+//
+// Will panic when pattern has zero width or height.
+func (t *Tool) SetPattern(pattern image.Selection) {
+	if pattern.Width() <= 0 {
+		panic("pattern width is not positive")
+	}
+	if pattern.Height() <= 0 {
+		panic("pattern height is not positive")
+	}
+	t.patternWidth = pattern.Width()
+	t.pattern = make([]image.Color, 0, pattern.Width()*pattern.Height())
+	for y := 0; y < pattern.Height(); y++ {
+		for x := 0; x < pattern.Width(); x++ {
+			t.pattern = append(t.pattern, pattern.Color(x, y))
+		}
+	}
+}
+
+// SetConnectivity sets which pixels are neighbours. Will panic for connectivity
+// other than Four or Eight.
+func (t *Tool) SetConnectivity(connectivity Connectivity) {
+	if connectivity != Four && connectivity != Eight {
+		panic("connectivity must be Four or Eight")
+	}
+	t.connectivity = connectivity
+}
+
+// SetMode sets whether only contiguous area is filled or all matching pixels
+// in the selection.
+func (t *Tool) SetMode(mode Mode) {
+	if mode != Contiguous && mode != Global {
+		panic("mode must be Contiguous or Global")
+	}
+	t.mode = mode
+}
+
+// SetTolerance sets how much colors can differ from the starting pixel color
+// to be filled. Tolerance is the maximum difference of each RGBA component.
+// 0 means that only exactly the same color is filled, 255 fills everything.
+//
+// Will panic when tolerance is negative.
+func (t *Tool) SetTolerance(tolerance int) {
+	if tolerance < 0 {
+		panic("negative tolerance")
+	}
+	t.tolerance = tolerance
+}
+
+// Fill fills the area starting at pixel (x,y). Coordinates are local to the
+// selection. Only pixels inside the selection (and the image) are filled.
+// Nothing is filled when starting pixel is outside the selection.
+func (t *Tool) Fill(selection image.Selection, x, y int) {
+	if x < 0 || y < 0 || x >= selection.Width() || y >= selection.Height() {
+		return
+	}
+	lines := selection.Lines()
+	if lines.Length() == 0 {
+		return
+	}
+	x -= lines.XOffset()
+	y -= lines.YOffset()
+	width := len(lines.LineForRead(0))
+	if x < 0 || y < 0 || x >= width || y >= lines.Length() {
+		return
+	}
+	t.rows = t.rows[:0]
+	for i := 0; i < lines.Length(); i++ {
+		t.rows = append(t.rows, lines.LineForRead(i))
+	}
+	area := area{
+		lines:  lines,
+		rows:   t.rows,
+		width:  width,
+		height: lines.Length(),
+	}
+	target := area.rows[y][x]
+	if t.mode == Global {
+		t.fillGlobal(area, target)
+		return
+	}
+	t.fillContiguous(area, target, x, y)
+}
+
+// area is a part of selection containing real pixels
+type area struct {
+	lines         image.Lines
+	rows          [][]image.Color
+	width, height int
+}
+
+func (t *Tool) fillGlobal(area area, target image.Color) {
+	for y := 0; y < area.height; y++ {
+		row := area.rows[y]
+		for x := 0; x < area.width; {
+			if !t.matches(row[x], target) {
+				x++
+				continue
+			}
+			start := x
+			for x < area.width && t.matches(row[x], target) {
+				x++
+			}
+			t.fillSpan(area, start, x-1, y)
+		}
+	}
+}
+
+func (t *Tool) fillContiguous(area area, target image.Color, x, y int) {
+	t.resetVisited(area.width * area.height)
+	t.stack = append(t.stack[:0], point{x: x, y: y})
+	for len(t.stack) > 0 {
+		p := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		if !t.fillable(area, target, p.x, p.y) {
+			continue
+		}
+		left := p.x
+		for left > 0 && t.fillable(area, target, left-1, p.y) {
+			left--
+		}
+		right := p.x
+		for right < area.width-1 && t.fillable(area, target, right+1, p.y) {
+			right++
+		}
+		for i := left; i <= right; i++ {
+			t.visited[p.y*area.width+i] = true
+		}
+		t.fillSpan(area, left, right, p.y)
+		from, to := left, right
+		if t.connectivity == Eight {
+			from--
+			to++
+		}
+		t.pushSeeds(area, target, from, to, p.y-1)
+		t.pushSeeds(area, target, from, to, p.y+1)
+	}
+}
+
+func (t *Tool) resetVisited(size int) {
+	if cap(t.visited) < size {
+		t.visited = make([]bool, size)
+		return
+	}
+	t.visited = t.visited[:size]
+	for i := range t.visited {
+		t.visited[i] = false
+	}
+}
+
+// pushSeeds pushes one seed for each run of fillable pixels in line y between
+// from and to (inclusive).
+func (t *Tool) pushSeeds(area area, target image.Color, from, to, y int) {
+	if y < 0 || y >= area.height {
+		return
+	}
+	if from < 0 {
+		from = 0
+	}
+	if to >= area.width {
+		to = area.width - 1
+	}
+	inRun := false
+	for x := from; x <= to; x++ {
+		if !t.fillable(area, target, x, y) {
+			inRun = false
+			continue
+		}
+		if !inRun {
+			t.stack = append(t.stack, point{x: x, y: y})
+			inRun = true
+		}
+	}
+}
+
+func (t *Tool) fillable(area area, target image.Color, x, y int) bool {
+	if t.visited[y*area.width+x] {
+		return false
+	}
+	return t.matches(area.rows[y][x], target)
+}
+
+func (t *Tool) matches(color, target image.Color) bool {
+	if t.tolerance == 0 {
+		return color == target
+	}
+	r1, g1, b1, a1 := color.RGBAi()
+	r2, g2, b2, a2 := target.RGBAi()
+	return abs(r1-r2) <= t.tolerance &&
+		abs(g1-g2) <= t.tolerance &&
+		abs(b1-b2) <= t.tolerance &&
+		abs(a1-a2) <= t.tolerance
+}
+
+// fillSpan fills pixels from x1 to x2 (inclusive) in line y. Coordinates are
+// local to the area.
+func (t *Tool) fillSpan(area area, x1, x2, y int) {
+	line := area.lines.LineForWrite(y)
+	if t.pattern == nil {
+		for x := x1; x <= x2; x++ {
+			line[x] = t.color
+		}
+		return
+	}
+	patternHeight := len(t.pattern) / t.patternWidth
+	// pattern starts at the top-left corner of the selection
+	patternY := mod(y+area.lines.YOffset(), patternHeight)
+	patternLine := t.pattern[patternY*t.patternWidth : (patternY+1)*t.patternWidth]
+	for x := x1; x <= x2; x++ {
+		line[x] = patternLine[mod(x+area.lines.XOffset(), t.patternWidth)]
+	}
+}
+
+func mod(a, b int) int {
+	m := a % b
+	if m < 0 {
+		m += b
+	}
+	return m
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
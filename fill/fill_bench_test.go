@@ -0,0 +1,24 @@
+package fill_test
+
+import (
+	"testing"
+
+	"github.com/elgopher/pixiq/fill"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+)
+
+func BenchmarkTool_Fill(b *testing.B) {
+	var (
+		img       = image.New(fake.NewAcceleratedImage(1920, 1080))
+		selection = img.WholeImageSelection()
+		tool      = fill.New()
+		colors    = []image.Color{image.RGB(1, 2, 3), image.RGB(4, 5, 6)}
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tool.SetColor(colors[i%2])
+		tool.Fill(selection, 960, 540)
+	}
+}
@@ -0,0 +1,449 @@
+package fill_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/fill"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+)
+
+// colors used in images defined as strings
+var colors = map[byte]image.Color{
+	'.': image.Transparent,
+	'a': image.RGB(10, 10, 10),
+	'b': image.RGB(12, 11, 10),
+	'c': image.RGB(100, 100, 100),
+	'#': image.RGB(255, 255, 255),
+	'x': image.RGB(0, 0, 255),
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should create tool", func(t *testing.T) {
+		tool := fill.New()
+		assert.NotNil(t, tool)
+	})
+}
+
+func TestTool_SetConnectivity(t *testing.T) {
+	t.Run("should panic for invalid connectivity", func(t *testing.T) {
+		tool := fill.New()
+		assert.Panics(t, func() {
+			tool.SetConnectivity(6)
+		})
+	})
+}
+
+func TestTool_SetMode(t *testing.T) {
+	t.Run("should panic for invalid mode", func(t *testing.T) {
+		tool := fill.New()
+		assert.Panics(t, func() {
+			tool.SetMode(-1)
+		})
+	})
+}
+
+func TestTool_SetTolerance(t *testing.T) {
+	t.Run("should panic for negative tolerance", func(t *testing.T) {
+		tool := fill.New()
+		assert.Panics(t, func() {
+			tool.SetTolerance(-1)
+		})
+	})
+}
+
+func TestTool_SetPattern(t *testing.T) {
+	t.Run("should panic for empty pattern", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(2, 2))
+		tests := map[string]image.Selection{
+			"zero width":  img.Selection(0, 0).WithSize(0, 1),
+			"zero height": img.Selection(0, 0).WithSize(1, 0),
+		}
+		for name, pattern := range tests {
+			t.Run(name, func(t *testing.T) {
+				tool := fill.New()
+				assert.Panics(t, func() {
+					tool.SetPattern(pattern)
+				})
+			})
+		}
+	})
+}
+
+func TestTool_Fill(t *testing.T) {
+	t.Run("should fill area", func(t *testing.T) {
+		tests := map[string]struct {
+			given        []string
+			x, y         int
+			connectivity fill.Connectivity
+			mode         fill.Mode
+			tolerance    int
+			expected     []string
+		}{
+			"single pixel": {
+				given: []string{
+					".a.",
+				},
+				x: 1, y: 0,
+				connectivity: fill.Four,
+				expected: []string{
+					".x.",
+				},
+			},
+			"whole image": {
+				given: []string{
+					"...",
+					"...",
+				},
+				x: 2, y: 1,
+				connectivity: fill.Four,
+				expected: []string{
+					"xxx",
+					"xxx",
+				},
+			},
+			"area enclosed by wall": {
+				given: []string{
+					"..#..",
+					".#.#.",
+					"#...#",
+					".#.#.",
+					"..#..",
+				},
+				x: 2, y: 2,
+				connectivity: fill.Four,
+				expected: []string{
+					"..#..",
+					".#x#.",
+					"#xxx#",
+					".#x#.",
+					"..#..",
+				},
+			},
+			"concave area": {
+				given: []string{
+					".....",
+					".###.",
+					".#.#.",
+					"##.#.",
+					"...#.",
+				},
+				x: 0, y: 0,
+				connectivity: fill.Four,
+				expected: []string{
+					"xxxxx",
+					"x###x",
+					"x#.#x",
+					"##.#x",
+					"...#x",
+				},
+			},
+			"4-connectivity does not leak through diagonal": {
+				given: []string{
+					"..#",
+					".#.",
+					"#..",
+				},
+				x: 0, y: 0,
+				connectivity: fill.Four,
+				expected: []string{
+					"xx#",
+					"x#.",
+					"#..",
+				},
+			},
+			"8-connectivity leaks through diagonal": {
+				given: []string{
+					"..#",
+					".#.",
+					"#..",
+				},
+				x: 0, y: 0,
+				connectivity: fill.Eight,
+				expected: []string{
+					"xx#",
+					"x#x",
+					"#xx",
+				},
+			},
+			"8-connectivity connects diagonal pixels": {
+				given: []string{
+					"a..",
+					".a.",
+					"..a",
+				},
+				x: 0, y: 0,
+				connectivity: fill.Eight,
+				expected: []string{
+					"x..",
+					".x.",
+					"..x",
+				},
+			},
+			"no tolerance": {
+				given: []string{
+					"aab",
+				},
+				x: 0, y: 0,
+				connectivity: fill.Four,
+				tolerance:    0,
+				expected: []string{
+					"xxb",
+				},
+			},
+			"tolerance": {
+				given: []string{
+					"abc",
+				},
+				x: 0, y: 0,
+				connectivity: fill.Four,
+				tolerance:    2,
+				expected: []string{
+					"xxc",
+				},
+			},
+			"tolerance lower than difference": {
+				given: []string{
+					"abc",
+				},
+				x: 0, y: 0,
+				connectivity: fill.Four,
+				tolerance:    1,
+				expected: []string{
+					"xbc",
+				},
+			},
+			"global mode": {
+				given: []string{
+					"a#a",
+					"###",
+					"a#b",
+				},
+				x: 0, y: 0,
+				connectivity: fill.Four,
+				mode:         fill.Global,
+				expected: []string{
+					"x#x",
+					"###",
+					"x#b",
+				},
+			},
+			"global mode with tolerance": {
+				given: []string{
+					"a#b",
+					"###",
+					"c#a",
+				},
+				x: 0, y: 0,
+				connectivity: fill.Four,
+				mode:         fill.Global,
+				tolerance:    2,
+				expected: []string{
+					"x#x",
+					"###",
+					"c#x",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := newImage(test.given)
+				tool := fill.New()
+				tool.SetColor(colors['x'])
+				tool.SetConnectivity(test.connectivity)
+				tool.SetMode(test.mode)
+				tool.SetTolerance(test.tolerance)
+				// when
+				tool.Fill(img.WholeImageSelection(), test.x, test.y)
+				// then
+				assertImage(t, test.expected, img)
+			})
+		}
+	})
+	t.Run("should not fill anything when starting point is outside selection", func(t *testing.T) {
+		tests := map[string]struct {
+			x, y int
+		}{
+			"left":   {x: -1, y: 0},
+			"top":    {x: 0, y: -1},
+			"right":  {x: 2, y: 0},
+			"bottom": {x: 0, y: 2},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := newImage([]string{
+					"....",
+					"....",
+					"....",
+				})
+				tool := fill.New()
+				tool.SetColor(colors['x'])
+				// when
+				tool.Fill(img.Selection(1, 1).WithSize(2, 2), test.x, test.y)
+				// then
+				assertImage(t, []string{
+					"....",
+					"....",
+					"....",
+				}, img)
+			})
+		}
+	})
+	t.Run("should not fill anything when starting point is outside image", func(t *testing.T) {
+		img := newImage([]string{
+			"..",
+		})
+		tool := fill.New()
+		tool.SetColor(colors['x'])
+		// when
+		tool.Fill(img.Selection(-1, 0).WithSize(3, 1), 0, 0)
+		// then
+		assertImage(t, []string{
+			"..",
+		}, img)
+	})
+	t.Run("should fill only pixels inside selection", func(t *testing.T) {
+		img := newImage([]string{
+			"....",
+			"....",
+			"....",
+		})
+		tool := fill.New()
+		tool.SetColor(colors['x'])
+		// when
+		tool.Fill(img.Selection(1, 1).WithSize(5, 5), 0, 0)
+		// then
+		assertImage(t, []string{
+			"....",
+			".xxx",
+			".xxx",
+		}, img)
+	})
+	t.Run("should fill when selection starts outside image", func(t *testing.T) {
+		img := newImage([]string{
+			"...",
+			".#.",
+			"...",
+		})
+		tool := fill.New()
+		tool.SetColor(colors['x'])
+		// when
+		tool.Fill(img.Selection(-1, -1).WithSize(3, 3), 1, 1)
+		// then
+		assertImage(t, []string{
+			"xx.",
+			"x#.",
+			"...",
+		}, img)
+	})
+	t.Run("should fill when new color is within tolerance", func(t *testing.T) {
+		img := newImage([]string{
+			"a.",
+		})
+		tool := fill.New()
+		tool.SetColor(colors['b'])
+		tool.SetTolerance(10)
+		// when
+		tool.Fill(img.WholeImageSelection(), 0, 0)
+		// then
+		assertImage(t, []string{
+			"b.",
+		}, img)
+	})
+	t.Run("should fill with pattern", func(t *testing.T) {
+		img := newImage([]string{
+			"......",
+			".####.",
+			"......",
+			"......",
+		})
+		pattern := newImage([]string{
+			"ab",
+			"ca",
+		})
+		tool := fill.New()
+		tool.SetPattern(pattern.WholeImageSelection())
+		// when
+		tool.Fill(img.WholeImageSelection(), 0, 0)
+		// then
+		assertImage(t, []string{
+			"ababab",
+			"c####a",
+			"ababab",
+			"cacaca",
+		}, img)
+	})
+	t.Run("should align pattern to selection", func(t *testing.T) {
+		img := newImage([]string{
+			"....",
+			"....",
+		})
+		pattern := newImage([]string{
+			"ab",
+		})
+		tool := fill.New()
+		tool.SetPattern(pattern.WholeImageSelection())
+		// when
+		tool.Fill(img.Selection(-1, 1).WithSize(5, 1), 1, 0)
+		// then
+		assertImage(t, []string{
+			"....",
+			"baba",
+		}, img)
+	})
+	t.Run("should fill with color after pattern was used", func(t *testing.T) {
+		img := newImage([]string{
+			"..",
+		})
+		pattern := newImage([]string{
+			"ab",
+		})
+		tool := fill.New()
+		tool.SetPattern(pattern.WholeImageSelection())
+		tool.SetColor(colors['x'])
+		// when
+		tool.Fill(img.WholeImageSelection(), 0, 0)
+		// then
+		assertImage(t, []string{
+			"xx",
+		}, img)
+	})
+	t.Run("should fill many times using the same tool", func(t *testing.T) {
+		img := newImage([]string{
+			".#.",
+			".#.",
+		})
+		tool := fill.New()
+		tool.SetColor(colors['x'])
+		// when
+		tool.Fill(img.WholeImageSelection(), 0, 0)
+		tool.Fill(img.WholeImageSelection(), 2, 1)
+		// then
+		assertImage(t, []string{
+			"x#x",
+			"x#x",
+		}, img)
+	})
+}
+
+func newImage(lines []string) *image.Image {
+	img := image.New(fake.NewAcceleratedImage(len(lines[0]), len(lines)))
+	selection := img.WholeImageSelection()
+	for y, line := range lines {
+		for x := 0; x < len(line); x++ {
+			selection.SetColor(x, y, colors[line[x]])
+		}
+	}
+	return img
+}
+
+func assertImage(t *testing.T, expected []string, img *image.Image) {
+	selection := img.WholeImageSelection()
+	for y, line := range expected {
+		for x := 0; x < len(line); x++ {
+			assert.Equal(t, colors[line[x]], selection.Color(x, y), "position(%d,%d)", x, y)
+		}
+	}
+}
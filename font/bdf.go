@@ -0,0 +1,277 @@
+package font
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+// ImageFactory creates a new image with given dimensions.
+//
+// *glfw.OpenGL instance can be used as an ImageFactory implementation.
+type ImageFactory interface {
+	NewImage(width, height int) *image.Image
+}
+
+// NewBDFDecoder creates a BDFDecoder instance which can be used many times for
+// decoding fonts in Glyph Bitmap Distribution Format (BDF).
+func NewBDFDecoder(imageFactory ImageFactory) *BDFDecoder {
+	if imageFactory == nil {
+		panic("nil imageFactory")
+	}
+	return &BDFDecoder{imageFactory: imageFactory}
+}
+
+// BDFDecoder decodes fonts in Glyph Bitmap Distribution Format (BDF).
+type BDFDecoder struct {
+	imageFactory ImageFactory
+}
+
+// bdfBox is a bounding box. x and y are offsets of the bottom-left corner
+// relative to the pen position on the baseline (y grows up).
+type bdfBox struct {
+	width, height, x, y int
+}
+
+type bdfGlyph struct {
+	encoding int
+	advance  int
+	box      bdfBox
+	rows     []string
+}
+
+// Decode decodes BDF font and creates a new *Font. All glyphs are stored
+// in a single image created by ImageFactory. Set pixels are white, all other
+// pixels are transparent. Glyphs without encoding (ENCODING -1) are skipped.
+func (d *BDFDecoder) Decode(reader io.Reader) (*Font, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	var (
+		scanner        = bufio.NewScanner(reader)
+		fontBox        bdfBox
+		fontAdvance    int
+		ascent         = -1
+		descent        = -1
+		glyphs         []bdfGlyph
+		current        *bdfGlyph
+		inBitmap       bool
+		startFontFound bool
+	)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		keyword, args := fields[0], fields[1:]
+		if inBitmap {
+			if keyword == "ENDCHAR" {
+				inBitmap = false
+				glyphs = append(glyphs, *current)
+				current = nil
+				continue
+			}
+			current.rows = append(current.rows, keyword)
+			continue
+		}
+		var err error
+		switch keyword {
+		case "STARTFONT":
+			startFontFound = true
+		case "FONTBOUNDINGBOX":
+			fontBox, err = parseBox(args)
+		case "FONT_ASCENT":
+			ascent, err = parseInt(args)
+		case "FONT_DESCENT":
+			descent, err = parseInt(args)
+		case "DWIDTH":
+			var advance int
+			advance, err = parseInt(args)
+			if current != nil {
+				current.advance = advance
+			} else {
+				fontAdvance = advance
+			}
+		case "STARTCHAR":
+			current = &bdfGlyph{advance: fontAdvance, box: fontBox}
+		case "ENCODING":
+			if current != nil {
+				current.encoding, err = parseInt(args)
+			}
+		case "BBX":
+			if current != nil {
+				current.box, err = parseBox(args)
+			}
+		case "BITMAP":
+			if current == nil {
+				return nil, errors.New("BITMAP outside STARTCHAR")
+			}
+			inBitmap = true
+		case "ENDCHAR":
+			if current != nil {
+				glyphs = append(glyphs, *current)
+				current = nil
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", keyword, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !startFontFound {
+		return nil, errors.New("not a BDF font: missing STARTFONT")
+	}
+	if ascent < 0 {
+		ascent = fontBox.height + fontBox.y
+	}
+	if descent < 0 {
+		descent = -fontBox.y
+	}
+	lineHeight := ascent + descent
+	if lineHeight <= 0 {
+		return nil, errors.New("font height is not positive")
+	}
+	return d.newFont(glyphs, lineHeight, ascent)
+}
+
+// DecodeFile decodes BDF font file and creates a new *Font.
+func (d *BDFDecoder) DecodeFile(fileName string) (*Font, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return d.Decode(file)
+}
+
+// newFont draws all glyphs into a single image. Glyphs are placed in rows
+// (shelves) of the same maximum width, which is chosen so that the image is
+// roughly square. Thanks to that fonts with thousands of glyphs still fit into
+// the maximum texture size.
+func (d *BDFDecoder) newFont(glyphs []bdfGlyph, lineHeight, ascent int) (*Font, error) {
+	encoded := make([]bdfGlyph, 0, len(glyphs))
+	area, widest := 0, 0
+	for _, glyph := range glyphs {
+		if glyph.encoding < 0 {
+			continue
+		}
+		if glyph.box.width < 0 || glyph.box.height < 0 {
+			return nil, fmt.Errorf("negative BBX size of glyph %d", glyph.encoding)
+		}
+		encoded = append(encoded, glyph)
+		area += glyph.box.width * glyph.box.height
+		if glyph.box.width > widest {
+			widest = glyph.box.width
+		}
+	}
+	shelfWidth := 1
+	for shelfWidth < widest || shelfWidth*shelfWidth < area {
+		shelfWidth *= 2
+	}
+	positions, imageWidth, imageHeight := placeGlyphs(encoded, shelfWidth)
+	img := d.imageFactory.NewImage(imageWidth, imageHeight)
+	selection := img.WholeImageSelection()
+	font := New(lineHeight)
+	for i, glyph := range encoded {
+		position := positions[i]
+		glyphSelection := selection.Selection(position.x, position.y).
+			WithSize(glyph.box.width, glyph.box.height)
+		if err := drawBitmap(glyphSelection, glyph.rows); err != nil {
+			return nil, fmt.Errorf("invalid BITMAP of glyph %d: %s", glyph.encoding, err)
+		}
+		font.SetGlyph(rune(glyph.encoding), Glyph{
+			Selection: glyphSelection,
+			OffsetX:   glyph.box.x,
+			OffsetY:   ascent - glyph.box.y - glyph.box.height,
+			Advance:   glyph.advance,
+		})
+	}
+	return font, nil
+}
+
+type glyphPosition struct {
+	x, y int
+}
+
+// placeGlyphs places glyphs in rows not wider than maxWidth. Returns positions
+// of glyphs and the size of the image, which is at least 1x1.
+func placeGlyphs(glyphs []bdfGlyph, maxWidth int) (positions []glyphPosition, width, height int) {
+	positions = make([]glyphPosition, len(glyphs))
+	x, y, rowHeight := 0, 0, 0
+	width = 1
+	for i, glyph := range glyphs {
+		if x > 0 && x+glyph.box.width > maxWidth {
+			x = 0
+			y += rowHeight
+			rowHeight = 0
+		}
+		positions[i] = glyphPosition{x: x, y: y}
+		x += glyph.box.width
+		if x > width {
+			width = x
+		}
+		if glyph.box.height > rowHeight {
+			rowHeight = glyph.box.height
+		}
+	}
+	height = y + rowHeight
+	if height < 1 {
+		height = 1
+	}
+	return positions, width, height
+}
+
+var white = image.RGB(255, 255, 255)
+
+func drawBitmap(selection image.Selection, rows []string) error {
+	if len(rows) < selection.Height() {
+		return errors.New("too few rows")
+	}
+	for y := 0; y < selection.Height(); y++ {
+		row := rows[y]
+		for x := 0; x < selection.Width(); x++ {
+			digitIndex := x / 4
+			if digitIndex >= len(row) {
+				return errors.New("too short row")
+			}
+			digit, err := strconv.ParseUint(row[digitIndex:digitIndex+1], 16, 8)
+			if err != nil {
+				return err
+			}
+			if digit&(8>>uint(x%4)) != 0 {
+				selection.SetColor(x, y, white)
+			}
+		}
+	}
+	return nil
+}
+
+func parseInt(args []string) (int, error) {
+	if len(args) < 1 {
+		return 0, errors.New("missing argument")
+	}
+	return strconv.Atoi(args[0])
+}
+
+func parseBox(args []string) (bdfBox, error) {
+	if len(args) < 4 {
+		return bdfBox{}, errors.New("4 arguments required")
+	}
+	var values [4]int
+	for i := range values {
+		v, err := strconv.Atoi(args[i])
+		if err != nil {
+			return bdfBox{}, err
+		}
+		values[i] = v
+	}
+	return bdfBox{width: values[0], height: values[1], x: values[2], y: values[3]}, nil
+}
@@ -0,0 +1,215 @@
+package font_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/font"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+)
+
+const bdf = `STARTFONT 2.1
+FONT -test-fixed-medium-r-normal--4-40-75-75-c-40-iso10646-1
+SIZE 4 75 75
+FONTBOUNDINGBOX 3 4 0 -1
+STARTPROPERTIES 2
+FONT_ASCENT 3
+FONT_DESCENT 1
+ENDPROPERTIES
+CHARS 3
+STARTCHAR A
+ENCODING 65
+SWIDTH 500 0
+DWIDTH 4 0
+BBX 3 3 0 0
+BITMAP
+40
+A0
+E0
+ENDCHAR
+STARTCHAR comma
+ENCODING 44
+DWIDTH 2 0
+BBX 1 2 1 -1
+BITMAP
+80
+80
+ENDCHAR
+STARTCHAR unencoded
+ENCODING -1
+DWIDTH 4 0
+BBX 3 4 0 -1
+BITMAP
+E0
+E0
+E0
+E0
+ENDCHAR
+ENDFONT
+`
+
+func TestNewBDFDecoder(t *testing.T) {
+	t.Run("should panic for nil ImageFactory", func(t *testing.T) {
+		assert.Panics(t, func() {
+			font.NewBDFDecoder(nil)
+		})
+	})
+}
+
+func TestBDFDecoder_Decode(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		decoder := font.NewBDFDecoder(fakeImageFactory{})
+		assert.Panics(t, func() {
+			_, _ = decoder.Decode(nil)
+		})
+	})
+	t.Run("should return error", func(t *testing.T) {
+		tests := map[string]string{
+			"empty":           "",
+			"not BDF":         "hello",
+			"invalid BBX":     "STARTFONT 2.1\nFONTBOUNDINGBOX 1 1 0\n",
+			"zero height":     "STARTFONT 2.1\nFONTBOUNDINGBOX 1 0 0 0\n",
+			"invalid bitmap":  "STARTFONT 2.1\nFONTBOUNDINGBOX 4 1 0 0\nSTARTCHAR a\nENCODING 97\nBITMAP\nZZ\nENDCHAR\n",
+			"too few rows":    "STARTFONT 2.1\nFONTBOUNDINGBOX 4 2 0 0\nSTARTCHAR a\nENCODING 97\nBITMAP\nFF\nENDCHAR\n",
+			"bitmap w/o char": "STARTFONT 2.1\nFONTBOUNDINGBOX 4 2 0 0\nBITMAP\n",
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				decoder := font.NewBDFDecoder(fakeImageFactory{})
+				// when
+				f, err := decoder.Decode(strings.NewReader(data))
+				// then
+				assert.Error(t, err)
+				assert.Nil(t, f)
+			})
+		}
+	})
+	t.Run("should decode font", func(t *testing.T) {
+		decoder := font.NewBDFDecoder(fakeImageFactory{})
+		// when
+		f, err := decoder.Decode(strings.NewReader(bdf))
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, f)
+		assert.Equal(t, 4, f.LineHeight())
+		// and
+		a, ok := f.Glyph('A')
+		require.True(t, ok)
+		assert.Equal(t, 4, a.Advance)
+		assert.Equal(t, 0, a.OffsetX)
+		assert.Equal(t, 0, a.OffsetY)
+		assertGlyph(t, []string{
+			".#.",
+			"#.#",
+			"###",
+		}, a)
+		// and
+		comma, ok := f.Glyph(',')
+		require.True(t, ok)
+		assert.Equal(t, 2, comma.Advance)
+		assert.Equal(t, 1, comma.OffsetX)
+		assert.Equal(t, 2, comma.OffsetY)
+		assertGlyph(t, []string{
+			"#",
+			"#",
+		}, comma)
+	})
+	t.Run("should use font bounding box when properties are missing", func(t *testing.T) {
+		decoder := font.NewBDFDecoder(fakeImageFactory{})
+		data := "STARTFONT 2.1\nFONTBOUNDINGBOX 2 5 0 -2\nDWIDTH 3 0\nSTARTCHAR a\nENCODING 97\nBITMAP\n00\n00\n00\n00\nC0\nENDCHAR\nENDFONT\n"
+		// when
+		f, err := decoder.Decode(strings.NewReader(data))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 5, f.LineHeight())
+		a, ok := f.Glyph('a')
+		require.True(t, ok)
+		assert.Equal(t, 3, a.Advance)
+		assert.Equal(t, 0, a.OffsetY)
+		assertGlyph(t, []string{
+			"..",
+			"..",
+			"..",
+			"..",
+			"##",
+		}, a)
+	})
+	t.Run("should place glyphs in image not bigger than maximum texture size", func(t *testing.T) {
+		const glyphCount = 4000
+		factory := &recordingImageFactory{}
+		decoder := font.NewBDFDecoder(factory)
+		// when
+		f, err := decoder.Decode(strings.NewReader(manyGlyphsBDF(glyphCount)))
+		// then
+		require.NoError(t, err)
+		// 1024 is the minimum MAX_TEXTURE_SIZE required by OpenGL 3.3
+		assert.LessOrEqual(t, factory.width, 1024)
+		assert.LessOrEqual(t, factory.height, 1024)
+		// and
+		for _, r := range []rune{0, glyphCount / 2, glyphCount - 1} {
+			glyph, ok := f.Glyph(r)
+			require.True(t, ok)
+			assertGlyph(t, []string{
+				"#.......",
+				".#......",
+				"........",
+			}, glyph)
+		}
+	})
+}
+
+func TestBDFDecoder_DecodeFile(t *testing.T) {
+	t.Run("should return error when file does not exist", func(t *testing.T) {
+		decoder := font.NewBDFDecoder(fakeImageFactory{})
+		f, err := decoder.DecodeFile("missing.bdf")
+		assert.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
+
+func assertGlyph(t *testing.T, expected []string, glyph font.Glyph) {
+	require.Equal(t, len(expected), glyph.Selection.Height())
+	require.Equal(t, len(expected[0]), glyph.Selection.Width())
+	for y, line := range expected {
+		for x := 0; x < len(line); x++ {
+			expectedColor := image.Transparent
+			if line[x] == '#' {
+				expectedColor = image.RGB(255, 255, 255)
+			}
+			assert.Equal(t, expectedColor, glyph.Selection.Color(x, y), "position(%d,%d)", x, y)
+		}
+	}
+}
+
+// manyGlyphsBDF returns BDF font with glyphs of size 8x3 encoded from 0
+// to count-1
+func manyGlyphsBDF(count int) string {
+	var builder strings.Builder
+	builder.WriteString("STARTFONT 2.1\nFONTBOUNDINGBOX 8 3 0 0\n")
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&builder, "STARTCHAR g%d\nENCODING %d\nDWIDTH 8 0\nBBX 8 3 0 0\nBITMAP\n80\n40\n00\nENDCHAR\n", i, i)
+	}
+	builder.WriteString("ENDFONT\n")
+	return builder.String()
+}
+
+// recordingImageFactory records the size of the last created image
+type recordingImageFactory struct {
+	width, height int
+}
+
+func (i *recordingImageFactory) NewImage(width, height int) *image.Image {
+	i.width, i.height = width, height
+	return image.New(fake.NewAcceleratedImage(width, height))
+}
+
+type fakeImageFactory struct{}
+
+func (i fakeImageFactory) NewImage(width, height int) *image.Image {
+	return image.New(fake.NewAcceleratedImage(width, height))
+}
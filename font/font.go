@@ -0,0 +1,114 @@
+// Package font provides bitmap (pixel) fonts. Font can be created from a sprite
+// sheet image or decoded from a BDF file:
+//
+//	glyphs := font.GridGlyphMap(8, 8, 16, " !\"#$%&'()*+,-./0123456789")
+//	f := font.NewFromSpriteSheet(sheet.WholeImageSelection(), glyphs)
+//
+// Text can be drawn using the text package.
+package font
+
+// New creates an empty Font with given line height. Glyphs can be added using
+// SetGlyph. Will panic when lineHeight is not positive.
+func New(lineHeight int) *Font {
+	if lineHeight <= 0 {
+		panic("lineHeight is not positive")
+	}
+	return &Font{
+		lineHeight: lineHeight,
+		glyphs:     map[rune]Glyph{},
+		kerning:    map[runePair]int{},
+	}
+}
+
+// Font is a collection of glyphs. Each glyph is an image selection. Non
+// transparent pixels of the glyph are drawn, usually tinted with a text color.
+// Therefore glyphs are usually white.
+type Font struct {
+	lineHeight  int
+	glyphs      map[rune]Glyph
+	kerning     map[runePair]int
+	fallback    rune
+	hasFallback bool
+}
+
+type runePair struct {
+	left, right rune
+}
+
+// LineHeight returns the distance in pixels between the tops of two consecutive
+// lines.
+func (f *Font) LineHeight() int {
+	return f.lineHeight
+}
+
+// SetGlyph adds or replaces the glyph for given rune.
+func (f *Font) SetGlyph(r rune, glyph Glyph) {
+	f.glyphs[r] = glyph
+}
+
+// Glyph returns glyph for given rune. If the font does not have a glyph for
+// this rune, the fallback glyph is returned (see SetFallback). False is
+// returned when neither glyph nor fallback glyph exist.
+func (f *Font) Glyph(r rune) (Glyph, bool) {
+	glyph, ok := f.glyphs[r]
+	if ok {
+		return glyph, true
+	}
+	if f.hasFallback {
+		glyph, ok = f.glyphs[f.fallback]
+	}
+	return glyph, ok
+}
+
+// SetFallback sets the rune which glyph will be used for runes not available
+// in the font, for example '?'.
+func (f *Font) SetFallback(r rune) {
+	f.fallback = r
+	f.hasFallback = true
+}
+
+// SetKerning sets the adjustment of space between two consecutive runes. Negative
+// adjustment moves the right rune closer to the left one, positive moves it
+// further away.
+func (f *Font) SetKerning(left, right rune, adjustment int) {
+	pair := runePair{left: left, right: right}
+	if adjustment == 0 {
+		delete(f.kerning, pair)
+		return
+	}
+	f.kerning[pair] = adjustment
+}
+
+// Kerning returns the adjustment of space between two consecutive runes. 0 is
+// returned when no kerning was set for this pair.
+func (f *Font) Kerning(left, right rune) int {
+	return f.kerning[runePair{left: left, right: right}]
+}
+
+// Advance returns the horizontal distance in pixels between the pen position
+// before and after drawing the rune r which is followed by the rune next. Kerning
+// is taken into account. Use -1 as next when r is the last rune in a line.
+func (f *Font) Advance(r, next rune) int {
+	glyph, ok := f.Glyph(r)
+	if !ok {
+		return 0
+	}
+	return glyph.Advance + f.Kerning(r, next)
+}
+
+// Width returns the width in pixels of a single line of text. New line characters
+// are not interpreted.
+func (f *Font) Width(line string) int {
+	width := 0
+	prev := rune(-1)
+	for _, r := range line {
+		if prev != -1 {
+			width += f.Advance(prev, r)
+		}
+		prev = r
+	}
+	if prev != -1 {
+		width += f.Advance(prev, -1)
+	}
+	return width
+}
@@ -0,0 +1,154 @@
+package font_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/font"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should panic when line height is not positive", func(t *testing.T) {
+		tests := map[string]int{
+			"zero":     0,
+			"negative": -1,
+		}
+		for name, lineHeight := range tests {
+			t.Run(name, func(t *testing.T) {
+				assert.Panics(t, func() {
+					font.New(lineHeight)
+				})
+			})
+		}
+	})
+	t.Run("should create font", func(t *testing.T) {
+		f := font.New(8)
+		require.NotNil(t, f)
+		assert.Equal(t, 8, f.LineHeight())
+	})
+}
+
+func TestFont_Glyph(t *testing.T) {
+	t.Run("should return false when glyph is missing", func(t *testing.T) {
+		f := font.New(1)
+		_, ok := f.Glyph('a')
+		assert.False(t, ok)
+	})
+	t.Run("should return glyph", func(t *testing.T) {
+		f := font.New(1)
+		glyph := font.Glyph{Advance: 3, OffsetX: 1, OffsetY: 2}
+		f.SetGlyph('a', glyph)
+		// when
+		actual, ok := f.Glyph('a')
+		// then
+		assert.True(t, ok)
+		assert.Equal(t, glyph, actual)
+	})
+	t.Run("should return fallback glyph", func(t *testing.T) {
+		f := font.New(1)
+		fallback := font.Glyph{Advance: 5}
+		f.SetGlyph('?', fallback)
+		f.SetFallback('?')
+		// when
+		actual, ok := f.Glyph('a')
+		// then
+		assert.True(t, ok)
+		assert.Equal(t, fallback, actual)
+	})
+	t.Run("should return false when fallback glyph is missing", func(t *testing.T) {
+		f := font.New(1)
+		f.SetFallback('?')
+		_, ok := f.Glyph('a')
+		assert.False(t, ok)
+	})
+}
+
+func TestFont_Kerning(t *testing.T) {
+	t.Run("should return 0 when kerning was not set", func(t *testing.T) {
+		f := font.New(1)
+		assert.Equal(t, 0, f.Kerning('A', 'V'))
+	})
+	t.Run("should return kerning", func(t *testing.T) {
+		f := font.New(1)
+		f.SetKerning('A', 'V', -2)
+		assert.Equal(t, -2, f.Kerning('A', 'V'))
+		assert.Equal(t, 0, f.Kerning('V', 'A'))
+	})
+}
+
+func TestFont_Width(t *testing.T) {
+	f := font.New(1)
+	f.SetGlyph('a', font.Glyph{Advance: 2})
+	f.SetGlyph('b', font.Glyph{Advance: 3})
+	f.SetKerning('a', 'b', -1)
+	tests := map[string]struct {
+		line     string
+		expected int
+	}{
+		"empty":          {line: "", expected: 0},
+		"single rune":    {line: "a", expected: 2},
+		"kerning":        {line: "ab", expected: 4},
+		"no kerning":     {line: "ba", expected: 5},
+		"missing glyphs": {line: "axa", expected: 4},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, f.Width(test.line))
+		})
+	}
+}
+
+func TestGridGlyphMap(t *testing.T) {
+	t.Run("should panic for non positive arguments", func(t *testing.T) {
+		assert.Panics(t, func() {
+			font.GridGlyphMap(0, 1, 1, "a")
+		})
+		assert.Panics(t, func() {
+			font.GridGlyphMap(1, 0, 1, "a")
+		})
+		assert.Panics(t, func() {
+			font.GridGlyphMap(1, 1, 0, "a")
+		})
+	})
+	t.Run("should create glyph map", func(t *testing.T) {
+		glyphMap := font.GridGlyphMap(2, 3, 2, "abcé")
+		assert.Equal(t, font.GlyphMap{
+			'a': {X: 0, Y: 0, Width: 2, Height: 3},
+			'b': {X: 2, Y: 0, Width: 2, Height: 3},
+			'c': {X: 0, Y: 3, Width: 2, Height: 3},
+			'é': {X: 2, Y: 3, Width: 2, Height: 3},
+		}, glyphMap)
+	})
+}
+
+func TestNewFromSpriteSheet(t *testing.T) {
+	t.Run("should panic for empty glyph map", func(t *testing.T) {
+		sheet := image.New(fake.NewAcceleratedImage(1, 1))
+		assert.Panics(t, func() {
+			font.NewFromSpriteSheet(sheet.WholeImageSelection(), font.GlyphMap{})
+		})
+	})
+	t.Run("should create font", func(t *testing.T) {
+		sheet := image.New(fake.NewAcceleratedImage(4, 3))
+		color := image.RGB(255, 255, 255)
+		sheet.WholeImageSelection().SetColor(1, 2, color)
+		// when
+		f := font.NewFromSpriteSheet(sheet.WholeImageSelection(), font.GlyphMap{
+			'a': {X: 0, Y: 0, Width: 1, Height: 1},
+			'b': {X: 1, Y: 1, Width: 2, Height: 2},
+		})
+		// then
+		require.NotNil(t, f)
+		assert.Equal(t, 2, f.LineHeight())
+		glyph, ok := f.Glyph('b')
+		require.True(t, ok)
+		assert.Equal(t, 2, glyph.Advance)
+		assert.Equal(t, 2, glyph.Selection.Width())
+		assert.Equal(t, 2, glyph.Selection.Height())
+		assert.Equal(t, color, glyph.Selection.Color(0, 1))
+	})
+}
@@ -0,0 +1,84 @@
+package font
+
+import (
+	"github.com/elgopher/pixiq/image"
+)
+
+// Glyph is an image of a single character.
+type Glyph struct {
+	// Selection contains pixels of the glyph
+	Selection image.Selection
+	// OffsetX is a horizontal offset of the glyph image relative to the pen position
+	OffsetX int
+	// OffsetY is a vertical offset of the glyph image relative to the top of the line
+	OffsetY int
+	// Advance is a horizontal distance in pixels by which the pen position is
+	// moved after drawing the glyph
+	Advance int
+}
+
+// Rectangle is a position and size of the glyph in the sprite sheet.
+type Rectangle struct {
+	X, Y, Width, Height int
+}
+
+// GlyphMap maps runes to glyph rectangles in the sprite sheet.
+type GlyphMap map[rune]Rectangle
+
+// GridGlyphMap creates a GlyphMap for sprite sheets where all glyphs have the
+// same size and are placed in a grid with given number of columns. Runes are
+// assigned to cells from left to right and from top to bottom.
+//
+// Will panic when cellWidth, cellHeight or columns is not positive.
+func GridGlyphMap(cellWidth, cellHeight, columns int, runes string) GlyphMap {
+	if cellWidth <= 0 {
+		panic("cellWidth is not positive")
+	}
+	if cellHeight <= 0 {
+		panic("cellHeight is not positive")
+	}
+	if columns <= 0 {
+		panic("columns is not positive")
+	}
+	glyphMap := GlyphMap{}
+	i := 0
+	for _, r := range runes {
+		glyphMap[r] = Rectangle{
+			X:      (i % columns) * cellWidth,
+			Y:      (i / columns) * cellHeight,
+			Width:  cellWidth,
+			Height: cellHeight,
+		}
+		i++
+	}
+	return glyphMap
+}
+
+// NewFromSpriteSheet creates a Font from glyphs stored in a sprite sheet image.
+// Glyph rectangles are relative to the sheet selection. The line height is
+// the height of the highest glyph and the advance of each glyph is equal to its
+// width.
+//
+// Pixels are not copied, therefore the sheet image must not be deleted as long
+// as the font is used.
+//
+// Will panic when glyphMap is empty.
+func NewFromSpriteSheet(sheet image.Selection, glyphMap GlyphMap) *Font {
+	if len(glyphMap) == 0 {
+		panic("empty glyphMap")
+	}
+	lineHeight := 1
+	for _, rect := range glyphMap {
+		if rect.Height > lineHeight {
+			lineHeight = rect.Height
+		}
+	}
+	font := New(lineHeight)
+	for r, rect := range glyphMap {
+		font.SetGlyph(r, Glyph{
+			Selection: sheet.Selection(rect.X, rect.Y).WithSize(rect.Width, rect.Height),
+			Advance:   rect.Width,
+		})
+	}
+	return font
+}
@@ -16,6 +16,16 @@ type API interface {
 	GetBufferSubData(target uint32, offset int, size int, data unsafe.Pointer)
 	// DeleteBuffers deletes named buffer objects
 	DeleteBuffers(n int32, buffers *uint32)
+	// MapBufferRange maps all or part of a buffer object's data store into the client's address space
+	MapBufferRange(target uint32, offset int, length int, access uint32) unsafe.Pointer
+	// UnmapBuffer releases the mapping of a buffer object's data store into the client's address space
+	UnmapBuffer(target uint32) bool
+	// FenceSync creates a new sync object and inserts it into the GL command stream
+	FenceSync(condition uint32, flags uint32) uintptr
+	// ClientWaitSync blocks and waits for a sync object to become signaled
+	ClientWaitSync(sync uintptr, flags uint32, timeout uint64) uint32
+	// DeleteSync deletes a sync object
+	DeleteSync(sync uintptr)
 	// GenVertexArrays generates vertex array object names
 	GenVertexArrays(n int32, arrays *uint32)
 	// DeleteVertexArrays deletes vertex array objects
@@ -24,6 +34,8 @@ type API interface {
 	BindVertexArray(array uint32)
 	// VertexAttribPointer defines an array of generic vertex attribute data
 	VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer unsafe.Pointer)
+	// VertexAttribDivisor modifies the rate at which generic vertex attributes advance during instanced rendering
+	VertexAttribDivisor(index uint32, divisor uint32)
 	// EnableVertexAttribArray enables a generic vertex attribute array
 	EnableVertexAttribArray(index uint32)
 	// CreateShader creates a shader object
@@ -74,6 +86,12 @@ type API interface {
 	Clear(mask uint32)
 	// DrawArrays render primitives from array data
 	DrawArrays(mode uint32, first int32, count int32)
+	// DrawElements render primitives from array data using indices stored in element array buffer
+	DrawElements(mode uint32, count int32, xtype uint32, indices unsafe.Pointer)
+	// DrawArraysInstanced draws multiple instances of a range of elements
+	DrawArraysInstanced(mode uint32, first int32, count int32, instancecount int32)
+	// DrawElementsInstanced draws multiple instances of a set of elements
+	DrawElementsInstanced(mode uint32, count int32, xtype uint32, indices unsafe.Pointer, instancecount int32)
 	// Uniform1f specifies the value of a uniform variable for the current program object
 	Uniform1f(location int32, v0 float32)
 	// Uniform2f specifies the value of a uniform variable for the current program object
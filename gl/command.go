@@ -3,6 +3,7 @@ package gl
 import (
 	"fmt"
 	"strings"
+	"unsafe"
 
 	"github.com/elgopher/pixiq/image"
 )
@@ -173,6 +174,18 @@ const (
 	// OneMinusDstAlpha is GL_ONE_MINUS_DST_ALPHA. Multiplies all components by 1 minus
 	// the destination alpha value.
 	OneMinusDstAlpha = BlendFactor(0x0305)
+	// SrcColor is GL_SRC_COLOR. Multiplies each component by the corresponding
+	// component of the source color.
+	SrcColor = BlendFactor(0x0300)
+	// OneMinusSrcColor is GL_ONE_MINUS_SRC_COLOR. Multiplies each component by 1 minus
+	// the corresponding component of the source color.
+	OneMinusSrcColor = BlendFactor(0x0301)
+	// DstColor is GL_DST_COLOR. Multiplies each component by the corresponding
+	// component of the destination color.
+	DstColor = BlendFactor(0x0306)
+	// OneMinusDstColor is GL_ONE_MINUS_DST_COLOR. Multiplies each component by 1 minus
+	// the corresponding component of the destination color.
+	OneMinusDstColor = BlendFactor(0x0307)
 )
 
 // BlendFactors contains source and destination factors used by blending formula
@@ -194,10 +207,78 @@ func (r *Renderer) SetBlendFactors(factors BlendFactors) {
 //
 // Before primitive is drawn this method validates if
 func (r *Renderer) DrawArrays(array *VertexArray, mode Mode, first, count int) {
+	r.prepareDraw(array)
+	r.api.DrawArrays(mode.glMode, int32(first), int32(count))
+}
+
+// DrawArraysInstanced draws instanceCount instances of primitives using
+// vertices defined in VertexArray. Attributes with non-zero
+// VertexBufferPointer.Divisor advance per instance instead of per vertex.
+// Vertex shader can also use gl_InstanceID to get the index of instance.
+//
+// Panics when instanceCount is negative.
+func (r *Renderer) DrawArraysInstanced(array *VertexArray, mode Mode, first, count, instanceCount int) {
+	if instanceCount < 0 {
+		panic("negative instanceCount")
+	}
+	r.prepareDraw(array)
+	r.api.DrawArraysInstanced(mode.glMode, int32(first), int32(count), int32(instanceCount))
+}
+
+// DrawElements draws primitives (such as triangles) using vertices defined in
+// VertexArray. Vertices are selected by count indices stored in the IndexBuffer
+// attached to VertexArray, starting from first index. Thanks to that vertices
+// can be shared by many primitives, for example each quad can be drawn with
+// 4 vertices and 6 indices using Triangles mode.
+//
+// Panics when VertexArray has no IndexBuffer, first or count is negative or
+// indices are out of IndexBuffer bounds.
+func (r *Renderer) DrawElements(array *VertexArray, mode Mode, first, count int) {
+	indexType, indices := r.indices(array, first, count)
+	r.prepareDraw(array)
+	r.api.DrawElements(mode.glMode, int32(count), indexType.xtype, indices)
+}
+
+// DrawElementsInstanced draws instanceCount instances of primitives using
+// indexed vertices, just like DrawElements does. Attributes with non-zero
+// VertexBufferPointer.Divisor advance per instance instead of per vertex.
+// Vertex shader can also use gl_InstanceID to get the index of instance.
+//
+// Panics for the same reasons as DrawElements and when instanceCount is
+// negative.
+func (r *Renderer) DrawElementsInstanced(array *VertexArray, mode Mode, first, count, instanceCount int) {
+	if instanceCount < 0 {
+		panic("negative instanceCount")
+	}
+	indexType, indices := r.indices(array, first, count)
+	r.prepareDraw(array)
+	r.api.DrawElementsInstanced(mode.glMode, int32(count), indexType.xtype, indices, int32(instanceCount))
+}
+
+// indices validates the range of indices and returns their type and offset
+// in the IndexBuffer
+func (r *Renderer) indices(array *VertexArray, first, count int) (IndexType, unsafe.Pointer) {
+	if array.indexBuffer == nil {
+		panic("vertex array has no index buffer")
+	}
+	if first < 0 {
+		panic("negative first")
+	}
+	if count < 0 {
+		panic("negative count")
+	}
+	indexBuffer := array.indexBuffer
+	if first+count > indexBuffer.size {
+		panic("indices out of index buffer bounds")
+	}
+	indexType := indexBuffer.indexType
+	return indexType, r.api.PtrOffset(first * indexType.size)
+}
+
+func (r *Renderer) prepareDraw(array *VertexArray) {
 	r.validateAttributeTypes(array)
 	r.api.BindVertexArray(array.id)
 	r.api.BlendFunc(uint32(r.blendFactors.SrcFactor), uint32(r.blendFactors.DstFactor))
-	r.api.DrawArrays(mode.glMode, int32(first), int32(count))
 }
 
 func (r *Renderer) validateAttributeTypes(array *VertexArray) {
@@ -3,6 +3,7 @@ package gl
 // Camel-cased GL constants
 const (
 	arrayBuffer              = 0x8892
+	elementArrayBuffer       = 0x8893
 	streamDraw               = 0x88E0
 	staticDraw               = 0x88E4
 	dynamicDraw              = 0x88E8
@@ -34,6 +35,8 @@ const (
 	framebuffer              = 0x8D40
 	maxTextureSize           = 0x0D33
 	unsignedByte             = 0x1401
+	unsignedShort            = 0x1403
+	unsignedInt              = 0x1405
 	rgba                     = 0x1908
 	colorAttachment0         = 0x8CE0
 	textureMinFilter         = 0x2801
@@ -45,4 +48,11 @@ const (
 	noError                  = 0
 	outOfMemory              = 0x0505
 	blend                    = 0x0BE2
+	pixelPackBuffer          = 0x88EB
+	streamRead               = 0x88E1
+	mapReadBit               = 0x0001
+	syncGPUCommandsComplete  = 0x9117
+	alreadySignaled          = 0x911A
+	conditionSatisfied       = 0x911C
+	waitFailed               = 0x911D
 )
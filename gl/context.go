@@ -11,6 +11,7 @@ import (
 type Context struct {
 	api             API
 	vertexBufferIDs vertexBufferIDs
+	indexBuffers    indexBuffers
 	allImages       allImages
 	capabilities    *Capabilities
 }
@@ -108,6 +109,29 @@ func (c *Context) NewFloatVertexBuffer(size int, usage Usage) *FloatVertexBuffer
 	return vb
 }
 
+// NewIndexBuffer creates an OpenGL's Element Buffer Object (EBO) containing
+// size indices of given type.
+func (c *Context) NewIndexBuffer(size int, indexType IndexType, usage Usage) *IndexBuffer {
+	if size < 0 {
+		panic("negative size")
+	}
+	if indexType != Uint16 && indexType != Uint32 {
+		panic("not supported index type")
+	}
+	var id uint32
+	c.api.GenBuffers(1, &id)
+	c.api.BindBuffer(arrayBuffer, id)
+	c.api.BufferData(arrayBuffer, size*indexType.size, c.api.Ptr(nil), usage.glUsage)
+	buffer := &IndexBuffer{
+		id:        id,
+		size:      size,
+		indexType: indexType,
+		api:       c.api,
+	}
+	c.indexBuffers[buffer] = struct{}{}
+	return buffer
+}
+
 // NewVertexArray creates a new instance of VertexArray. All vertex attributes
 // specified in layout will be enabled.
 func (c *Context) NewVertexArray(layout VertexLayout) *VertexArray {
@@ -125,6 +149,8 @@ func (c *Context) NewVertexArray(layout VertexLayout) *VertexArray {
 		layout:          layout,
 		api:             c.api,
 		vertexBufferIDs: c.vertexBufferIDs,
+		indexBuffers:    c.indexBuffers,
+		divisors:        make([]int, len(layout)),
 	}
 }
 
@@ -284,6 +310,10 @@ func (p *program) attributes() map[int32]attribute {
 	for i := int32(0); i < count; i++ {
 		p.api.GetActiveAttrib(p.id, uint32(i), nameMaxLength, &bufSize, &length, &xtype, &name[0])
 		location := p.api.GetAttribLocation(p.id, &name[0])
+		if location < 0 {
+			// built-in attributes, such as gl_InstanceID, do not have location
+			continue
+		}
 		attributes[location] = attribute{typ: valueOf(xtype),
 			name: p.api.GoStr(&name[0])}
 	}
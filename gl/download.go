@@ -0,0 +1,178 @@
+package gl
+
+import (
+	"github.com/elgopher/pixiq/image"
+)
+
+// DownloadAsync starts an asynchronous transfer of pixels from the given selection
+// of AcceleratedImage to RAM. Pixels are copied into a pixel pack buffer
+// by the video card, without stalling the pipeline. The method returns immediately.
+//
+// Pixels can be obtained by calling AsyncDownload.Resolve, ideally a frame later.
+// AsyncDownload.Ready can be used to check whether Resolve will block.
+// Resolving the download immediately blocks until the transfer is finished.
+//
+// Will panic if the selection image was not created in this context or the
+// selection location is out of image bounds.
+func (c *Context) DownloadAsync(selection image.AcceleratedImageSelection) *AsyncDownload {
+	img := c.imageForDownload(selection)
+	download := &AsyncDownload{
+		api:     c.api,
+		context: c,
+	}
+	c.api.GenBuffers(1, &download.bufferID)
+	download.transfer(img, selection.Location)
+	return download
+}
+
+func (c *Context) imageForDownload(selection image.AcceleratedImageSelection) *AcceleratedImage {
+	if selection.Image == nil {
+		panic("nil selection Image")
+	}
+	img, ok := c.allImages[selection.Image]
+	if !ok {
+		panic("image has not been created in this OpenGL context or has been deleted")
+	}
+	loc := selection.Location
+	if loc.X < 0 || loc.Y < 0 || loc.Width < 0 || loc.Height < 0 ||
+		loc.X+loc.Width > img.width || loc.Y+loc.Height > img.height {
+		panic("selection location out of image bounds")
+	}
+	return img
+}
+
+// AsyncDownload is a handle for pixels being transferred asynchronously from
+// VRAM to RAM. AsyncDownload is an external resource (like file for example)
+// and must be deleted manually.
+type AsyncDownload struct {
+	bufferID uint32
+	// capacity is a number of pixels which can be stored in the buffer
+	capacity  int
+	allocated bool
+	size      int
+	// fence is signaled by OpenGL when the transfer is finished. 0 means that
+	// there is nothing to wait for.
+	fence   uintptr
+	api     API
+	context *Context
+	deleted bool
+}
+
+func (d *AsyncDownload) transfer(img *AcceleratedImage, loc image.AcceleratedImageLocation) {
+	d.deleteFence()
+	d.size = loc.Width * loc.Height
+	d.api.BindBuffer(pixelPackBuffer, d.bufferID)
+	if !d.allocated || d.size > d.capacity {
+		d.api.BufferData(pixelPackBuffer, d.size*4, d.api.Ptr(nil), streamRead)
+		d.capacity = d.size
+		d.allocated = true
+	}
+	if d.size > 0 {
+		d.api.BindFramebuffer(framebuffer, img.frameBufferID)
+		d.api.ReadPixels(
+			int32(loc.X),
+			int32(img.height-loc.Y-loc.Height),
+			int32(loc.Width),
+			int32(loc.Height),
+			rgba,
+			unsignedByte,
+			d.api.PtrOffset(0),
+		)
+		d.fence = d.api.FenceSync(syncGPUCommandsComplete, 0)
+	}
+	// pixel pack buffer must be unbound, otherwise synchronous downloads
+	// would write to the buffer instead of the client memory
+	d.api.BindBuffer(pixelPackBuffer, 0)
+}
+
+func (d *AsyncDownload) deleteFence() {
+	if d.fence != 0 {
+		d.api.DeleteSync(d.fence)
+		d.fence = 0
+	}
+}
+
+// Size returns the number of downloaded pixels.
+func (d *AsyncDownload) Size() int {
+	return d.size
+}
+
+// Reuse starts a new asynchronous transfer of pixels from the given selection
+// using the same pixel pack buffer. Previously downloaded pixels are discarded.
+// The buffer is reallocated only when the selection has more pixels than any
+// previous one, therefore AsyncDownload can be reused every frame without
+// allocating new buffers.
+//
+// Will panic if AsyncDownload has been deleted, the selection image was not
+// created in the same context or the selection location is out of image
+// bounds.
+func (d *AsyncDownload) Reuse(selection image.AcceleratedImageSelection) {
+	if d.deleted {
+		panic("deleted AsyncDownload")
+	}
+	img := d.context.imageForDownload(selection)
+	d.transfer(img, selection.Location)
+}
+
+// Ready returns true if the transfer has finished, which means that Resolve
+// will not block. Ready never blocks.
+func (d *AsyncDownload) Ready() bool {
+	if d.deleted {
+		panic("deleted AsyncDownload")
+	}
+	if d.fence == 0 {
+		return true
+	}
+	switch d.api.ClientWaitSync(d.fence, 0, 0) {
+	case alreadySignaled, conditionSatisfied:
+		d.deleteFence()
+		return true
+	case waitFailed:
+		panic("ClientWaitSync failed")
+	}
+	return false
+}
+
+// Resolve waits for transfer to finish and copies pixels into output.
+//
+// Output will have pixel colors sorted by coordinates.
+// Pixels are send for last line first, from left to right.
+// Output must be of size location.Width*location.Height of the downloaded
+// selection.
+//
+// Resolve can be called many times. Will panic when the pixel pack buffer
+// can't be mapped.
+func (d *AsyncDownload) Resolve(output []image.Color) {
+	if d.deleted {
+		panic("deleted AsyncDownload")
+	}
+	if len(output) != d.size {
+		panic("output slice is not of length location.Width*location.Height")
+	}
+	if d.size == 0 {
+		return
+	}
+	d.api.BindBuffer(pixelPackBuffer, d.bufferID)
+	ptr := d.api.MapBufferRange(pixelPackBuffer, 0, d.size*4, mapReadBit)
+	if ptr == nil {
+		d.api.BindBuffer(pixelPackBuffer, 0)
+		panic("pixel pack buffer can't be mapped")
+	}
+	pixels := (*[1 << 28]image.Color)(ptr)[:d.size:d.size]
+	copy(output, pixels)
+	d.api.UnmapBuffer(pixelPackBuffer)
+	d.api.BindBuffer(pixelPackBuffer, 0)
+	// mapping waits for the transfer, so the fence is not needed anymore
+	d.deleteFence()
+}
+
+// Delete should be called whenever you don't plan to use AsyncDownload anymore.
+// Calling Delete more than once does nothing.
+func (d *AsyncDownload) Delete() {
+	if d.deleted {
+		return
+	}
+	d.deleteFence()
+	d.api.DeleteBuffers(1, &d.bufferID)
+	d.deleted = true
+}
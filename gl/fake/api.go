@@ -0,0 +1,558 @@
+package fake
+
+import "unsafe"
+
+// GenBuffers generates buffer object names
+func (a *API) GenBuffers(n int32, buffers *uint32) {
+	a.api.GenBuffers(n, buffers)
+	generated := names(n, buffers)
+	a.record("GenBuffers", n, generated)
+	for _, name := range generated {
+		a.created(Buffer, name)
+	}
+}
+
+// BindBuffer binds a named buffer object
+func (a *API) BindBuffer(target uint32, buffer uint32) {
+	a.record("BindBuffer", target, buffer)
+	a.use("BindBuffer", Buffer, buffer)
+	a.api.BindBuffer(target, buffer)
+	a.state.Buffers[target] = buffer
+}
+
+// BufferData creates and initializes a buffer object's data store
+func (a *API) BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
+	a.record("BufferData", target, size, data, usage)
+	a.api.BufferData(target, size, data, usage)
+}
+
+// BufferSubData updates a subset of a buffer object's data store
+func (a *API) BufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+	a.record("BufferSubData", target, offset, size, data)
+	a.api.BufferSubData(target, offset, size, data)
+}
+
+// GetBufferSubData returns a subset of a buffer object's data store
+func (a *API) GetBufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+	a.record("GetBufferSubData", target, offset, size, data)
+	a.api.GetBufferSubData(target, offset, size, data)
+}
+
+// DeleteBuffers deletes named buffer objects
+func (a *API) DeleteBuffers(n int32, buffers *uint32) {
+	deleted := names(n, buffers)
+	a.record("DeleteBuffers", n, deleted)
+	a.api.DeleteBuffers(n, buffers)
+	for _, name := range deleted {
+		a.deleted("DeleteBuffers", Buffer, name)
+		for target, buffer := range a.state.Buffers {
+			if buffer == name {
+				a.state.Buffers[target] = 0
+			}
+		}
+	}
+}
+
+// MapBufferRange maps all or part of a buffer object's data store into the client's address space
+func (a *API) MapBufferRange(target uint32, offset int, length int, access uint32) unsafe.Pointer {
+	call := a.record("MapBufferRange", target, offset, length, access)
+	result := a.api.MapBufferRange(target, offset, length, access)
+	call.Result = result
+	return result
+}
+
+// UnmapBuffer releases the mapping of a buffer object's data store into the client's address space
+func (a *API) UnmapBuffer(target uint32) bool {
+	call := a.record("UnmapBuffer", target)
+	result := a.api.UnmapBuffer(target)
+	call.Result = result
+	return result
+}
+
+// FenceSync creates a new sync object and inserts it into the GL command stream
+func (a *API) FenceSync(condition uint32, flags uint32) uintptr {
+	call := a.record("FenceSync", condition, flags)
+	result := a.api.FenceSync(condition, flags)
+	call.Result = result
+	return result
+}
+
+// ClientWaitSync blocks and waits for a sync object to become signaled
+func (a *API) ClientWaitSync(sync uintptr, flags uint32, timeout uint64) uint32 {
+	call := a.record("ClientWaitSync", sync, flags, timeout)
+	result := a.api.ClientWaitSync(sync, flags, timeout)
+	call.Result = result
+	return result
+}
+
+// DeleteSync deletes a sync object
+func (a *API) DeleteSync(sync uintptr) {
+	a.record("DeleteSync", sync)
+	a.api.DeleteSync(sync)
+}
+
+// GenVertexArrays generates vertex array object names
+func (a *API) GenVertexArrays(n int32, arrays *uint32) {
+	a.api.GenVertexArrays(n, arrays)
+	generated := names(n, arrays)
+	a.record("GenVertexArrays", n, generated)
+	for _, name := range generated {
+		a.created(VertexArray, name)
+	}
+}
+
+// DeleteVertexArrays deletes vertex array objects
+func (a *API) DeleteVertexArrays(n int32, arrays *uint32) {
+	deleted := names(n, arrays)
+	a.record("DeleteVertexArrays", n, deleted)
+	a.api.DeleteVertexArrays(n, arrays)
+	for _, name := range deleted {
+		a.deleted("DeleteVertexArrays", VertexArray, name)
+		if a.state.VertexArray == name {
+			a.state.VertexArray = 0
+		}
+	}
+}
+
+// BindVertexArray binds a vertex array object
+func (a *API) BindVertexArray(array uint32) {
+	a.record("BindVertexArray", array)
+	a.use("BindVertexArray", VertexArray, array)
+	a.api.BindVertexArray(array)
+	a.state.VertexArray = array
+}
+
+// VertexAttribPointer defines an array of generic vertex attribute data
+func (a *API) VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer unsafe.Pointer) {
+	a.record("VertexAttribPointer", index, size, xtype, normalized, stride, pointer)
+	a.api.VertexAttribPointer(index, size, xtype, normalized, stride, pointer)
+}
+
+// VertexAttribDivisor modifies the rate at which generic vertex attributes advance during instanced rendering
+func (a *API) VertexAttribDivisor(index uint32, divisor uint32) {
+	a.record("VertexAttribDivisor", index, divisor)
+	a.api.VertexAttribDivisor(index, divisor)
+}
+
+// EnableVertexAttribArray enables a generic vertex attribute array
+func (a *API) EnableVertexAttribArray(index uint32) {
+	a.record("EnableVertexAttribArray", index)
+	a.api.EnableVertexAttribArray(index)
+}
+
+// CreateShader creates a shader object
+func (a *API) CreateShader(xtype uint32) uint32 {
+	call := a.record("CreateShader", xtype)
+	shader := a.api.CreateShader(xtype)
+	call.Result = shader
+	a.created(Shader, shader)
+	return shader
+}
+
+// ShaderSource replaces the source code in a shader object
+func (a *API) ShaderSource(shader uint32, count int32, xstring **uint8, length *int32) {
+	var sources []string
+	if count > 0 && xstring != nil {
+		cstrs := (*[1 << 28]*uint8)(unsafe.Pointer(xstring))[:count:count]
+		for i, cstr := range cstrs {
+			l := -1
+			if length != nil {
+				l = int((*[1 << 28]int32)(unsafe.Pointer(length))[i])
+			}
+			sources = append(sources, goString(cstr, l))
+		}
+	}
+	a.record("ShaderSource", shader, count, sources)
+	a.use("ShaderSource", Shader, shader)
+	a.api.ShaderSource(shader, count, xstring, length)
+}
+
+// CompileShader compiles a shader object
+func (a *API) CompileShader(shader uint32) {
+	a.record("CompileShader", shader)
+	a.use("CompileShader", Shader, shader)
+	a.api.CompileShader(shader)
+}
+
+// GetShaderiv returns a parameter from a shader object
+func (a *API) GetShaderiv(shader uint32, pname uint32, params *int32) {
+	a.record("GetShaderiv", shader, pname)
+	a.use("GetShaderiv", Shader, shader)
+	a.api.GetShaderiv(shader, pname, params)
+}
+
+// GetShaderInfoLog returns the information log for a shader object
+func (a *API) GetShaderInfoLog(shader uint32, bufSize int32, length *int32, infoLog *uint8) {
+	a.record("GetShaderInfoLog", shader, bufSize)
+	a.use("GetShaderInfoLog", Shader, shader)
+	a.api.GetShaderInfoLog(shader, bufSize, length, infoLog)
+}
+
+// DeleteShader deletes a shader object
+func (a *API) DeleteShader(shader uint32) {
+	a.record("DeleteShader", shader)
+	a.deleted("DeleteShader", Shader, shader)
+	a.api.DeleteShader(shader)
+}
+
+// AttachShader attaches a shader object to a program object
+func (a *API) AttachShader(program uint32, shader uint32) {
+	a.record("AttachShader", program, shader)
+	a.use("AttachShader", Program, program)
+	a.use("AttachShader", Shader, shader)
+	a.api.AttachShader(program, shader)
+}
+
+// LinkProgram links a program object
+func (a *API) LinkProgram(program uint32) {
+	a.record("LinkProgram", program)
+	a.use("LinkProgram", Program, program)
+	a.api.LinkProgram(program)
+}
+
+// GetProgramiv returns a parameter from a program object
+func (a *API) GetProgramiv(program uint32, pname uint32, params *int32) {
+	a.record("GetProgramiv", program, pname)
+	a.use("GetProgramiv", Program, program)
+	a.api.GetProgramiv(program, pname, params)
+}
+
+// GetProgramInfoLog returns the information log for a program object
+func (a *API) GetProgramInfoLog(program uint32, bufSize int32, length *int32, infoLog *uint8) {
+	a.record("GetProgramInfoLog", program, bufSize)
+	a.use("GetProgramInfoLog", Program, program)
+	a.api.GetProgramInfoLog(program, bufSize, length, infoLog)
+}
+
+// UseProgram installs a program object as part of current rendering state
+func (a *API) UseProgram(program uint32) {
+	a.record("UseProgram", program)
+	a.use("UseProgram", Program, program)
+	a.api.UseProgram(program)
+	a.state.Program = program
+}
+
+// CreateProgram creates a program object
+func (a *API) CreateProgram() uint32 {
+	call := a.record("CreateProgram")
+	program := a.api.CreateProgram()
+	call.Result = program
+	a.created(Program, program)
+	return program
+}
+
+// DeleteProgram deletes a program object. Program which is in use stays
+// in State until another program is used, just like in OpenGL.
+func (a *API) DeleteProgram(program uint32) {
+	a.record("DeleteProgram", program)
+	a.deleted("DeleteProgram", Program, program)
+	a.api.DeleteProgram(program)
+}
+
+// GetActiveUniform returns information about an active uniform variable for the specified program object
+func (a *API) GetActiveUniform(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	a.record("GetActiveUniform", program, index, bufSize)
+	a.use("GetActiveUniform", Program, program)
+	a.api.GetActiveUniform(program, index, bufSize, length, size, xtype, name)
+}
+
+// GetActiveAttrib returns information about an active attribute variable for the specified program object
+func (a *API) GetActiveAttrib(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	a.record("GetActiveAttrib", program, index, bufSize)
+	a.use("GetActiveAttrib", Program, program)
+	a.api.GetActiveAttrib(program, index, bufSize, length, size, xtype, name)
+}
+
+// GetAttribLocation returns the location of an attribute variable
+func (a *API) GetAttribLocation(program uint32, name *uint8) int32 {
+	call := a.record("GetAttribLocation", program, goString(name, -1))
+	a.use("GetAttribLocation", Program, program)
+	location := a.api.GetAttribLocation(program, name)
+	call.Result = location
+	return location
+}
+
+// Enable enables server-side GL capabilities
+func (a *API) Enable(cap uint32) {
+	a.record("Enable", cap)
+	a.api.Enable(cap)
+	a.state.Capabilities[cap] = true
+}
+
+// Disable disables server-side GL capabilities
+func (a *API) Disable(cap uint32) {
+	a.record("Disable", cap)
+	a.api.Disable(cap)
+	a.state.Capabilities[cap] = false
+}
+
+// BindFramebuffer binds a framebuffer to a framebuffer target
+func (a *API) BindFramebuffer(target uint32, framebuffer uint32) {
+	a.record("BindFramebuffer", target, framebuffer)
+	a.use("BindFramebuffer", Framebuffer, framebuffer)
+	a.api.BindFramebuffer(target, framebuffer)
+	a.state.Framebuffer = framebuffer
+}
+
+// Scissor defines the scissor box
+func (a *API) Scissor(x int32, y int32, width int32, height int32) {
+	a.record("Scissor", x, y, width, height)
+	a.api.Scissor(x, y, width, height)
+	a.state.Scissor = [4]int32{x, y, width, height}
+}
+
+// Viewport sets the viewport
+func (a *API) Viewport(x int32, y int32, width int32, height int32) {
+	a.record("Viewport", x, y, width, height)
+	a.api.Viewport(x, y, width, height)
+	a.state.Viewport = [4]int32{x, y, width, height}
+}
+
+// ClearColor specifies clear values for the color buffers
+func (a *API) ClearColor(red float32, green float32, blue float32, alpha float32) {
+	a.record("ClearColor", red, green, blue, alpha)
+	a.api.ClearColor(red, green, blue, alpha)
+	a.state.ClearColor = [4]float32{red, green, blue, alpha}
+}
+
+// Clear clears buffers to preset values
+func (a *API) Clear(mask uint32) {
+	a.record("Clear", mask)
+	a.api.Clear(mask)
+}
+
+// DrawArrays render primitives from array data
+func (a *API) DrawArrays(mode uint32, first int32, count int32) {
+	a.record("DrawArrays", mode, first, count)
+	a.api.DrawArrays(mode, first, count)
+}
+
+// DrawElements render primitives from array data using indices stored in element array buffer
+func (a *API) DrawElements(mode uint32, count int32, xtype uint32, indices unsafe.Pointer) {
+	a.record("DrawElements", mode, count, xtype, indices)
+	a.api.DrawElements(mode, count, xtype, indices)
+}
+
+// DrawArraysInstanced draws multiple instances of a range of elements
+func (a *API) DrawArraysInstanced(mode uint32, first int32, count int32, instancecount int32) {
+	a.record("DrawArraysInstanced", mode, first, count, instancecount)
+	a.api.DrawArraysInstanced(mode, first, count, instancecount)
+}
+
+// DrawElementsInstanced draws multiple instances of a set of elements
+func (a *API) DrawElementsInstanced(mode uint32, count int32, xtype uint32, indices unsafe.Pointer, instancecount int32) {
+	a.record("DrawElementsInstanced", mode, count, xtype, indices, instancecount)
+	a.api.DrawElementsInstanced(mode, count, xtype, indices, instancecount)
+}
+
+// Uniform1f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform1f(location int32, v0 float32) {
+	a.record("Uniform1f", location, v0)
+	a.api.Uniform1f(location, v0)
+}
+
+// Uniform2f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform2f(location int32, v0 float32, v1 float32) {
+	a.record("Uniform2f", location, v0, v1)
+	a.api.Uniform2f(location, v0, v1)
+}
+
+// Uniform3f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform3f(location int32, v0 float32, v1 float32, v2 float32) {
+	a.record("Uniform3f", location, v0, v1, v2)
+	a.api.Uniform3f(location, v0, v1, v2)
+}
+
+// Uniform4f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform4f(location int32, v0 float32, v1 float32, v2 float32, v3 float32) {
+	a.record("Uniform4f", location, v0, v1, v2, v3)
+	a.api.Uniform4f(location, v0, v1, v2, v3)
+}
+
+// Uniform1i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform1i(location int32, v0 int32) {
+	a.record("Uniform1i", location, v0)
+	a.api.Uniform1i(location, v0)
+}
+
+// Uniform2i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform2i(location int32, v0 int32, v1 int32) {
+	a.record("Uniform2i", location, v0, v1)
+	a.api.Uniform2i(location, v0, v1)
+}
+
+// Uniform3i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform3i(location int32, v0 int32, v1 int32, v2 int32) {
+	a.record("Uniform3i", location, v0, v1, v2)
+	a.api.Uniform3i(location, v0, v1, v2)
+}
+
+// Uniform4i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform4i(location int32, v0 int32, v1 int32, v2 int32, v3 int32) {
+	a.record("Uniform4i", location, v0, v1, v2, v3)
+	a.api.Uniform4i(location, v0, v1, v2, v3)
+}
+
+// UniformMatrix3fv specifies the value of a uniform variable for the current program object
+func (a *API) UniformMatrix3fv(location int32, count int32, transpose bool, value *float32) {
+	a.record("UniformMatrix3fv", location, count, transpose, float32s(int(count)*9, value))
+	a.api.UniformMatrix3fv(location, count, transpose, value)
+}
+
+// UniformMatrix4fv specifies the value of a uniform variable for the current program object
+func (a *API) UniformMatrix4fv(location int32, count int32, transpose bool, value *float32) {
+	a.record("UniformMatrix4fv", location, count, transpose, float32s(int(count)*16, value))
+	a.api.UniformMatrix4fv(location, count, transpose, value)
+}
+
+// ActiveTexture selects active texture unit
+func (a *API) ActiveTexture(texture uint32) {
+	a.record("ActiveTexture", texture)
+	a.api.ActiveTexture(texture)
+	a.state.ActiveTexture = int(texture) - texture0
+}
+
+// BindTexture binds a named texture to a texturing target
+func (a *API) BindTexture(target uint32, texture uint32) {
+	a.record("BindTexture", target, texture)
+	a.use("BindTexture", Texture, texture)
+	a.api.BindTexture(target, texture)
+	if target == texture2D {
+		a.state.Textures[a.state.ActiveTexture] = texture
+	}
+}
+
+// GetIntegerv returns the value or values of the specified parameter
+func (a *API) GetIntegerv(pname uint32, data *int32) {
+	a.record("GetIntegerv", pname)
+	a.api.GetIntegerv(pname, data)
+}
+
+// GenTextures generates texture names
+func (a *API) GenTextures(n int32, textures *uint32) {
+	a.api.GenTextures(n, textures)
+	generated := names(n, textures)
+	a.record("GenTextures", n, generated)
+	for _, name := range generated {
+		a.created(Texture, name)
+	}
+}
+
+// DeleteTextures deletes named textures
+func (a *API) DeleteTextures(n int32, textures *uint32) {
+	deleted := names(n, textures)
+	a.record("DeleteTextures", n, deleted)
+	a.api.DeleteTextures(n, textures)
+	for _, name := range deleted {
+		a.deleted("DeleteTextures", Texture, name)
+		for unit, texture := range a.state.Textures {
+			if texture == name {
+				a.state.Textures[unit] = 0
+			}
+		}
+	}
+}
+
+// TexImage2D specifies a two-dimensional texture image
+func (a *API) TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	a.record("TexImage2D", target, level, internalformat, width, height, border, format, xtype, pixels)
+	a.api.TexImage2D(target, level, internalformat, width, height, border, format, xtype, pixels)
+}
+
+// TexParameteri sets texture parameter
+func (a *API) TexParameteri(target uint32, pname uint32, param int32) {
+	a.record("TexParameteri", target, pname, param)
+	a.api.TexParameteri(target, pname, param)
+}
+
+// GenFramebuffers generates framebuffer object names
+func (a *API) GenFramebuffers(n int32, framebuffers *uint32) {
+	a.api.GenFramebuffers(n, framebuffers)
+	generated := names(n, framebuffers)
+	a.record("GenFramebuffers", n, generated)
+	for _, name := range generated {
+		a.created(Framebuffer, name)
+	}
+}
+
+// DeleteFramebuffers deletes named framebuffer objects
+func (a *API) DeleteFramebuffers(n int32, framebuffers *uint32) {
+	deleted := names(n, framebuffers)
+	a.record("DeleteFramebuffers", n, deleted)
+	a.api.DeleteFramebuffers(n, framebuffers)
+	for _, name := range deleted {
+		a.deleted("DeleteFramebuffers", Framebuffer, name)
+		if a.state.Framebuffer == name {
+			a.state.Framebuffer = 0
+		}
+	}
+}
+
+// FramebufferTexture2D attaches a level of a texture object as a logical buffer to the currently bound framebuffer object
+func (a *API) FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
+	a.record("FramebufferTexture2D", target, attachment, textarget, texture, level)
+	a.use("FramebufferTexture2D", Texture, texture)
+	a.api.FramebufferTexture2D(target, attachment, textarget, texture, level)
+}
+
+// TexSubImage2D specifies a two-dimensional texture subimage
+func (a *API) TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	a.record("TexSubImage2D", target, level, xoffset, yoffset, width, height, format, xtype, pixels)
+	a.api.TexSubImage2D(target, level, xoffset, yoffset, width, height, format, xtype, pixels)
+}
+
+// GetTexImage returns a texture image
+func (a *API) GetTexImage(target uint32, level int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	a.record("GetTexImage", target, level, format, xtype, pixels)
+	a.api.GetTexImage(target, level, format, xtype, pixels)
+}
+
+// GetError returns error information
+func (a *API) GetError() uint32 {
+	call := a.record("GetError")
+	code := a.api.GetError()
+	call.Result = code
+	return code
+}
+
+// ReadPixels reads a block of pixels from the frame buffer
+func (a *API) ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	a.record("ReadPixels", x, y, width, height, format, xtype, pixels)
+	a.api.ReadPixels(x, y, width, height, format, xtype, pixels)
+}
+
+// BlendFunc specifies pixel arithmetic
+func (a *API) BlendFunc(sfactor uint32, dfactor uint32) {
+	a.record("BlendFunc", sfactor, dfactor)
+	a.api.BlendFunc(sfactor, dfactor)
+	a.state.BlendFactors = [2]uint32{sfactor, dfactor}
+}
+
+// Finish blocks until all GL execution is complete
+func (a *API) Finish() {
+	a.record("Finish")
+	a.api.Finish()
+}
+
+// Ptr takes a slice or pointer (to a singular scalar value or the first
+// element of an array or slice) and returns its GL-compatible address.
+func (a *API) Ptr(data interface{}) unsafe.Pointer {
+	return a.api.Ptr(data)
+}
+
+// PtrOffset takes a pointer offset and returns a GL-compatible pointer.
+func (a *API) PtrOffset(offset int) unsafe.Pointer {
+	return a.api.PtrOffset(offset)
+}
+
+// GoStr takes a null-terminated string returned by OpenGL and constructs a
+// corresponding Go string.
+func (a *API) GoStr(cstr *uint8) string {
+	return a.api.GoStr(cstr)
+}
+
+// Strs takes a list of Go strings (with or without null-termination) and
+// returns their C counterpart.
+func (a *API) Strs(strs ...string) (cstrs **uint8, free func()) {
+	return a.api.Strs(strs...)
+}
@@ -0,0 +1,290 @@
+// Package fake provides a gl.API implementation which can be used in unit
+// testing of gl.Command implementations.
+//
+// API records every call together with its arguments, tracks lifetimes of
+// OpenGL objects and currently bound state:
+//
+//	api := fake.NewAPI()
+//	context := gl.NewContext(api)
+//	... // run the command
+//	assert.Len(t, api.CallsTo("DrawArrays"), 1)
+//	assert.Empty(t, api.Leaks())
+//	assert.Empty(t, api.InvalidUsages())
+//
+// Calls are delegated to another gl.API, by default to the software
+// implementation, so shaders are really compiled and images are really drawn.
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unsafe"
+
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/gl/software"
+)
+
+// Camel-cased GL constants
+const (
+	texture0  = 0x84C0
+	texture2D = 0x0DE1
+)
+
+// NewAPI returns a new fake API which delegates all calls to a new instance of
+// software API.
+func NewAPI() *API {
+	return Wrap(software.NewAPI())
+}
+
+// Wrap returns a new fake API which records calls and then delegates them to
+// given api. It can be used to record calls made to real OpenGL driver.
+func Wrap(api gl.API) *API {
+	if api == nil {
+		panic("nil api")
+	}
+	return &API{
+		api:     api,
+		objects: map[Object]bool{},
+		state:   newState(),
+	}
+}
+
+// API is a gl.API implementation recording calls. It is not safe for
+// concurrent use.
+type API struct {
+	api           gl.API
+	calls         []Call
+	objects       map[Object]bool // false means deleted
+	invalidUsages []string
+	state         State
+}
+
+// Call is a recorded call of gl.API method.
+//
+// Output parameters are not recorded, with the exception of names generated
+// by GenXXX methods. Slices of names, uniform matrices and shader sources are
+// recorded as Go slices. Ptr, PtrOffset, GoStr and Strs methods are not
+// recorded, because they do not call OpenGL.
+type Call struct {
+	// Function is a name of gl.API method, for example "BindTexture"
+	Function string
+	Args     []interface{}
+	// Result is a value returned by the method or nil
+	Result interface{}
+}
+
+// String returns call in the form of "BindTexture(3553, 1)". Pointers are
+// printed as <pointer>, because their values are not deterministic.
+func (c Call) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		switch arg := arg.(type) {
+		case unsafe.Pointer:
+			if arg == nil {
+				args[i] = "nil"
+			} else {
+				args[i] = "<pointer>"
+			}
+		default:
+			args[i] = fmt.Sprintf("%v", arg)
+		}
+	}
+	return c.Function + "(" + strings.Join(args, ", ") + ")"
+}
+
+// ObjectKind is a kind of OpenGL object
+type ObjectKind string
+
+const (
+	// Buffer is an object created with GenBuffers
+	Buffer ObjectKind = "buffer"
+	// VertexArray is an object created with GenVertexArrays
+	VertexArray ObjectKind = "vertex array"
+	// Texture is an object created with GenTextures
+	Texture ObjectKind = "texture"
+	// Framebuffer is an object created with GenFramebuffers
+	Framebuffer ObjectKind = "framebuffer"
+	// Shader is an object created with CreateShader
+	Shader ObjectKind = "shader"
+	// Program is an object created with CreateProgram
+	Program ObjectKind = "program"
+)
+
+// Object is an OpenGL object identified by kind and name
+type Object struct {
+	Kind ObjectKind
+	Name uint32
+}
+
+func (o Object) String() string {
+	return fmt.Sprintf("%s %d", o.Kind, o.Name)
+}
+
+// State is a bound state tracked by API. Maps are indexed by target, unit or
+// capability.
+type State struct {
+	Program     uint32
+	VertexArray uint32
+	Framebuffer uint32
+	// Buffers are buffers bound to targets, such as GL_ARRAY_BUFFER
+	Buffers map[uint32]uint32
+	// ActiveTexture is an index of active texture unit, starting from 0
+	ActiveTexture int
+	// Textures are GL_TEXTURE_2D textures bound to texture units
+	Textures     map[int]uint32
+	Capabilities map[uint32]bool
+	Scissor      [4]int32
+	Viewport     [4]int32
+	BlendFactors [2]uint32
+	ClearColor   [4]float32
+}
+
+func newState() State {
+	return State{
+		Buffers:      map[uint32]uint32{},
+		Textures:     map[int]uint32{},
+		Capabilities: map[uint32]bool{},
+		BlendFactors: [2]uint32{1, 0},
+	}
+}
+
+// Calls returns all recorded calls in order
+func (a *API) Calls() []Call {
+	calls := make([]Call, len(a.calls))
+	copy(calls, a.calls)
+	return calls
+}
+
+// CallsTo returns recorded calls of given method in order
+func (a *API) CallsTo(function string) []Call {
+	var calls []Call
+	for _, call := range a.calls {
+		if call.Function == function {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// ResetCalls forgets all recorded calls. Objects and state are not reset.
+func (a *API) ResetCalls() {
+	a.calls = nil
+}
+
+// Leaks returns objects which were created but not deleted, sorted by kind and
+// name.
+func (a *API) Leaks() []Object {
+	var leaks []Object
+	for object, alive := range a.objects {
+		if alive {
+			leaks = append(leaks, object)
+		}
+	}
+	sort.Slice(leaks, func(i, j int) bool {
+		if leaks[i].Kind != leaks[j].Kind {
+			return leaks[i].Kind < leaks[j].Kind
+		}
+		return leaks[i].Name < leaks[j].Name
+	})
+	return leaks
+}
+
+// InvalidUsages returns descriptions of calls which used names of objects
+// which were never created or were already deleted.
+func (a *API) InvalidUsages() []string {
+	usages := make([]string, len(a.invalidUsages))
+	copy(usages, a.invalidUsages)
+	return usages
+}
+
+// State returns a copy of currently bound state
+func (a *API) State() State {
+	state := a.state
+	state.Buffers = map[uint32]uint32{}
+	for target, buffer := range a.state.Buffers {
+		state.Buffers[target] = buffer
+	}
+	state.Textures = map[int]uint32{}
+	for unit, texture := range a.state.Textures {
+		state.Textures[unit] = texture
+	}
+	state.Capabilities = map[uint32]bool{}
+	for capability, enabled := range a.state.Capabilities {
+		state.Capabilities[capability] = enabled
+	}
+	return state
+}
+
+func (a *API) record(function string, args ...interface{}) *Call {
+	a.calls = append(a.calls, Call{Function: function, Args: args})
+	return &a.calls[len(a.calls)-1]
+}
+
+func (a *API) created(kind ObjectKind, name uint32) {
+	if name != 0 {
+		a.objects[Object{Kind: kind, Name: name}] = true
+	}
+}
+
+// use validates that object exists. Name 0 is always valid, because it means
+// default object or no object at all.
+func (a *API) use(function string, kind ObjectKind, name uint32) {
+	if name == 0 {
+		return
+	}
+	alive, found := a.objects[Object{Kind: kind, Name: name}]
+	switch {
+	case !found:
+		a.invalidUsages = append(a.invalidUsages,
+			fmt.Sprintf("%s: %s %d does not exist", function, kind, name))
+	case !alive:
+		a.invalidUsages = append(a.invalidUsages,
+			fmt.Sprintf("%s: %s %d was deleted", function, kind, name))
+	}
+}
+
+func (a *API) deleted(function string, kind ObjectKind, name uint32) {
+	a.use(function, kind, name)
+	object := Object{Kind: kind, Name: name}
+	if a.objects[object] {
+		a.objects[object] = false
+	}
+}
+
+// names returns a copy of n names stored at pointer
+func names(n int32, pointer *uint32) []uint32 {
+	if n <= 0 || pointer == nil {
+		return nil
+	}
+	slice := (*[1 << 28]uint32)(unsafe.Pointer(pointer))[:n:n]
+	result := make([]uint32, n)
+	copy(result, slice)
+	return result
+}
+
+func float32s(n int, pointer *float32) []float32 {
+	if n <= 0 || pointer == nil {
+		return nil
+	}
+	slice := (*[1 << 28]float32)(unsafe.Pointer(pointer))[:n:n]
+	result := make([]float32, n)
+	copy(result, slice)
+	return result
+}
+
+// goString converts C string to Go string. When length is negative the string
+// is null-terminated.
+func goString(cstr *uint8, length int) string {
+	if cstr == nil {
+		return ""
+	}
+	chars := (*[1 << 30]byte)(unsafe.Pointer(cstr))
+	if length < 0 {
+		length = 0
+		for chars[length] != 0 {
+			length++
+		}
+	}
+	return string(chars[:length])
+}
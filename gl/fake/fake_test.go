@@ -0,0 +1,278 @@
+package fake_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/gl/fake"
+	"github.com/elgopher/pixiq/gl/software"
+	"github.com/elgopher/pixiq/image"
+)
+
+const (
+	texture2D   = 0x0DE1
+	scissorTest = 0x0C11
+	blend       = 0x0BE2
+	framebuffer = 0x8D40
+)
+
+func TestNewAPI(t *testing.T) {
+	t.Run("should create API which can be used by gl.Context", func(t *testing.T) {
+		// when
+		api := fake.NewAPI()
+		// then
+		context := gl.NewContext(api)
+		assert.NoError(t, context.Error())
+		assert.Empty(t, api.InvalidUsages())
+	})
+}
+
+func TestWrap(t *testing.T) {
+	t.Run("should panic when api is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			fake.Wrap(nil)
+		})
+	})
+	t.Run("should delegate calls to wrapped api", func(t *testing.T) {
+		wrapped := software.NewAPI()
+		api := fake.Wrap(wrapped)
+		// when
+		api.BindTexture(texture2D, 99)
+		// then
+		assert.NotEqual(t, uint32(0), wrapped.GetError())
+	})
+}
+
+func TestAPI_Calls(t *testing.T) {
+	t.Run("should return empty calls", func(t *testing.T) {
+		api := fake.NewAPI()
+		assert.Empty(t, api.Calls())
+	})
+	t.Run("should record calls with arguments in order", func(t *testing.T) {
+		api := fake.NewAPI()
+		// when
+		api.Enable(scissorTest)
+		api.Scissor(1, 2, 3, 4)
+		// then
+		assert.Equal(t, []fake.Call{
+			{Function: "Enable", Args: []interface{}{uint32(scissorTest)}},
+			{Function: "Scissor", Args: []interface{}{int32(1), int32(2), int32(3), int32(4)}},
+		}, api.Calls())
+	})
+	t.Run("should record generated names", func(t *testing.T) {
+		api := fake.NewAPI()
+		var names [2]uint32
+		// when
+		api.GenTextures(2, &names[0])
+		// then
+		calls := api.CallsTo("GenTextures")
+		require.Len(t, calls, 1)
+		assert.Equal(t, []interface{}{int32(2), names[:]}, calls[0].Args)
+	})
+	t.Run("should record result", func(t *testing.T) {
+		api := fake.NewAPI()
+		// when
+		program := api.CreateProgram()
+		// then
+		assert.Equal(t, program, api.CallsTo("CreateProgram")[0].Result)
+	})
+	t.Run("should record shader source", func(t *testing.T) {
+		api := fake.NewAPI()
+		context := gl.NewContext(api)
+		// when
+		_, _ = context.CompileFragmentShader("void main() {}")
+		// then
+		calls := api.CallsTo("ShaderSource")
+		require.Len(t, calls, 1)
+		assert.Equal(t, []string{"void main() {}"}, calls[0].Args[2])
+	})
+}
+
+func TestAPI_CallsTo(t *testing.T) {
+	t.Run("should return calls of given method", func(t *testing.T) {
+		api := fake.NewAPI()
+		api.Enable(scissorTest)
+		api.Disable(blend)
+		api.Enable(blend)
+		// when
+		calls := api.CallsTo("Enable")
+		// then
+		assert.Equal(t, []fake.Call{
+			{Function: "Enable", Args: []interface{}{uint32(scissorTest)}},
+			{Function: "Enable", Args: []interface{}{uint32(blend)}},
+		}, calls)
+	})
+}
+
+func TestAPI_ResetCalls(t *testing.T) {
+	t.Run("should forget recorded calls", func(t *testing.T) {
+		api := fake.NewAPI()
+		api.Enable(scissorTest)
+		// when
+		api.ResetCalls()
+		// then
+		assert.Empty(t, api.Calls())
+	})
+}
+
+func TestCall_String(t *testing.T) {
+	var data [4]byte
+	tests := map[string]struct {
+		call     fake.Call
+		expected string
+	}{
+		"no args": {
+			call:     fake.Call{Function: "Finish"},
+			expected: "Finish()",
+		},
+		"scalars": {
+			call:     fake.Call{Function: "BindTexture", Args: []interface{}{uint32(3553), uint32(1)}},
+			expected: "BindTexture(3553, 1)",
+		},
+		"slice": {
+			call:     fake.Call{Function: "DeleteBuffers", Args: []interface{}{int32(2), []uint32{1, 2}}},
+			expected: "DeleteBuffers(2, [1 2])",
+		},
+		"pointers": {
+			call: fake.Call{Function: "BufferData", Args: []interface{}{
+				uint32(1), 4, unsafe.Pointer(nil), unsafe.Pointer(&data), uint32(2),
+			}},
+			expected: "BufferData(1, 4, nil, <pointer>, 2)",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.call.String())
+		})
+	}
+}
+
+func TestAPI_Leaks(t *testing.T) {
+	t.Run("should return objects which were not deleted", func(t *testing.T) {
+		api := fake.NewAPI()
+		context := gl.NewContext(api)
+		// when
+		context.NewAcceleratedImage(1, 1)
+		// then
+		assert.Equal(t, []fake.Object{
+			{Kind: fake.Framebuffer, Name: 2},
+			{Kind: fake.Texture, Name: 1},
+		}, api.Leaks())
+	})
+	t.Run("should not return deleted objects", func(t *testing.T) {
+		api := fake.NewAPI()
+		context := gl.NewContext(api)
+		vertexShader, err := context.CompileVertexShader("#version 330 core\nvoid main() {}")
+		require.NoError(t, err)
+		fragmentShader, err := context.CompileFragmentShader("#version 330 core\nvoid main() {}")
+		require.NoError(t, err)
+		program, err := context.LinkProgram(vertexShader, fragmentShader)
+		require.NoError(t, err)
+		img := context.NewAcceleratedImage(1, 1)
+		buffer := context.NewFloatVertexBuffer(1, gl.StaticDraw)
+		array := context.NewVertexArray(gl.VertexLayout{gl.Float})
+		// when
+		vertexShader.Delete()
+		fragmentShader.Delete()
+		program.Delete()
+		img.Delete()
+		buffer.Delete()
+		array.Delete()
+		// then
+		assert.Empty(t, api.Leaks())
+		assert.Empty(t, api.InvalidUsages())
+	})
+}
+
+func TestAPI_InvalidUsages(t *testing.T) {
+	t.Run("should report usage of object which does not exist", func(t *testing.T) {
+		api := fake.NewAPI()
+		// when
+		api.BindTexture(texture2D, 99)
+		// then
+		assert.Equal(t, []string{"BindTexture: texture 99 does not exist"}, api.InvalidUsages())
+	})
+	t.Run("should report usage of deleted object", func(t *testing.T) {
+		api := fake.NewAPI()
+		var buffer uint32
+		api.GenBuffers(1, &buffer)
+		api.DeleteBuffers(1, &buffer)
+		// when
+		api.DeleteBuffers(1, &buffer)
+		// then
+		assert.Equal(t, []string{"DeleteBuffers: buffer 1 was deleted"}, api.InvalidUsages())
+	})
+	t.Run("should not report usage of name 0", func(t *testing.T) {
+		api := fake.NewAPI()
+		// when
+		api.BindFramebuffer(framebuffer, 0)
+		api.UseProgram(0)
+		// then
+		assert.Empty(t, api.InvalidUsages())
+	})
+}
+
+func TestAPI_State(t *testing.T) {
+	t.Run("should return initial state", func(t *testing.T) {
+		api := fake.NewAPI()
+		// when
+		state := api.State()
+		// then
+		assert.Equal(t, uint32(0), state.Program)
+		assert.Equal(t, [2]uint32{1, 0}, state.BlendFactors)
+		assert.Empty(t, state.Textures)
+	})
+	t.Run("should return state after running command", func(t *testing.T) {
+		api := fake.NewAPI()
+		context := gl.NewContext(api)
+		vertexShader, err := context.CompileVertexShader("#version 330 core\nvoid main() {}")
+		require.NoError(t, err)
+		fragmentShader, err := context.CompileFragmentShader(`
+			#version 330 core
+			uniform sampler2D tex;
+			void main() {}`)
+		require.NoError(t, err)
+		program, err := context.LinkProgram(vertexShader, fragmentShader)
+		require.NoError(t, err)
+		output := context.NewAcceleratedImage(4, 3)
+		texture := context.NewAcceleratedImage(1, 1)
+		command := program.AcceleratedCommand(&command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+			renderer.BindTexture(1, "tex", texture)
+		}})
+		// when
+		command.Run(image.AcceleratedImageSelection{
+			Location: image.AcceleratedImageLocation{X: 1, Width: 2, Height: 3},
+			Image:    output,
+		}, nil)
+		// then
+		state := api.State()
+		assert.Equal(t, program.ID(), state.Program)
+		assert.True(t, state.Capabilities[scissorTest])
+		assert.True(t, state.Capabilities[blend])
+		assert.Equal(t, [4]int32{1, 0, 2, 3}, state.Scissor)
+		assert.Equal(t, [4]int32{1, 0, 2, 3}, state.Viewport)
+		assert.Equal(t, 1, state.ActiveTexture)
+		assert.Equal(t, texture.TextureID(), state.Textures[1])
+		assert.Empty(t, api.InvalidUsages())
+	})
+	t.Run("should return a copy", func(t *testing.T) {
+		api := fake.NewAPI()
+		state := api.State()
+		// when
+		state.Capabilities[blend] = true
+		// then
+		assert.False(t, api.State().Capabilities[blend])
+	})
+}
+
+type command struct {
+	runGL func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection)
+}
+
+func (c *command) RunGL(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+	c.runGL(renderer, selections)
+}
@@ -9,6 +9,9 @@
 package gl
 
 import (
+	"fmt"
+	"math"
+
 	"github.com/elgopher/pixiq/image"
 )
 
@@ -20,6 +23,7 @@ func NewContext(api API) *Context {
 	return &Context{
 		api:             api,
 		vertexBufferIDs: vertexBufferIDs{},
+		indexBuffers:    indexBuffers{},
 		allImages:       allImages{},
 		capabilities:    gatherCapabilities(api),
 	}
@@ -41,6 +45,9 @@ type VertexBuffer interface {
 
 // vertexBufferIDs contains all vertex buffer identifiers in OpenGL context
 type vertexBufferIDs map[VertexBuffer]uint32
+
+// indexBuffers contains all index buffers in OpenGL context
+type indexBuffers map[*IndexBuffer]struct{}
 type allImages map[image.AcceleratedImage]*AcceleratedImage
 
 // FloatVertexBuffer is a struct representing OpenGL's Vertex Buffer Object (VBO) containing only float32 numbers.
@@ -103,6 +110,123 @@ func (b *FloatVertexBuffer) Download(offset int, output []float32) {
 	b.api.GetBufferSubData(arrayBuffer, offset*4, size*4, b.api.Ptr(output))
 }
 
+// IndexType is a data type of indices stored in IndexBuffer.
+type IndexType struct {
+	xtype uint32
+	size  int
+	max   uint32
+	name  string
+}
+
+func (t IndexType) String() string {
+	return t.name
+}
+
+var (
+	// Uint16 is an unsigned 16-bit index (GL_UNSIGNED_SHORT). Indices can
+	// address at most 65536 vertices.
+	Uint16 = IndexType{xtype: unsignedShort, size: 2, max: math.MaxUint16, name: "Uint16"}
+	// Uint32 is an unsigned 32-bit index (GL_UNSIGNED_INT).
+	Uint32 = IndexType{xtype: unsignedInt, size: 4, max: math.MaxUint32, name: "Uint32"}
+)
+
+// IndexBuffer is a struct representing OpenGL's Element Buffer Object (EBO)
+// containing indices of vertices. It can be attached to VertexArray and used
+// by Renderer.DrawElements.
+type IndexBuffer struct {
+	id        uint32
+	deleted   bool
+	size      int
+	indexType IndexType
+	api       API
+}
+
+// Size is the number of indices defined during creation time.
+func (b *IndexBuffer) Size() int {
+	return b.size
+}
+
+// Type returns type of indices defined during creation time.
+func (b *IndexBuffer) Type() IndexType {
+	return b.indexType
+}
+
+// ID returns OpenGL identifier/name.
+func (b *IndexBuffer) ID() uint32 {
+	return b.id
+}
+
+// Upload sends indices to the buffer. All slice data will be inserted starting
+// at a given offset position.
+//
+// Panics when buffer is too small to hold the data, offset is negative or
+// index does not fit in the buffer's IndexType.
+func (b *IndexBuffer) Upload(offset int, indices []uint32) {
+	if offset < 0 {
+		panic("negative offset")
+	}
+	if b.size < len(indices)+offset {
+		panic("IndexBuffer is to small to store indices")
+	}
+	if len(indices) == 0 {
+		return
+	}
+	var data interface{} = indices
+	if b.indexType == Uint16 {
+		shorts := make([]uint16, len(indices))
+		for i, index := range indices {
+			if index > b.indexType.max {
+				panic(fmt.Sprintf("index %d does not fit in %s", index, b.indexType))
+			}
+			shorts[i] = uint16(index)
+		}
+		data = shorts
+	}
+	// Binding to GL_ELEMENT_ARRAY_BUFFER would modify currently bound vertex array
+	b.api.BindBuffer(arrayBuffer, b.id)
+	b.api.BufferSubData(arrayBuffer, offset*b.indexType.size, len(indices)*b.indexType.size, b.api.Ptr(data))
+}
+
+// Download gets indices starting at a given offset in VRAM and put them into
+// slice. Whole output slice will be filled with data, unless output slice is
+// bigger then the buffer.
+func (b *IndexBuffer) Download(offset int, output []uint32) {
+	if b.deleted {
+		panic("deleted buffer")
+	}
+	if offset < 0 {
+		panic("negative offset")
+	}
+	if len(output) == 0 {
+		return
+	}
+	size := len(output)
+	if size+offset > b.size {
+		size = b.size - offset
+	}
+	if size <= 0 {
+		return
+	}
+	b.api.BindBuffer(arrayBuffer, b.id)
+	if b.indexType == Uint16 {
+		shorts := make([]uint16, size)
+		b.api.GetBufferSubData(arrayBuffer, offset*2, size*2, b.api.Ptr(shorts))
+		for i, index := range shorts {
+			output[i] = uint32(index)
+		}
+		return
+	}
+	b.api.GetBufferSubData(arrayBuffer, offset*4, size*4, b.api.Ptr(output))
+}
+
+// Delete should be called whenever you don't plan to use index buffer anymore.
+// Index Buffer is external resource (like file for example) and must be
+// deleted manually
+func (b *IndexBuffer) Delete() {
+	b.api.DeleteBuffers(1, &b.id)
+	b.deleted = true
+}
+
 // VertexLayout defines data types of VertexArray locations.
 type VertexLayout []Type
 
@@ -153,6 +277,9 @@ type VertexArray struct {
 	id              uint32
 	layout          VertexLayout
 	vertexBufferIDs vertexBufferIDs
+	indexBuffers    indexBuffers
+	indexBuffer     *IndexBuffer
+	divisors        []int
 	api             API
 }
 
@@ -167,6 +294,10 @@ type VertexBufferPointer struct {
 	Buffer VertexBuffer
 	Offset int
 	Stride int
+	// Divisor is used by instanced rendering. When 0 (default), the attribute
+	// advances once per vertex. Otherwise it advances once per Divisor
+	// instances, so for example each sprite can have its own position.
+	Divisor int
 }
 
 // Set sets a location of VertexArray pointing to VertexBuffer slice.
@@ -177,6 +308,9 @@ func (a *VertexArray) Set(location int, pointer VertexBufferPointer) {
 	if pointer.Stride < 0 {
 		panic("negative pointer stride")
 	}
+	if pointer.Divisor < 0 {
+		panic("negative pointer divisor")
+	}
 	if pointer.Buffer == nil {
 		panic("nil pointer buffer")
 	}
@@ -202,6 +336,24 @@ func (a *VertexArray) Set(location int, pointer VertexBufferPointer) {
 		int32(pointer.Stride*4),
 		a.api.PtrOffset(pointer.Offset*4),
 	)
+	if a.divisors[location] != pointer.Divisor {
+		a.api.VertexAttribDivisor(uint32(location), uint32(pointer.Divisor))
+		a.divisors[location] = pointer.Divisor
+	}
+}
+
+// SetIndexBuffer attaches IndexBuffer to VertexArray. Indices from the buffer
+// are used by Renderer.DrawElements.
+func (a *VertexArray) SetIndexBuffer(buffer *IndexBuffer) {
+	if buffer == nil {
+		panic("nil buffer")
+	}
+	if _, ok := a.indexBuffers[buffer]; !ok {
+		panic("index buffer has not been created in this context")
+	}
+	a.api.BindVertexArray(a.id)
+	a.api.BindBuffer(elementArrayBuffer, buffer.id)
+	a.indexBuffer = buffer
 }
 
 // ID returns VertexArray identifier (aka name)
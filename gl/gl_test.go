@@ -5,6 +5,7 @@ import (
 	"unsafe"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/elgopher/pixiq/gl"
 	"github.com/elgopher/pixiq/image"
@@ -136,6 +137,124 @@ func TestFloatVertexBuffer_Download(t *testing.T) {
 	})
 }
 
+func TestContext_NewIndexBuffer(t *testing.T) {
+	t.Run("should panic when size is negative", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		assert.Panics(t, func() {
+			// when
+			context.NewIndexBuffer(-1, gl.Uint16, gl.StaticDraw)
+		})
+	})
+	t.Run("should panic when index type is not supported", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		assert.Panics(t, func() {
+			// when
+			context.NewIndexBuffer(1, gl.IndexType{}, gl.StaticDraw)
+		})
+	})
+	t.Run("should create IndexBuffer", func(t *testing.T) {
+		tests := map[string]struct {
+			size      int
+			indexType gl.IndexType
+		}{
+			"size 0, Uint16": {
+				indexType: gl.Uint16,
+			},
+			"size 1, Uint16": {
+				size:      1,
+				indexType: gl.Uint16,
+			},
+			"size 2, Uint32": {
+				size:      2,
+				indexType: gl.Uint32,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				context := gl.NewContext(apiStub{})
+				// when
+				buffer := context.NewIndexBuffer(test.size, test.indexType, gl.StaticDraw)
+				// then
+				assert.NotNil(t, buffer)
+				assert.Equal(t, test.size, buffer.Size())
+				assert.Equal(t, test.indexType, buffer.Type())
+			})
+		}
+	})
+}
+
+func TestIndexBuffer_Upload(t *testing.T) {
+	t.Run("should panic when trying to upload slice bigger than size", func(t *testing.T) {
+		tests := map[string]struct {
+			offset  int
+			size    int
+			indices []uint32
+		}{
+			"size 0, offset 0, indices len 1": {
+				indices: []uint32{1},
+			},
+			"size 1, offset 0, indices len 2": {
+				size:    1,
+				indices: []uint32{1, 2},
+			},
+			"size 1, offset 1, indices len 1": {
+				size:    1,
+				offset:  1,
+				indices: []uint32{1},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				context := gl.NewContext(apiStub{})
+				buffer := context.NewIndexBuffer(test.size, gl.Uint32, gl.StaticDraw)
+				assert.Panics(t, func() {
+					// when
+					buffer.Upload(test.offset, test.indices)
+				})
+			})
+		}
+	})
+	t.Run("should panic when offset is negative", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		buffer := context.NewIndexBuffer(1, gl.Uint32, gl.StaticDraw)
+		assert.Panics(t, func() {
+			// when
+			buffer.Upload(-1, []uint32{1})
+		})
+	})
+	t.Run("should panic when index does not fit in Uint16", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		buffer := context.NewIndexBuffer(1, gl.Uint16, gl.StaticDraw)
+		assert.Panics(t, func() {
+			// when
+			buffer.Upload(0, []uint32{65536})
+		})
+	})
+}
+
+func TestIndexBuffer_Download(t *testing.T) {
+	t.Run("should panic when offset is negative", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		buffer := context.NewIndexBuffer(1, gl.Uint32, gl.StaticDraw)
+		defer buffer.Delete()
+		output := make([]uint32, 1)
+		assert.Panics(t, func() {
+			// when
+			buffer.Download(-1, output)
+		})
+	})
+	t.Run("should panic when buffer has been deleted", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		buffer := context.NewIndexBuffer(1, gl.Uint32, gl.StaticDraw)
+		buffer.Delete()
+		output := make([]uint32, 1)
+		assert.Panics(t, func() {
+			// when
+			buffer.Download(0, output)
+		})
+	})
+}
+
 func TestOpenGL_NewVertexArray(t *testing.T) {
 	t.Run("should panic", func(t *testing.T) {
 		tests := map[string]struct {
@@ -192,6 +311,21 @@ func TestVertexArray_Set(t *testing.T) {
 			vao.Set(0, pointer)
 		})
 	})
+	t.Run("should panic when divisor is negative", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		vao := context.NewVertexArray(gl.VertexLayout{gl.Float})
+		buffer := context.NewFloatVertexBuffer(1, gl.StaticDraw)
+		pointer := gl.VertexBufferPointer{
+			Buffer:  buffer,
+			Offset:  0,
+			Stride:  1,
+			Divisor: -1,
+		}
+		assert.Panics(t, func() {
+			// when
+			vao.Set(0, pointer)
+		})
+	})
 	t.Run("should panic when location is negative", func(t *testing.T) {
 		context := gl.NewContext(apiStub{})
 		vao := context.NewVertexArray(gl.VertexLayout{gl.Float})
@@ -248,6 +382,28 @@ func TestVertexArray_Set(t *testing.T) {
 		})
 	})
 }
+
+func TestVertexArray_SetIndexBuffer(t *testing.T) {
+	t.Run("should panic when buffer is nil", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		vao := context.NewVertexArray(gl.VertexLayout{gl.Float})
+		assert.Panics(t, func() {
+			// when
+			vao.SetIndexBuffer(nil)
+		})
+	})
+	t.Run("should panic when buffer was not created by context", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		vao := context.NewVertexArray(gl.VertexLayout{gl.Float})
+		otherContext := gl.NewContext(apiStub{})
+		buffer := otherContext.NewIndexBuffer(1, gl.Uint16, gl.StaticDraw)
+		assert.Panics(t, func() {
+			// when
+			vao.SetIndexBuffer(buffer)
+		})
+	})
+}
+
 func TestOpenGL_LinkProgram(t *testing.T) {
 	t.Run("should panic when vertex shader is nil", func(t *testing.T) {
 		context := gl.NewContext(apiStub{})
@@ -266,6 +422,47 @@ func TestOpenGL_LinkProgram(t *testing.T) {
 			_, _ = context.LinkProgram(vertexShader, nil)
 		})
 	})
+	t.Run("should ignore built-in attributes", func(t *testing.T) {
+		context := gl.NewContext(builtinAttributeAPI{})
+		vertexShader, _ := context.CompileVertexShader("")
+		fragmentShader, _ := context.CompileFragmentShader("")
+		// when
+		program, err := context.LinkProgram(vertexShader, fragmentShader)
+		// then
+		require.NoError(t, err)
+		assert.NotNil(t, program)
+	})
+}
+
+// builtinAttributeAPI reports gl_InstanceID as an active attribute, just like
+// some drivers do
+type builtinAttributeAPI struct {
+	apiStub
+}
+
+func (a builtinAttributeAPI) GetProgramiv(program uint32, pname uint32, params *int32) {
+	const (
+		activeAttributes         = 0x8B89
+		activeAttributeMaxLength = 0x8B8A
+	)
+	switch pname {
+	case activeAttributes:
+		*params = 1
+		return
+	case activeAttributeMaxLength:
+		*params = int32(len("gl_InstanceID") + 1)
+		return
+	}
+	a.apiStub.GetProgramiv(program, pname, params)
+}
+
+func (a builtinAttributeAPI) GetActiveAttrib(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	const intType = 0x1404
+	*xtype = intType
+}
+
+func (a builtinAttributeAPI) GetAttribLocation(program uint32, name *uint8) int32 {
+	return -1
 }
 func TestContext_NewAcceleratedImage(t *testing.T) {
 	t.Run("should panic for negative width", func(t *testing.T) {
@@ -307,6 +504,352 @@ func TestContext_NewAcceleratedImage(t *testing.T) {
 		assert.Equal(t, 2, img.Height())
 	})
 }
+
+func TestAcceleratedImage_UploadRegion(t *testing.T) {
+	t.Run("should panic when pixels slice is not of location width*height length", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		img := context.NewAcceleratedImage(2, 2)
+		location := image.AcceleratedImageLocation{Width: 2, Height: 1}
+		assert.Panics(t, func() {
+			// when
+			img.UploadRegion(location, make([]image.Color, 1))
+		})
+	})
+}
+
+func TestAcceleratedImage_DownloadRegion(t *testing.T) {
+	t.Run("should panic when output slice is not of location width*height length", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		img := context.NewAcceleratedImage(2, 2)
+		location := image.AcceleratedImageLocation{Width: 1, Height: 2}
+		assert.Panics(t, func() {
+			// when
+			img.DownloadRegion(location, make([]image.Color, 3))
+		})
+	})
+}
+
+func TestContext_DownloadAsync(t *testing.T) {
+	t.Run("should panic when selection image is nil", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		assert.Panics(t, func() {
+			// when
+			context.DownloadAsync(image.AcceleratedImageSelection{})
+		})
+	})
+	t.Run("should panic when image was created in a different context", func(t *testing.T) {
+		imageContext := gl.NewContext(apiStub{})
+		img := imageContext.NewAcceleratedImage(1, 1)
+		context := gl.NewContext(apiStub{})
+		assert.Panics(t, func() {
+			// when
+			context.DownloadAsync(image.AcceleratedImageSelection{
+				Image:    img,
+				Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+			})
+		})
+	})
+	t.Run("should panic when location is out of image bounds", func(t *testing.T) {
+		tests := map[string]image.AcceleratedImageLocation{
+			"negative X":     {X: -1, Width: 1, Height: 1},
+			"negative Y":     {Y: -1, Width: 1, Height: 1},
+			"too big width":  {Width: 3, Height: 1},
+			"too big height": {Width: 1, Height: 3},
+			"too big X":      {X: 2, Width: 1, Height: 1},
+			"too big Y":      {Y: 2, Width: 1, Height: 1},
+		}
+		for name, location := range tests {
+			t.Run(name, func(t *testing.T) {
+				context := gl.NewContext(apiStub{})
+				img := context.NewAcceleratedImage(2, 2)
+				assert.Panics(t, func() {
+					// when
+					context.DownloadAsync(image.AcceleratedImageSelection{
+						Image:    img,
+						Location: location,
+					})
+				})
+			})
+		}
+	})
+	t.Run("should return AsyncDownload", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		img := context.NewAcceleratedImage(2, 2)
+		// when
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{X: 1, Width: 1, Height: 2},
+		})
+		// then
+		assert.NotNil(t, download)
+		assert.Equal(t, 2, download.Size())
+	})
+}
+
+func TestAsyncDownload_Resolve(t *testing.T) {
+	t.Run("should panic when output slice is not of location width*height length", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		img := context.NewAcceleratedImage(2, 2)
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 2, Height: 1},
+		})
+		assert.Panics(t, func() {
+			// when
+			download.Resolve(make([]image.Color, 1))
+		})
+	})
+	t.Run("should panic when AsyncDownload has been deleted", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		img := context.NewAcceleratedImage(1, 1)
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		})
+		download.Delete()
+		assert.Panics(t, func() {
+			// when
+			download.Resolve(make([]image.Color, 1))
+		})
+	})
+	t.Run("should copy pixels from mapped buffer", func(t *testing.T) {
+		color1 := image.RGBA(10, 20, 30, 40)
+		color2 := image.RGBA(50, 60, 70, 80)
+		api := &mappedBufferAPI{mapped: []image.Color{color1, color2}}
+		context := gl.NewContext(api)
+		img := context.NewAcceleratedImage(2, 1)
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 2, Height: 1},
+		})
+		output := make([]image.Color, 2)
+		// when
+		download.Resolve(output)
+		// then
+		assert.Equal(t, []image.Color{color1, color2}, output)
+		assert.True(t, api.unmapped)
+	})
+	t.Run("should panic when pixel pack buffer can't be mapped", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		img := context.NewAcceleratedImage(1, 1)
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		})
+		assert.Panics(t, func() {
+			// when
+			download.Resolve(make([]image.Color, 1))
+		})
+	})
+}
+
+func TestAsyncDownload_Ready(t *testing.T) {
+	t.Run("should return result of waiting for the fence", func(t *testing.T) {
+		tests := map[string]struct {
+			waitResult uint32
+			expected   bool
+		}{
+			"already signaled": {
+				waitResult: 0x911A,
+				expected:   true,
+			},
+			"condition satisfied": {
+				waitResult: 0x911C,
+				expected:   true,
+			},
+			"timeout expired": {
+				waitResult: 0x911B,
+				expected:   false,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				api := &downloadAPI{waitResult: test.waitResult}
+				context := gl.NewContext(api)
+				img := context.NewAcceleratedImage(1, 1)
+				download := context.DownloadAsync(image.AcceleratedImageSelection{
+					Image:    img,
+					Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+				})
+				// when
+				ready := download.Ready()
+				// then
+				assert.Equal(t, test.expected, ready)
+				assert.Equal(t, 0, api.waitTimeout)
+			})
+		}
+	})
+	t.Run("should panic when waiting failed", func(t *testing.T) {
+		const waitFailed = 0x911D
+		context := gl.NewContext(&downloadAPI{waitResult: waitFailed})
+		img := context.NewAcceleratedImage(1, 1)
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		})
+		assert.Panics(t, func() {
+			// when
+			download.Ready()
+		})
+	})
+	t.Run("should return true for empty selection", func(t *testing.T) {
+		const timeoutExpired = 0x911B
+		context := gl.NewContext(&downloadAPI{waitResult: timeoutExpired})
+		img := context.NewAcceleratedImage(1, 1)
+		download := context.DownloadAsync(image.AcceleratedImageSelection{Image: img})
+		// when
+		ready := download.Ready()
+		// then
+		assert.True(t, ready)
+	})
+	t.Run("should panic when AsyncDownload has been deleted", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		img := context.NewAcceleratedImage(1, 1)
+		download := context.DownloadAsync(image.AcceleratedImageSelection{Image: img})
+		download.Delete()
+		assert.Panics(t, func() {
+			// when
+			download.Ready()
+		})
+	})
+}
+
+func TestAsyncDownload_Reuse(t *testing.T) {
+	t.Run("should panic when AsyncDownload has been deleted", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		img := context.NewAcceleratedImage(1, 1)
+		selection := image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		}
+		download := context.DownloadAsync(selection)
+		download.Delete()
+		assert.Panics(t, func() {
+			// when
+			download.Reuse(selection)
+		})
+	})
+	t.Run("should panic when location is out of image bounds", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		img := context.NewAcceleratedImage(1, 1)
+		download := context.DownloadAsync(image.AcceleratedImageSelection{Image: img})
+		assert.Panics(t, func() {
+			// when
+			download.Reuse(image.AcceleratedImageSelection{
+				Image:    img,
+				Location: image.AcceleratedImageLocation{Width: 2, Height: 1},
+			})
+		})
+	})
+	t.Run("should reuse buffer when selection is not bigger", func(t *testing.T) {
+		api := &downloadAPI{}
+		context := gl.NewContext(api)
+		img := context.NewAcceleratedImage(2, 2)
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 2, Height: 2},
+		})
+		api.bufferDataCalls = 0
+		// when
+		download.Reuse(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 2},
+		})
+		// then
+		assert.Equal(t, 2, download.Size())
+		assert.Equal(t, 1, api.genBuffersCalls)
+		assert.Equal(t, 0, api.bufferDataCalls)
+	})
+	t.Run("should reallocate buffer when selection is bigger", func(t *testing.T) {
+		api := &downloadAPI{}
+		context := gl.NewContext(api)
+		img := context.NewAcceleratedImage(2, 2)
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		})
+		api.bufferDataCalls = 0
+		// when
+		download.Reuse(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 2, Height: 2},
+		})
+		// then
+		assert.Equal(t, 4, download.Size())
+		assert.Equal(t, 1, api.genBuffersCalls)
+		assert.Equal(t, 1, api.bufferDataCalls)
+	})
+}
+
+func TestAsyncDownload_Delete(t *testing.T) {
+	t.Run("should delete buffer and fence only once", func(t *testing.T) {
+		api := &downloadAPI{}
+		context := gl.NewContext(api)
+		img := context.NewAcceleratedImage(1, 1)
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		})
+		// when
+		download.Delete()
+		download.Delete()
+		// then
+		assert.Equal(t, 1, api.deleteBuffersCalls)
+		assert.Equal(t, 1, api.deleteSyncCalls)
+	})
+}
+
+// downloadAPI counts calls related to AsyncDownload
+type downloadAPI struct {
+	apiStub
+	waitResult         uint32
+	waitTimeout        int
+	genBuffersCalls    int
+	bufferDataCalls    int
+	deleteBuffersCalls int
+	deleteSyncCalls    int
+}
+
+func (a *downloadAPI) GenBuffers(n int32, buffers *uint32) {
+	a.genBuffersCalls++
+}
+
+func (a *downloadAPI) BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
+	a.bufferDataCalls++
+}
+
+func (a *downloadAPI) DeleteBuffers(n int32, buffers *uint32) {
+	a.deleteBuffersCalls++
+}
+
+func (a *downloadAPI) FenceSync(condition uint32, flags uint32) uintptr {
+	return 1
+}
+
+func (a *downloadAPI) ClientWaitSync(sync uintptr, flags uint32, timeout uint64) uint32 {
+	a.waitTimeout = int(timeout)
+	return a.waitResult
+}
+
+func (a *downloadAPI) DeleteSync(sync uintptr) {
+	a.deleteSyncCalls++
+}
+
+type mappedBufferAPI struct {
+	apiStub
+	mapped   []image.Color
+	unmapped bool
+}
+
+func (a *mappedBufferAPI) MapBufferRange(target uint32, offset int, length int, access uint32) unsafe.Pointer {
+	return unsafe.Pointer(&a.mapped[0])
+}
+
+func (a *mappedBufferAPI) UnmapBuffer(target uint32) bool {
+	a.unmapped = true
+	return true
+}
+
 func TestProgram_AcceleratedCommand(t *testing.T) {
 	t.Run("should return command", func(t *testing.T) {
 		context := gl.NewContext(apiStub{})
@@ -417,6 +960,124 @@ func TestRenderer_BindTexture(t *testing.T) {
 	})
 }
 
+func TestRenderer_DrawElements(t *testing.T) {
+	t.Run("should panic", func(t *testing.T) {
+		tests := map[string]struct {
+			withoutIndexBuffer bool
+			first, count       int
+			expectedPanic      string
+		}{
+			"when vertex array has no index buffer": {
+				withoutIndexBuffer: true,
+				count:              1,
+				expectedPanic:      "vertex array has no index buffer",
+			},
+			"when first is negative": {
+				first:         -1,
+				count:         1,
+				expectedPanic: "negative first",
+			},
+			"when count is negative": {
+				count:         -1,
+				expectedPanic: "negative count",
+			},
+			"when count is higher than index buffer size": {
+				count:         4,
+				expectedPanic: "indices out of index buffer bounds",
+			},
+			"when first+count is higher than index buffer size": {
+				first:         1,
+				count:         3,
+				expectedPanic: "indices out of index buffer bounds",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				context := gl.NewContext(apiStub{})
+				array := context.NewVertexArray(gl.VertexLayout{gl.Float})
+				if !test.withoutIndexBuffer {
+					array.SetIndexBuffer(context.NewIndexBuffer(3, gl.Uint16, gl.StaticDraw))
+				}
+				output := context.NewAcceleratedImage(1, 1)
+				executed := false
+				command := workingProgram(context).AcceleratedCommand(&command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+					executed = true
+					assert.PanicsWithValue(t, test.expectedPanic, func() {
+						// when
+						renderer.DrawElements(array, gl.Triangles, test.first, test.count)
+					})
+				}})
+				command.Run(image.AcceleratedImageSelection{
+					Image:    output,
+					Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+				}, nil)
+				assert.True(t, executed)
+			})
+		}
+	})
+}
+
+func TestRenderer_DrawArraysInstanced(t *testing.T) {
+	t.Run("should panic when instanceCount is negative", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		array := context.NewVertexArray(gl.VertexLayout{gl.Float})
+		output := context.NewAcceleratedImage(1, 1)
+		executed := false
+		command := workingProgram(context).AcceleratedCommand(&command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+			executed = true
+			assert.PanicsWithValue(t, "negative instanceCount", func() {
+				// when
+				renderer.DrawArraysInstanced(array, gl.Triangles, 0, 3, -1)
+			})
+		}})
+		command.Run(image.AcceleratedImageSelection{
+			Image:    output,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		}, nil)
+		assert.True(t, executed)
+	})
+}
+
+func TestRenderer_DrawElementsInstanced(t *testing.T) {
+	t.Run("should panic when instanceCount is negative", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		array := context.NewVertexArray(gl.VertexLayout{gl.Float})
+		array.SetIndexBuffer(context.NewIndexBuffer(3, gl.Uint16, gl.StaticDraw))
+		output := context.NewAcceleratedImage(1, 1)
+		executed := false
+		command := workingProgram(context).AcceleratedCommand(&command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+			executed = true
+			assert.PanicsWithValue(t, "negative instanceCount", func() {
+				// when
+				renderer.DrawElementsInstanced(array, gl.Triangles, 0, 3, -1)
+			})
+		}})
+		command.Run(image.AcceleratedImageSelection{
+			Image:    output,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		}, nil)
+		assert.True(t, executed)
+	})
+	t.Run("should panic when vertex array has no index buffer", func(t *testing.T) {
+		context := gl.NewContext(apiStub{})
+		array := context.NewVertexArray(gl.VertexLayout{gl.Float})
+		output := context.NewAcceleratedImage(1, 1)
+		executed := false
+		command := workingProgram(context).AcceleratedCommand(&command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+			executed = true
+			assert.PanicsWithValue(t, "vertex array has no index buffer", func() {
+				// when
+				renderer.DrawElementsInstanced(array, gl.Triangles, 0, 3, 1)
+			})
+		}})
+		command.Run(image.AcceleratedImageSelection{
+			Image:    output,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		}, nil)
+		assert.True(t, executed)
+	})
+}
+
 func TestOpenGL_Error(t *testing.T) {
 	t.Run("should no return error", func(t *testing.T) {
 		context := gl.NewContext(apiStub{})
@@ -469,11 +1130,24 @@ func (a apiStub) BufferData(target uint32, size int, data unsafe.Pointer, usage
 func (a apiStub) BufferSubData(target uint32, offset int, size int, data unsafe.Pointer)    {}
 func (a apiStub) GetBufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {}
 func (a apiStub) DeleteBuffers(n int32, buffers *uint32)                                    {}
-func (a apiStub) GenVertexArrays(n int32, arrays *uint32)                                   {}
-func (a apiStub) DeleteVertexArrays(n int32, arrays *uint32)                                {}
-func (a apiStub) BindVertexArray(array uint32)                                              {}
+func (a apiStub) MapBufferRange(target uint32, offset int, length int, access uint32) unsafe.Pointer {
+	return nil
+}
+func (a apiStub) UnmapBuffer(target uint32) bool { return true }
+func (a apiStub) FenceSync(condition uint32, flags uint32) uintptr {
+	return 0
+}
+func (a apiStub) ClientWaitSync(sync uintptr, flags uint32, timeout uint64) uint32 {
+	const alreadySignaled = 0x911A
+	return alreadySignaled
+}
+func (a apiStub) DeleteSync(sync uintptr)                    {}
+func (a apiStub) GenVertexArrays(n int32, arrays *uint32)    {}
+func (a apiStub) DeleteVertexArrays(n int32, arrays *uint32) {}
+func (a apiStub) BindVertexArray(array uint32)               {}
 func (a apiStub) VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer unsafe.Pointer) {
 }
+func (a apiStub) VertexAttribDivisor(index uint32, divisor uint32)                        {}
 func (a apiStub) EnableVertexAttribArray(index uint32)                                    {}
 func (a apiStub) CreateShader(xtype uint32) uint32                                        { return 0 }
 func (a apiStub) ShaderSource(shader uint32, count int32, xstring **uint8, length *int32) {}
@@ -508,15 +1182,20 @@ func (a apiStub) GetActiveUniform(program uint32, index uint32, bufSize int32, l
 }
 func (a apiStub) GetActiveAttrib(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
 }
-func (a apiStub) GetAttribLocation(program uint32, name *uint8) int32                          { return 0 }
-func (a apiStub) Enable(cap uint32)                                                            {}
-func (a apiStub) Disable(cap uint32)                                                           {}
-func (a apiStub) BindFramebuffer(target uint32, framebuffer uint32)                            {}
-func (a apiStub) Scissor(x int32, y int32, width int32, height int32)                          {}
-func (a apiStub) Viewport(x int32, y int32, width int32, height int32)                         {}
-func (a apiStub) ClearColor(red float32, green float32, blue float32, alpha float32)           {}
-func (a apiStub) Clear(mask uint32)                                                            {}
-func (a apiStub) DrawArrays(mode uint32, first int32, count int32)                             {}
+func (a apiStub) GetAttribLocation(program uint32, name *uint8) int32                         { return 0 }
+func (a apiStub) Enable(cap uint32)                                                           {}
+func (a apiStub) Disable(cap uint32)                                                          {}
+func (a apiStub) BindFramebuffer(target uint32, framebuffer uint32)                           {}
+func (a apiStub) Scissor(x int32, y int32, width int32, height int32)                         {}
+func (a apiStub) Viewport(x int32, y int32, width int32, height int32)                        {}
+func (a apiStub) ClearColor(red float32, green float32, blue float32, alpha float32)          {}
+func (a apiStub) Clear(mask uint32)                                                           {}
+func (a apiStub) DrawArrays(mode uint32, first int32, count int32)                            {}
+func (a apiStub) DrawElements(mode uint32, count int32, xtype uint32, indices unsafe.Pointer) {}
+func (a apiStub) DrawArraysInstanced(mode uint32, first int32, count int32, instancecount int32) {
+}
+func (a apiStub) DrawElementsInstanced(mode uint32, count int32, xtype uint32, indices unsafe.Pointer, instancecount int32) {
+}
 func (a apiStub) Uniform1f(location int32, v0 float32)                                         {}
 func (a apiStub) Uniform2f(location int32, v0 float32, v1 float32)                             {}
 func (a apiStub) Uniform3f(location int32, v0 float32, v1 float32, v2 float32)                 {}
@@ -114,6 +114,29 @@ func (i *AcceleratedImage) Upload(pixels []image.Color) {
 	)
 }
 
+// UploadRegion send pixels of a given region to video card. It implements
+// image.RegionUploader.
+func (i *AcceleratedImage) UploadRegion(location image.AcceleratedImageLocation, pixels []image.Color) {
+	if len(pixels) == 0 {
+		return
+	}
+	if len(pixels) != location.Width*location.Height {
+		panic("pixels slice is not of length location.Width*location.Height")
+	}
+	i.api.BindTexture(texture2D, i.textureID)
+	i.api.TexSubImage2D(
+		texture2D,
+		0,
+		int32(location.X),
+		int32(i.height-location.Y-location.Height),
+		int32(location.Width),
+		int32(location.Height),
+		rgba,
+		unsignedByte,
+		i.api.Ptr(pixels),
+	)
+}
+
 // Download gets pixels pixels from video card
 func (i *AcceleratedImage) Download(output []image.Color) {
 	if len(output) == 0 {
@@ -129,6 +152,27 @@ func (i *AcceleratedImage) Download(output []image.Color) {
 	)
 }
 
+// DownloadRegion gets pixels of a given region from video card. It implements
+// image.RegionDownloader.
+func (i *AcceleratedImage) DownloadRegion(location image.AcceleratedImageLocation, output []image.Color) {
+	if len(output) == 0 {
+		return
+	}
+	if len(output) != location.Width*location.Height {
+		panic("output slice is not of length location.Width*location.Height")
+	}
+	i.api.BindFramebuffer(framebuffer, i.frameBufferID)
+	i.api.ReadPixels(
+		int32(location.X),
+		int32(i.height-location.Y-location.Height),
+		int32(location.Width),
+		int32(location.Height),
+		rgba,
+		unsignedByte,
+		i.api.Ptr(output),
+	)
+}
+
 // Width returns the number of pixels in a row.
 func (i *AcceleratedImage) Width() int {
 	return i.width
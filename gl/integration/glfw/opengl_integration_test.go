@@ -467,6 +467,187 @@ func TestAcceleratedImage_Upload(t *testing.T) {
 	})
 }
 
+func TestAcceleratedImage_UploadRegion(t *testing.T) {
+	color1 := image.RGBA(10, 20, 30, 40)
+	color2 := image.RGBA(50, 60, 70, 80)
+	tr := image.Transparent
+
+	tests := map[string]struct {
+		location       image.AcceleratedImageLocation
+		inputColors    []image.Color
+		expectedColors []image.Color
+	}{
+		"top-left pixel": {
+			location:       image.AcceleratedImageLocation{Width: 1, Height: 1},
+			inputColors:    []image.Color{color1},
+			expectedColors: []image.Color{tr, tr, tr, color1, tr, tr},
+		},
+		"bottom-right 2x1": {
+			location:       image.AcceleratedImageLocation{X: 1, Y: 1, Width: 2, Height: 1},
+			inputColors:    []image.Color{color1, color2},
+			expectedColors: []image.Color{tr, color1, color2, tr, tr, tr},
+		},
+		"right column": {
+			location:       image.AcceleratedImageLocation{X: 2, Width: 1, Height: 2},
+			inputColors:    []image.Color{color1, color2},
+			expectedColors: []image.Color{tr, tr, color1, tr, tr, color2},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+			defer openGL.Destroy()
+			context := openGL.Context()
+			img := context.NewAcceleratedImage(3, 2)
+			// when
+			img.UploadRegion(test.location, test.inputColors)
+			// then
+			assertColors(t, test.expectedColors, img)
+		})
+	}
+}
+
+func TestAcceleratedImage_DownloadRegion(t *testing.T) {
+	color1 := image.RGBA(10, 20, 30, 40)
+	color2 := image.RGBA(50, 60, 70, 80)
+	color3 := image.RGBA(90, 100, 110, 120)
+	color4 := image.RGBA(130, 140, 150, 160)
+	color5 := image.RGBA(170, 180, 190, 200)
+	color6 := image.RGBA(210, 220, 230, 240)
+
+	tests := map[string]struct {
+		location       image.AcceleratedImageLocation
+		expectedColors []image.Color
+	}{
+		"top-left pixel": {
+			location:       image.AcceleratedImageLocation{Width: 1, Height: 1},
+			expectedColors: []image.Color{color4},
+		},
+		"bottom-right 2x1": {
+			location:       image.AcceleratedImageLocation{X: 1, Y: 1, Width: 2, Height: 1},
+			expectedColors: []image.Color{color2, color3},
+		},
+		"right column": {
+			location:       image.AcceleratedImageLocation{X: 2, Width: 1, Height: 2},
+			expectedColors: []image.Color{color3, color6},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+			defer openGL.Destroy()
+			context := openGL.Context()
+			img := context.NewAcceleratedImage(3, 2)
+			img.Upload([]image.Color{color1, color2, color3, color4, color5, color6})
+			output := make([]image.Color, len(test.expectedColors))
+			// when
+			img.DownloadRegion(test.location, output)
+			// then
+			assert.Equal(t, test.expectedColors, output)
+		})
+	}
+}
+
+func TestContext_DownloadAsync(t *testing.T) {
+	color1 := image.RGBA(10, 20, 30, 40)
+	color2 := image.RGBA(50, 60, 70, 80)
+	color3 := image.RGBA(90, 100, 110, 120)
+	color4 := image.RGBA(130, 140, 150, 160)
+
+	t.Run("should download pixels", func(t *testing.T) {
+		tests := map[string]struct {
+			location       image.AcceleratedImageLocation
+			expectedColors []image.Color
+		}{
+			"whole image": {
+				location:       image.AcceleratedImageLocation{Width: 2, Height: 2},
+				expectedColors: []image.Color{color1, color2, color3, color4},
+			},
+			"top-right pixel": {
+				location:       image.AcceleratedImageLocation{X: 1, Width: 1, Height: 1},
+				expectedColors: []image.Color{color4},
+			},
+			"left column": {
+				location:       image.AcceleratedImageLocation{Width: 1, Height: 2},
+				expectedColors: []image.Color{color1, color3},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+				defer openGL.Destroy()
+				context := openGL.Context()
+				img := context.NewAcceleratedImage(2, 2)
+				img.Upload([]image.Color{color1, color2, color3, color4})
+				download := context.DownloadAsync(image.AcceleratedImageSelection{
+					Image:    img,
+					Location: test.location,
+				})
+				defer download.Delete()
+				output := make([]image.Color, len(test.expectedColors))
+				// when
+				download.Resolve(output)
+				// then
+				assert.Equal(t, test.expectedColors, output)
+				assert.NoError(t, context.Error())
+			})
+		}
+	})
+	t.Run("synchronous download should work after asynchronous one", func(t *testing.T) {
+		openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+		defer openGL.Destroy()
+		context := openGL.Context()
+		img := context.NewAcceleratedImage(1, 1)
+		img.Upload([]image.Color{color1})
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		})
+		defer download.Delete()
+		// when
+		assertColors(t, []image.Color{color1}, img)
+	})
+	t.Run("should download pixels using reused buffer", func(t *testing.T) {
+		openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+		defer openGL.Destroy()
+		context := openGL.Context()
+		img := context.NewAcceleratedImage(2, 2)
+		img.Upload([]image.Color{color1, color2, color3, color4})
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 2, Height: 2},
+		})
+		defer download.Delete()
+		// when
+		download.Reuse(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{X: 1, Width: 1, Height: 2},
+		})
+		// then
+		output := make([]image.Color, 2)
+		download.Resolve(output)
+		assert.Equal(t, []image.Color{color2, color4}, output)
+		assert.NoError(t, context.Error())
+	})
+	t.Run("should be ready after GPU finished all commands", func(t *testing.T) {
+		openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+		defer openGL.Destroy()
+		context := openGL.Context()
+		img := context.NewAcceleratedImage(1, 1)
+		img.Upload([]image.Color{color1})
+		download := context.DownloadAsync(image.AcceleratedImageSelection{
+			Image:    img,
+			Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		})
+		defer download.Delete()
+		// when
+		context.API().Finish()
+		// then
+		assert.True(t, download.Ready())
+		assert.NoError(t, context.Error())
+	})
+}
+
 func TestAcceleratedImage_Delete(t *testing.T) {
 	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
 	defer openGL.Destroy()
@@ -1209,6 +1390,187 @@ func TestRenderer_DrawArrays(t *testing.T) {
 	})
 }
 
+func TestIndexBuffer_Upload(t *testing.T) {
+	indexTypes := map[string]gl.IndexType{
+		"Uint16": gl.Uint16,
+		"Uint32": gl.Uint32,
+	}
+	for name, indexType := range indexTypes {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should upload indices", func(t *testing.T) {
+				openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+				defer openGL.Destroy()
+				context := openGL.Context()
+				buffer := context.NewIndexBuffer(3, indexType, gl.StaticDraw)
+				defer buffer.Delete()
+				// when
+				buffer.Upload(1, []uint32{65535, 7})
+				// then
+				output := make([]uint32, 2)
+				buffer.Download(1, output)
+				assert.Equal(t, []uint32{65535, 7}, output)
+				assert.NoError(t, context.Error())
+			})
+		})
+	}
+}
+
+func TestRenderer_DrawElements(t *testing.T) {
+	indexTypes := map[string]gl.IndexType{
+		"Uint16": gl.Uint16,
+		"Uint32": gl.Uint32,
+	}
+	for name, indexType := range indexTypes {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should draw quad using indexed vertices", func(t *testing.T) {
+				openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+				defer openGL.Destroy()
+				context := openGL.Context()
+				img := context.NewAcceleratedImage(4, 2)
+				img.Upload(make([]image.Color, 8))
+				vertexShader, err := context.CompileVertexShader(`
+					#version 330 core
+					layout(location = 0) in vec2 vertexPosition;
+					void main() {
+						gl_Position = vec4(vertexPosition, 0, 1);
+					}
+					`)
+				require.NoError(t, err)
+				fragmentShader, err := context.CompileFragmentShader(`
+					#version 330 core
+					out vec4 color;
+					void main() {
+						color = vec4(0.2, 0.4, 0.6, 0.8);
+					}
+					`)
+				require.NoError(t, err)
+				program, err := context.LinkProgram(vertexShader, fragmentShader)
+				require.NoError(t, err)
+				array := context.NewVertexArray(gl.VertexLayout{gl.Vec2})
+				buffer := context.NewFloatVertexBuffer(8, gl.StaticDraw)
+				// right half of the image
+				buffer.Upload(0, []float32{0, -1, 1, -1, 1, 1, 0, 1})
+				array.Set(0, gl.VertexBufferPointer{Buffer: buffer, Stride: 2})
+				indices := context.NewIndexBuffer(9, indexType, gl.StaticDraw)
+				indices.Upload(0, []uint32{3, 3, 3, 0, 1, 2, 0, 2, 3})
+				array.SetIndexBuffer(indices)
+				glCommand := &command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+					// when
+					renderer.DrawElements(array, gl.Triangles, 3, 6)
+				}}
+				command := program.AcceleratedCommand(glCommand)
+				command.Run(image.AcceleratedImageSelection{
+					Location: image.AcceleratedImageLocation{Width: 4, Height: 2},
+					Image:    img,
+				}, []image.AcceleratedImageSelection{})
+				// then
+				color := image.RGBA(51, 102, 153, 204)
+				assertColors(t, []image.Color{
+					image.Transparent, image.Transparent, color, color,
+					image.Transparent, image.Transparent, color, color,
+				}, img)
+				assert.NoError(t, context.Error())
+			})
+		})
+	}
+}
+
+func TestRenderer_DrawArraysInstanced(t *testing.T) {
+	t.Run("should draw instances using per-instance attribute and gl_InstanceID", func(t *testing.T) {
+		openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+		defer openGL.Destroy()
+		context := openGL.Context()
+		img := context.NewAcceleratedImage(4, 1)
+		img.Upload(make([]image.Color, 4))
+		program := linkInstancedProgram(t, context)
+		array := context.NewVertexArray(gl.VertexLayout{gl.Vec2, gl.Float})
+		positions := context.NewFloatVertexBuffer(2, gl.StaticDraw)
+		positions.Upload(0, []float32{-0.75, 0})
+		array.Set(0, gl.VertexBufferPointer{Buffer: positions, Stride: 2})
+		offsets := context.NewFloatVertexBuffer(4, gl.StaticDraw)
+		offsets.Upload(0, []float32{0, 0.5, 1, 1.5})
+		array.Set(1, gl.VertexBufferPointer{Buffer: offsets, Stride: 1, Divisor: 1})
+		glCommand := &command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+			// when
+			renderer.DrawArraysInstanced(array, gl.Points, 0, 1, 4)
+		}}
+		command := program.AcceleratedCommand(glCommand)
+		command.Run(image.AcceleratedImageSelection{
+			Location: image.AcceleratedImageLocation{Width: 4, Height: 1},
+			Image:    img,
+		}, []image.AcceleratedImageSelection{})
+		// then
+		assertColors(t, []image.Color{
+			image.RGB(0, 0, 0), image.RGB(85, 0, 0), image.RGB(170, 0, 0), image.RGB(255, 0, 0),
+		}, img)
+		assert.NoError(t, context.Error())
+	})
+}
+
+func TestRenderer_DrawElementsInstanced(t *testing.T) {
+	t.Run("should draw quad instances", func(t *testing.T) {
+		openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+		defer openGL.Destroy()
+		context := openGL.Context()
+		img := context.NewAcceleratedImage(4, 1)
+		img.Upload(make([]image.Color, 4))
+		program := linkInstancedProgram(t, context)
+		array := context.NewVertexArray(gl.VertexLayout{gl.Vec2, gl.Float})
+		positions := context.NewFloatVertexBuffer(8, gl.StaticDraw)
+		// first pixel of the image
+		positions.Upload(0, []float32{-1, -1, -0.5, -1, -0.5, 1, -1, 1})
+		array.Set(0, gl.VertexBufferPointer{Buffer: positions, Stride: 2})
+		offsets := context.NewFloatVertexBuffer(2, gl.StaticDraw)
+		offsets.Upload(0, []float32{0, 1})
+		array.Set(1, gl.VertexBufferPointer{Buffer: offsets, Stride: 1, Divisor: 1})
+		indices := context.NewIndexBuffer(6, gl.Uint16, gl.StaticDraw)
+		indices.Upload(0, []uint32{0, 1, 2, 0, 2, 3})
+		array.SetIndexBuffer(indices)
+		glCommand := &command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+			// when
+			renderer.DrawElementsInstanced(array, gl.Triangles, 0, 6, 2)
+		}}
+		command := program.AcceleratedCommand(glCommand)
+		command.Run(image.AcceleratedImageSelection{
+			Location: image.AcceleratedImageLocation{Width: 4, Height: 1},
+			Image:    img,
+		}, []image.AcceleratedImageSelection{})
+		// then
+		assertColors(t, []image.Color{
+			image.RGB(0, 0, 0), image.Transparent, image.RGB(85, 0, 0), image.Transparent,
+		}, img)
+		assert.NoError(t, context.Error())
+	})
+}
+
+// linkInstancedProgram links program which moves vertices horizontally
+// by per-instance offset and colors them using gl_InstanceID
+func linkInstancedProgram(t *testing.T, context *gl.Context) *gl.Program {
+	vertexShader, err := context.CompileVertexShader(`
+		#version 330 core
+		layout(location = 0) in vec2 vertexPosition;
+		layout(location = 1) in float offset;
+		flat out float red;
+		void main() {
+			gl_Position = vec4(vertexPosition.x + offset, vertexPosition.y, 0, 1);
+			red = float(gl_InstanceID) / 3.0;
+		}
+		`)
+	require.NoError(t, err)
+	fragmentShader, err := context.CompileFragmentShader(`
+		#version 330 core
+		flat in float red;
+		out vec4 color;
+		void main() {
+			color = vec4(red, 0, 0, 1);
+		}
+		`)
+	require.NoError(t, err)
+	program, err := context.LinkProgram(vertexShader, fragmentShader)
+	require.NoError(t, err)
+	return program
+}
+
 func TestRenderer_BindTexture(t *testing.T) {
 	t.Run("can't bind texture with uniformName not specified in program", func(t *testing.T) {
 		names := []string{"foo", "bar"}
@@ -1780,6 +2142,41 @@ func TestRenderer_SetBlend(t *testing.T) {
 			},
 			expectedColor: image.RGBA(2, 3, 5, 6),
 		},
+		"SrcColor, Zero": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.SrcColor,
+				DstFactor: gl.Zero,
+			},
+			expectedColor: image.RGBA(10, 14, 19, 25),
+		},
+		"OneMinusSrcColor, Zero": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.OneMinusSrcColor,
+				DstFactor: gl.Zero,
+			},
+			expectedColor: image.RGBA(40, 46, 51, 55),
+		},
+		"DstColor, Zero": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.DstColor,
+				DstFactor: gl.Zero,
+			},
+			expectedColor: image.RGBA(2, 5, 8, 13),
+		},
+		"Zero, DstColor": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.Zero,
+				DstFactor: gl.DstColor,
+			},
+			expectedColor: image.RGBA(0, 2, 4, 6),
+		},
+		"Zero, OneMinusDstColor": {
+			blend: gl.BlendFactors{
+				SrcFactor: gl.Zero,
+				DstFactor: gl.OneMinusDstColor,
+			},
+			expectedColor: image.RGBA(10, 18, 26, 34),
+		},
 		"SourceBlendFactors": {
 			blend:         gl.SourceBlendFactors,
 			expectedColor: srcColor,
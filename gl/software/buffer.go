@@ -0,0 +1,278 @@
+package software
+
+import (
+	"unsafe"
+)
+
+type buffer struct {
+	data []byte
+}
+
+type vertexArray struct {
+	attributes [vertexAttribs]attributePointer
+	// elementBuffer is a name of buffer bound to GL_ELEMENT_ARRAY_BUFFER
+	elementBuffer uint32
+}
+
+type attributePointer struct {
+	enabled bool
+	buffer  *buffer
+	size    int
+	stride  int
+	offset  int
+	// divisor is a number of instances which use the same attribute value.
+	// 0 means that attribute advances per vertex.
+	divisor int
+}
+
+// read returns attribute value of i-th vertex. Missing components are filled
+// with 0 (or 1 in case of w component).
+func (p attributePointer) read(i int) value {
+	v := value{0, 0, 0, 1}
+	if !p.enabled || p.buffer == nil {
+		return v
+	}
+	stride := p.stride
+	if stride == 0 {
+		stride = p.size * 4
+	}
+	start := p.offset + i*stride
+	if start < 0 || start+p.size*4 > len(p.buffer.data) {
+		return v
+	}
+	for j := 0; j < p.size; j++ {
+		v[j] = float64(*(*float32)(unsafe.Pointer(&p.buffer.data[start+j*4])))
+	}
+	return v
+}
+
+// GenBuffers generates buffer object names
+func (a *API) GenBuffers(n int32, buffers *uint32) {
+	a.generate(n, buffers, func(name uint32) {
+		a.buffers[name] = &buffer{}
+	})
+}
+
+// BindBuffer binds a named buffer object
+func (a *API) BindBuffer(target uint32, buffer uint32) {
+	if !validBufferTarget(target) {
+		a.error(invalidEnum)
+		return
+	}
+	if _, ok := a.buffers[buffer]; !ok && buffer != 0 {
+		a.error(invalidOperation)
+		return
+	}
+	if target == elementArrayBuffer {
+		// element array buffer binding is a part of vertex array state
+		if array := a.boundVertexArray(); array != nil {
+			array.elementBuffer = buffer
+		}
+		return
+	}
+	a.boundBuffers[target] = buffer
+}
+
+func validBufferTarget(target uint32) bool {
+	return target == arrayBuffer || target == elementArrayBuffer ||
+		target == pixelPackBuffer || target == pixelUnpackBuffer
+}
+
+// boundBufferName returns name of buffer bound to target or 0
+func (a *API) boundBufferName(target uint32) uint32 {
+	if target == elementArrayBuffer {
+		if array := a.vertexArrays[a.vertexArray]; array != nil {
+			return array.elementBuffer
+		}
+		return 0
+	}
+	return a.boundBuffers[target]
+}
+
+// boundBuffer returns buffer bound to target. Records an error when there is
+// no such buffer.
+func (a *API) boundBuffer(target uint32) *buffer {
+	if !validBufferTarget(target) {
+		a.error(invalidEnum)
+		return nil
+	}
+	b := a.buffers[a.boundBufferName(target)]
+	if b == nil {
+		a.error(invalidOperation)
+	}
+	return b
+}
+
+// bufferRange returns slice of the buffer bound to target. Records an error
+// when there is no such buffer or range is outside the buffer.
+func (a *API) bufferRange(target uint32, offset, size int) []byte {
+	b := a.boundBuffer(target)
+	if b == nil {
+		return nil
+	}
+	if offset < 0 || size < 0 || offset+size > len(b.data) {
+		a.error(invalidValue)
+		return nil
+	}
+	return b.data[offset : offset+size]
+}
+
+// BufferData creates and initializes a buffer object's data store
+func (a *API) BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
+	if size < 0 {
+		a.error(invalidValue)
+		return
+	}
+	b := a.boundBuffer(target)
+	if b == nil {
+		return
+	}
+	b.data = make([]byte, size)
+	if data != nil {
+		copy(b.data, bytes(data, size))
+	}
+}
+
+// BufferSubData updates a subset of a buffer object's data store
+func (a *API) BufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+	if r := a.bufferRange(target, offset, size); r != nil {
+		copy(r, bytes(data, size))
+	}
+}
+
+// GetBufferSubData returns a subset of a buffer object's data store
+func (a *API) GetBufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+	if r := a.bufferRange(target, offset, size); r != nil {
+		copy(bytes(data, size), r)
+	}
+}
+
+// DeleteBuffers deletes named buffer objects
+func (a *API) DeleteBuffers(n int32, buffers *uint32) {
+	if n < 0 {
+		a.error(invalidValue)
+		return
+	}
+	if n == 0 {
+		return
+	}
+	for _, name := range uint32s(buffers, int(n)) {
+		delete(a.buffers, name)
+		for target, bound := range a.boundBuffers {
+			if bound == name {
+				a.boundBuffers[target] = 0
+			}
+		}
+		if array := a.vertexArrays[a.vertexArray]; array != nil && array.elementBuffer == name {
+			array.elementBuffer = 0
+		}
+	}
+}
+
+// MapBufferRange maps all or part of a buffer object's data store into
+// the client's address space. Returned pointer points directly to buffer data.
+func (a *API) MapBufferRange(target uint32, offset int, length int, access uint32) unsafe.Pointer {
+	r := a.bufferRange(target, offset, length)
+	if len(r) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(&r[0])
+}
+
+// UnmapBuffer releases the mapping of a buffer object's data store
+func (a *API) UnmapBuffer(target uint32) bool {
+	return a.boundBuffer(target) != nil
+}
+
+// GenVertexArrays generates vertex array object names
+func (a *API) GenVertexArrays(n int32, arrays *uint32) {
+	a.generate(n, arrays, func(name uint32) {
+		a.vertexArrays[name] = &vertexArray{}
+	})
+}
+
+// DeleteVertexArrays deletes vertex array objects
+func (a *API) DeleteVertexArrays(n int32, arrays *uint32) {
+	if n < 0 {
+		a.error(invalidValue)
+		return
+	}
+	if n == 0 {
+		return
+	}
+	for _, name := range uint32s(arrays, int(n)) {
+		delete(a.vertexArrays, name)
+		if a.vertexArray == name {
+			a.vertexArray = 0
+		}
+	}
+}
+
+// BindVertexArray binds a vertex array object
+func (a *API) BindVertexArray(array uint32) {
+	if _, ok := a.vertexArrays[array]; !ok && array != 0 {
+		a.error(invalidOperation)
+		return
+	}
+	a.vertexArray = array
+}
+
+// boundVertexArray returns currently bound vertex array. Records an error
+// when no vertex array is bound.
+func (a *API) boundVertexArray() *vertexArray {
+	array := a.vertexArrays[a.vertexArray]
+	if array == nil {
+		a.error(invalidOperation)
+	}
+	return array
+}
+
+// VertexAttribPointer defines an array of generic vertex attribute data. Only
+// float attributes are supported. Pointer must be created using PtrOffset.
+func (a *API) VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer unsafe.Pointer) {
+	if index >= vertexAttribs || size < 1 || size > 4 || stride < 0 {
+		a.error(invalidValue)
+		return
+	}
+	if xtype != float {
+		a.error(invalidEnum)
+		return
+	}
+	array := a.boundVertexArray()
+	if array == nil {
+		return
+	}
+	b := a.buffers[a.boundBuffers[arrayBuffer]]
+	if b == nil {
+		a.error(invalidOperation)
+		return
+	}
+	attribute := &array.attributes[index]
+	attribute.buffer = b
+	attribute.size = int(size)
+	attribute.stride = int(stride)
+	attribute.offset = offsetOf(pointer)
+}
+
+// VertexAttribDivisor modifies the rate at which generic vertex attributes
+// advance during instanced rendering
+func (a *API) VertexAttribDivisor(index uint32, divisor uint32) {
+	if index >= vertexAttribs {
+		a.error(invalidValue)
+		return
+	}
+	if array := a.boundVertexArray(); array != nil {
+		array.attributes[index].divisor = int(divisor)
+	}
+}
+
+// EnableVertexAttribArray enables a generic vertex attribute array
+func (a *API) EnableVertexAttribArray(index uint32) {
+	if index >= vertexAttribs {
+		a.error(invalidValue)
+		return
+	}
+	if array := a.boundVertexArray(); array != nil {
+		array.attributes[index].enabled = true
+	}
+}
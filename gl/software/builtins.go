@@ -0,0 +1,378 @@
+package software
+
+import "math"
+
+// operand is a compiled expression together with its type
+type operand struct {
+	eval evaluator
+	typ  glslType
+}
+
+// builtinFunction returns evaluator of the function call for given arguments.
+// False is returned when there is no overload matching argument types.
+type builtinFunction func(args []operand) (evaluator, glslType, bool)
+
+var builtinFunctions map[string]builtinFunction
+
+func init() {
+	builtinFunctions = map[string]builtinFunction{
+		"radians":     genFloat1(func(x float64) float64 { return x * math.Pi / 180 }),
+		"degrees":     genFloat1(func(x float64) float64 { return x * 180 / math.Pi }),
+		"sin":         genFloat1(math.Sin),
+		"cos":         genFloat1(math.Cos),
+		"tan":         genFloat1(math.Tan),
+		"asin":        genFloat1(math.Asin),
+		"acos":        genFloat1(math.Acos),
+		"exp":         genFloat1(math.Exp),
+		"log":         genFloat1(math.Log),
+		"exp2":        genFloat1(math.Exp2),
+		"log2":        genFloat1(math.Log2),
+		"sqrt":        genFloat1(math.Sqrt),
+		"inversesqrt": genFloat1(func(x float64) float64 { return 1 / math.Sqrt(x) }),
+		"floor":       genFloat1(math.Floor),
+		"ceil":        genFloat1(math.Ceil),
+		"trunc":       genFloat1(math.Trunc),
+		"round":       genFloat1(math.Round),
+		"roundEven":   genFloat1(math.RoundToEven),
+		"fract":       genFloat1(func(x float64) float64 { return x - math.Floor(x) }),
+		"pow":         genFloat2(math.Pow, false),
+		"atan":        genFloat2(math.Atan2, false),
+		"mod":         genFloat2(func(x, y float64) float64 { return x - y*math.Floor(x/y) }, true),
+		"step": func(args []operand) (evaluator, glslType, bool) {
+			if len(args) != 2 || !isFloatLike(args[1].typ) || !isFloatLike(args[0].typ) ||
+				(args[0].typ.size != 1 && args[0].typ.size != args[1].typ.size) {
+				return nil, voidType, false
+			}
+			edge := broadcast(args[0], args[1].typ.size)
+			return componentwise2(edge, args[1].eval, args[1].typ.size, func(e, x float64) float64 {
+				if x < e {
+					return 0
+				}
+				return 1
+			}), args[1].typ.withBase(baseFloat), true
+		},
+		"abs":  genNumeric1(math.Abs),
+		"sign": genNumeric1(sign),
+		"min":  genNumeric2(math.Min),
+		"max":  genNumeric2(math.Max),
+		"clamp": func(args []operand) (evaluator, glslType, bool) {
+			if len(args) != 3 || !args[0].typ.isNumeric() {
+				return nil, voidType, false
+			}
+			typ := args[0].typ
+			for _, arg := range args[1:] {
+				if !compatibleNumeric(arg.typ, typ) {
+					return nil, voidType, false
+				}
+			}
+			typ = promote(typ, args[1].typ, args[2].typ)
+			x, minVal, maxVal := args[0].eval, broadcast(args[1], typ.size), broadcast(args[2], typ.size)
+			return func(m *machine) value {
+				v, low, high := x(m), minVal(m), maxVal(m)
+				for i := 0; i < typ.size; i++ {
+					v[i] = math.Min(math.Max(v[i], low[i]), high[i])
+				}
+				return v
+			}, typ, true
+		},
+		"mix": func(args []operand) (evaluator, glslType, bool) {
+			if len(args) != 3 || !isFloatLike(args[0].typ) || !isFloatLike(args[1].typ) ||
+				args[0].typ.size != args[1].typ.size || !isFloatLike(args[2].typ) ||
+				(args[2].typ.size != 1 && args[2].typ.size != args[0].typ.size) {
+				return nil, voidType, false
+			}
+			size := args[0].typ.size
+			x, y, a := args[0].eval, args[1].eval, broadcast(args[2], size)
+			return func(m *machine) value {
+				v1, v2, weight := x(m), y(m), a(m)
+				var r value
+				for i := 0; i < size; i++ {
+					r[i] = v1[i]*(1-weight[i]) + v2[i]*weight[i]
+				}
+				return r
+			}, args[0].typ.withBase(baseFloat), true
+		},
+		"smoothstep": func(args []operand) (evaluator, glslType, bool) {
+			if len(args) != 3 || !isFloatLike(args[2].typ) {
+				return nil, voidType, false
+			}
+			size := args[2].typ.size
+			for _, arg := range args[:2] {
+				if !isFloatLike(arg.typ) || (arg.typ.size != 1 && arg.typ.size != size) {
+					return nil, voidType, false
+				}
+			}
+			edge0, edge1, x := broadcast(args[0], size), broadcast(args[1], size), args[2].eval
+			return func(m *machine) value {
+				e0, e1, v := edge0(m), edge1(m), x(m)
+				var r value
+				for i := 0; i < size; i++ {
+					t := math.Min(math.Max((v[i]-e0[i])/(e1[i]-e0[i]), 0), 1)
+					r[i] = t * t * (3 - 2*t)
+				}
+				return r
+			}, args[2].typ.withBase(baseFloat), true
+		},
+		"length": func(args []operand) (evaluator, glslType, bool) {
+			if len(args) != 1 || !isFloatLike(args[0].typ) {
+				return nil, voidType, false
+			}
+			x, size := args[0].eval, args[0].typ.size
+			return func(m *machine) value {
+				v := x(m)
+				return value{math.Sqrt(dot(v, v, size))}
+			}, floatType, true
+		},
+		"distance": func(args []operand) (evaluator, glslType, bool) {
+			if len(args) != 2 || !isFloatLike(args[0].typ) || args[0].typ.withBase(baseFloat) != args[1].typ.withBase(baseFloat) {
+				return nil, voidType, false
+			}
+			x, y, size := args[0].eval, args[1].eval, args[0].typ.size
+			return func(m *machine) value {
+				a, b := x(m), y(m)
+				for i := 0; i < size; i++ {
+					a[i] -= b[i]
+				}
+				return value{math.Sqrt(dot(a, a, size))}
+			}, floatType, true
+		},
+		"dot": func(args []operand) (evaluator, glslType, bool) {
+			if len(args) != 2 || !isFloatLike(args[0].typ) || args[0].typ.withBase(baseFloat) != args[1].typ.withBase(baseFloat) {
+				return nil, voidType, false
+			}
+			x, y, size := args[0].eval, args[1].eval, args[0].typ.size
+			return func(m *machine) value {
+				return value{dot(x(m), y(m), size)}
+			}, floatType, true
+		},
+		"normalize": func(args []operand) (evaluator, glslType, bool) {
+			if len(args) != 1 || !isFloatLike(args[0].typ) {
+				return nil, voidType, false
+			}
+			x, size := args[0].eval, args[0].typ.size
+			return func(m *machine) value {
+				v := x(m)
+				length := math.Sqrt(dot(v, v, size))
+				for i := 0; i < size; i++ {
+					v[i] /= length
+				}
+				return v
+			}, args[0].typ.withBase(baseFloat), true
+		},
+		"lessThan":         vectorRelational(func(a, b float64) bool { return a < b }),
+		"lessThanEqual":    vectorRelational(func(a, b float64) bool { return a <= b }),
+		"greaterThan":      vectorRelational(func(a, b float64) bool { return a > b }),
+		"greaterThanEqual": vectorRelational(func(a, b float64) bool { return a >= b }),
+		"equal":            vectorRelational(func(a, b float64) bool { return a == b }),
+		"notEqual":         vectorRelational(func(a, b float64) bool { return a != b }),
+		"any": boolVector(boolType, func(v value, size int) value {
+			for i := 0; i < size; i++ {
+				if v[i] != 0 {
+					return value{1}
+				}
+			}
+			return value{0}
+		}),
+		"all": boolVector(boolType, func(v value, size int) value {
+			for i := 0; i < size; i++ {
+				if v[i] == 0 {
+					return value{0}
+				}
+			}
+			return value{1}
+		}),
+		"not": boolVector(glslType{}, func(v value, size int) value {
+			for i := 0; i < size; i++ {
+				v[i] = 1 - v[i]
+			}
+			return v
+		}),
+		"texture": func(args []operand) (evaluator, glslType, bool) {
+			if len(args) != 2 || args[0].typ != samplerType || args[1].typ != vec2Type {
+				return nil, voidType, false
+			}
+			sampler, coordinates := args[0].eval, args[1].eval
+			return func(m *machine) value {
+				st := coordinates(m)
+				return m.texture(int(sampler(m)[0])).sample(st[0], st[1])
+			}, vec4Type, true
+		},
+		"texelFetch": func(args []operand) (evaluator, glslType, bool) {
+			if len(args) != 3 || args[0].typ != samplerType || args[1].typ != ivec2Type || args[2].typ != intType {
+				return nil, voidType, false
+			}
+			sampler, coordinates := args[0].eval, args[1].eval
+			return func(m *machine) value {
+				p := coordinates(m)
+				return m.texture(int(sampler(m)[0])).fetch(int(p[0]), int(p[1]))
+			}, vec4Type, true
+		},
+		"textureSize": func(args []operand) (evaluator, glslType, bool) {
+			if len(args) != 2 || args[0].typ != samplerType || args[1].typ != intType {
+				return nil, voidType, false
+			}
+			sampler := args[0].eval
+			return func(m *machine) value {
+				t := m.texture(int(sampler(m)[0]))
+				return value{float64(t.width), float64(t.height)}
+			}, ivec2Type, true
+		},
+	}
+}
+
+func isFloatLike(t glslType) bool {
+	return t.isNumeric()
+}
+
+// compatibleNumeric returns true when arg can be used together with type typ
+// in a component-wise function. Arg can be a scalar.
+func compatibleNumeric(arg, typ glslType) bool {
+	return arg.isNumeric() && (arg.size == 1 || arg.size == typ.size)
+}
+
+// promote returns typ with float base when any of the types is float
+func promote(typ glslType, others ...glslType) glslType {
+	for _, other := range others {
+		if other.base == baseFloat {
+			return typ.withBase(baseFloat)
+		}
+	}
+	return typ
+}
+
+// broadcast returns evaluator replicating scalar operand into all components
+// of a vector with given size
+func broadcast(op operand, size int) evaluator {
+	if op.typ.size == size || op.typ.size != 1 {
+		return op.eval
+	}
+	eval := op.eval
+	return func(m *machine) value {
+		v := eval(m)[0]
+		return value{v, v, v, v}
+	}
+}
+
+func componentwise1(x evaluator, size int, f func(float64) float64) evaluator {
+	return func(m *machine) value {
+		v := x(m)
+		for i := 0; i < size; i++ {
+			v[i] = f(v[i])
+		}
+		return v
+	}
+}
+
+func componentwise2(x, y evaluator, size int, f func(a, b float64) float64) evaluator {
+	return func(m *machine) value {
+		a, b := x(m), y(m)
+		var r value
+		for i := 0; i < size; i++ {
+			r[i] = f(a[i], b[i])
+		}
+		return r
+	}
+}
+
+// genFloat1 creates function accepting float, vec2, vec3 or vec4
+func genFloat1(f func(float64) float64) builtinFunction {
+	return func(args []operand) (evaluator, glslType, bool) {
+		if len(args) != 1 || !isFloatLike(args[0].typ) {
+			return nil, voidType, false
+		}
+		typ := args[0].typ.withBase(baseFloat)
+		return componentwise1(args[0].eval, typ.size, f), typ, true
+	}
+}
+
+// genFloat2 creates function accepting two arguments of the same float type.
+// When scalarSecond is true the second argument can be a float.
+func genFloat2(f func(a, b float64) float64, scalarSecond bool) builtinFunction {
+	return func(args []operand) (evaluator, glslType, bool) {
+		if len(args) != 2 || !isFloatLike(args[0].typ) || !isFloatLike(args[1].typ) {
+			return nil, voidType, false
+		}
+		typ := args[0].typ.withBase(baseFloat)
+		if args[1].typ.size != typ.size && !(scalarSecond && args[1].typ.size == 1) {
+			return nil, voidType, false
+		}
+		return componentwise2(args[0].eval, broadcast(args[1], typ.size), typ.size, f), typ, true
+	}
+}
+
+// genNumeric1 creates function accepting float or int scalar or vector
+func genNumeric1(f func(float64) float64) builtinFunction {
+	return func(args []operand) (evaluator, glslType, bool) {
+		if len(args) != 1 || !args[0].typ.isNumeric() {
+			return nil, voidType, false
+		}
+		return componentwise1(args[0].eval, args[0].typ.size, f), args[0].typ, true
+	}
+}
+
+// genNumeric2 creates function accepting two float or int arguments. The
+// second one can be a scalar.
+func genNumeric2(f func(a, b float64) float64) builtinFunction {
+	return func(args []operand) (evaluator, glslType, bool) {
+		if len(args) != 2 || !args[0].typ.isNumeric() || !compatibleNumeric(args[1].typ, args[0].typ) {
+			return nil, voidType, false
+		}
+		typ := promote(args[0].typ, args[1].typ)
+		return componentwise2(args[0].eval, broadcast(args[1], typ.size), typ.size, f), typ, true
+	}
+}
+
+func vectorRelational(f func(a, b float64) bool) builtinFunction {
+	return func(args []operand) (evaluator, glslType, bool) {
+		if len(args) != 2 || args[0].typ.size < 2 || args[0].typ.size != args[1].typ.size ||
+			args[0].typ.base == baseSampler || args[1].typ.base == baseSampler {
+			return nil, voidType, false
+		}
+		return componentwise2(args[0].eval, args[1].eval, args[0].typ.size, func(a, b float64) float64 {
+			return boolValue(f(a, b))
+		}), args[0].typ.withBase(baseBool), true
+	}
+}
+
+// boolVector creates function accepting bvec2, bvec3 or bvec4. Zero value of
+// result type means that the result has the type of the argument.
+func boolVector(result glslType, f func(v value, size int) value) builtinFunction {
+	return func(args []operand) (evaluator, glslType, bool) {
+		if len(args) != 1 || args[0].typ.base != baseBool || args[0].typ.size < 2 {
+			return nil, voidType, false
+		}
+		x, size := args[0].eval, args[0].typ.size
+		typ := result
+		if typ == (glslType{}) {
+			typ = args[0].typ
+		}
+		return func(m *machine) value {
+			return f(x(m), size)
+		}, typ, true
+	}
+}
+
+func dot(a, b value, size int) float64 {
+	var sum float64
+	for i := 0; i < size; i++ {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,1308 @@
+package software
+
+import (
+	"fmt"
+	"strings"
+)
+
+type shaderKind int
+
+const (
+	vertexShaderKind shaderKind = iota
+	fragmentShaderKind
+)
+
+// evaluator is a compiled expression
+type evaluator func(m *machine) value
+
+// executor is a compiled statement
+type executor func(m *machine) control
+
+type control int
+
+const (
+	controlNext control = iota
+	controlBreak
+	controlContinue
+	controlReturn
+	controlDiscard
+)
+
+// maxParams is a maximum number of function parameters
+const maxParams = 16
+
+type variable struct {
+	name     string
+	typ      glslType
+	slot     int
+	uniform  bool
+	readOnly bool
+}
+
+type global struct {
+	*variable
+	storage  storage
+	flat     bool
+	location int
+	builtin  bool
+}
+
+type function struct {
+	name       string
+	line       int
+	returnType glslType
+	params     []*variable
+	body       executor
+	defined    bool
+	calls      []*function
+}
+
+// shader is a compiled shader. It does not hold any state, therefore the same
+// shader can be used by many programs.
+//
+// All variables (including function parameters and local variables) have
+// static slots in machine memory, which is possible because GLSL does not
+// allow recursion.
+type shader struct {
+	kind         shaderKind
+	globals      []*global
+	memorySize   int
+	uniformsSize int
+	init         []executor
+	main         *function
+}
+
+func (s *shader) global(name string) *global {
+	for _, g := range s.globals {
+		if g.name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// globals returns globals with given storage, excluding built-in variables
+func (s *shader) globalsWith(storage storage) []*global {
+	var globals []*global
+	for _, g := range s.globals {
+		if g.storage == storage && !g.builtin {
+			globals = append(globals, g)
+		}
+	}
+	return globals
+}
+
+// machine executes shader code. It holds values of all variables.
+type machine struct {
+	memory   []value
+	uniforms []value
+	initial  []value
+	returned value
+	texture  func(unit int) *texture
+	main     executor
+}
+
+func newMachine(s *shader, textures func(unit int) *texture) *machine {
+	m := &machine{
+		memory:   make([]value, s.memorySize),
+		uniforms: make([]value, s.uniformsSize),
+		initial:  make([]value, s.memorySize),
+		texture:  textures,
+		main:     s.main.body,
+	}
+	for _, init := range s.init {
+		init(m)
+	}
+	copy(m.initial, m.memory)
+	return m
+}
+
+// discarded is a panic value used for discarding the fragment inside
+// a function called from main.
+type discarded struct{}
+
+// reset restores initial values of all variables
+func (m *machine) reset() {
+	copy(m.memory, m.initial)
+}
+
+// run runs main function. Returns false when fragment was discarded.
+func (m *machine) run() (executed bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(discarded); !ok {
+				panic(r)
+			}
+			executed = false
+		}
+	}()
+	return m.main(m) != controlDiscard
+}
+
+type compileError struct {
+	line    int
+	message string
+}
+
+func (e compileError) Error() string {
+	return fmt.Sprintf("0:%d: %s", e.line, e.message)
+}
+
+type compiler struct {
+	shader    *shader
+	scopes    []map[string]*variable
+	functions map[string][]*function
+	current   *function
+	loops     int
+	breakable int
+}
+
+// compile compiles GLSL source code of a shader with given kind.
+func compile(kind shaderKind, src string) (s *shader, err error) {
+	unit, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	c := &compiler{
+		shader:    &shader{kind: kind},
+		functions: map[string][]*function{},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(compileError)
+			if !ok {
+				panic(r)
+			}
+			s, err = nil, e
+		}
+	}()
+	c.translationUnit(unit)
+	return c.shader, nil
+}
+
+func (c *compiler) fail(line int, format string, args ...interface{}) {
+	panic(compileError{line: line, message: fmt.Sprintf(format, args...)})
+}
+
+func (c *compiler) pushScope() {
+	c.scopes = append(c.scopes, map[string]*variable{})
+}
+
+func (c *compiler) popScope() {
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+func (c *compiler) lookup(name string) *variable {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if v, ok := c.scopes[i][name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (c *compiler) declare(line int, name string, typ glslType, uniform bool) *variable {
+	scope := c.scopes[len(c.scopes)-1]
+	if _, ok := scope[name]; ok {
+		c.fail(line, "redefinition of %s", name)
+	}
+	if strings.HasPrefix(name, "gl_") && len(c.scopes) > 1 {
+		c.fail(line, "identifier %s is reserved", name)
+	}
+	v := &variable{name: name, typ: typ, uniform: uniform}
+	if uniform {
+		v.slot = c.shader.uniformsSize
+		c.shader.uniformsSize++
+	} else {
+		v.slot = c.shader.memorySize
+		c.shader.memorySize++
+	}
+	scope[name] = v
+	return v
+}
+
+func (c *compiler) translationUnit(unit *translationUnit) {
+	c.pushScope()
+	c.builtinVariables()
+	for _, decl := range unit.functions {
+		c.declareFunction(decl)
+	}
+	for _, item := range unit.order {
+		switch decl := item.(type) {
+		case *globalDecl:
+			c.global(decl)
+		case *functionDecl:
+			if decl.body != nil {
+				c.functionBody(decl)
+			}
+		}
+	}
+	for _, overloads := range c.functions {
+		for _, f := range overloads {
+			for _, callee := range f.calls {
+				if !callee.defined {
+					c.fail(callee.line, "function %s is not defined", callee.name)
+				}
+			}
+			c.checkRecursion(f, nil)
+		}
+	}
+	// missing main is reported by the linker, just like in OpenGL
+	mains := c.functions["main"]
+	if len(mains) == 0 {
+		return
+	}
+	if len(mains) != 1 || len(mains[0].params) != 0 || mains[0].returnType != voidType {
+		c.fail(mains[0].line, "main function must have signature void main()")
+	}
+	c.shader.main = mains[0]
+}
+
+func (c *compiler) builtinVariables() {
+	add := func(name string, typ glslType, storage storage) {
+		v := c.declare(0, name, typ, false)
+		c.shader.globals = append(c.shader.globals, &global{variable: v, storage: storage, location: -1, builtin: true})
+	}
+	if c.shader.kind == vertexShaderKind {
+		add("gl_Position", vec4Type, storageOut)
+		add("gl_VertexID", intType, storageIn)
+		add("gl_InstanceID", intType, storageIn)
+	} else {
+		add("gl_FragCoord", vec4Type, storageIn)
+	}
+	for _, g := range c.shader.globals {
+		g.readOnly = g.storage == storageIn
+	}
+}
+
+func (c *compiler) global(decl *globalDecl) {
+	line := decl.line()
+	if decl.typ == voidType {
+		c.fail(line, "variable %s declared as void", decl.name)
+	}
+	if decl.typ.base == baseSampler && decl.storage != storageUniform {
+		c.fail(line, "sampler %s must be a uniform", decl.name)
+	}
+	if decl.storage == storageIn || decl.storage == storageOut {
+		if decl.typ.base == baseBool {
+			c.fail(line, "%s can't be a boolean", decl.name)
+		}
+		varying := (decl.storage == storageOut) == (c.shader.kind == vertexShaderKind)
+		if varying && decl.typ.base == baseInt && !decl.flat {
+			c.fail(line, "integer varying %s must be flat", decl.name)
+		}
+		if decl.init != nil {
+			c.fail(line, "%s can't be initialized", decl.name)
+		}
+	}
+	var init operand
+	if decl.init != nil {
+		if decl.storage == storageUniform {
+			c.fail(line, "uniform initializers are not supported")
+		}
+		init = c.convert(line, c.expr(decl.init), decl.typ)
+	} else if decl.storage == storageConst {
+		c.fail(line, "const %s must be initialized", decl.name)
+	}
+	v := c.declare(line, decl.name, decl.typ, decl.storage == storageUniform)
+	v.readOnly = decl.storage == storageIn || decl.storage == storageUniform || decl.storage == storageConst
+	c.shader.globals = append(c.shader.globals, &global{
+		variable: v,
+		storage:  decl.storage,
+		flat:     decl.flat,
+		location: decl.location,
+	})
+	if init.eval != nil {
+		slot, eval := v.slot, init.eval
+		c.shader.init = append(c.shader.init, func(m *machine) control {
+			m.memory[slot] = eval(m)
+			return controlNext
+		})
+	}
+}
+
+func (c *compiler) declareFunction(decl *functionDecl) {
+	line := decl.line()
+	if _, ok := typesByName[decl.name]; ok {
+		c.fail(line, "function %s has the same name as a type", decl.name)
+	}
+	if len(decl.params) > maxParams {
+		c.fail(line, "function %s has too many parameters", decl.name)
+	}
+	for _, f := range c.functions[decl.name] {
+		if !sameParams(f, decl.params) {
+			continue
+		}
+		if f.returnType != decl.returnType {
+			c.fail(line, "function %s redeclared with different return type", decl.name)
+		}
+		if decl.body != nil {
+			if f.defined {
+				c.fail(line, "function %s already has a body", decl.name)
+			}
+			f.defined = true
+		}
+		return
+	}
+	f := &function{
+		name:       decl.name,
+		line:       line,
+		returnType: decl.returnType,
+		defined:    decl.body != nil,
+	}
+	for _, p := range decl.params {
+		if p.typ == voidType {
+			c.fail(line, "parameter %s declared as void", p.name)
+		}
+		f.params = append(f.params, &variable{name: p.name, typ: p.typ})
+	}
+	c.functions[decl.name] = append(c.functions[decl.name], f)
+}
+
+func sameParams(f *function, params []param) bool {
+	if len(f.params) != len(params) {
+		return false
+	}
+	for i, p := range params {
+		if f.params[i].typ != p.typ {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *compiler) functionBody(decl *functionDecl) {
+	var f *function
+	for _, overload := range c.functions[decl.name] {
+		if sameParams(overload, decl.params) {
+			f = overload
+		}
+	}
+	c.current = f
+	c.pushScope()
+	for i, p := range decl.params {
+		param := c.declare(decl.line(), p.name, p.typ, false)
+		f.params[i].slot = param.slot
+	}
+	body := c.statements(decl.body.stmts)
+	c.popScope()
+	c.current = nil
+	f.body = body
+}
+
+func (c *compiler) checkRecursion(f *function, stack []*function) {
+	for _, caller := range stack {
+		if caller == f {
+			c.fail(f.line, "recursive call of function %s", f.name)
+		}
+	}
+	stack = append(stack, f)
+	for _, callee := range f.calls {
+		c.checkRecursion(callee, stack)
+	}
+}
+
+func (c *compiler) statements(stmts []stmt) executor {
+	executors := make([]executor, 0, len(stmts))
+	for _, s := range stmts {
+		executors = append(executors, c.statement(s))
+	}
+	return sequence(executors)
+}
+
+func sequence(executors []executor) executor {
+	if len(executors) == 1 {
+		return executors[0]
+	}
+	return func(m *machine) control {
+		for _, e := range executors {
+			if ctrl := e(m); ctrl != controlNext {
+				return ctrl
+			}
+		}
+		return controlNext
+	}
+}
+
+func (c *compiler) statement(s stmt) executor {
+	switch s := s.(type) {
+	case *blockStmt:
+		c.pushScope()
+		defer c.popScope()
+		return c.statements(s.stmts)
+	case *declStmt:
+		return c.declaration(s)
+	case *exprStmt:
+		eval := c.expr(s.expr).eval
+		return func(m *machine) control {
+			eval(m)
+			return controlNext
+		}
+	case *ifStmt:
+		return c.ifStatement(s)
+	case *forStmt:
+		return c.forStatement(s)
+	case *whileStmt:
+		return c.whileStatement(s)
+	case *switchStmt:
+		return c.switchStatement(s)
+	case *caseStmt:
+		c.fail(s.line(), "case label outside switch")
+	case *breakStmt:
+		if c.breakable == 0 {
+			c.fail(s.line(), "break outside loop or switch")
+		}
+		return func(*machine) control { return controlBreak }
+	case *continueStmt:
+		if c.loops == 0 {
+			c.fail(s.line(), "continue outside loop")
+		}
+		return func(*machine) control { return controlContinue }
+	case *returnStmt:
+		return c.returnStatement(s)
+	case *discardStmt:
+		if c.shader.kind != fragmentShaderKind {
+			c.fail(s.line(), "discard used outside fragment shader")
+		}
+		return func(*machine) control { return controlDiscard }
+	}
+	panic(fmt.Sprintf("unsupported statement %T", s))
+}
+
+func (c *compiler) declaration(s *declStmt) executor {
+	if s.typ == voidType || s.typ.base == baseSampler {
+		c.fail(s.line(), "local variable can't have type %s", s.typ)
+	}
+	executors := make([]executor, len(s.names))
+	for i, name := range s.names {
+		var init evaluator
+		if s.inits[i] != nil {
+			init = c.convert(s.line(), c.expr(s.inits[i]), s.typ).eval
+		} else if s.consts {
+			c.fail(s.line(), "const %s must be initialized", name)
+		}
+		v := c.declare(s.line(), name, s.typ, false)
+		v.readOnly = s.consts
+		slot := v.slot
+		if init == nil {
+			executors[i] = func(m *machine) control {
+				m.memory[slot] = value{}
+				return controlNext
+			}
+			continue
+		}
+		executors[i] = func(m *machine) control {
+			m.memory[slot] = init(m)
+			return controlNext
+		}
+	}
+	return sequence(executors)
+}
+
+func (c *compiler) condition(e expr) evaluator {
+	op := c.expr(e)
+	if op.typ != boolType {
+		c.fail(e.line(), "condition must be a boolean, got %s", op.typ)
+	}
+	return op.eval
+}
+
+func (c *compiler) ifStatement(s *ifStmt) executor {
+	condition := c.condition(s.condition)
+	then := c.scopedStatement(s.then)
+	if s.elze == nil {
+		return func(m *machine) control {
+			if condition(m)[0] != 0 {
+				return then(m)
+			}
+			return controlNext
+		}
+	}
+	elze := c.scopedStatement(s.elze)
+	return func(m *machine) control {
+		if condition(m)[0] != 0 {
+			return then(m)
+		}
+		return elze(m)
+	}
+}
+
+func (c *compiler) scopedStatement(s stmt) executor {
+	c.pushScope()
+	defer c.popScope()
+	return c.statement(s)
+}
+
+func (c *compiler) loopBody(s stmt) executor {
+	c.loops++
+	c.breakable++
+	body := c.scopedStatement(s)
+	c.loops--
+	c.breakable--
+	return body
+}
+
+func (c *compiler) forStatement(s *forStmt) executor {
+	c.pushScope()
+	defer c.popScope()
+	init := func(*machine) control { return controlNext }
+	if s.init != nil {
+		init = c.statement(s.init)
+	}
+	var condition, post evaluator
+	if s.condition != nil {
+		condition = c.condition(s.condition)
+	}
+	if s.post != nil {
+		post = c.expr(s.post).eval
+	}
+	body := c.loopBody(s.body)
+	return func(m *machine) control {
+		init(m)
+		for {
+			if condition != nil && condition(m)[0] == 0 {
+				return controlNext
+			}
+			switch ctrl := body(m); ctrl {
+			case controlBreak:
+				return controlNext
+			case controlReturn, controlDiscard:
+				return ctrl
+			}
+			if post != nil {
+				post(m)
+			}
+		}
+	}
+}
+
+func (c *compiler) whileStatement(s *whileStmt) executor {
+	condition := c.condition(s.condition)
+	body := c.loopBody(s.body)
+	doWhile := s.doWhile
+	return func(m *machine) control {
+		for first := true; (first && doWhile) || condition(m)[0] != 0; first = false {
+			switch ctrl := body(m); ctrl {
+			case controlBreak:
+				return controlNext
+			case controlReturn, controlDiscard:
+				return ctrl
+			}
+		}
+		return controlNext
+	}
+}
+
+func (c *compiler) switchStatement(s *switchStmt) executor {
+	selector := c.expr(s.selector)
+	if selector.typ != intType {
+		c.fail(s.line(), "switch selector must be an integer, got %s", selector.typ)
+	}
+	c.pushScope()
+	defer c.popScope()
+	c.breakable++
+	defer func() { c.breakable-- }()
+	cases := map[int]int{}
+	defaultCase := -1
+	var executors []executor
+	for _, stmt := range s.body {
+		label, ok := stmt.(*caseStmt)
+		if !ok {
+			executors = append(executors, c.statement(stmt))
+			continue
+		}
+		if label.value == nil {
+			if defaultCase >= 0 {
+				c.fail(label.line(), "duplicate default label")
+			}
+			defaultCase = len(executors)
+			continue
+		}
+		v := c.constantInt(label.value)
+		if _, ok := cases[v]; ok {
+			c.fail(label.line(), "duplicate case label %d", v)
+		}
+		cases[v] = len(executors)
+	}
+	eval := selector.eval
+	return func(m *machine) control {
+		start, ok := cases[int(eval(m)[0])]
+		if !ok {
+			start = defaultCase
+			if start < 0 {
+				return controlNext
+			}
+		}
+		for _, e := range executors[start:] {
+			switch ctrl := e(m); ctrl {
+			case controlNext:
+			case controlBreak:
+				return controlNext
+			default:
+				return ctrl
+			}
+		}
+		return controlNext
+	}
+}
+
+// constantInt returns value of constant integer expression used in case label
+func (c *compiler) constantInt(e expr) int {
+	op := c.expr(e)
+	if op.typ != intType || !isConstant(e) {
+		c.fail(e.line(), "case label must be a constant integer expression")
+	}
+	return int(op.eval(nil)[0])
+}
+
+func isConstant(e expr) bool {
+	switch e := e.(type) {
+	case *literalExpr:
+		return true
+	case *unaryExpr:
+		return (e.op == "-" || e.op == "+" || e.op == "~") && isConstant(e.operand)
+	case *binaryExpr:
+		return isConstant(e.left) && isConstant(e.right)
+	}
+	return false
+}
+
+func (c *compiler) returnStatement(s *returnStmt) executor {
+	returnType := c.current.returnType
+	if s.value == nil {
+		if returnType != voidType {
+			c.fail(s.line(), "function %s must return %s", c.current.name, returnType)
+		}
+		return func(*machine) control { return controlReturn }
+	}
+	if returnType == voidType {
+		c.fail(s.line(), "void function %s can't return a value", c.current.name)
+	}
+	eval := c.convert(s.line(), c.expr(s.value), returnType).eval
+	return func(m *machine) control {
+		m.returned = eval(m)
+		return controlReturn
+	}
+}
+
+// convert converts operand to given type using implicit conversion
+func (c *compiler) convert(line int, op operand, typ glslType) operand {
+	if !op.typ.convertibleTo(typ) {
+		c.fail(line, "can't convert %s to %s", op.typ, typ)
+	}
+	return operand{eval: op.eval, typ: typ}
+}
+
+func (c *compiler) expr(e expr) operand {
+	switch e := e.(type) {
+	case *literalExpr:
+		v := e.value
+		return operand{eval: func(*machine) value { return v }, typ: e.typ}
+	case *identExpr:
+		return c.identifier(e)
+	case *binaryExpr:
+		return c.binary(e)
+	case *unaryExpr:
+		return c.unary(e)
+	case *assignExpr:
+		return c.assignment(e)
+	case *ternaryExpr:
+		return c.ternary(e)
+	case *callExpr:
+		return c.call(e)
+	case *fieldExpr:
+		return c.swizzle(e)
+	case *indexExpr:
+		return c.index(e)
+	}
+	panic(fmt.Sprintf("unsupported expression %T", e))
+}
+
+func (c *compiler) identifier(e *identExpr) operand {
+	v := c.lookup(e.name)
+	if v == nil {
+		c.fail(e.line(), "undeclared identifier %s", e.name)
+	}
+	slot := v.slot
+	if v.uniform {
+		return operand{eval: func(m *machine) value { return m.uniforms[slot] }, typ: v.typ}
+	}
+	return operand{eval: func(m *machine) value { return m.memory[slot] }, typ: v.typ}
+}
+
+func (c *compiler) ternary(e *ternaryExpr) operand {
+	condition := c.condition(e.condition)
+	then, otherwise := c.expr(e.then), c.expr(e.otherwise)
+	typ := then.typ
+	if otherwise.typ.convertibleTo(typ) {
+		otherwise.typ = typ
+	} else if then.typ.convertibleTo(otherwise.typ) {
+		typ = otherwise.typ
+	} else {
+		c.fail(e.line(), "ternary operator branches have different types %s and %s", then.typ, otherwise.typ)
+	}
+	return operand{
+		eval: func(m *machine) value {
+			if condition(m)[0] != 0 {
+				return then.eval(m)
+			}
+			return otherwise.eval(m)
+		},
+		typ: typ,
+	}
+}
+
+func (c *compiler) binary(e *binaryExpr) operand {
+	left, right := c.expr(e.left), c.expr(e.right)
+	switch e.op {
+	case "&&", "||", "^^":
+		if left.typ != boolType || right.typ != boolType {
+			c.fail(e.line(), "operator %s requires booleans", e.op)
+		}
+		l, r := left.eval, right.eval
+		var eval evaluator
+		switch e.op {
+		case "&&":
+			eval = func(m *machine) value {
+				if l(m)[0] == 0 {
+					return value{0}
+				}
+				return r(m)
+			}
+		case "||":
+			eval = func(m *machine) value {
+				if l(m)[0] != 0 {
+					return value{1}
+				}
+				return r(m)
+			}
+		default:
+			eval = func(m *machine) value {
+				return value{boolValue(l(m)[0] != r(m)[0])}
+			}
+		}
+		return operand{eval: eval, typ: boolType}
+	case "==", "!=":
+		typ := left.typ
+		if right.typ.convertibleTo(typ) {
+			right.typ = typ
+		} else if left.typ.convertibleTo(right.typ) {
+			typ = right.typ
+		} else {
+			c.fail(e.line(), "can't compare %s with %s", left.typ, right.typ)
+		}
+		if typ.base == baseSampler {
+			c.fail(e.line(), "can't compare samplers")
+		}
+		l, r, size, equal := left.eval, right.eval, typ.size, e.op == "=="
+		return operand{
+			eval: func(m *machine) value {
+				a, b := l(m), r(m)
+				for i := 0; i < size; i++ {
+					if a[i] != b[i] {
+						return value{boolValue(!equal)}
+					}
+				}
+				return value{boolValue(equal)}
+			},
+			typ: boolType,
+		}
+	case "<", ">", "<=", ">=":
+		if !left.typ.isNumeric() || !right.typ.isNumeric() || !left.typ.isScalar() || !right.typ.isScalar() {
+			c.fail(e.line(), "operator %s requires scalar numbers", e.op)
+		}
+		return operand{eval: componentwise2(left.eval, right.eval, 1, relational(e.op)), typ: boolType}
+	}
+	return c.arithmetic(e.line(), e.op, left, right)
+}
+
+func relational(op string) func(a, b float64) float64 {
+	switch op {
+	case "<":
+		return func(a, b float64) float64 { return boolValue(a < b) }
+	case ">":
+		return func(a, b float64) float64 { return boolValue(a > b) }
+	case "<=":
+		return func(a, b float64) float64 { return boolValue(a <= b) }
+	default:
+		return func(a, b float64) float64 { return boolValue(a >= b) }
+	}
+}
+
+// arithmetic compiles component-wise operator. It is used by binary
+// expressions and compound assignments.
+func (c *compiler) arithmetic(line int, op string, left, right operand) operand {
+	if !left.typ.isNumeric() || !right.typ.isNumeric() {
+		c.fail(line, "operator %s requires numbers, got %s and %s", op, left.typ, right.typ)
+	}
+	typ := promote(left.typ, right.typ)
+	switch {
+	case left.typ.size == right.typ.size:
+	case left.typ.size == 1:
+		typ = promote(right.typ, left.typ)
+	case right.typ.size != 1:
+		c.fail(line, "operator %s requires operands with the same size, got %s and %s", op, left.typ, right.typ)
+	}
+	var f func(a, b float64) float64
+	if typ.base == baseFloat {
+		switch op {
+		case "+":
+			f = func(a, b float64) float64 { return a + b }
+		case "-":
+			f = func(a, b float64) float64 { return a - b }
+		case "*":
+			f = func(a, b float64) float64 { return a * b }
+		case "/":
+			f = func(a, b float64) float64 { return a / b }
+		default:
+			c.fail(line, "operator %s requires integers", op)
+		}
+	} else {
+		f = integerOperator(op)
+	}
+	return operand{
+		eval: componentwise2(broadcast(left, typ.size), broadcast(right, typ.size), typ.size, f),
+		typ:  typ,
+	}
+}
+
+func integerOperator(op string) func(a, b float64) float64 {
+	var f func(a, b int32) int32
+	switch op {
+	case "+":
+		f = func(a, b int32) int32 { return a + b }
+	case "-":
+		f = func(a, b int32) int32 { return a - b }
+	case "*":
+		f = func(a, b int32) int32 { return a * b }
+	case "/":
+		f = func(a, b int32) int32 {
+			if b == 0 {
+				return 0
+			}
+			return a / b
+		}
+	case "%":
+		f = func(a, b int32) int32 {
+			if b == 0 {
+				return 0
+			}
+			return a % b
+		}
+	case "&":
+		f = func(a, b int32) int32 { return a & b }
+	case "|":
+		f = func(a, b int32) int32 { return a | b }
+	case "^":
+		f = func(a, b int32) int32 { return a ^ b }
+	case "<<":
+		f = func(a, b int32) int32 { return a << uint32(b&31) }
+	case ">>":
+		f = func(a, b int32) int32 { return a >> uint32(b&31) }
+	}
+	return func(a, b float64) float64 {
+		return float64(f(toInt(a), toInt(b)))
+	}
+}
+
+// toInt converts float to int32 the same way as GLSL int constructor does
+func toInt(f float64) int32 {
+	return int32(int64(f))
+}
+
+func (c *compiler) unary(e *unaryExpr) operand {
+	switch e.op {
+	case "++", "--":
+		return c.increment(e)
+	}
+	op := c.expr(e.operand)
+	eval, size := op.eval, op.typ.size
+	switch e.op {
+	case "+":
+		if !op.typ.isNumeric() {
+			c.fail(e.line(), "operator + requires a number")
+		}
+		return op
+	case "-":
+		if !op.typ.isNumeric() {
+			c.fail(e.line(), "operator - requires a number")
+		}
+		if op.typ.base == baseInt {
+			return operand{eval: componentwise1(eval, size, func(v float64) float64 { return float64(-toInt(v)) }), typ: op.typ}
+		}
+		return operand{eval: componentwise1(eval, size, func(v float64) float64 { return -v }), typ: op.typ}
+	case "!":
+		if op.typ != boolType {
+			c.fail(e.line(), "operator ! requires a boolean")
+		}
+		return operand{eval: func(m *machine) value { return value{1 - eval(m)[0]} }, typ: boolType}
+	default: // ~
+		if op.typ.base != baseInt {
+			c.fail(e.line(), "operator ~ requires an integer")
+		}
+		return operand{eval: componentwise1(eval, size, func(v float64) float64 { return float64(^toInt(v)) }), typ: op.typ}
+	}
+}
+
+func (c *compiler) increment(e *unaryExpr) operand {
+	target := c.lvalue(e.operand)
+	if !target.typ.isNumeric() {
+		c.fail(e.line(), "operator %s requires a number", e.op)
+	}
+	delta := 1.0
+	if e.op == "--" {
+		delta = -1
+	}
+	load, store, size, postfix := target.load, target.store, target.typ.size, e.postfix
+	return operand{
+		eval: func(m *machine) value {
+			old := load(m)
+			v := old
+			for i := 0; i < size; i++ {
+				v[i] += delta
+			}
+			store(m, v)
+			if postfix {
+				return old
+			}
+			return v
+		},
+		typ: target.typ,
+	}
+}
+
+func (c *compiler) assignment(e *assignExpr) operand {
+	target := c.lvalue(e.target)
+	source := c.expr(e.value)
+	if e.op != "=" {
+		current := operand{eval: target.load, typ: target.typ}
+		source = c.arithmetic(e.line(), strings.TrimSuffix(e.op, "="), current, source)
+	}
+	eval := c.convert(e.line(), source, target.typ).eval
+	store := target.store
+	return operand{
+		eval: func(m *machine) value {
+			v := eval(m)
+			store(m, v)
+			return v
+		},
+		typ: target.typ,
+	}
+}
+
+// lvalue is an expression which can be assigned
+type lvalue struct {
+	typ   glslType
+	load  evaluator
+	store func(m *machine, v value)
+}
+
+func (c *compiler) lvalue(e expr) lvalue {
+	slot, typ, components, index := c.lvalueParts(e)
+	var load evaluator
+	var store func(m *machine, v value)
+	switch {
+	case index != nil:
+		size := typ.size
+		load = func(m *machine) value {
+			i := int(index(m)[0])
+			if i < 0 || i >= size {
+				return value{}
+			}
+			return value{m.memory[slot][i]}
+		}
+		store = func(m *machine, v value) {
+			i := int(index(m)[0])
+			if i >= 0 && i < size {
+				m.memory[slot][i] = v[0]
+			}
+		}
+		typ = typ.withSize(1)
+	case components != nil:
+		load = func(m *machine) value {
+			return swizzle(m.memory[slot], components)
+		}
+		store = func(m *machine, v value) {
+			for i, component := range components {
+				m.memory[slot][component] = v[i]
+			}
+		}
+		typ = typ.withSize(len(components))
+	default:
+		load = func(m *machine) value { return m.memory[slot] }
+		store = func(m *machine, v value) { m.memory[slot] = v }
+	}
+	return lvalue{typ: typ, load: load, store: store}
+}
+
+// lvalueParts returns slot and type of assigned variable, swizzle components
+// and dynamic index.
+func (c *compiler) lvalueParts(e expr) (slot int, typ glslType, components []int, index evaluator) {
+	switch e := e.(type) {
+	case *identExpr:
+		v := c.lookup(e.name)
+		if v == nil {
+			c.fail(e.line(), "undeclared identifier %s", e.name)
+		}
+		if v.readOnly {
+			c.fail(e.line(), "%s is read-only", e.name)
+		}
+		return v.slot, v.typ, nil, nil
+	case *fieldExpr:
+		slot, typ, components, index = c.lvalueParts(e.operand)
+		if index != nil {
+			c.fail(e.line(), "swizzle of indexed vector can't be assigned")
+		}
+		size := typ.size
+		if components != nil {
+			size = len(components)
+		}
+		selected := c.components(e, typ.withSize(size))
+		used := map[int]bool{}
+		for i, s := range selected {
+			if components != nil {
+				selected[i] = components[s]
+			}
+			if used[selected[i]] {
+				c.fail(e.line(), "swizzle %s used for assignment has repeated components", e.field)
+			}
+			used[selected[i]] = true
+		}
+		return slot, typ, selected, nil
+	case *indexExpr:
+		slot, typ, components, index = c.lvalueParts(e.operand)
+		if index != nil || components != nil || typ.size == 1 {
+			c.fail(e.line(), "unsupported indexing")
+		}
+		i := c.expr(e.index)
+		if i.typ != intType {
+			c.fail(e.line(), "index must be an integer")
+		}
+		return slot, typ, nil, i.eval
+	}
+	c.fail(e.line(), "expression is not assignable")
+	return
+}
+
+func (c *compiler) swizzle(e *fieldExpr) operand {
+	op := c.expr(e.operand)
+	if op.typ.base == baseSampler || op.typ == voidType {
+		c.fail(e.line(), "%s has no fields", op.typ)
+	}
+	components := c.components(e, op.typ)
+	eval := op.eval
+	return operand{
+		eval: func(m *machine) value { return swizzle(eval(m), components) },
+		typ:  op.typ.withSize(len(components)),
+	}
+}
+
+var swizzleSets = []string{"xyzw", "rgba", "stpq"}
+
+// components returns indexes of components selected by swizzle
+func (c *compiler) components(e *fieldExpr, typ glslType) []int {
+	if len(e.field) > 4 {
+		c.fail(e.line(), "invalid swizzle %s", e.field)
+	}
+	for _, set := range swizzleSets {
+		if strings.IndexByte(set, e.field[0]) < 0 {
+			continue
+		}
+		components := make([]int, len(e.field))
+		for i := range e.field {
+			component := strings.IndexByte(set, e.field[i])
+			if component < 0 || component >= typ.size {
+				c.fail(e.line(), "invalid swizzle %s of %s", e.field, typ)
+			}
+			components[i] = component
+		}
+		return components
+	}
+	c.fail(e.line(), "invalid swizzle %s of %s", e.field, typ)
+	return nil
+}
+
+func swizzle(v value, components []int) value {
+	var r value
+	for i, component := range components {
+		r[i] = v[component]
+	}
+	return r
+}
+
+func (c *compiler) index(e *indexExpr) operand {
+	op := c.expr(e.operand)
+	if op.typ.size < 2 {
+		c.fail(e.line(), "%s can't be indexed", op.typ)
+	}
+	i := c.expr(e.index)
+	if i.typ != intType {
+		c.fail(e.line(), "index must be an integer")
+	}
+	eval, index, size := op.eval, i.eval, op.typ.size
+	return operand{
+		eval: func(m *machine) value {
+			j := int(index(m)[0])
+			if j < 0 || j >= size {
+				return value{}
+			}
+			return value{eval(m)[j]}
+		},
+		typ: op.typ.withSize(1),
+	}
+}
+
+func (c *compiler) call(e *callExpr) operand {
+	args := make([]operand, len(e.args))
+	for i, arg := range e.args {
+		args[i] = c.expr(arg)
+		if args[i].typ == voidType {
+			c.fail(e.line(), "void argument passed to %s", e.name)
+		}
+	}
+	if typ, ok := typesByName[e.name]; ok {
+		return c.constructor(e, typ, args)
+	}
+	if f := c.resolveFunction(e.name, args); f != nil {
+		return c.callFunction(f, args)
+	}
+	if builtin, ok := builtinFunctions[e.name]; ok {
+		if eval, typ, ok := builtin(args); ok {
+			return operand{eval: eval, typ: typ}
+		}
+		c.fail(e.line(), "no matching overload of %s for arguments %s", e.name, typeNames(args))
+	}
+	c.fail(e.line(), "undeclared function %s", e.name)
+	return operand{}
+}
+
+func typeNames(args []operand) string {
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = arg.typ.String()
+	}
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+// resolveFunction finds user defined function. Exact match is preferred over
+// implicit conversions.
+func (c *compiler) resolveFunction(name string, args []operand) *function {
+	var converted *function
+	for _, f := range c.functions[name] {
+		if len(f.params) != len(args) {
+			continue
+		}
+		exact, convertible := true, true
+		for i, p := range f.params {
+			exact = exact && args[i].typ == p.typ
+			convertible = convertible && args[i].typ.convertibleTo(p.typ)
+		}
+		if exact {
+			return f
+		}
+		if convertible && converted == nil {
+			converted = f
+		}
+	}
+	return converted
+}
+
+func (c *compiler) callFunction(f *function, args []operand) operand {
+	if c.current != nil {
+		c.current.calls = append(c.current.calls, f)
+	} else {
+		c.fail(f.line, "function %s called in global initializer", f.name)
+	}
+	evaluators := make([]evaluator, len(args))
+	for i, arg := range args {
+		evaluators[i] = arg.eval
+	}
+	params := f.params
+	return operand{
+		eval: func(m *machine) value {
+			var values [maxParams]value
+			for i, eval := range evaluators {
+				values[i] = eval(m)
+			}
+			for i, p := range params {
+				m.memory[p.slot] = values[i]
+			}
+			if f.body(m) == controlDiscard {
+				panic(discarded{})
+			}
+			return m.returned
+		},
+		typ: f.returnType,
+	}
+}
+
+func (c *compiler) constructor(e *callExpr, typ glslType, args []operand) operand {
+	if typ == voidType || typ.base == baseSampler {
+		c.fail(e.line(), "%s can't be constructed", typ)
+	}
+	if len(args) == 0 {
+		c.fail(e.line(), "constructor %s requires arguments", typ)
+	}
+	components := 0
+	for i, arg := range args {
+		if arg.typ.base == baseSampler {
+			c.fail(e.line(), "sampler can't be used in constructor")
+		}
+		if components >= typ.size && !(i == 0 && len(args) == 1) {
+			c.fail(e.line(), "too many arguments in %s constructor", typ)
+		}
+		components += arg.typ.size
+	}
+	convert := converter(typ.base)
+	size := typ.size
+	if len(args) == 1 {
+		eval := args[0].eval
+		if args[0].typ.size == 1 {
+			return operand{
+				eval: func(m *machine) value {
+					v := convert(eval(m)[0])
+					return value{v, v, v, v}
+				},
+				typ: typ,
+			}
+		}
+		if components < size {
+			c.fail(e.line(), "not enough arguments in %s constructor", typ)
+		}
+		return operand{eval: componentwise1(eval, size, convert), typ: typ}
+	}
+	if components < size {
+		c.fail(e.line(), "not enough arguments in %s constructor", typ)
+	}
+	sizes := make([]int, len(args))
+	evaluators := make([]evaluator, len(args))
+	for i, arg := range args {
+		sizes[i] = arg.typ.size
+		evaluators[i] = arg.eval
+	}
+	return operand{
+		eval: func(m *machine) value {
+			var r value
+			n := 0
+			for i, eval := range evaluators {
+				v := eval(m)
+				for j := 0; j < sizes[i] && n < size; j++ {
+					r[n] = convert(v[j])
+					n++
+				}
+			}
+			return r
+		},
+		typ: typ,
+	}
+}
+
+func converter(base baseType) func(float64) float64 {
+	switch base {
+	case baseInt:
+		return func(v float64) float64 { return float64(toInt(v)) }
+	case baseBool:
+		return func(v float64) float64 { return boolValue(v != 0) }
+	}
+	return func(v float64) float64 { return v }
+}
@@ -0,0 +1,82 @@
+package software
+
+// Camel-cased GL constants
+const (
+	noError                  = 0
+	invalidEnum              = 0x0500
+	invalidValue             = 0x0501
+	invalidOperation         = 0x0502
+	arrayBuffer              = 0x8892
+	elementArrayBuffer       = 0x8893
+	pixelPackBuffer          = 0x88EB
+	pixelUnpackBuffer        = 0x88EC
+	float                    = 0x1406
+	floatVec2                = 0x8B50
+	floatVec3                = 0x8B51
+	floatVec4                = 0x8B52
+	intType32                = 0x1404
+	intVec2                  = 0x8B53
+	intVec3                  = 0x8B54
+	intVec4                  = 0x8B55
+	boolType32               = 0x8B56
+	boolVec2                 = 0x8B57
+	boolVec3                 = 0x8B58
+	boolVec4                 = 0x8B59
+	sampler2D                = 0x8B5E
+	vertexShader             = 0x8B31
+	fragmentShader           = 0x8B30
+	shaderTypeParam          = 0x8B4F
+	compileStatus            = 0x8B81
+	linkStatus               = 0x8B82
+	infoLogLength            = 0x8B84
+	attachedShaders          = 0x8B85
+	activeUniforms           = 0x8B86
+	activeUniformMaxLength   = 0x8B87
+	activeAttributes         = 0x8B89
+	activeAttributeMaxLength = 0x8B8A
+	texture0                 = 0x84C0
+	texture2D                = 0x0DE1
+	points                   = 0x0000
+	lines                    = 0x0001
+	lineLoop                 = 0x0002
+	lineStrip                = 0x0003
+	triangles                = 0x0004
+	triangleStrip            = 0x0005
+	triangleFan              = 0x0006
+	colorBufferBit           = 0x00004000
+	scissorTest              = 0x0C11
+	blend                    = 0x0BE2
+	framebuffer              = 0x8D40
+	readFramebuffer          = 0x8CA8
+	drawFramebuffer          = 0x8CA9
+	colorAttachment0         = 0x8CE0
+	maxTextureSize           = 0x0D33
+	maxTextureImageUnits     = 0x8872
+	maxVertexAttribs         = 0x8869
+	viewport                 = 0x0BA2
+	scissorBox               = 0x0C10
+	unsignedByte             = 0x1401
+	unsignedShort            = 0x1403
+	unsignedInt              = 0x1405
+	rgba                     = 0x1908
+	rgba8                    = 0x8058
+	textureWrapS             = 0x2802
+	textureWrapT             = 0x2803
+	repeat                   = 0x2901
+	clampToEdge              = 0x812F
+	clampToBorder            = 0x812D
+	mirroredRepeat           = 0x8370
+	zero                     = 0
+	one                      = 1
+	srcColor                 = 0x0300
+	oneMinusSrcColor         = 0x0301
+	srcAlpha                 = 0x0302
+	oneMinusSrcAlpha         = 0x0303
+	dstAlpha                 = 0x0304
+	oneMinusDstAlpha         = 0x0305
+	dstColor                 = 0x0306
+	syncGPUCommandsComplete  = 0x9117
+	alreadySignaled          = 0x911A
+	waitFailed               = 0x911D
+	oneMinusDstColor         = 0x0307
+)
@@ -0,0 +1,122 @@
+package software
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenInt
+	tokenFloat
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of file"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// operators sorted by length, so the longest operator is matched first
+var operators = []string{
+	"<<=", ">>=",
+	"==", "!=", "<=", ">=", "&&", "||", "^^", "++", "--", "+=", "-=", "*=", "/=",
+	"%=", "&=", "|=", "^=", "<<", ">>",
+	"+", "-", "*", "/", "%", "^", "&", "|", "!", "~", "=", "<", ">", "?", ":",
+	";", ",", ".", "(", ")", "{", "}", "[", "]",
+}
+
+// tokenize splits GLSL source code into tokens. Comments and preprocessor
+// directives (such as #version) are skipped.
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	line := 1
+	lineStart := true
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			lineStart = true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		case c == '#' && lineStart:
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, compileError{line: line, message: "unterminated comment"}
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+			continue
+		}
+		lineStart = false
+		switch {
+		case isLetter(c):
+			start := i
+			for i < len(src) && (isLetter(src[i]) || isDigit(src[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: src[start:i], line: line})
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			start := i
+			kind := tokenInt
+			for i < len(src) && (isDigit(src[i]) || isLetter(src[i]) || src[i] == '.' ||
+				((src[i] == '+' || src[i] == '-') && (src[i-1] == 'e' || src[i-1] == 'E'))) {
+				if src[i] == '.' || src[i] == 'e' || src[i] == 'E' || src[i] == 'f' || src[i] == 'F' {
+					if !strings.HasPrefix(src[start:], "0x") && !strings.HasPrefix(src[start:], "0X") {
+						kind = tokenFloat
+					}
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: kind, text: src[start:i], line: line})
+		default:
+			matched := false
+			for _, operator := range operators {
+				if strings.HasPrefix(src[i:], operator) {
+					tokens = append(tokens, token{kind: tokenOperator, text: operator, line: line})
+					i += len(operator)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, compileError{line: line, message: fmt.Sprintf("unexpected character %q", c)}
+			}
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, line: line})
+	return tokens, nil
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,636 @@
+package software
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Abstract syntax tree of GLSL shader
+
+type expr interface {
+	line() int
+}
+
+type position int
+
+func (p position) line() int {
+	return int(p)
+}
+
+type (
+	literalExpr struct {
+		position
+		typ   glslType
+		value value
+	}
+	identExpr struct {
+		position
+		name string
+	}
+	binaryExpr struct {
+		position
+		op          string
+		left, right expr
+	}
+	unaryExpr struct {
+		position
+		op      string
+		operand expr
+		postfix bool
+	}
+	assignExpr struct {
+		position
+		op            string
+		target, value expr
+	}
+	ternaryExpr struct {
+		position
+		condition, then, otherwise expr
+	}
+	callExpr struct {
+		position
+		name string
+		args []expr
+	}
+	fieldExpr struct {
+		position
+		operand expr
+		field   string
+	}
+	indexExpr struct {
+		position
+		operand, index expr
+	}
+)
+
+type stmt interface{}
+
+type (
+	declStmt struct {
+		position
+		typ    glslType
+		names  []string
+		inits  []expr
+		consts bool
+	}
+	exprStmt struct {
+		expr expr
+	}
+	blockStmt struct {
+		stmts []stmt
+	}
+	ifStmt struct {
+		condition  expr
+		then, elze stmt
+	}
+	forStmt struct {
+		init      stmt
+		condition expr
+		post      expr
+		body      stmt
+	}
+	whileStmt struct {
+		condition expr
+		body      stmt
+		doWhile   bool
+	}
+	switchStmt struct {
+		position
+		selector expr
+		body     []stmt
+	}
+	caseStmt struct {
+		position
+		value expr // nil means default
+	}
+	breakStmt    struct{ position }
+	continueStmt struct{ position }
+	returnStmt   struct {
+		position
+		value expr
+	}
+	discardStmt struct{ position }
+)
+
+type storage int
+
+const (
+	storageNone storage = iota
+	storageIn
+	storageOut
+	storageUniform
+	storageConst
+)
+
+type globalDecl struct {
+	position
+	storage  storage
+	flat     bool
+	location int // -1 when not specified
+	typ      glslType
+	name     string
+	init     expr
+}
+
+type param struct {
+	typ  glslType
+	name string
+}
+
+type functionDecl struct {
+	position
+	returnType glslType
+	name       string
+	params     []param
+	body       *blockStmt // nil for prototype
+}
+
+type translationUnit struct {
+	globals   []*globalDecl
+	functions []*functionDecl
+	// order contains globals and functions in declaration order
+	order []interface{}
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse parses GLSL source code. Only a subset of GLSL 3.30 is supported.
+func parse(src string) (unit *translationUnit, err error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(compileError)
+			if !ok {
+				panic(r)
+			}
+			unit, err = nil, e
+		}
+	}()
+	return p.translationUnit(), nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == tokenOperator || t.kind == tokenIdentifier) && t.text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) token {
+	if !p.is(text) {
+		p.fail("expected %q, got %s", text, p.peek())
+	}
+	return p.next()
+}
+
+func (p *parser) identifier() string {
+	t := p.next()
+	if t.kind != tokenIdentifier {
+		p.failAt(t.line, "expected identifier, got %s", t)
+	}
+	return t.text
+}
+
+func (p *parser) fail(format string, args ...interface{}) {
+	p.failAt(p.peek().line, format, args...)
+}
+
+func (p *parser) failAt(line int, format string, args ...interface{}) {
+	panic(compileError{line: line, message: fmt.Sprintf(format, args...)})
+}
+
+func (p *parser) here() position {
+	return position(p.peek().line)
+}
+
+func (p *parser) translationUnit() *translationUnit {
+	unit := &translationUnit{}
+	for p.peek().kind != tokenEOF {
+		if p.accept(";") {
+			continue
+		}
+		if p.accept("precision") {
+			p.next() // precision qualifier
+			p.next() // type
+			p.expect(";")
+			continue
+		}
+		pos := p.here()
+		decl := &globalDecl{position: pos, location: -1}
+		p.qualifiers(decl)
+		typ := p.typ()
+		name := p.identifier()
+		if decl.storage == storageNone && !decl.flat && p.is("(") {
+			function := p.function(pos, typ, name)
+			unit.functions = append(unit.functions, function)
+			unit.order = append(unit.order, function)
+			continue
+		}
+		for {
+			d := *decl
+			d.typ, d.name = typ, name
+			if p.accept("=") {
+				d.init = p.assignment()
+			}
+			unit.globals = append(unit.globals, &d)
+			unit.order = append(unit.order, &d)
+			if !p.accept(",") {
+				break
+			}
+			name = p.identifier()
+		}
+		p.expect(";")
+	}
+	return unit
+}
+
+func (p *parser) qualifiers(decl *globalDecl) {
+	for {
+		switch {
+		case p.accept("layout"):
+			p.expect("(")
+			for !p.accept(")") {
+				name := p.identifier()
+				if p.accept("=") {
+					t := p.next()
+					value, err := strconv.Atoi(t.text)
+					if err != nil {
+						p.failAt(t.line, "invalid layout qualifier value %s", t)
+					}
+					if name == "location" {
+						decl.location = value
+					}
+				}
+				p.accept(",")
+			}
+		case p.accept("in"):
+			decl.storage = storageIn
+		case p.accept("out"):
+			decl.storage = storageOut
+		case p.accept("uniform"):
+			decl.storage = storageUniform
+		case p.accept("const"):
+			decl.storage = storageConst
+		case p.accept("flat"):
+			decl.flat = true
+		case p.accept("smooth"), p.accept("noperspective"), p.accept("highp"),
+			p.accept("mediump"), p.accept("lowp"):
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) typ() glslType {
+	t := p.next()
+	typ, ok := typesByName[t.text]
+	if !ok {
+		p.failAt(t.line, "unsupported type %s", t)
+	}
+	return typ
+}
+
+func (p *parser) isType() bool {
+	_, ok := typesByName[p.peek().text]
+	return ok && p.peek().kind == tokenIdentifier
+}
+
+func (p *parser) function(pos position, returnType glslType, name string) *functionDecl {
+	function := &functionDecl{position: pos, returnType: returnType, name: name}
+	p.expect("(")
+	if p.is("void") && p.peekAt(1).text == ")" {
+		p.next()
+	}
+	for !p.accept(")") {
+		if p.accept("out") || p.accept("inout") {
+			p.fail("out parameters are not supported")
+		}
+		p.accept("in")
+		p.accept("const")
+		typ := p.typ()
+		function.params = append(function.params, param{typ: typ, name: p.identifier()})
+		if !p.accept(",") {
+			p.expect(")")
+			break
+		}
+	}
+	if p.accept(";") {
+		return function
+	}
+	function.body = p.block()
+	return function
+}
+
+func (p *parser) block() *blockStmt {
+	p.expect("{")
+	block := &blockStmt{}
+	for !p.accept("}") {
+		if p.peek().kind == tokenEOF {
+			p.fail("unexpected end of file")
+		}
+		block.stmts = append(block.stmts, p.statement())
+	}
+	return block
+}
+
+func (p *parser) statement() stmt {
+	pos := p.here()
+	switch {
+	case p.is("{"):
+		return p.block()
+	case p.accept(";"):
+		return &blockStmt{}
+	case p.accept("if"):
+		p.expect("(")
+		s := &ifStmt{condition: p.expression()}
+		p.expect(")")
+		s.then = p.statement()
+		if p.accept("else") {
+			s.elze = p.statement()
+		}
+		return s
+	case p.accept("for"):
+		p.expect("(")
+		s := &forStmt{}
+		if !p.accept(";") {
+			s.init = p.simpleStatement()
+		}
+		if !p.is(";") {
+			s.condition = p.expression()
+		}
+		p.expect(";")
+		if !p.is(")") {
+			s.post = p.expression()
+		}
+		p.expect(")")
+		s.body = p.statement()
+		return s
+	case p.accept("while"):
+		p.expect("(")
+		s := &whileStmt{condition: p.expression()}
+		p.expect(")")
+		s.body = p.statement()
+		return s
+	case p.accept("do"):
+		s := &whileStmt{doWhile: true}
+		s.body = p.statement()
+		p.expect("while")
+		p.expect("(")
+		s.condition = p.expression()
+		p.expect(")")
+		p.expect(";")
+		return s
+	case p.accept("switch"):
+		p.expect("(")
+		s := &switchStmt{position: pos, selector: p.expression()}
+		p.expect(")")
+		p.expect("{")
+		for !p.accept("}") {
+			if p.peek().kind == tokenEOF {
+				p.fail("unexpected end of file")
+			}
+			s.body = append(s.body, p.statement())
+		}
+		return s
+	case p.accept("case"):
+		s := &caseStmt{position: pos, value: p.expression()}
+		p.expect(":")
+		return s
+	case p.accept("default"):
+		p.expect(":")
+		return &caseStmt{position: pos}
+	case p.accept("break"):
+		p.expect(";")
+		return &breakStmt{position: pos}
+	case p.accept("continue"):
+		p.expect(";")
+		return &continueStmt{position: pos}
+	case p.accept("return"):
+		s := &returnStmt{position: pos}
+		if !p.is(";") {
+			s.value = p.expression()
+		}
+		p.expect(";")
+		return s
+	case p.accept("discard"):
+		p.expect(";")
+		return &discardStmt{position: pos}
+	}
+	s := p.simpleStatement()
+	return s
+}
+
+// simpleStatement parses declaration or expression statement ending with ;
+func (p *parser) simpleStatement() stmt {
+	pos := p.here()
+	consts := p.accept("const")
+	for p.accept("highp") || p.accept("mediump") || p.accept("lowp") {
+	}
+	if p.isType() && p.peekAt(1).kind == tokenIdentifier {
+		decl := &declStmt{position: pos, typ: p.typ(), consts: consts}
+		for {
+			decl.names = append(decl.names, p.identifier())
+			var init expr
+			if p.accept("=") {
+				init = p.assignment()
+			}
+			decl.inits = append(decl.inits, init)
+			if !p.accept(",") {
+				break
+			}
+		}
+		p.expect(";")
+		return decl
+	}
+	if consts {
+		p.fail("expected type")
+	}
+	s := &exprStmt{expr: p.expression()}
+	p.expect(";")
+	return s
+}
+
+func (p *parser) expression() expr {
+	e := p.assignment()
+	for p.is(",") {
+		p.fail("comma operator is not supported")
+	}
+	return e
+}
+
+func (p *parser) assignment() expr {
+	pos := p.here()
+	target := p.ternary()
+	switch t := p.peek(); t.text {
+	case "=", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "<<=", ">>=":
+		if t.kind == tokenOperator {
+			p.next()
+			return &assignExpr{position: pos, op: t.text, target: target, value: p.assignment()}
+		}
+	}
+	return target
+}
+
+func (p *parser) ternary() expr {
+	pos := p.here()
+	condition := p.binary(0)
+	if !p.accept("?") {
+		return condition
+	}
+	then := p.assignment()
+	p.expect(":")
+	return &ternaryExpr{position: pos, condition: condition, then: then, otherwise: p.assignment()}
+}
+
+// binaryOperators contains operators grouped by precedence, from the lowest
+var binaryOperators = [][]string{
+	{"||"},
+	{"^^"},
+	{"&&"},
+	{"|"},
+	{"^"},
+	{"&"},
+	{"==", "!="},
+	{"<", ">", "<=", ">="},
+	{"<<", ">>"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) binary(level int) expr {
+	if level == len(binaryOperators) {
+		return p.unary()
+	}
+	left := p.binary(level + 1)
+	for {
+		t := p.peek()
+		if t.kind != tokenOperator || !contains(binaryOperators[level], t.text) {
+			return left
+		}
+		p.next()
+		right := p.binary(level + 1)
+		left = &binaryExpr{position: position(t.line), op: t.text, left: left, right: right}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) unary() expr {
+	t := p.peek()
+	if t.kind == tokenOperator {
+		switch t.text {
+		case "-", "+", "!", "~", "++", "--":
+			p.next()
+			return &unaryExpr{position: position(t.line), op: t.text, operand: p.unary()}
+		}
+	}
+	return p.postfix()
+}
+
+func (p *parser) postfix() expr {
+	e := p.primary()
+	for {
+		pos := p.here()
+		switch {
+		case p.accept("."):
+			e = &fieldExpr{position: pos, operand: e, field: p.identifier()}
+		case p.accept("["):
+			e = &indexExpr{position: pos, operand: e, index: p.expression()}
+			p.expect("]")
+		case p.is("++") || p.is("--"):
+			e = &unaryExpr{position: pos, op: p.next().text, operand: e, postfix: true}
+		default:
+			return e
+		}
+	}
+}
+
+func (p *parser) primary() expr {
+	t := p.next()
+	pos := position(t.line)
+	switch t.kind {
+	case tokenInt:
+		text := strings.TrimRight(t.text, "uU")
+		v, err := strconv.ParseInt(text, 0, 64)
+		if err != nil {
+			p.failAt(t.line, "invalid number %s", t)
+		}
+		return &literalExpr{position: pos, typ: intType, value: value{float64(int32(v))}}
+	case tokenFloat:
+		v, err := strconv.ParseFloat(strings.TrimRight(t.text, "fF"), 64)
+		if err != nil {
+			p.failAt(t.line, "invalid number %s", t)
+		}
+		return &literalExpr{position: pos, typ: floatType, value: value{v}}
+	case tokenIdentifier:
+		switch t.text {
+		case "true":
+			return &literalExpr{position: pos, typ: boolType, value: value{1}}
+		case "false":
+			return &literalExpr{position: pos, typ: boolType, value: value{0}}
+		}
+		if p.accept("(") {
+			call := &callExpr{position: pos, name: t.text}
+			if p.is("void") && p.peekAt(1).text == ")" {
+				p.next()
+			}
+			for !p.accept(")") {
+				call.args = append(call.args, p.assignment())
+				if !p.accept(",") {
+					p.expect(")")
+					break
+				}
+			}
+			return call
+		}
+		return &identExpr{position: pos, name: t.text}
+	case tokenOperator:
+		if t.text == "(" {
+			e := p.expression()
+			p.expect(")")
+			return e
+		}
+	}
+	p.failAt(t.line, "unexpected %s", t)
+	return nil
+}
@@ -0,0 +1,570 @@
+package software
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+type shaderObject struct {
+	kind     shaderKind
+	xtype    uint32
+	source   string
+	compiled *shader
+	log      string
+}
+
+type program struct {
+	shaders    []*shaderObject
+	linked     bool
+	log        string
+	vertex     *machine
+	fragment   *machine
+	uniforms   []*uniform
+	attributes []*attribute
+	varyings   []varying
+	// slots of built-in variables and fragment output (-1 when there is no output)
+	position, vertexID, instanceID, fragCoord, output int
+}
+
+type uniform struct {
+	name  string
+	typ   glslType
+	slots []uniformSlot
+}
+
+type uniformSlot struct {
+	machine *machine
+	slot    int
+}
+
+type attribute struct {
+	name     string
+	typ      glslType
+	location int
+	slot     int
+}
+
+// varying connects vertex shader output with fragment shader input
+type varying struct {
+	vertexSlot, fragmentSlot int
+	size                     int
+	flat                     bool
+}
+
+// CreateShader creates a shader object
+func (a *API) CreateShader(xtype uint32) uint32 {
+	var kind shaderKind
+	switch xtype {
+	case vertexShader:
+		kind = vertexShaderKind
+	case fragmentShader:
+		kind = fragmentShaderKind
+	default:
+		a.error(invalidEnum)
+		return 0
+	}
+	name := a.newName()
+	a.shaders[name] = &shaderObject{kind: kind, xtype: xtype}
+	return name
+}
+
+func (a *API) shader(name uint32) *shaderObject {
+	s := a.shaders[name]
+	if s == nil {
+		a.error(invalidValue)
+	}
+	return s
+}
+
+// ShaderSource replaces the source code in a shader object
+func (a *API) ShaderSource(shader uint32, count int32, xstring **uint8, length *int32) {
+	s := a.shader(shader)
+	if s == nil {
+		return
+	}
+	if count < 0 {
+		a.error(invalidValue)
+		return
+	}
+	var source strings.Builder
+	if count > 0 {
+		pointers := (*[1 << 20]*uint8)(unsafe.Pointer(xstring))[:count:count]
+		var lengths []int32
+		if length != nil {
+			lengths = int32s(length, int(count))
+		}
+		for i, pointer := range pointers {
+			if lengths == nil || lengths[i] < 0 {
+				source.WriteString(a.GoStr(pointer))
+			} else {
+				source.Write(bytes(unsafe.Pointer(pointer), int(lengths[i])))
+			}
+		}
+	}
+	s.source = source.String()
+}
+
+// CompileShader compiles a shader object
+func (a *API) CompileShader(shader uint32) {
+	s := a.shader(shader)
+	if s == nil {
+		return
+	}
+	compiled, err := compile(s.kind, s.source)
+	s.compiled = compiled
+	s.log = ""
+	if err != nil {
+		s.log = "ERROR: " + err.Error() + "\n"
+	}
+}
+
+// GetShaderiv returns a parameter from a shader object
+func (a *API) GetShaderiv(shader uint32, pname uint32, params *int32) {
+	s := a.shader(shader)
+	if s == nil {
+		return
+	}
+	switch pname {
+	case compileStatus:
+		*params = int32(boolValue(s.compiled != nil))
+	case infoLogLength:
+		*params = logLength(s.log)
+	case shaderTypeParam:
+		*params = int32(s.xtype)
+	default:
+		a.error(invalidEnum)
+	}
+}
+
+func logLength(log string) int32 {
+	if log == "" {
+		return 0
+	}
+	return int32(len(log) + 1)
+}
+
+// GetShaderInfoLog returns the information log for a shader object
+func (a *API) GetShaderInfoLog(shader uint32, bufSize int32, length *int32, infoLog *uint8) {
+	if s := a.shader(shader); s != nil {
+		writeString(s.log, bufSize, length, infoLog)
+	}
+}
+
+// DeleteShader deletes a shader object. Programs using the shader are not
+// affected.
+func (a *API) DeleteShader(shader uint32) {
+	if shader != 0 {
+		delete(a.shaders, shader)
+	}
+}
+
+// CreateProgram creates a program object
+func (a *API) CreateProgram() uint32 {
+	name := a.newName()
+	a.programs[name] = &program{}
+	return name
+}
+
+func (a *API) programObject(name uint32) *program {
+	p := a.programs[name]
+	if p == nil {
+		a.error(invalidValue)
+	}
+	return p
+}
+
+// DeleteProgram deletes a program object
+func (a *API) DeleteProgram(program uint32) {
+	if program != 0 {
+		delete(a.programs, program)
+	}
+}
+
+// AttachShader attaches a shader object to a program object
+func (a *API) AttachShader(program uint32, shader uint32) {
+	p, s := a.programObject(program), a.shader(shader)
+	if p == nil || s == nil {
+		return
+	}
+	for _, attached := range p.shaders {
+		if attached == s {
+			a.error(invalidOperation)
+			return
+		}
+	}
+	p.shaders = append(p.shaders, s)
+}
+
+// LinkProgram links a program object
+func (a *API) LinkProgram(program uint32) {
+	p := a.programObject(program)
+	if p == nil {
+		return
+	}
+	linked, err := a.link(p.shaders)
+	if err != nil {
+		p.linked = false
+		p.log = "error: " + err.Error() + "\n"
+		return
+	}
+	*p = *linked
+}
+
+func (a *API) link(shaders []*shaderObject) (*program, error) {
+	var vertex, fragment *shader
+	for _, s := range shaders {
+		if s.compiled == nil {
+			return nil, fmt.Errorf("shader is not compiled")
+		}
+		if s.kind == vertexShaderKind {
+			vertex = s.compiled
+		} else {
+			fragment = s.compiled
+		}
+	}
+	if vertex == nil || fragment == nil {
+		return nil, fmt.Errorf("program must have vertex and fragment shader")
+	}
+	if vertex.main == nil {
+		return nil, fmt.Errorf("vertex shader does not have main function")
+	}
+	if fragment.main == nil {
+		return nil, fmt.Errorf("fragment shader does not have main function")
+	}
+	p := &program{
+		shaders:    shaders,
+		linked:     true,
+		vertex:     newMachine(vertex, a.unitTexture),
+		fragment:   newMachine(fragment, a.unitTexture),
+		position:   vertex.global("gl_Position").slot,
+		vertexID:   vertex.global("gl_VertexID").slot,
+		instanceID: vertex.global("gl_InstanceID").slot,
+		output:     -1,
+	}
+	p.fragCoord = fragment.global("gl_FragCoord").slot
+	if err := p.linkVaryings(vertex, fragment); err != nil {
+		return nil, err
+	}
+	outputs := fragment.globalsWith(storageOut)
+	if len(outputs) > 1 {
+		return nil, fmt.Errorf("only one fragment shader output is supported")
+	}
+	if len(outputs) == 1 {
+		if outputs[0].typ.base != baseFloat {
+			return nil, fmt.Errorf("fragment shader output %s must be a float vector", outputs[0].name)
+		}
+		p.output = outputs[0].slot
+	}
+	if err := p.linkUniforms(vertex, fragment); err != nil {
+		return nil, err
+	}
+	if err := p.linkAttributes(vertex); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *program) linkVaryings(vertex, fragment *shader) error {
+	for _, in := range fragment.globalsWith(storageIn) {
+		out := vertex.global(in.name)
+		if out == nil || out.storage != storageOut {
+			return fmt.Errorf("fragment shader input %s is not written by vertex shader", in.name)
+		}
+		if out.typ != in.typ {
+			return fmt.Errorf("type of %s is different in vertex and fragment shader", in.name)
+		}
+		if out.flat != in.flat {
+			return fmt.Errorf("interpolation of %s is different in vertex and fragment shader", in.name)
+		}
+		p.varyings = append(p.varyings, varying{
+			vertexSlot:   out.slot,
+			fragmentSlot: in.slot,
+			size:         in.typ.size,
+			flat:         in.flat,
+		})
+	}
+	return nil
+}
+
+func (p *program) linkUniforms(vertex, fragment *shader) error {
+	byName := map[string]*uniform{}
+	for _, s := range []struct {
+		shader  *shader
+		machine *machine
+	}{{vertex, p.vertex}, {fragment, p.fragment}} {
+		for _, g := range s.shader.globalsWith(storageUniform) {
+			u, ok := byName[g.name]
+			if !ok {
+				u = &uniform{name: g.name, typ: g.typ}
+				byName[g.name] = u
+				p.uniforms = append(p.uniforms, u)
+			}
+			if u.typ != g.typ {
+				return fmt.Errorf("type of uniform %s is different in vertex and fragment shader", g.name)
+			}
+			u.slots = append(u.slots, uniformSlot{machine: s.machine, slot: g.slot})
+		}
+	}
+	return nil
+}
+
+func (p *program) linkAttributes(vertex *shader) error {
+	used := map[int]bool{}
+	for _, in := range vertex.globalsWith(storageIn) {
+		if in.typ.base != baseFloat {
+			return fmt.Errorf("attribute %s must be a float vector", in.name)
+		}
+		if in.location >= vertexAttribs {
+			return fmt.Errorf("location of attribute %s is too big", in.name)
+		}
+		if in.location >= 0 {
+			if used[in.location] {
+				return fmt.Errorf("location %d is used by many attributes", in.location)
+			}
+			used[in.location] = true
+		}
+		p.attributes = append(p.attributes, &attribute{name: in.name, typ: in.typ, location: in.location, slot: in.slot})
+	}
+	next := 0
+	for _, attr := range p.attributes {
+		if attr.location >= 0 {
+			continue
+		}
+		for used[next] {
+			next++
+		}
+		if next >= vertexAttribs {
+			return fmt.Errorf("too many attributes")
+		}
+		attr.location = next
+		used[next] = true
+	}
+	sort.SliceStable(p.attributes, func(i, j int) bool {
+		return p.attributes[i].location < p.attributes[j].location
+	})
+	return nil
+}
+
+// GetProgramiv returns a parameter from a program object
+func (a *API) GetProgramiv(program uint32, pname uint32, params *int32) {
+	p := a.programObject(program)
+	if p == nil {
+		return
+	}
+	switch pname {
+	case linkStatus:
+		*params = int32(boolValue(p.linked))
+	case infoLogLength:
+		*params = logLength(p.log)
+	case attachedShaders:
+		*params = int32(len(p.shaders))
+	case activeUniforms:
+		*params = int32(len(p.uniforms))
+	case activeUniformMaxLength:
+		*params = 0
+		for _, u := range p.uniforms {
+			*params = max(*params, int32(len(u.name)+1))
+		}
+	case activeAttributes:
+		*params = int32(len(p.attributes))
+	case activeAttributeMaxLength:
+		*params = 0
+		for _, attr := range p.attributes {
+			*params = max(*params, int32(len(attr.name)+1))
+		}
+	default:
+		a.error(invalidEnum)
+	}
+}
+
+func max(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// GetProgramInfoLog returns the information log for a program object
+func (a *API) GetProgramInfoLog(program uint32, bufSize int32, length *int32, infoLog *uint8) {
+	if p := a.programObject(program); p != nil {
+		writeString(p.log, bufSize, length, infoLog)
+	}
+}
+
+// UseProgram installs a program object as part of current rendering state
+func (a *API) UseProgram(program uint32) {
+	if program == 0 {
+		a.program = nil
+		return
+	}
+	p := a.programObject(program)
+	if p == nil {
+		return
+	}
+	if !p.linked {
+		a.error(invalidOperation)
+		return
+	}
+	a.program = p
+}
+
+var glTypes = map[glslType]uint32{
+	floatType:            float,
+	vec2Type:             floatVec2,
+	typesByName["vec3"]:  floatVec3,
+	vec4Type:             floatVec4,
+	intType:              intType32,
+	ivec2Type:            intVec2,
+	typesByName["ivec3"]: intVec3,
+	typesByName["ivec4"]: intVec4,
+	boolType:             boolType32,
+	typesByName["bvec2"]: boolVec2,
+	typesByName["bvec3"]: boolVec3,
+	typesByName["bvec4"]: boolVec4,
+	samplerType:          sampler2D,
+}
+
+// GetActiveUniform returns information about an active uniform variable for
+// the specified program object. Uniform location is equal to its index.
+func (a *API) GetActiveUniform(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	p := a.programObject(program)
+	if p == nil {
+		return
+	}
+	if int(index) >= len(p.uniforms) {
+		a.error(invalidValue)
+		return
+	}
+	u := p.uniforms[index]
+	writeString(u.name, bufSize, length, name)
+	*size = 1
+	*xtype = glTypes[u.typ]
+}
+
+// GetActiveAttrib returns information about an active attribute variable for
+// the specified program object
+func (a *API) GetActiveAttrib(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	p := a.programObject(program)
+	if p == nil {
+		return
+	}
+	if int(index) >= len(p.attributes) {
+		a.error(invalidValue)
+		return
+	}
+	attr := p.attributes[index]
+	writeString(attr.name, bufSize, length, name)
+	*size = 1
+	*xtype = glTypes[attr.typ]
+}
+
+// GetAttribLocation returns the location of an attribute variable
+func (a *API) GetAttribLocation(program uint32, name *uint8) int32 {
+	p := a.programObject(program)
+	if p == nil {
+		return -1
+	}
+	goName := a.GoStr(name)
+	for _, attr := range p.attributes {
+		if attr.name == goName {
+			return int32(attr.location)
+		}
+	}
+	return -1
+}
+
+// setUniform sets value of uniform in current program. Values set using
+// integer functions can be assigned to int, bool and sampler uniforms.
+// Values set using float functions can be assigned to float and bool uniforms.
+func (a *API) setUniform(location int32, size int, integer bool, v value) {
+	if location == -1 {
+		return
+	}
+	if a.program == nil || location < 0 || int(location) >= len(a.program.uniforms) {
+		a.error(invalidOperation)
+		return
+	}
+	u := a.program.uniforms[location]
+	switch {
+	case u.typ.size != size:
+		a.error(invalidOperation)
+		return
+	case u.typ.base == baseBool:
+		for i := range v {
+			v[i] = boolValue(v[i] != 0)
+		}
+	case u.typ.base == baseSampler:
+		if !integer {
+			a.error(invalidOperation)
+			return
+		}
+		if v[0] < 0 || v[0] >= textureUnits {
+			a.error(invalidValue)
+			return
+		}
+	case (u.typ.base == baseInt) != integer:
+		a.error(invalidOperation)
+		return
+	}
+	for _, s := range u.slots {
+		s.machine.uniforms[s.slot] = v
+	}
+}
+
+// Uniform1f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform1f(location int32, v0 float32) {
+	a.setUniform(location, 1, false, value{float64(v0)})
+}
+
+// Uniform2f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform2f(location int32, v0 float32, v1 float32) {
+	a.setUniform(location, 2, false, value{float64(v0), float64(v1)})
+}
+
+// Uniform3f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform3f(location int32, v0 float32, v1 float32, v2 float32) {
+	a.setUniform(location, 3, false, value{float64(v0), float64(v1), float64(v2)})
+}
+
+// Uniform4f specifies the value of a uniform variable for the current program object
+func (a *API) Uniform4f(location int32, v0 float32, v1 float32, v2 float32, v3 float32) {
+	a.setUniform(location, 4, false, value{float64(v0), float64(v1), float64(v2), float64(v3)})
+}
+
+// Uniform1i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform1i(location int32, v0 int32) {
+	a.setUniform(location, 1, true, value{float64(v0)})
+}
+
+// Uniform2i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform2i(location int32, v0 int32, v1 int32) {
+	a.setUniform(location, 2, true, value{float64(v0), float64(v1)})
+}
+
+// Uniform3i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform3i(location int32, v0 int32, v1 int32, v2 int32) {
+	a.setUniform(location, 3, true, value{float64(v0), float64(v1), float64(v2)})
+}
+
+// Uniform4i specifies the value of a uniform variable for the current program object
+func (a *API) Uniform4i(location int32, v0 int32, v1 int32, v2 int32, v3 int32) {
+	a.setUniform(location, 4, true, value{float64(v0), float64(v1), float64(v2), float64(v3)})
+}
+
+// UniformMatrix3fv specifies the value of a uniform variable for the current
+// program object. Matrices are not supported, so it always records an error.
+func (a *API) UniformMatrix3fv(location int32, count int32, transpose bool, value *float32) {
+	if location != -1 {
+		a.error(invalidOperation)
+	}
+}
+
+// UniformMatrix4fv specifies the value of a uniform variable for the current
+// program object. Matrices are not supported, so it always records an error.
+func (a *API) UniformMatrix4fv(location int32, count int32, transpose bool, value *float32) {
+	if location != -1 {
+		a.error(invalidOperation)
+	}
+}
@@ -0,0 +1,530 @@
+package software
+
+import (
+	"math"
+	"unsafe"
+)
+
+// Scissor defines the scissor box
+func (a *API) Scissor(x int32, y int32, width int32, height int32) {
+	if width < 0 || height < 0 {
+		a.error(invalidValue)
+		return
+	}
+	a.scissor = box{x: int(x), y: int(y), width: int(width), height: int(height)}
+}
+
+// Viewport sets the viewport
+func (a *API) Viewport(x int32, y int32, width int32, height int32) {
+	if width < 0 || height < 0 {
+		a.error(invalidValue)
+		return
+	}
+	a.viewport = box{x: int(x), y: int(y), width: int(width), height: int(height)}
+}
+
+// ClearColor specifies clear values for the color buffers
+func (a *API) ClearColor(red float32, green float32, blue float32, alpha float32) {
+	a.clearColor = [4]float32{red, green, blue, alpha}
+}
+
+// BlendFunc specifies pixel arithmetic
+func (a *API) BlendFunc(sfactor uint32, dfactor uint32) {
+	if !validBlendFactor(sfactor) || !validBlendFactor(dfactor) {
+		a.error(invalidEnum)
+		return
+	}
+	a.blendFactors = [2]uint32{sfactor, dfactor}
+}
+
+func validBlendFactor(factor uint32) bool {
+	return factor == zero || factor == one || (factor >= srcColor && factor <= oneMinusDstColor)
+}
+
+// Clear clears buffers to preset values. Scissor test is used when enabled.
+func (a *API) Clear(mask uint32) {
+	if mask&^colorBufferBit != 0 {
+		a.error(invalidValue)
+		return
+	}
+	target := a.renderTarget()
+	if target == nil || mask == 0 {
+		return
+	}
+	area := box{width: target.width, height: target.height}
+	if a.capabilities[scissorTest] {
+		area = area.intersect(a.scissor)
+	}
+	var color [4]byte
+	for i, c := range a.clearColor {
+		color[i] = toByte(float64(c))
+	}
+	for y := area.y; y < area.y+area.height; y++ {
+		for x := area.x; x < area.x+area.width; x++ {
+			i := (y*target.width + x) * 4
+			copy(target.pixels[i:i+4], color[:])
+		}
+	}
+}
+
+func (b box) intersect(other box) box {
+	x1, y1 := maxInt(b.x, other.x), maxInt(b.y, other.y)
+	x2, y2 := minInt(b.x+b.width, other.x+other.width), minInt(b.y+b.height, other.y+other.height)
+	if x2 < x1 || y2 < y1 {
+		return box{}
+	}
+	return box{x: x1, y: y1, width: x2 - x1, height: y2 - y1}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// toByte converts color component to normalized unsigned byte. Component is
+// first rounded to float32 precision, just like GPU does.
+func toByte(c float64) byte {
+	c = float64(float32(c))
+	if !(c > 0) {
+		return 0
+	}
+	if c >= 1 {
+		return 255
+	}
+	return byte(math.Floor(c*255 + 0.5))
+}
+
+// vertex is a vertex processed by vertex shader
+type vertex struct {
+	// x, y and z are window coordinates, w is 1/clip w
+	x, y, z, w float64
+	varyings   []value
+	clipped    bool
+}
+
+// DrawArrays render primitives from array data
+func (a *API) DrawArrays(mode uint32, first int32, count int32) {
+	a.DrawArraysInstanced(mode, first, count, 1)
+}
+
+// DrawArraysInstanced draws multiple instances of a range of elements
+func (a *API) DrawArraysInstanced(mode uint32, first int32, count int32, instancecount int32) {
+	if mode > triangleFan {
+		a.error(invalidEnum)
+		return
+	}
+	if first < 0 || count < 0 || instancecount < 0 {
+		a.error(invalidValue)
+		return
+	}
+	indices := make([]int, count)
+	for i := range indices {
+		indices[i] = int(first) + i
+	}
+	a.draw(mode, indices, int(instancecount))
+}
+
+// DrawElements render primitives from array data using indices stored in
+// element array buffer. Indices pointer must be created using PtrOffset.
+func (a *API) DrawElements(mode uint32, count int32, xtype uint32, indices unsafe.Pointer) {
+	a.DrawElementsInstanced(mode, count, xtype, indices, 1)
+}
+
+// DrawElementsInstanced draws multiple instances of a set of elements.
+// Indices pointer must be created using PtrOffset.
+func (a *API) DrawElementsInstanced(mode uint32, count int32, xtype uint32, indices unsafe.Pointer, instancecount int32) {
+	if mode > triangleFan {
+		a.error(invalidEnum)
+		return
+	}
+	if count < 0 || instancecount < 0 {
+		a.error(invalidValue)
+		return
+	}
+	size := indexSize(xtype)
+	if size == 0 {
+		a.error(invalidEnum)
+		return
+	}
+	array := a.vertexArrays[a.vertexArray]
+	if array == nil {
+		a.error(invalidOperation)
+		return
+	}
+	b := a.buffers[array.elementBuffer]
+	if b == nil {
+		a.error(invalidOperation)
+		return
+	}
+	offset := offsetOf(indices)
+	if offset < 0 || offset+int(count)*size > len(b.data) {
+		a.error(invalidOperation)
+		return
+	}
+	elements := make([]int, count)
+	for i := range elements {
+		data := b.data[offset+i*size:]
+		switch xtype {
+		case unsignedByte:
+			elements[i] = int(data[0])
+		case unsignedShort:
+			elements[i] = int(*(*uint16)(unsafe.Pointer(&data[0])))
+		case unsignedInt:
+			elements[i] = int(*(*uint32)(unsafe.Pointer(&data[0])))
+		}
+	}
+	a.draw(mode, elements, int(instancecount))
+}
+
+func indexSize(xtype uint32) int {
+	switch xtype {
+	case unsignedByte:
+		return 1
+	case unsignedShort:
+		return 2
+	case unsignedInt:
+		return 4
+	}
+	return 0
+}
+
+// draw processes vertices with given indices and rasterizes primitives. It is
+// done for each instance.
+func (a *API) draw(mode uint32, indices []int, instances int) {
+	p, array := a.program, a.vertexArrays[a.vertexArray]
+	if p == nil || array == nil {
+		a.error(invalidOperation)
+		return
+	}
+	target := a.renderTarget()
+	if target == nil || len(indices) == 0 {
+		return
+	}
+	r := a.newRasterizer(p, target)
+	for instance := 0; instance < instances; instance++ {
+		vertices := make([]vertex, len(indices))
+		processed := map[int]int{} // index -> position in vertices
+		for i, index := range indices {
+			if j, ok := processed[index]; ok {
+				vertices[i] = vertices[j]
+				continue
+			}
+			vertices[i] = a.processVertex(p, array, index, instance)
+			processed[index] = i
+		}
+		assemble(mode, vertices, r)
+	}
+}
+
+func (a *API) processVertex(p *program, array *vertexArray, index, instance int) vertex {
+	m := p.vertex
+	m.reset()
+	for _, attr := range p.attributes {
+		pointer := array.attributes[attr.location]
+		if pointer.divisor > 0 {
+			m.memory[attr.slot] = pointer.read(instance / pointer.divisor)
+		} else {
+			m.memory[attr.slot] = pointer.read(index)
+		}
+	}
+	m.memory[p.vertexID] = value{float64(index)}
+	m.memory[p.instanceID] = value{float64(instance)}
+	m.run()
+	position := m.memory[p.position]
+	v := vertex{varyings: make([]value, len(p.varyings))}
+	for i, varying := range p.varyings {
+		v.varyings[i] = m.memory[varying.vertexSlot]
+	}
+	if !(position[3] > 0) {
+		v.clipped = true
+		return v
+	}
+	w := 1 / position[3]
+	vp := a.viewport
+	v.x = (position[0]*w+1)*float64(vp.width)/2 + float64(vp.x)
+	v.y = (position[1]*w+1)*float64(vp.height)/2 + float64(vp.y)
+	v.z = (position[2]*w + 1) / 2
+	v.w = w
+	return v
+}
+
+type primitiveRasterizer interface {
+	point(v *vertex)
+	line(v0, v1 *vertex)
+	// triangle rasterizes triangle. Flat varyings are taken from provoking
+	// vertex.
+	triangle(v0, v1, v2, provoking *vertex)
+}
+
+// assemble splits vertices into primitives. The last vertex of the primitive
+// is a provoking vertex.
+func assemble(mode uint32, v []vertex, r primitiveRasterizer) {
+	n := len(v)
+	switch mode {
+	case points:
+		for i := range v {
+			r.point(&v[i])
+		}
+	case lines:
+		for i := 0; i+1 < n; i += 2 {
+			r.line(&v[i], &v[i+1])
+		}
+	case lineStrip, lineLoop:
+		for i := 0; i+1 < n; i++ {
+			r.line(&v[i], &v[i+1])
+		}
+		if mode == lineLoop && n > 1 {
+			r.line(&v[n-1], &v[0])
+		}
+	case triangles:
+		for i := 0; i+2 < n; i += 3 {
+			r.triangle(&v[i], &v[i+1], &v[i+2], &v[i+2])
+		}
+	case triangleStrip:
+		for i := 0; i+2 < n; i++ {
+			if i%2 == 0 {
+				r.triangle(&v[i], &v[i+1], &v[i+2], &v[i+2])
+			} else {
+				r.triangle(&v[i+1], &v[i], &v[i+2], &v[i+2])
+			}
+		}
+	case triangleFan:
+		for i := 1; i+1 < n; i++ {
+			r.triangle(&v[0], &v[i], &v[i+1], &v[i+1])
+		}
+	}
+}
+
+type rasterizer struct {
+	program      *program
+	target       *texture
+	clip         box
+	blend        bool
+	blendFactors [2]uint32
+}
+
+func (a *API) newRasterizer(p *program, target *texture) *rasterizer {
+	clip := box{width: target.width, height: target.height}.intersect(a.viewport)
+	if a.capabilities[scissorTest] {
+		clip = clip.intersect(a.scissor)
+	}
+	return &rasterizer{
+		program:      p,
+		target:       target,
+		clip:         clip,
+		blend:        a.capabilities[blend],
+		blendFactors: a.blendFactors,
+	}
+}
+
+func (r *rasterizer) point(v *vertex) {
+	if v.clipped {
+		return
+	}
+	r.fragment(int(math.Floor(v.x)), int(math.Floor(v.y)), v.z, v.w, func(i int) value {
+		return v.varyings[i]
+	})
+}
+
+// line rasterizes line using pixel centers. The last pixel is not drawn.
+func (r *rasterizer) line(v0, v1 *vertex) {
+	if v0.clipped || v1.clipped {
+		return
+	}
+	dx, dy := v1.x-v0.x, v1.y-v0.y
+	xMajor := math.Abs(dx) >= math.Abs(dy)
+	start, end, delta := v0.x, v1.x, dx
+	if !xMajor {
+		start, end, delta = v0.y, v1.y, dy
+	}
+	if delta == 0 {
+		return
+	}
+	from, to := math.Floor(math.Min(start, end)), math.Ceil(math.Max(start, end))
+	for major := from; major <= to; major++ {
+		center := major + 0.5
+		// half-open segment [start, end)
+		if (delta > 0 && (center < start || center >= end)) || (delta < 0 && (center > start || center <= end)) {
+			continue
+		}
+		t := (center - start) / delta
+		x, y := int(major), int(math.Floor(v0.y+t*dy))
+		if !xMajor {
+			x, y = int(math.Floor(v0.x+t*dx)), int(major)
+		}
+		weights := []float64{(1 - t) * v0.w, t * v1.w}
+		z := v0.z*(1-t) + v1.z*t
+		r.fragment(x, y, z, weights[0]+weights[1], r.interpolate([]*vertex{v0, v1}, weights, v1))
+	}
+}
+
+// triangle rasterizes triangle with pixel centers inside the triangle. Pixels
+// on the shared edge are drawn only once.
+func (r *rasterizer) triangle(v0, v1, v2, provoking *vertex) {
+	if v0.clipped || v1.clipped || v2.clipped {
+		return
+	}
+	area := edgeFunction(v0, v1, v2.x, v2.y)
+	if area == 0 {
+		return
+	}
+	if area < 0 {
+		v1, v2 = v2, v1
+		area = -area
+	}
+	vertices := []*vertex{v0, v1, v2}
+	minX := math.Min(v0.x, math.Min(v1.x, v2.x))
+	maxX := math.Max(v0.x, math.Max(v1.x, v2.x))
+	minY := math.Min(v0.y, math.Min(v1.y, v2.y))
+	maxY := math.Max(v0.y, math.Max(v1.y, v2.y))
+	bounds := box{
+		x:      int(math.Floor(minX)),
+		y:      int(math.Floor(minY)),
+		width:  int(math.Ceil(maxX)) - int(math.Floor(minX)),
+		height: int(math.Ceil(maxY)) - int(math.Floor(minY)),
+	}.intersect(r.clip)
+	weights := make([]float64, 3)
+	for y := bounds.y; y < bounds.y+bounds.height; y++ {
+		for x := bounds.x; x < bounds.x+bounds.width; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			inside := true
+			for i := 0; i < 3 && inside; i++ {
+				a, b := vertices[(i+1)%3], vertices[(i+2)%3]
+				e := edgeFunction(a, b, px, py)
+				inside = e > 0 || (e == 0 && isTopLeft(a, b))
+				weights[i] = e / area
+			}
+			if !inside {
+				continue
+			}
+			z := weights[0]*v0.z + weights[1]*v1.z + weights[2]*v2.z
+			perspective := make([]float64, 3)
+			var w float64
+			for i, v := range vertices {
+				perspective[i] = weights[i] * v.w
+				w += perspective[i]
+			}
+			r.fragment(x, y, z, w, r.interpolate(vertices, perspective, provoking))
+		}
+	}
+}
+
+// edgeFunction returns doubled signed area of triangle a, b, p. The value
+// for edge (a,b) is always the negation of value for edge (b,a), which
+// is needed for proper handling of shared edges.
+func edgeFunction(a, b *vertex, px, py float64) float64 {
+	if b.x < a.x || (b.x == a.x && b.y < a.y) {
+		return -edgeFunction(b, a, px, py)
+	}
+	return (b.x-a.x)*(py-a.y) - (b.y-a.y)*(px-a.x)
+}
+
+// isTopLeft decides if pixels exactly on the edge belong to the triangle.
+// For the shared edge it returns true only for one of two triangles.
+func isTopLeft(a, b *vertex) bool {
+	dx, dy := b.x-a.x, b.y-a.y
+	return dy < 0 || (dy == 0 && dx > 0)
+}
+
+// interpolate returns function calculating varying of the fragment. Weights
+// are already multiplied by 1/w of each vertex.
+func (r *rasterizer) interpolate(vertices []*vertex, weights []float64, provoking *vertex) func(i int) value {
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	return func(i int) value {
+		if r.program.varyings[i].flat {
+			return provoking.varyings[i]
+		}
+		var result value
+		for j, v := range vertices {
+			w := weights[j] / sum
+			for c := 0; c < r.program.varyings[i].size; c++ {
+				result[c] += v.varyings[i][c] * w
+			}
+		}
+		return result
+	}
+}
+
+// fragment runs fragment shader for pixel x, y and writes the output color
+// to the target.
+func (r *rasterizer) fragment(x, y int, z, w float64, varying func(i int) value) {
+	if x < r.clip.x || y < r.clip.y || x >= r.clip.x+r.clip.width || y >= r.clip.y+r.clip.height {
+		return
+	}
+	p := r.program
+	m := p.fragment
+	m.reset()
+	for i, v := range p.varyings {
+		m.memory[v.fragmentSlot] = varying(i)
+	}
+	m.memory[p.fragCoord] = value{float64(x) + 0.5, float64(y) + 0.5, z, w}
+	if !m.run() || p.output < 0 {
+		return
+	}
+	source := m.memory[p.output]
+	for i := range source {
+		source[i] = clamp(source[i])
+	}
+	i := (y*r.target.width + x) * 4
+	pixel := r.target.pixels[i : i+4]
+	if r.blend {
+		var destination value
+		for c := range destination {
+			destination[c] = float64(pixel[c]) / 255
+		}
+		for c := range source {
+			source[c] = clamp(source[c]*blendFactor(r.blendFactors[0], source, destination, c) +
+				destination[c]*blendFactor(r.blendFactors[1], source, destination, c))
+		}
+	}
+	for c := range pixel {
+		pixel[c] = toByte(source[c])
+	}
+}
+
+func clamp(c float64) float64 {
+	if !(c > 0) {
+		return 0
+	}
+	if c > 1 {
+		return 1
+	}
+	return c
+}
+
+func blendFactor(factor uint32, source, destination value, c int) float64 {
+	switch factor {
+	case zero:
+		return 0
+	case one:
+		return 1
+	case srcColor:
+		return source[c]
+	case oneMinusSrcColor:
+		return 1 - source[c]
+	case srcAlpha:
+		return source[3]
+	case oneMinusSrcAlpha:
+		return 1 - source[3]
+	case dstAlpha:
+		return destination[3]
+	case oneMinusDstAlpha:
+		return 1 - destination[3]
+	case dstColor:
+		return destination[c]
+	default:
+		return 1 - destination[c]
+	}
+}
@@ -0,0 +1,293 @@
+// Package software provides gl.API implementation which does not need GPU.
+// All drawing is done by CPU, which makes it slow, but it can be used anywhere:
+// in headless environments, continuous integration servers and tests:
+//
+//	context := gl.NewContext(software.NewAPI())
+//	img := image.New(context.NewAcceleratedImage(16, 16))
+//
+// Only the subset of OpenGL used by Pixiq is implemented:
+//
+//   - buffers, vertex arrays (only float attributes), DrawArrays and
+//     DrawElements for all modes, including instanced rendering
+//   - RGBA textures with nearest filtering and framebuffers with single color
+//     attachment
+//   - scissor test, viewport and blending with BlendFunc
+//   - vertex and fragment shaders written in a subset of GLSL 3.30 (scalars,
+//     vectors, samplers, control flow statements, user functions and most
+//     common built-in functions). Matrices, arrays, structs and out function
+//     parameters are not supported.
+//
+// Primitives are not clipped against near and far planes and primitives with
+// any vertex behind the viewer (clip w <= 0) are skipped. Drawing into
+// the default framebuffer (with id 0) does nothing, because there is no window.
+//
+// Objects created by the API are garbage collected Go values, therefore it is
+// not necessary to run API in the main thread. API is not safe for concurrent
+// use though.
+package software
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+const (
+	textureUnits  = 16
+	vertexAttribs = 16
+	// maxSize is a maximum width and height of texture
+	maxSize = 8192
+)
+
+// NewAPI creates a new software gl.API implementation. Each API instance has
+// its own objects (like buffers, textures and programs) and state, it is like
+// a separate OpenGL context.
+func NewAPI() *API {
+	return &API{
+		buffers:      map[uint32]*buffer{},
+		boundBuffers: map[uint32]uint32{},
+		vertexArrays: map[uint32]*vertexArray{},
+		textures:     map[uint32]*texture{},
+		framebuffers: map[uint32]*framebufferObject{},
+		shaders:      map[uint32]*shaderObject{},
+		programs:     map[uint32]*program{},
+		capabilities: map[uint32]bool{},
+		syncs:        map[uintptr]bool{},
+		blendFactors: [2]uint32{one, zero},
+	}
+}
+
+// API is a gl.API implementation rendering using CPU.
+type API struct {
+	errors        []uint32
+	lastName      uint32
+	buffers       map[uint32]*buffer
+	boundBuffers  map[uint32]uint32
+	vertexArrays  map[uint32]*vertexArray
+	vertexArray   uint32
+	textures      map[uint32]*texture
+	activeTexture int
+	textureUnits  [textureUnits]uint32
+	framebuffers  map[uint32]*framebufferObject
+	framebuffer   uint32
+	shaders       map[uint32]*shaderObject
+	programs      map[uint32]*program
+	program       *program
+	capabilities  map[uint32]bool
+	syncs         map[uintptr]bool
+	scissor       box
+	viewport      box
+	clearColor    [4]float32
+	blendFactors  [2]uint32
+}
+
+// box is a rectangle in window coordinates
+type box struct {
+	x, y, width, height int
+}
+
+func (a *API) error(code uint32) {
+	a.errors = append(a.errors, code)
+}
+
+func (a *API) newName() uint32 {
+	a.lastName++
+	return a.lastName
+}
+
+// generate generates n names and stores them in names array
+func (a *API) generate(n int32, names *uint32, create func(name uint32)) {
+	if n < 0 {
+		a.error(invalidValue)
+		return
+	}
+	if n == 0 {
+		return
+	}
+	out := uint32s(names, int(n))
+	for i := range out {
+		out[i] = a.newName()
+		create(out[i])
+	}
+}
+
+// GetError returns error information
+func (a *API) GetError() uint32 {
+	if len(a.errors) == 0 {
+		return noError
+	}
+	code := a.errors[0]
+	a.errors = a.errors[1:]
+	return code
+}
+
+// GetIntegerv returns the value or values of the specified parameter
+func (a *API) GetIntegerv(pname uint32, data *int32) {
+	switch pname {
+	case maxTextureSize:
+		*data = maxSize
+	case maxTextureImageUnits:
+		*data = textureUnits
+	case maxVertexAttribs:
+		*data = vertexAttribs
+	case viewport:
+		copy(int32s(data, 4), []int32{int32(a.viewport.x), int32(a.viewport.y), int32(a.viewport.width), int32(a.viewport.height)})
+	case scissorBox:
+		copy(int32s(data, 4), []int32{int32(a.scissor.x), int32(a.scissor.y), int32(a.scissor.width), int32(a.scissor.height)})
+	default:
+		a.error(invalidEnum)
+	}
+}
+
+// Enable enables server-side GL capabilities. Only scissor test and blending
+// have any effect.
+func (a *API) Enable(cap uint32) {
+	a.capabilities[cap] = true
+}
+
+// Disable disables server-side GL capabilities
+func (a *API) Disable(cap uint32) {
+	a.capabilities[cap] = false
+}
+
+// Finish blocks until all GL execution is complete. All commands are executed
+// synchronously, so it does nothing.
+func (a *API) Finish() {}
+
+// FenceSync creates a new sync object. All commands are executed
+// synchronously, so the sync object is signaled immediately.
+func (a *API) FenceSync(condition uint32, flags uint32) uintptr {
+	if condition != syncGPUCommandsComplete {
+		a.error(invalidEnum)
+		return 0
+	}
+	if flags != 0 {
+		a.error(invalidValue)
+		return 0
+	}
+	sync := uintptr(a.newName())
+	a.syncs[sync] = true
+	return sync
+}
+
+// ClientWaitSync returns immediately, because all sync objects are signaled
+func (a *API) ClientWaitSync(sync uintptr, flags uint32, timeout uint64) uint32 {
+	if !a.syncs[sync] {
+		a.error(invalidValue)
+		return waitFailed
+	}
+	return alreadySignaled
+}
+
+// DeleteSync deletes a sync object
+func (a *API) DeleteSync(sync uintptr) {
+	if sync == 0 {
+		return
+	}
+	if !a.syncs[sync] {
+		a.error(invalidValue)
+		return
+	}
+	delete(a.syncs, sync)
+}
+
+// Ptr takes a slice or pointer (to a singular scalar value or the first
+// element of an array or slice) and returns its address.
+func (a *API) Ptr(data interface{}) unsafe.Pointer {
+	if data == nil {
+		return nil
+	}
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.UnsafePointer:
+		return unsafe.Pointer(v.Pointer())
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return nil
+		}
+		return unsafe.Pointer(v.Index(0).UnsafeAddr())
+	}
+	panic(fmt.Sprintf("unsupported type %s; must be a slice or pointer", v.Type()))
+}
+
+// pointerOffset is an offset returned by PtrOffset
+type pointerOffset struct {
+	offset int
+}
+
+// PtrOffset takes a pointer offset and returns a pointer which can be passed
+// to functions such as VertexAttribPointer.
+func (a *API) PtrOffset(offset int) unsafe.Pointer {
+	return unsafe.Pointer(&pointerOffset{offset: offset})
+}
+
+func offsetOf(pointer unsafe.Pointer) int {
+	if pointer == nil {
+		return 0
+	}
+	return (*pointerOffset)(pointer).offset
+}
+
+// GoStr takes a null-terminated string and constructs a corresponding Go string.
+func (a *API) GoStr(cstr *uint8) string {
+	if cstr == nil {
+		return ""
+	}
+	chars := (*[1 << 30]byte)(unsafe.Pointer(cstr))
+	length := 0
+	for chars[length] != 0 {
+		length++
+	}
+	return string(chars[:length])
+}
+
+// Strs takes a list of Go strings (with or without null-termination) and
+// returns their null-terminated counterparts. The returned free function does
+// nothing, because strings are garbage collected.
+func (a *API) Strs(strs ...string) (cstrs **uint8, free func()) {
+	if len(strs) == 0 {
+		panic("Strs: expected at least 1 string")
+	}
+	pointers := make([]*uint8, len(strs))
+	for i, s := range strs {
+		if !strings.HasSuffix(s, "\x00") {
+			s += "\x00"
+		}
+		pointers[i] = &[]byte(s)[0]
+	}
+	return &pointers[0], func() {}
+}
+
+// bytes returns slice of size bytes starting at pointer
+func bytes(pointer unsafe.Pointer, size int) []byte {
+	if size == 0 {
+		return nil
+	}
+	return (*[1 << 30]byte)(pointer)[:size:size]
+}
+
+func uint32s(pointer *uint32, n int) []uint32 {
+	return (*[1 << 28]uint32)(unsafe.Pointer(pointer))[:n:n]
+}
+
+func int32s(pointer *int32, n int) []int32 {
+	return (*[1 << 28]int32)(unsafe.Pointer(pointer))[:n:n]
+}
+
+// writeString writes null-terminated string into buffer with size bufSize
+func writeString(s string, bufSize int32, length *int32, buffer *uint8) {
+	n := 0
+	if bufSize > 0 {
+		n = len(s)
+		if n > int(bufSize)-1 {
+			n = int(bufSize) - 1
+		}
+		out := bytes(unsafe.Pointer(buffer), n+1)
+		copy(out, s[:n])
+		out[n] = 0
+	}
+	if length != nil {
+		*length = int32(n)
+	}
+}
@@ -0,0 +1,531 @@
+package software_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/gl/software"
+	"github.com/elgopher/pixiq/image"
+)
+
+const vertexShaderSrc = `
+	#version 330 core
+	layout(location = 0) in vec2 xy;
+	void main() {
+		gl_Position = vec4(xy, 0.0, 1.0);
+	}
+	`
+
+func TestNewAPI(t *testing.T) {
+	t.Run("should create API which can be used by gl.Context", func(t *testing.T) {
+		// when
+		api := software.NewAPI()
+		// then
+		context := gl.NewContext(api)
+		assert.Equal(t, 8192, context.Capabilities().MaxTextureSize())
+		assert.NoError(t, context.Error())
+	})
+}
+
+func TestAPI_GetError(t *testing.T) {
+	t.Run("should return errors in order", func(t *testing.T) {
+		api := software.NewAPI()
+		api.BindBuffer(0x1234, 0)
+		api.BindTexture(0x0DE1, 999)
+		// expect
+		assert.Equal(t, uint32(0x500), api.GetError()) // invalid enum
+		assert.Equal(t, uint32(0x502), api.GetError()) // invalid operation
+		assert.Equal(t, uint32(0), api.GetError())
+	})
+}
+
+func TestAPI_Strs(t *testing.T) {
+	t.Run("should panic when no strings are given", func(t *testing.T) {
+		api := software.NewAPI()
+		assert.Panics(t, func() {
+			api.Strs()
+		})
+	})
+	t.Run("should convert strings which can be read by GoStr", func(t *testing.T) {
+		api := software.NewAPI()
+		// when
+		cstrs, free := api.Strs("name\x00", "other")
+		defer free()
+		// then
+		assert.Equal(t, "name", api.GoStr(*cstrs))
+	})
+}
+
+func TestAPI_Ptr(t *testing.T) {
+	t.Run("should panic for unsupported type", func(t *testing.T) {
+		api := software.NewAPI()
+		assert.Panics(t, func() {
+			api.Ptr("string")
+		})
+	})
+	t.Run("should return nil", func(t *testing.T) {
+		api := software.NewAPI()
+		assert.Equal(t, unsafe.Pointer(nil), api.Ptr(nil))
+		assert.Equal(t, unsafe.Pointer(nil), api.Ptr([]float32{}))
+	})
+	t.Run("should return address of first element", func(t *testing.T) {
+		api := software.NewAPI()
+		slice := []int32{1, 2}
+		// expect
+		assert.Equal(t, &slice[0], (*int32)(api.Ptr(slice)))
+		assert.Equal(t, &slice[1], (*int32)(api.Ptr(&slice[1])))
+	})
+}
+
+func TestCompileShader(t *testing.T) {
+	t.Run("should return error", func(t *testing.T) {
+		tests := map[string]string{
+			"syntax error":          `void main() { float a = ; }`,
+			"undeclared identifier": `void main() { a = 1.0; }`,
+			"type mismatch":         `void main() { int a = 1.0; }`,
+			"invalid swizzle":       `void main() { vec2 v = vec2(1.0); float z = v.z; }`,
+			"undefined function":    `void f(); void main() { f(); }`,
+			"recursion":             `void f() { f(); } void main() { f(); }`,
+			"missing return":        `float f() { return; } void main() { f(); }`,
+			"break outside loop":    `void main() { break; }`,
+			"matrix":                `uniform mat4 m; void main() {}`,
+			"out parameter":         `void f(out float a) {} void main() {}`,
+			"wrong main signature":  `int main() { return 0; }`,
+		}
+		for name, src := range tests {
+			t.Run(name, func(t *testing.T) {
+				context := gl.NewContext(software.NewAPI())
+				// when
+				shader, err := context.CompileFragmentShader("#version 330 core\n" + src)
+				// then
+				assert.Error(t, err)
+				assert.Nil(t, shader)
+			})
+		}
+	})
+	t.Run("should report line of error", func(t *testing.T) {
+		context := gl.NewContext(software.NewAPI())
+		// when
+		_, err := context.CompileVertexShader("#version 330 core\nvoid main() {\n\tdiscard;\n}")
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "0:3: discard used outside fragment shader")
+	})
+}
+
+func TestLinkProgram(t *testing.T) {
+	t.Run("should return error when main is missing", func(t *testing.T) {
+		context := gl.NewContext(software.NewAPI())
+		vertexShader, err := context.CompileVertexShader(vertexShaderSrc)
+		require.NoError(t, err)
+		fragmentShader, err := context.CompileFragmentShader("#version 330 core\nvoid noMain() {}")
+		require.NoError(t, err)
+		// when
+		program, err := context.LinkProgram(vertexShader, fragmentShader)
+		// then
+		assert.Error(t, err)
+		assert.Nil(t, program)
+	})
+	t.Run("should return error when varying types are different", func(t *testing.T) {
+		context := gl.NewContext(software.NewAPI())
+		vertexShader, err := context.CompileVertexShader(`
+			#version 330 core
+			out vec2 v;
+			void main() {
+				v = vec2(0.0);
+				gl_Position = vec4(0.0);
+			}`)
+		require.NoError(t, err)
+		fragmentShader, err := context.CompileFragmentShader(`
+			#version 330 core
+			in vec3 v;
+			void main() {}`)
+		require.NoError(t, err)
+		// when
+		program, err := context.LinkProgram(vertexShader, fragmentShader)
+		// then
+		assert.Error(t, err)
+		assert.Nil(t, program)
+	})
+}
+
+func TestInterpreter(t *testing.T) {
+	t.Run("should evaluate expression to true", func(t *testing.T) {
+		tests := map[string]string{
+			"float arithmetic": `ok = 1.5 + 2.0 * 3.0 - 1.0 / 4.0 == 7.25;`,
+			"int arithmetic":   `ok = 7 / 2 == 3 && -7 / 2 == -3 && 7 % 3 == 1 && (5 & 3) == 1 && (1 << 4) == 16;`,
+			"int overflow":     `ok = 2147483647 + 1 == -2147483648;`,
+			"increments": `
+				int i = 1;
+				int a = i++;
+				int b = ++i;
+				ok = a == 1 && b == 3 && i == 3;`,
+			"compound assignment": `
+				vec2 v = vec2(1.0, 2.0);
+				v *= 2.0;
+				v += vec2(1.0);
+				ok = v == vec2(3.0, 5.0);`,
+			"swizzle": `
+				vec4 v = vec4(1.0, 2.0, 3.0, 4.0);
+				ok = v.wzyx == vec4(4.0, 3.0, 2.0, 1.0) && v.rg == vec2(1.0, 2.0) && v.sss == vec3(1.0);`,
+			"swizzle assignment": `
+				vec4 v = vec4(0.0);
+				v.zx = vec2(1.0, 2.0);
+				v.y += 3.0;
+				ok = v == vec4(2.0, 3.0, 1.0, 0.0);`,
+			"indexing": `
+				vec3 v = vec3(1.0, 2.0, 3.0);
+				int i = 2;
+				v[i - 1] = 5.0;
+				ok = v[i] == 3.0 && v[1] == 5.0;`,
+			"constructors": `
+				vec4 v = vec4(vec2(1.0, 2.0), 3, true);
+				ivec2 i = ivec2(vec2(1.7, -1.7));
+				ok = v == vec4(1.0, 2.0, 3.0, 1.0) && i == ivec2(1, -1) && bool(2) && float(true) == 1.0;`,
+			"ternary": `ok = (1 > 2 ? 1.0 : 2.0) == 2.0;`,
+			"short circuit": `
+				int i = 0;
+				bool b = false && ++i > 0;
+				b = true || ++i > 0;
+				ok = i == 0;`,
+			"if else": `
+				int i = 3;
+				if (i < 2) { ok = false; } else if (i < 4) { ok = true; } else { ok = false; }`,
+			"for loop": `
+				int sum = 0;
+				for (int i = 0; i < 10; i++) {
+					if (i == 2) continue;
+					if (i == 5) break;
+					sum += i;
+				}
+				ok = sum == 8;`,
+			"while loop": `
+				int i = 0;
+				while (i < 5) i += 2;
+				ok = i == 6;`,
+			"do while loop": `
+				int i = 10;
+				do { i++; } while (i < 5);
+				ok = i == 11;`,
+			"switch": `
+				int a = 0;
+				switch (2) {
+				case 1:
+					a = 1;
+				case 2:
+					a += 2;
+				case 3:
+					a += 3;
+					break;
+				default:
+					a = 10;
+				}
+				ok = a == 5;`,
+			"switch default": `
+				int a = 0;
+				switch (7) {
+				case 1: a = 1; break;
+				default: a = 10;
+				}
+				ok = a == 10;`,
+			"user functions": `ok = twice(2.0) == 4.0 && twice(3) == 6 && twice(vec2(1.0, 2.0)) == vec2(2.0, 4.0);`,
+			"const globals":  `ok = limit == 5.0;`,
+			"uniforms":       `ok = uniformValue == vec2(0.0);`,
+			"trigonometry":   `ok = sin(0.0) == 0.0 && cos(0.0) == 1.0 && abs(degrees(radians(90.0)) - 90.0) < 0.0001;`,
+			"common functions": `ok = clamp(2.0, 0.0, 1.0) == 1.0 && mix(0.0, 2.0, 0.25) == 0.5 &&
+				floor(-1.5) == -2.0 && mod(5.0, 3.0) == 2.0 && max(vec2(1.0, 5.0), 3.0) == vec2(3.0, 5.0) &&
+				abs(-2) == 2 && sign(-3.0) == -1.0 && step(0.5, 0.7) == 1.0 && fract(1.25) == 0.25 &&
+				round(0.5) == 1.0 && roundEven(2.5) == 2.0 && pow(2.0, 3.0) == 8.0 && sqrt(16.0) == 4.0;`,
+			"geometric functions": `ok = length(vec2(3.0, 4.0)) == 5.0 && dot(vec3(1.0, 2.0, 3.0), vec3(1.0)) == 6.0 &&
+				distance(vec2(1.0), vec2(4.0, 5.0)) == 5.0 && normalize(vec2(2.0, 0.0)) == vec2(1.0, 0.0);`,
+			"vector relational functions": `ok = all(lessThan(vec2(1.0, 2.0), vec2(2.0, 3.0))) &&
+				any(equal(ivec2(1, 2), ivec2(3, 2))) && !all(not(bvec2(true, false)));`,
+			"gl_FragCoord": `ok = gl_FragCoord.xy == vec2(0.5, 0.5);`,
+		}
+		for name, statements := range tests {
+			t.Run(name, func(t *testing.T) {
+				fragmentShader := `
+					#version 330 core
+					const float limit = 5.0;
+					uniform vec2 uniformValue;
+					out vec4 color;
+					float twice(float f) { return f * 2.0; }
+					int twice(int i) { return i * 2; }
+					vec2 twice(vec2 v) { return v * 2.0; }
+					void main() {
+						bool ok = false;
+						` + statements + `
+						color = ok ? vec4(1.0) : vec4(1.0, 0.0, 0.0, 1.0);
+					}`
+				// when
+				color := drawPoint(t, fragmentShader)
+				// then
+				assert.Equal(t, image.RGBA(255, 255, 255, 255), color)
+			})
+		}
+	})
+	t.Run("should discard fragment", func(t *testing.T) {
+		color := drawPoint(t, `
+			#version 330 core
+			out vec4 color;
+			void skip() {
+				discard;
+			}
+			void main() {
+				color = vec4(1.0);
+				skip();
+			}`)
+		assert.Equal(t, image.Transparent, color)
+	})
+	t.Run("should sample texture", func(t *testing.T) {
+		context := gl.NewContext(software.NewAPI())
+		texture := context.NewAcceleratedImage(2, 1)
+		texture.Upload([]image.Color{image.RGB(10, 20, 30), image.RGBA(40, 50, 60, 70)})
+		output := context.NewAcceleratedImage(2, 1)
+		program := compileProgram(t, context, `
+			#version 330 core
+			out vec4 color;
+			uniform sampler2D tex;
+			void main() {
+				ivec2 size = textureSize(tex, 0);
+				vec4 fetched = texelFetch(tex, ivec2(gl_FragCoord.xy), 0);
+				vec4 sampled = texture(tex, gl_FragCoord.xy / vec2(size));
+				color = fetched == sampled ? fetched : vec4(0.0);
+			}`)
+		array := context.NewVertexArray(gl.VertexLayout{gl.Vec2})
+		buffer := context.NewFloatVertexBuffer(8, gl.StaticDraw)
+		buffer.Upload(0, []float32{-1, -1, 1, -1, -1, 1, 1, 1})
+		array.Set(0, gl.VertexBufferPointer{Buffer: buffer, Stride: 2})
+		command := program.AcceleratedCommand(&command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+			renderer.BindTexture(0, "tex", texture)
+			renderer.DrawArrays(array, gl.TriangleStrip, 0, 4)
+		}})
+		// when
+		command.Run(image.AcceleratedImageSelection{
+			Location: image.AcceleratedImageLocation{Width: 2, Height: 1},
+			Image:    output,
+		}, nil)
+		// then
+		colors := make([]image.Color, 2)
+		output.Download(colors)
+		assert.Equal(t, []image.Color{image.RGB(10, 20, 30), image.RGBA(40, 50, 60, 70)}, colors)
+		assert.NoError(t, context.Error())
+	})
+}
+
+func TestRenderer_Clear(t *testing.T) {
+	t.Run("should clear only selected part of image", func(t *testing.T) {
+		context := gl.NewContext(software.NewAPI())
+		output := context.NewAcceleratedImage(3, 2)
+		output.Upload(make([]image.Color, 6))
+		color := image.RGBA(10, 20, 30, 40)
+		command := context.NewClearCommand()
+		command.SetColor(color)
+		// when
+		command.Run(image.AcceleratedImageSelection{
+			Location: image.AcceleratedImageLocation{X: 1, Width: 2, Height: 2},
+			Image:    output,
+		}, nil)
+		// then
+		colors := make([]image.Color, 6)
+		output.Download(colors)
+		assert.Equal(t, []image.Color{
+			image.Transparent, color, color,
+			image.Transparent, color, color,
+		}, colors)
+	})
+}
+
+func TestRenderer_DrawElements(t *testing.T) {
+	indexTypes := map[string]gl.IndexType{
+		"Uint16": gl.Uint16,
+		"Uint32": gl.Uint32,
+	}
+	for name, indexType := range indexTypes {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should draw quad using indexed vertices", func(t *testing.T) {
+				context := gl.NewContext(software.NewAPI())
+				program := compileProgram(t, context, `
+					#version 330 core
+					out vec4 color;
+					void main() {
+						color = vec4(1.0, 0.0, 0.0, 1.0);
+					}`)
+				output := context.NewAcceleratedImage(4, 2)
+				output.Upload(make([]image.Color, 8))
+				array := context.NewVertexArray(gl.VertexLayout{gl.Vec2})
+				buffer := context.NewFloatVertexBuffer(8, gl.StaticDraw)
+				// right half of the image
+				buffer.Upload(0, []float32{0, -1, 1, -1, 1, 1, 0, 1})
+				array.Set(0, gl.VertexBufferPointer{Buffer: buffer, Stride: 2})
+				indices := context.NewIndexBuffer(9, indexType, gl.StaticDraw)
+				indices.Upload(0, []uint32{3, 3, 3, 0, 1, 2, 0, 2, 3})
+				array.SetIndexBuffer(indices)
+				command := program.AcceleratedCommand(&command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+					// when
+					renderer.DrawElements(array, gl.Triangles, 3, 6)
+				}})
+				command.Run(image.AcceleratedImageSelection{
+					Location: image.AcceleratedImageLocation{Width: 4, Height: 2},
+					Image:    output,
+				}, nil)
+				// then
+				require.NoError(t, context.Error())
+				colors := make([]image.Color, 8)
+				output.Download(colors)
+				red := image.RGB(255, 0, 0)
+				assert.Equal(t, []image.Color{
+					image.Transparent, image.Transparent, red, red,
+					image.Transparent, image.Transparent, red, red,
+				}, colors)
+			})
+			t.Run("should download uploaded indices", func(t *testing.T) {
+				context := gl.NewContext(software.NewAPI())
+				buffer := context.NewIndexBuffer(3, indexType, gl.StaticDraw)
+				buffer.Upload(1, []uint32{65535, 7})
+				output := make([]uint32, 2)
+				// when
+				buffer.Download(1, output)
+				// then
+				assert.Equal(t, []uint32{65535, 7}, output)
+			})
+		})
+	}
+}
+
+func TestRenderer_DrawArraysInstanced(t *testing.T) {
+	t.Run("should draw instances using per-instance attribute and gl_InstanceID", func(t *testing.T) {
+		context := gl.NewContext(software.NewAPI())
+		program := compileInstancedProgram(t, context)
+		output := context.NewAcceleratedImage(4, 1)
+		output.Upload(make([]image.Color, 4))
+		array := context.NewVertexArray(gl.VertexLayout{gl.Vec2, gl.Float})
+		positions := context.NewFloatVertexBuffer(2, gl.StaticDraw)
+		positions.Upload(0, []float32{-0.75, 0})
+		array.Set(0, gl.VertexBufferPointer{Buffer: positions, Stride: 2})
+		offsets := context.NewFloatVertexBuffer(4, gl.StaticDraw)
+		offsets.Upload(0, []float32{0, 0.5, 1, 1.5})
+		array.Set(1, gl.VertexBufferPointer{Buffer: offsets, Stride: 1, Divisor: 1})
+		command := program.AcceleratedCommand(&command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+			// when
+			renderer.DrawArraysInstanced(array, gl.Points, 0, 1, 4)
+		}})
+		command.Run(image.AcceleratedImageSelection{
+			Location: image.AcceleratedImageLocation{Width: 4, Height: 1},
+			Image:    output,
+		}, nil)
+		// then
+		require.NoError(t, context.Error())
+		colors := make([]image.Color, 4)
+		output.Download(colors)
+		assert.Equal(t, []image.Color{
+			image.RGB(0, 0, 0), image.RGB(85, 0, 0), image.RGB(170, 0, 0), image.RGB(255, 0, 0),
+		}, colors)
+	})
+}
+
+func TestRenderer_DrawElementsInstanced(t *testing.T) {
+	t.Run("should draw quad instances", func(t *testing.T) {
+		context := gl.NewContext(software.NewAPI())
+		program := compileInstancedProgram(t, context)
+		output := context.NewAcceleratedImage(4, 1)
+		output.Upload(make([]image.Color, 4))
+		array := context.NewVertexArray(gl.VertexLayout{gl.Vec2, gl.Float})
+		positions := context.NewFloatVertexBuffer(8, gl.StaticDraw)
+		// first pixel of the image
+		positions.Upload(0, []float32{-1, -1, -0.5, -1, -0.5, 1, -1, 1})
+		array.Set(0, gl.VertexBufferPointer{Buffer: positions, Stride: 2})
+		offsets := context.NewFloatVertexBuffer(2, gl.StaticDraw)
+		offsets.Upload(0, []float32{0, 1})
+		array.Set(1, gl.VertexBufferPointer{Buffer: offsets, Stride: 1, Divisor: 1})
+		indices := context.NewIndexBuffer(6, gl.Uint16, gl.StaticDraw)
+		indices.Upload(0, []uint32{0, 1, 2, 0, 2, 3})
+		array.SetIndexBuffer(indices)
+		command := program.AcceleratedCommand(&command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+			// when
+			renderer.DrawElementsInstanced(array, gl.Triangles, 0, 6, 2)
+		}})
+		command.Run(image.AcceleratedImageSelection{
+			Location: image.AcceleratedImageLocation{Width: 4, Height: 1},
+			Image:    output,
+		}, nil)
+		// then
+		require.NoError(t, context.Error())
+		colors := make([]image.Color, 4)
+		output.Download(colors)
+		assert.Equal(t, []image.Color{
+			image.RGB(0, 0, 0), image.Transparent, image.RGB(85, 0, 0), image.Transparent,
+		}, colors)
+	})
+}
+
+// compileInstancedProgram compiles program which moves vertices horizontally
+// by per-instance offset and colors them using gl_InstanceID
+func compileInstancedProgram(t *testing.T, context *gl.Context) *gl.Program {
+	vertexShader, err := context.CompileVertexShader(`
+		#version 330 core
+		layout(location = 0) in vec2 xy;
+		layout(location = 1) in float offset;
+		flat out float red;
+		void main() {
+			gl_Position = vec4(xy.x + offset, xy.y, 0.0, 1.0);
+			red = float(gl_InstanceID) / 3.0;
+		}`)
+	require.NoError(t, err)
+	fragmentShader, err := context.CompileFragmentShader(`
+		#version 330 core
+		flat in float red;
+		out vec4 color;
+		void main() {
+			color = vec4(red, 0.0, 0.0, 1.0);
+		}`)
+	require.NoError(t, err)
+	program, err := context.LinkProgram(vertexShader, fragmentShader)
+	require.NoError(t, err)
+	return program
+}
+
+// drawPoint draws a single point into 1x1 image using given fragment shader
+// and returns the color of the pixel
+func drawPoint(t *testing.T, fragmentShaderSrc string) image.Color {
+	context := gl.NewContext(software.NewAPI())
+	program := compileProgram(t, context, fragmentShaderSrc)
+	output := context.NewAcceleratedImage(1, 1)
+	array := context.NewVertexArray(gl.VertexLayout{gl.Vec2})
+	buffer := context.NewFloatVertexBuffer(2, gl.StaticDraw)
+	buffer.Upload(0, []float32{0, 0})
+	array.Set(0, gl.VertexBufferPointer{Buffer: buffer, Stride: 2})
+	command := program.AcceleratedCommand(&command{runGL: func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+		renderer.DrawArrays(array, gl.Points, 0, 1)
+	}})
+	command.Run(image.AcceleratedImageSelection{
+		Location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+		Image:    output,
+	}, nil)
+	require.NoError(t, context.Error())
+	colors := make([]image.Color, 1)
+	output.Download(colors)
+	return colors[0]
+}
+
+func compileProgram(t *testing.T, context *gl.Context, fragmentShaderSrc string) *gl.Program {
+	vertexShader, err := context.CompileVertexShader(vertexShaderSrc)
+	require.NoError(t, err)
+	fragmentShader, err := context.CompileFragmentShader(fragmentShaderSrc)
+	require.NoError(t, err)
+	program, err := context.LinkProgram(vertexShader, fragmentShader)
+	require.NoError(t, err)
+	return program
+}
+
+type command struct {
+	runGL func(renderer *gl.Renderer, selections []image.AcceleratedImageSelection)
+}
+
+func (c *command) RunGL(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+	c.runGL(renderer, selections)
+}
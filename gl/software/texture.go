@@ -0,0 +1,352 @@
+package software
+
+import (
+	"math"
+	"unsafe"
+)
+
+// texture is a 2D RGBA texture. Rows are stored bottom-up, the same way
+// as in OpenGL.
+type texture struct {
+	width, height int
+	pixels        []byte
+	wrapS, wrapT  int32
+}
+
+type framebufferObject struct {
+	texture uint32
+}
+
+// fetch returns color of texel at given position. Texels outside
+// the texture are transparent.
+func (t *texture) fetch(x, y int) value {
+	if t == nil {
+		return value{0, 0, 0, 1}
+	}
+	if x < 0 || y < 0 || x >= t.width || y >= t.height {
+		return value{}
+	}
+	i := (y*t.width + x) * 4
+	p := t.pixels[i : i+4]
+	return value{float64(p[0]) / 255, float64(p[1]) / 255, float64(p[2]) / 255, float64(p[3]) / 255}
+}
+
+// sample returns color of the texel using nearest filtering
+func (t *texture) sample(s, tt float64) value {
+	if t == nil {
+		return value{0, 0, 0, 1}
+	}
+	return t.fetch(wrap(s, t.width, t.wrapS), wrap(tt, t.height, t.wrapT))
+}
+
+// wrap converts normalized texture coordinate to texel position
+func wrap(coordinate float64, size int, mode int32) int {
+	position := int(math.Floor(coordinate * float64(size)))
+	switch mode {
+	case clampToEdge:
+		if position < 0 {
+			return 0
+		}
+		if position >= size {
+			return size - 1
+		}
+	case repeat:
+		position %= size
+		if position < 0 {
+			position += size
+		}
+	case mirroredRepeat:
+		period := 2 * size
+		position %= period
+		if position < 0 {
+			position += period
+		}
+		if position >= size {
+			position = period - 1 - position
+		}
+	}
+	return position
+}
+
+func (t *texture) contains(x, y, width, height int) bool {
+	return x >= 0 && y >= 0 && width >= 0 && height >= 0 && x+width <= t.width && y+height <= t.height
+}
+
+// copyRegion copies pixels between the texture region and tightly packed
+// pixels. When write is true pixels are copied into texture.
+func (t *texture) copyRegion(x, y, width, height int, pixels []byte, write bool) {
+	rowSize := width * 4
+	for row := 0; row < height; row++ {
+		start := ((y+row)*t.width + x) * 4
+		textureRow := t.pixels[start : start+rowSize]
+		pixelsRow := pixels[row*rowSize : (row+1)*rowSize]
+		if write {
+			copy(textureRow, pixelsRow)
+		} else {
+			copy(pixelsRow, textureRow)
+		}
+	}
+}
+
+// GenTextures generates texture names
+func (a *API) GenTextures(n int32, textures *uint32) {
+	a.generate(n, textures, func(name uint32) {
+		a.textures[name] = &texture{wrapS: repeat, wrapT: repeat}
+	})
+}
+
+// DeleteTextures deletes named textures
+func (a *API) DeleteTextures(n int32, textures *uint32) {
+	if n < 0 {
+		a.error(invalidValue)
+		return
+	}
+	if n == 0 {
+		return
+	}
+	for _, name := range uint32s(textures, int(n)) {
+		delete(a.textures, name)
+		for unit, bound := range a.textureUnits {
+			if bound == name {
+				a.textureUnits[unit] = 0
+			}
+		}
+	}
+}
+
+// ActiveTexture selects active texture unit
+func (a *API) ActiveTexture(texture uint32) {
+	unit := int(texture) - texture0
+	if unit < 0 || unit >= textureUnits {
+		a.error(invalidEnum)
+		return
+	}
+	a.activeTexture = unit
+}
+
+// BindTexture binds a named texture to a texturing target
+func (a *API) BindTexture(target uint32, texture uint32) {
+	if target != texture2D {
+		a.error(invalidEnum)
+		return
+	}
+	if _, ok := a.textures[texture]; !ok && texture != 0 {
+		a.error(invalidOperation)
+		return
+	}
+	a.textureUnits[a.activeTexture] = texture
+}
+
+func (a *API) unitTexture(unit int) *texture {
+	if unit < 0 || unit >= textureUnits {
+		return nil
+	}
+	return a.textures[a.textureUnits[unit]]
+}
+
+// boundTexture returns texture bound to active texture unit. Records an error
+// when target is invalid or there is no such texture.
+func (a *API) boundTexture(target uint32) *texture {
+	if target != texture2D {
+		a.error(invalidEnum)
+		return nil
+	}
+	t := a.unitTexture(a.activeTexture)
+	if t == nil {
+		a.error(invalidOperation)
+	}
+	return t
+}
+
+func (a *API) validPixelFormat(format, xtype uint32) bool {
+	if format != rgba || xtype != unsignedByte {
+		a.error(invalidEnum)
+		return false
+	}
+	return true
+}
+
+// TexImage2D specifies a two-dimensional texture image. Only RGBA format with
+// unsigned bytes is supported. Mipmap levels other than 0 are ignored.
+func (a *API) TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	t := a.boundTexture(target)
+	if t == nil || !a.validPixelFormat(format, xtype) {
+		return
+	}
+	if internalformat != rgba && internalformat != rgba8 {
+		a.error(invalidEnum)
+		return
+	}
+	if width < 0 || height < 0 || width > maxSize || height > maxSize || border != 0 || level < 0 {
+		a.error(invalidValue)
+		return
+	}
+	if level > 0 {
+		return
+	}
+	t.width = int(width)
+	t.height = int(height)
+	t.pixels = make([]byte, t.width*t.height*4)
+	if pixels != nil {
+		copy(t.pixels, bytes(pixels, len(t.pixels)))
+	}
+}
+
+// TexSubImage2D specifies a two-dimensional texture subimage
+func (a *API) TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	t := a.boundTexture(target)
+	if t == nil || !a.validPixelFormat(format, xtype) {
+		return
+	}
+	if level != 0 || !t.contains(int(xoffset), int(yoffset), int(width), int(height)) {
+		a.error(invalidValue)
+		return
+	}
+	size := int(width) * int(height) * 4
+	if size == 0 {
+		return
+	}
+	t.copyRegion(int(xoffset), int(yoffset), int(width), int(height), bytes(pixels, size), true)
+}
+
+// GetTexImage returns a texture image
+func (a *API) GetTexImage(target uint32, level int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	t := a.boundTexture(target)
+	if t == nil || !a.validPixelFormat(format, xtype) {
+		return
+	}
+	if level != 0 {
+		a.error(invalidValue)
+		return
+	}
+	if len(t.pixels) > 0 {
+		copy(bytes(pixels, len(t.pixels)), t.pixels)
+	}
+}
+
+// TexParameteri sets texture parameter. Only wrapping can be changed.
+// Filtering is always nearest.
+func (a *API) TexParameteri(target uint32, pname uint32, param int32) {
+	t := a.boundTexture(target)
+	if t == nil {
+		return
+	}
+	switch pname {
+	case textureWrapS, textureWrapT:
+		switch param {
+		case clampToBorder, clampToEdge, repeat, mirroredRepeat:
+		default:
+			a.error(invalidEnum)
+			return
+		}
+		if pname == textureWrapS {
+			t.wrapS = param
+		} else {
+			t.wrapT = param
+		}
+	}
+}
+
+// GenFramebuffers generates framebuffer object names
+func (a *API) GenFramebuffers(n int32, framebuffers *uint32) {
+	a.generate(n, framebuffers, func(name uint32) {
+		a.framebuffers[name] = &framebufferObject{}
+	})
+}
+
+// DeleteFramebuffers deletes named framebuffer objects
+func (a *API) DeleteFramebuffers(n int32, framebuffers *uint32) {
+	if n < 0 {
+		a.error(invalidValue)
+		return
+	}
+	if n == 0 {
+		return
+	}
+	for _, name := range uint32s(framebuffers, int(n)) {
+		delete(a.framebuffers, name)
+		if a.framebuffer == name {
+			a.framebuffer = 0
+		}
+	}
+}
+
+func validFramebufferTarget(target uint32) bool {
+	return target == framebuffer || target == readFramebuffer || target == drawFramebuffer
+}
+
+// BindFramebuffer binds a framebuffer to a framebuffer target. Read and draw
+// framebuffers are always the same.
+func (a *API) BindFramebuffer(target uint32, framebuffer uint32) {
+	if !validFramebufferTarget(target) {
+		a.error(invalidEnum)
+		return
+	}
+	if _, ok := a.framebuffers[framebuffer]; !ok && framebuffer != 0 {
+		a.error(invalidOperation)
+		return
+	}
+	a.framebuffer = framebuffer
+}
+
+// FramebufferTexture2D attaches a level of a texture object as a logical
+// buffer to the currently bound framebuffer object
+func (a *API) FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
+	if !validFramebufferTarget(target) || attachment != colorAttachment0 || textarget != texture2D {
+		a.error(invalidEnum)
+		return
+	}
+	fb := a.framebuffers[a.framebuffer]
+	if fb == nil {
+		a.error(invalidOperation)
+		return
+	}
+	if _, ok := a.textures[texture]; !ok && texture != 0 {
+		a.error(invalidOperation)
+		return
+	}
+	fb.texture = texture
+}
+
+// renderTarget returns texture attached to bound framebuffer. Nil is returned
+// for default framebuffer.
+func (a *API) renderTarget() *texture {
+	fb := a.framebuffers[a.framebuffer]
+	if fb == nil {
+		return nil
+	}
+	return a.textures[fb.texture]
+}
+
+// ReadPixels reads a block of pixels from the frame buffer. Pixels are written
+// to the buffer bound to pixel pack buffer target (if any).
+func (a *API) ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	if !a.validPixelFormat(format, xtype) {
+		return
+	}
+	if width < 0 || height < 0 {
+		a.error(invalidValue)
+		return
+	}
+	size := int(width) * int(height) * 4
+	var output []byte
+	if a.boundBuffers[pixelPackBuffer] != 0 {
+		output = a.bufferRange(pixelPackBuffer, offsetOf(pixels), size)
+	} else {
+		output = bytes(pixels, size)
+	}
+	target := a.renderTarget()
+	if target == nil || output == nil {
+		return
+	}
+	for row := 0; row < int(height); row++ {
+		for column := 0; column < int(width); column++ {
+			tx, ty := int(x)+column, int(y)+row
+			if tx < 0 || ty < 0 || tx >= target.width || ty >= target.height {
+				continue
+			}
+			i := (ty*target.width + tx) * 4
+			copy(output[(row*int(width)+column)*4:], target.pixels[i:i+4])
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package software
+
+import "fmt"
+
+// value is a runtime value of any GLSL type. Scalars use only the first
+// component. Integers and booleans are stored as float64 too (booleans as 0 or 1).
+type value [4]float64
+
+type baseType int
+
+const (
+	baseVoid baseType = iota
+	baseFloat
+	baseInt
+	baseBool
+	baseSampler
+)
+
+type glslType struct {
+	base baseType
+	size int
+}
+
+var (
+	voidType    = glslType{base: baseVoid}
+	floatType   = glslType{base: baseFloat, size: 1}
+	intType     = glslType{base: baseInt, size: 1}
+	boolType    = glslType{base: baseBool, size: 1}
+	vec2Type    = glslType{base: baseFloat, size: 2}
+	vec4Type    = glslType{base: baseFloat, size: 4}
+	ivec2Type   = glslType{base: baseInt, size: 2}
+	samplerType = glslType{base: baseSampler, size: 1}
+)
+
+var typesByName = map[string]glslType{
+	"void":      voidType,
+	"float":     floatType,
+	"vec2":      vec2Type,
+	"vec3":      {base: baseFloat, size: 3},
+	"vec4":      vec4Type,
+	"int":       intType,
+	"ivec2":     ivec2Type,
+	"ivec3":     {base: baseInt, size: 3},
+	"ivec4":     {base: baseInt, size: 4},
+	"bool":      boolType,
+	"bvec2":     {base: baseBool, size: 2},
+	"bvec3":     {base: baseBool, size: 3},
+	"bvec4":     {base: baseBool, size: 4},
+	"sampler2D": samplerType,
+}
+
+func (t glslType) String() string {
+	for name, typ := range typesByName {
+		if typ == t {
+			return name
+		}
+	}
+	return fmt.Sprintf("%v%d", t.base, t.size)
+}
+
+func (t glslType) isScalar() bool {
+	return t.size == 1 && t.base != baseSampler
+}
+
+func (t glslType) isNumeric() bool {
+	return t.base == baseFloat || t.base == baseInt
+}
+
+func (t glslType) withBase(base baseType) glslType {
+	return glslType{base: base, size: t.size}
+}
+
+func (t glslType) withSize(size int) glslType {
+	return glslType{base: t.base, size: size}
+}
+
+// convertibleTo returns true when value of type t can be implicitly converted
+// to type target. GLSL 3.30 allows only conversion of integers to floats.
+func (t glslType) convertibleTo(target glslType) bool {
+	if t == target {
+		return true
+	}
+	return t.base == baseInt && target.base == baseFloat && t.size == target.size
+}
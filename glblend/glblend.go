@@ -3,65 +3,59 @@
 package glblend
 
 import (
+	"github.com/elgopher/pixiq/blend"
 	"github.com/elgopher/pixiq/gl"
 	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/internal/glquad"
 )
 
 // NewSource creates a new blending tool which replaces target selection with source
 // colors. It is like coping of source selection colors into target.
 func NewSource(context *gl.Context) (*Source, error) {
-	command, err := newBlendCommand(context, gl.SourceBlendFactors)
+	options := newOptions()
+	command, err := newBlendCommand(context, gl.SourceBlendFactors, options)
 	if err != nil {
 		return nil, err
 	}
-	return &Source{command: command}, nil
+	return &Source{command: command, options: options}, nil
 }
 
 // NewSourceOver creates a new blending tool which blends together source and target
 // taking into account alpha channel of both. Source-over means that source will be
 // painted on top of the target.
 func NewSourceOver(context *gl.Context) (*SourceOver, error) {
+	options := newOptions()
 	command, err := newBlendCommand(context, gl.BlendFactors{
 		SrcFactor: gl.One,
 		DstFactor: gl.OneMinusSrcAlpha,
-	})
+	}, options)
 	if err != nil {
 		return nil, err
 	}
-	return &SourceOver{source: &Source{command: command}}, nil
+	return &SourceOver{
+		source: &Source{command: command, options: options},
+	}, nil
 }
 
-const vertexShaderSrc = `
-#version 330 core
-	
-layout(location = 0) in vec2 xy;
-layout(location = 1) in vec2 st;
-out vec2 interpolatedST;
-
-void main() {
-	gl_Position = vec4(xy, 0.0, 1.0);
-	interpolatedST = st;
-}
-`
-
 const fragmentShaderSrc = `
 #version 330 core
 
 uniform sampler2D tex;
+uniform vec4 tint;
 in vec2 interpolatedST;
 out vec4 color;
 
 void main() {
 	// color is blended with buffer using formula: S * sf + D * df 
-	color = texture(tex, interpolatedST);
+	color = texture(tex, interpolatedST) * tint;
 }
 `
 
-func newBlendCommand(context *gl.Context, factors gl.BlendFactors) (*gl.AcceleratedCommand, error) {
+func newBlendCommand(context *gl.Context, factors gl.BlendFactors, options *options) (*gl.AcceleratedCommand, error) {
 	if context == nil {
 		panic("nil context")
 	}
-	vertexShader, err := context.CompileVertexShader(vertexShaderSrc)
+	vertexShader, err := context.CompileVertexShader(glquad.VertexShaderSrc)
 	if err != nil {
 		return nil, err
 	}
@@ -74,89 +68,203 @@ func newBlendCommand(context *gl.Context, factors gl.BlendFactors) (*gl.Accelera
 		return nil, err
 	}
 	vertexBuffer := context.NewFloatVertexBuffer(16, gl.DynamicDraw)
-	vertexArray := makeVertexArray(context, vertexBuffer)
+	vertexArray := glquad.NewVertexArray(context, vertexBuffer)
 	command := program.AcceleratedCommand(
 		&blendCommand{
 			vertexBuffer: vertexBuffer,
 			vertexArray:  vertexArray,
 			factors:      factors,
+			options:      options,
 		})
 	return command, nil
 }
 
-func makeVertexArray(context *gl.Context, buffer *gl.FloatVertexBuffer) *gl.VertexArray {
-	array := context.NewVertexArray(gl.VertexLayout{gl.Vec2, gl.Vec2})
-	xy := gl.VertexBufferPointer{Offset: 0, Stride: 4, Buffer: buffer}
-	array.Set(0, xy)
-	st := gl.VertexBufferPointer{Offset: 2, Stride: 4, Buffer: buffer}
-	array.Set(1, st)
-	return array
-}
-
 type blendCommand struct {
 	vertexBuffer *gl.FloatVertexBuffer
 	vertexArray  *gl.VertexArray
 	factors      gl.BlendFactors
+	options      *options
 }
 
 func (c *blendCommand) RunGL(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
 	source := selections[0]
 	renderer.BindTexture(0, "tex", source.Image)
-	var (
-		imageWidth  = float32(source.Image.Width())
-		left        = float32(source.Location.X) / imageWidth
-		right       = float32(source.Location.X+source.Location.Width) / imageWidth
-		imageHeight = float32(source.Image.Height())
-		top         = (imageHeight - float32(source.Location.Y)) / imageHeight
-		bottom      = (imageHeight - float32(source.Location.Y) - float32(source.Location.Height)) / imageHeight
-	)
+	c.options.setUniforms(renderer)
+	st := c.options.sourceCorners(source)
 	// xy -> st
 	vertices := []float32{
-		-1, 1, left, top,
-		1, 1, right, top,
-		1, -1, right, bottom,
-		-1, -1, left, bottom,
+		-1, 1, st[0][0], st[0][1],
+		1, 1, st[1][0], st[1][1],
+		1, -1, st[2][0], st[2][1],
+		-1, -1, st[3][0], st[3][1],
 	}
 	c.vertexBuffer.Upload(0, vertices)
 	renderer.SetBlendFactors(c.factors)
 	renderer.DrawArrays(c.vertexArray, gl.TriangleFan, 0, 4)
 }
 
+// textureCoordinates returns st coordinates of the selection edges
+func textureCoordinates(selection image.AcceleratedImageSelection) (left, right, top, bottom float32) {
+	var (
+		location    = selection.Location
+		imageWidth  = float32(selection.Image.Width())
+		imageHeight = float32(selection.Image.Height())
+	)
+	left = float32(location.X) / imageWidth
+	right = float32(location.X+location.Width) / imageWidth
+	top = (imageHeight - float32(location.Y)) / imageHeight
+	bottom = (imageHeight - float32(location.Y) - float32(location.Height)) / imageHeight
+	return
+}
+
 // Source is a blending tool which replaces target selection with source
 // colors. It is like coping of source selection colors into target.
 type Source struct {
 	command *gl.AcceleratedCommand
+	options *options
+}
+
+// SetTransform sets the transform (flip or rotation) applied to the source
+// before blending. Will panic when transform is invalid.
+func (s *Source) SetTransform(transform blend.Transform) {
+	s.options.setTransform(transform)
 }
 
 // BlendSourceToTarget blends source into target selection.
 // Only position of the target Selection is used and the source is not clamped by
 // the target size.
 func (s *Source) BlendSourceToTarget(source image.Selection, target image.Selection) {
-	source = clampSourceToTargetImage(source, target)
-	target = target.WithSize(source.Width(), source.Height())
+	source, target = clampSelections(source, target, s.options.transform)
 	// FIXME is it fast enough? or is it better to use the whole texture as a target and update xy in the vertextbuffer accordingly?
 	target.Modify(s.command, source)
 }
 
-func clampSourceToTargetImage(source image.Selection, target image.Selection) image.Selection {
-	width := source.Width()
+// clampSelections clamps the target to the target image and returns the part of
+// the source which is visible in the clamped target after transformation.
+// Video card does not clip pixels on the right and bottom, because viewport is
+// clamped to the image instead.
+func clampSelections(source, target image.Selection, transform blend.Transform) (image.Selection, image.Selection) {
+	sourceWidth, sourceHeight := source.Width(), source.Height()
+	width, height := transform.Size(sourceWidth, sourceHeight)
 	if width+target.ImageX() > target.Image().Width() {
 		width = target.Image().Width() - target.ImageX()
 	}
-	height := source.Height()
 	if height+target.ImageY() > target.Image().Height() {
 		height = target.Image().Height() - target.ImageY()
 	}
-	return source.WithSize(width, height)
+	if width <= 0 || height <= 0 {
+		return source.WithSize(0, 0), target.WithSize(0, 0)
+	}
+	x1, y1 := transform.SourcePosition(0, 0, sourceWidth, sourceHeight)
+	x2, y2 := transform.SourcePosition(width-1, height-1, sourceWidth, sourceHeight)
+	source = source.Selection(min(x1, x2), min(y1, y2)).
+		WithSize(abs(x2-x1)+1, abs(y2-y1)+1)
+	return source, target.WithSize(width, height)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Tool is a blending tool which blends together two selections using video
+// card. Use constructors such as NewMultiply or NewSourceAtop to create it.
+//
+// Source colors can be multiplied by opacity and tint before blending.
+type Tool struct {
+	command *gl.AcceleratedCommand
+	options *options
+	// targetCopy is used by blend modes which need target colors in the
+	// fragment shader. It is nil for Porter-Duff operators.
+	targetCopy *targetCopy
+}
+
+func newPorterDuffTool(context *gl.Context, factors gl.BlendFactors) (*Tool, error) {
+	options := newOptions()
+	command, err := newBlendCommand(context, factors, options)
+	if err != nil {
+		return nil, err
+	}
+	return &Tool{command: command, options: options}, nil
+}
+
+// SetOpacity sets the global opacity multiplied with all source colors.
+// 0 means fully transparent and 1 (the default) fully opaque source. Will panic
+// when opacity is outside range [0,1].
+func (t *Tool) SetOpacity(opacity float64) {
+	t.options.setOpacity(opacity)
+}
+
+// SetTint sets the color multiplied with all source colors. Each component is
+// multiplied separately. The default is opaque white which does not modify
+// source colors.
+func (t *Tool) SetTint(tint image.Color) {
+	t.options.setTint(tint)
+}
+
+// SetTransform sets the transform (flip or rotation) applied to the source
+// before blending. Will panic when transform is invalid.
+func (t *Tool) SetTransform(transform blend.Transform) {
+	t.options.setTransform(transform)
+}
+
+// BlendSourceToTarget blends source into target selection. Results will be stored
+// in the image pointed by target selection.
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (t *Tool) BlendSourceToTarget(source, target image.Selection) {
+	source, target = clampSelections(source, target, t.options.transform)
+	if t.targetCopy == nil {
+		target.Modify(t.command, source)
+		return
+	}
+	if source.Width() <= 0 || source.Height() <= 0 {
+		return
+	}
+	targetCopy := t.targetCopy.copy(target)
+	target.Modify(t.command, source, targetCopy)
 }
 
 // SourceOver (aka Normal) is a blending tool which blends together source and target
 // taking into account alpha channel of both. Source-over means that source will be
 // painted on top of the target.
+//
+// Source colors can be multiplied by opacity and tint before blending. This can
+// be used for fading sprites in and out, damage flashes or ghost effects.
 type SourceOver struct {
 	source *Source
 }
 
+// SetOpacity sets the global opacity multiplied with all source colors.
+// 0 means fully transparent and 1 (the default) fully opaque source. Will panic
+// when opacity is outside range [0,1].
+func (s *SourceOver) SetOpacity(opacity float64) {
+	s.source.options.setOpacity(opacity)
+}
+
+// SetTint sets the color multiplied with all source colors. Each component is
+// multiplied separately, for example image.RGBA(255, 0, 0, 255) keeps only red
+// component of the source. The default is opaque white which does not modify
+// source colors.
+func (s *SourceOver) SetTint(tint image.Color) {
+	s.source.options.setTint(tint)
+}
+
+// SetTransform sets the transform (flip or rotation) applied to the source
+// before blending. Will panic when transform is invalid.
+func (s *SourceOver) SetTransform(transform blend.Transform) {
+	s.source.SetTransform(transform)
+}
+
 // BlendSourceToTarget blends source into target selection.
 // Only position of the target Selection is used and the source is not clamped by
 // the target size.
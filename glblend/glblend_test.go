@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/elgopher/pixiq/gl"
 	"github.com/elgopher/pixiq/glblend"
 )
 
@@ -15,3 +16,34 @@ func TestNewSource(t *testing.T) {
 		})
 	})
 }
+
+func TestNewTool(t *testing.T) {
+	constructors := map[string]func(context *gl.Context) (*glblend.Tool, error){
+		"Clear":           glblend.NewClear,
+		"Destination":     glblend.NewDestination,
+		"DestinationOver": glblend.NewDestinationOver,
+		"SourceIn":        glblend.NewSourceIn,
+		"DestinationIn":   glblend.NewDestinationIn,
+		"SourceOut":       glblend.NewSourceOut,
+		"DestinationOut":  glblend.NewDestinationOut,
+		"SourceAtop":      glblend.NewSourceAtop,
+		"DestinationAtop": glblend.NewDestinationAtop,
+		"Xor":             glblend.NewXor,
+		"Add":             glblend.NewAdd,
+		"Multiply":        glblend.NewMultiply,
+		"Screen":          glblend.NewScreen,
+		"Overlay":         glblend.NewOverlay,
+		"Subtract":        glblend.NewSubtract,
+		"Darken":          glblend.NewDarken,
+		"Lighten":         glblend.NewLighten,
+	}
+	for name, newTool := range constructors {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should panic when context is nil", func(t *testing.T) {
+				assert.Panics(t, func() {
+					_, _ = newTool(nil)
+				})
+			})
+		})
+	}
+}
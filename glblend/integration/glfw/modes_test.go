@@ -0,0 +1,63 @@
+package glfw_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/glblend/internal/blendmodes"
+	"github.com/elgopher/pixiq/glfw"
+	"github.com/elgopher/pixiq/internal/imagetest"
+)
+
+func TestTool_BlendSourceToTarget(t *testing.T) {
+	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+	defer openGL.Destroy()
+	context := openGL.Context()
+
+	for name, mode := range blendmodes.All {
+		t.Run(name, func(t *testing.T) {
+			gpuTool, err := mode.GPUTool(context)
+			require.NoError(t, err)
+			cpuTool := mode.CPUTool()
+
+			t.Run("should give the same results as CPU tool", func(t *testing.T) {
+				tests := map[string]struct {
+					sourceX, sourceY int
+					targetX, targetY int
+				}{
+					"whole images":           {},
+					"source outside image":   {sourceX: -2, sourceY: 3},
+					"target outside image":   {targetX: -3, targetY: -1},
+					"target at bottom right": {targetX: 10, targetY: 12},
+				}
+				for name, test := range tests {
+					t.Run(name, func(t *testing.T) {
+						source := imagetest.NewPatternImage(context, 16, 16, 7)
+						cpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+						gpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+						sourceSelection := source.Selection(test.sourceX, test.sourceY).WithSize(16, 16)
+						// when
+						cpuTool.BlendSourceToTarget(sourceSelection, cpuTarget.Selection(test.targetX, test.targetY))
+						gpuTool.BlendSourceToTarget(sourceSelection, gpuTarget.Selection(test.targetX, test.targetY))
+						// then
+						imagetest.AssertSimilarColors(t, cpuTarget, gpuTarget, 1)
+					})
+				}
+			})
+
+			t.Run("should blend many times with different sizes", func(t *testing.T) {
+				for _, size := range []int{2, 8, 4} {
+					source := imagetest.NewPatternImage(context, size, size, 3)
+					cpuTarget := imagetest.NewPatternImage(context, size, size, 5)
+					gpuTarget := imagetest.NewPatternImage(context, size, size, 5)
+					// when
+					cpuTool.BlendSourceToTarget(source.WholeImageSelection(), cpuTarget.WholeImageSelection())
+					gpuTool.BlendSourceToTarget(source.WholeImageSelection(), gpuTarget.WholeImageSelection())
+					// then
+					imagetest.AssertSimilarColors(t, cpuTarget, gpuTarget, 1)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package glfw_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/blend"
+	"github.com/elgopher/pixiq/glblend"
+	"github.com/elgopher/pixiq/glblend/internal/blendmodes"
+	"github.com/elgopher/pixiq/glfw"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/internal/imagetest"
+)
+
+var modulations = map[string]struct {
+	opacity float64
+	tint    image.Color
+}{
+	"default":               {opacity: 1, tint: image.RGBA(255, 255, 255, 255)},
+	"transparent":           {opacity: 0, tint: image.RGBA(255, 255, 255, 255)},
+	"half opacity":          {opacity: 0.5, tint: image.RGBA(255, 255, 255, 255)},
+	"red tint":              {opacity: 1, tint: image.RGBA(255, 0, 0, 255)},
+	"semitransparent tint":  {opacity: 1, tint: image.NRGBA(100, 200, 50, 128)},
+	"tint and opacity":      {opacity: 0.3, tint: image.RGBA(10, 128, 255, 255)},
+	"transparent tint":      {opacity: 1, tint: image.Transparent},
+	"almost opaque opacity": {opacity: 0.99, tint: image.RGBA(255, 255, 255, 255)},
+}
+
+func TestSourceOver_SetOpacityAndTint(t *testing.T) {
+	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+	defer openGL.Destroy()
+	context := openGL.Context()
+	gpuTool, err := glblend.NewSourceOver(context)
+	require.NoError(t, err)
+	cpuTool := blend.NewSourceOver()
+
+	t.Run("should panic when opacity is outside range", func(t *testing.T) {
+		for _, opacity := range []float64{-0.1, 1.1, math.NaN()} {
+			assert.Panics(t, func() {
+				gpuTool.SetOpacity(opacity)
+			})
+		}
+	})
+
+	for name, modulation := range modulations {
+		t.Run(name, func(t *testing.T) {
+			source := imagetest.NewPatternImage(context, 16, 16, 7)
+			cpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+			gpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+			cpuTool.SetOpacity(modulation.opacity)
+			cpuTool.SetTint(modulation.tint)
+			gpuTool.SetOpacity(modulation.opacity)
+			gpuTool.SetTint(modulation.tint)
+			// when
+			cpuTool.BlendSourceToTarget(source.WholeImageSelection(), cpuTarget.Selection(1, 2))
+			gpuTool.BlendSourceToTarget(source.WholeImageSelection(), gpuTarget.Selection(1, 2))
+			// then
+			imagetest.AssertSimilarColors(t, cpuTarget, gpuTarget, 1)
+		})
+	}
+}
+
+func TestTool_SetOpacityAndTint(t *testing.T) {
+	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+	defer openGL.Destroy()
+	context := openGL.Context()
+
+	for modeName, mode := range blendmodes.All {
+		t.Run(modeName, func(t *testing.T) {
+			gpuTool, err := mode.GPUTool(context)
+			require.NoError(t, err)
+			cpuTool := mode.CPUTool()
+
+			for name, modulation := range modulations {
+				t.Run(name, func(t *testing.T) {
+					source := imagetest.NewPatternImage(context, 16, 16, 7)
+					cpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+					gpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+					cpuTool.SetOpacity(modulation.opacity)
+					cpuTool.SetTint(modulation.tint)
+					gpuTool.SetOpacity(modulation.opacity)
+					gpuTool.SetTint(modulation.tint)
+					// when
+					cpuTool.BlendSourceToTarget(source.WholeImageSelection(), cpuTarget.WholeImageSelection())
+					gpuTool.BlendSourceToTarget(source.WholeImageSelection(), gpuTarget.WholeImageSelection())
+					// then
+					// blend.Tool rounds modulated source colors before blending
+					imagetest.AssertSimilarColors(t, cpuTarget, gpuTarget, 2)
+				})
+			}
+		})
+	}
+}
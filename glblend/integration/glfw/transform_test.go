@@ -0,0 +1,92 @@
+package glfw_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/blend"
+	"github.com/elgopher/pixiq/glblend"
+	"github.com/elgopher/pixiq/glfw"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/internal/imagetest"
+)
+
+var transforms = map[string]blend.Transform{
+	"NoTransform":    blend.NoTransform,
+	"FlipHorizontal": blend.FlipHorizontal,
+	"FlipVertical":   blend.FlipVertical,
+	"Rotate90":       blend.Rotate90,
+	"Rotate180":      blend.Rotate180,
+	"Rotate270":      blend.Rotate270,
+}
+
+type transformableTool interface {
+	SetTransform(blend.Transform)
+	BlendSourceToTarget(source, target image.Selection)
+}
+
+func TestSetTransform(t *testing.T) {
+	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+	defer openGL.Destroy()
+	context := openGL.Context()
+
+	gpuSource, err := glblend.NewSource(context)
+	require.NoError(t, err)
+	gpuSourceOver, err := glblend.NewSourceOver(context)
+	require.NoError(t, err)
+	gpuMultiply, err := glblend.NewMultiply(context)
+	require.NoError(t, err)
+	gpuXor, err := glblend.NewXor(context)
+	require.NoError(t, err)
+
+	tools := map[string]struct {
+		cpuTool, gpuTool transformableTool
+	}{
+		"Source":     {cpuTool: blend.NewSource(), gpuTool: gpuSource},
+		"SourceOver": {cpuTool: blend.NewSourceOver(), gpuTool: gpuSourceOver},
+		"Multiply":   {cpuTool: blend.NewMultiply(), gpuTool: gpuMultiply},
+		"Xor":        {cpuTool: blend.NewXor(), gpuTool: gpuXor},
+	}
+
+	t.Run("should panic when transform is invalid", func(t *testing.T) {
+		for _, transform := range []blend.Transform{-1, blend.Rotate270 + 1} {
+			assert.Panics(t, func() {
+				gpuSourceOver.SetTransform(transform)
+			})
+		}
+	})
+
+	for toolName, tool := range tools {
+		t.Run(toolName, func(t *testing.T) {
+			for transformName, transform := range transforms {
+				t.Run(transformName, func(t *testing.T) {
+					tool.cpuTool.SetTransform(transform)
+					tool.gpuTool.SetTransform(transform)
+					tests := map[string]struct {
+						targetX, targetY int
+					}{
+						"target inside image":      {targetX: 2, targetY: 1},
+						"target outside top left":  {targetX: -2, targetY: -1},
+						"target outside bottom":    {targetX: 3, targetY: 6},
+						"target outside right":     {targetX: 7, targetY: 0},
+						"target at top left image": {},
+					}
+					for name, test := range tests {
+						t.Run(name, func(t *testing.T) {
+							source := imagetest.NewPatternImage(context, 5, 3, 7)
+							cpuTarget := imagetest.NewPatternImage(context, 9, 8, 13)
+							gpuTarget := imagetest.NewPatternImage(context, 9, 8, 13)
+							// when
+							tool.cpuTool.BlendSourceToTarget(source.WholeImageSelection(), cpuTarget.Selection(test.targetX, test.targetY))
+							tool.gpuTool.BlendSourceToTarget(source.WholeImageSelection(), gpuTarget.Selection(test.targetX, test.targetY))
+							// then
+							imagetest.AssertSimilarColors(t, cpuTarget, gpuTarget, 1)
+						})
+					}
+				})
+			}
+		})
+	}
+}
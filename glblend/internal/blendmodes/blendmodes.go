@@ -0,0 +1,36 @@
+// Package blendmodes lists blend modes available both in blend and glblend
+// packages. It is used by tests comparing GPU tools with their CPU twins.
+package blendmodes
+
+import (
+	"github.com/elgopher/pixiq/blend"
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/glblend"
+)
+
+// Mode creates CPU and GPU tools for the same blend mode.
+type Mode struct {
+	CPUTool func() *blend.Tool
+	GPUTool func(context *gl.Context) (*glblend.Tool, error)
+}
+
+// All contains all modes implemented by blend.Tool and glblend.Tool, by name.
+var All = map[string]Mode{
+	"Clear":           {CPUTool: blend.NewClear, GPUTool: glblend.NewClear},
+	"Destination":     {CPUTool: blend.NewDestination, GPUTool: glblend.NewDestination},
+	"DestinationOver": {CPUTool: blend.NewDestinationOver, GPUTool: glblend.NewDestinationOver},
+	"SourceIn":        {CPUTool: blend.NewSourceIn, GPUTool: glblend.NewSourceIn},
+	"DestinationIn":   {CPUTool: blend.NewDestinationIn, GPUTool: glblend.NewDestinationIn},
+	"SourceOut":       {CPUTool: blend.NewSourceOut, GPUTool: glblend.NewSourceOut},
+	"DestinationOut":  {CPUTool: blend.NewDestinationOut, GPUTool: glblend.NewDestinationOut},
+	"SourceAtop":      {CPUTool: blend.NewSourceAtop, GPUTool: glblend.NewSourceAtop},
+	"DestinationAtop": {CPUTool: blend.NewDestinationAtop, GPUTool: glblend.NewDestinationAtop},
+	"Xor":             {CPUTool: blend.NewXor, GPUTool: glblend.NewXor},
+	"Add":             {CPUTool: blend.NewAdd, GPUTool: glblend.NewAdd},
+	"Multiply":        {CPUTool: blend.NewMultiply, GPUTool: glblend.NewMultiply},
+	"Screen":          {CPUTool: blend.NewScreen, GPUTool: glblend.NewScreen},
+	"Overlay":         {CPUTool: blend.NewOverlay, GPUTool: glblend.NewOverlay},
+	"Subtract":        {CPUTool: blend.NewSubtract, GPUTool: glblend.NewSubtract},
+	"Darken":          {CPUTool: blend.NewDarken, GPUTool: glblend.NewDarken},
+	"Lighten":         {CPUTool: blend.NewLighten, GPUTool: glblend.NewLighten},
+}
@@ -0,0 +1,82 @@
+package glblend
+
+import (
+	"math"
+
+	"github.com/elgopher/pixiq/blend"
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/image"
+)
+
+// options are shared by the tool and its accelerated command
+type options struct {
+	// tint and opacity multiply source colors in fragment shader
+	tint      image.Color
+	opacity   float64
+	transform blend.Transform
+}
+
+func newOptions() *options {
+	return &options{
+		tint:    image.RGBA(255, 255, 255, 255),
+		opacity: 1,
+	}
+}
+
+func (o *options) setOpacity(opacity float64) {
+	if opacity < 0 || opacity > 1 || math.IsNaN(opacity) {
+		panic("opacity outside range [0,1]")
+	}
+	o.opacity = opacity
+}
+
+func (o *options) setTint(tint image.Color) {
+	o.tint = tint
+}
+
+func (o *options) setTransform(transform blend.Transform) {
+	if transform < blend.NoTransform || transform > blend.Rotate270 {
+		panic("invalid transform")
+	}
+	o.transform = transform
+}
+
+// setUniforms sets tint uniform with tint components multiplied by opacity
+func (o *options) setUniforms(renderer *gl.Renderer) {
+	r, g, b, a := o.tint.RGBAi()
+	opacity := float32(o.opacity)
+	renderer.SetVec4("tint",
+		float32(r)/255*opacity,
+		float32(g)/255*opacity,
+		float32(b)/255*opacity,
+		float32(a)/255*opacity,
+	)
+}
+
+// transformedCorners contains indexes of source corners (top-left, top-right,
+// bottom-right, bottom-left) placed at consecutive corners of the target
+var transformedCorners = [...][4]int{
+	blend.NoTransform:    {0, 1, 2, 3},
+	blend.FlipHorizontal: {1, 0, 3, 2},
+	blend.FlipVertical:   {3, 2, 1, 0},
+	blend.Rotate90:       {3, 0, 1, 2},
+	blend.Rotate180:      {2, 3, 0, 1},
+	blend.Rotate270:      {1, 2, 3, 0},
+}
+
+// sourceCorners returns st coordinates of source corners placed at target
+// corners: top-left, top-right, bottom-right and bottom-left
+func (o *options) sourceCorners(source image.AcceleratedImageSelection) [4][2]float32 {
+	left, right, top, bottom := textureCoordinates(source)
+	corners := [4][2]float32{
+		{left, top},
+		{right, top},
+		{right, bottom},
+		{left, bottom},
+	}
+	var transformed [4][2]float32
+	for i, corner := range transformedCorners[o.transform] {
+		transformed[i] = corners[corner]
+	}
+	return transformed
+}
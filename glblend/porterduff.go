@@ -0,0 +1,82 @@
+package glblend
+
+import (
+	"github.com/elgopher/pixiq/gl"
+)
+
+// Porter-Duff operators compose source and target using formula:
+//
+//	R = S*Fs + D*Fd
+//
+// where S is a source color component, D is a target (destination) color
+// component and Fs, Fd are factors depending on the source and target alpha.
+// All colors are premultiplied by alpha. Results are the same as for tools
+// in blend package.
+//
+// See https://www.w3.org/TR/compositing-1/#porterduffcompositingoperators
+
+// NewClear creates a new blending tool which clears the target (Fs=0, Fd=0).
+func NewClear(context *gl.Context) (*Tool, error) {
+	return newPorterDuffTool(context, gl.BlendFactors{SrcFactor: gl.Zero, DstFactor: gl.Zero})
+}
+
+// NewDestination creates a new blending tool which preserves the target
+// (Fs=0, Fd=1).
+func NewDestination(context *gl.Context) (*Tool, error) {
+	return newPorterDuffTool(context, gl.BlendFactors{SrcFactor: gl.Zero, DstFactor: gl.One})
+}
+
+// NewDestinationOver creates a new blending tool which paints the target on
+// top of the source (Fs=1-Da, Fd=1).
+func NewDestinationOver(context *gl.Context) (*Tool, error) {
+	return newPorterDuffTool(context, gl.BlendFactors{SrcFactor: gl.OneMinusDstAlpha, DstFactor: gl.One})
+}
+
+// NewSourceIn creates a new blending tool which shows the source only where
+// the target is (Fs=Da, Fd=0). The target is cleared elsewhere.
+func NewSourceIn(context *gl.Context) (*Tool, error) {
+	return newPorterDuffTool(context, gl.BlendFactors{SrcFactor: gl.DstAlpha, DstFactor: gl.Zero})
+}
+
+// NewDestinationIn creates a new blending tool which preserves the target only
+// where the source is (Fs=0, Fd=Sa). It can be used for masking.
+func NewDestinationIn(context *gl.Context) (*Tool, error) {
+	return newPorterDuffTool(context, gl.BlendFactors{SrcFactor: gl.Zero, DstFactor: gl.SrcAlpha})
+}
+
+// NewSourceOut creates a new blending tool which shows the source only where
+// the target is not (Fs=1-Da, Fd=0). The target is cleared elsewhere.
+func NewSourceOut(context *gl.Context) (*Tool, error) {
+	return newPorterDuffTool(context, gl.BlendFactors{SrcFactor: gl.OneMinusDstAlpha, DstFactor: gl.Zero})
+}
+
+// NewDestinationOut creates a new blending tool which preserves the target
+// only where the source is not (Fs=0, Fd=1-Sa). It can be used for erasing.
+func NewDestinationOut(context *gl.Context) (*Tool, error) {
+	return newPorterDuffTool(context, gl.BlendFactors{SrcFactor: gl.Zero, DstFactor: gl.OneMinusSrcAlpha})
+}
+
+// NewSourceAtop creates a new blending tool which paints the source on top
+// of the target, but only where the target is (Fs=Da, Fd=1-Sa).
+func NewSourceAtop(context *gl.Context) (*Tool, error) {
+	return newPorterDuffTool(context, gl.BlendFactors{SrcFactor: gl.DstAlpha, DstFactor: gl.OneMinusSrcAlpha})
+}
+
+// NewDestinationAtop creates a new blending tool which paints the target on
+// top of the source, but only where the source is (Fs=1-Da, Fd=Sa).
+func NewDestinationAtop(context *gl.Context) (*Tool, error) {
+	return newPorterDuffTool(context, gl.BlendFactors{SrcFactor: gl.OneMinusDstAlpha, DstFactor: gl.SrcAlpha})
+}
+
+// NewXor creates a new blending tool which shows the source where the target
+// is not and the target where the source is not (Fs=1-Da, Fd=1-Sa).
+func NewXor(context *gl.Context) (*Tool, error) {
+	return newPorterDuffTool(context, gl.BlendFactors{SrcFactor: gl.OneMinusDstAlpha, DstFactor: gl.OneMinusSrcAlpha})
+}
+
+// NewAdd creates a new blending tool which adds source and target colors
+// (Fs=1, Fd=1), aka Plus or Linear Dodge. Results are clamped. It can be used
+// for lighting.
+func NewAdd(context *gl.Context) (*Tool, error) {
+	return newPorterDuffTool(context, gl.BlendFactors{SrcFactor: gl.One, DstFactor: gl.One})
+}
@@ -0,0 +1,217 @@
+package glblend
+
+import (
+	"fmt"
+
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/image"
+)
+
+// Separable blend modes mix source and target colors using a function applied
+// to each color component separately. The result is painted on top of the
+// target the same way as SourceOver does:
+//
+//	R = S*(1-Da) + D*(1-Sa) + Sa*Da*B(D/Da, S/Sa)
+//	Ra = Sa + Da - Sa*Da
+//
+// where B is a blending function, S is a source color component, D is a target
+// (destination) color component, Sa and Da are source and target alpha.
+// Results are the same as for tools in blend package.
+//
+// Target colors are copied into a temporary image before blending, because
+// fragment shader cannot read pixels of the image it is drawing to.
+//
+// See https://www.w3.org/TR/compositing-1/#blending
+
+// NewMultiply creates a new blending tool which multiplies source and target
+// colors. The result is always darker. It can be used for shadows and lighting
+// (with light map as a source).
+func NewMultiply(context *gl.Context) (*Tool, error) {
+	return newSeparableTool(context, `return d * s;`)
+}
+
+// NewScreen creates a new blending tool which multiplies complements of source
+// and target colors. The result is always lighter. It is the opposite of
+// Multiply.
+func NewScreen(context *gl.Context) (*Tool, error) {
+	return newSeparableTool(context, `return d + s - d * s;`)
+}
+
+// NewOverlay creates a new blending tool which multiplies or screens colors,
+// depending on the target color. Dark target colors get darker, light target
+// colors get lighter.
+func NewOverlay(context *gl.Context) (*Tool, error) {
+	return newSeparableTool(context, `
+		if (d <= 0.5) {
+			return s * 2.0 * d;
+		}
+		float d2 = 2.0 * d - 1.0;
+		return d2 + s - d2 * s;`)
+}
+
+// NewSubtract creates a new blending tool which subtracts source color from
+// the target. Results are clamped.
+func NewSubtract(context *gl.Context) (*Tool, error) {
+	return newSeparableTool(context, `return max(0.0, d - s);`)
+}
+
+// NewDarken creates a new blending tool which selects darker of source and
+// target color components.
+func NewDarken(context *gl.Context) (*Tool, error) {
+	return newSeparableTool(context, `return min(d, s);`)
+}
+
+// NewLighten creates a new blending tool which selects lighter of source and
+// target color components.
+func NewLighten(context *gl.Context) (*Tool, error) {
+	return newSeparableTool(context, `return max(d, s);`)
+}
+
+const separableVertexShaderSrc = `
+#version 330 core
+	
+layout(location = 0) in vec2 xy;
+layout(location = 1) in vec2 st;
+layout(location = 2) in vec2 targetST;
+out vec2 interpolatedST;
+out vec2 interpolatedTargetST;
+
+void main() {
+	gl_Position = vec4(xy, 0.0, 1.0);
+	interpolatedST = st;
+	interpolatedTargetST = targetST;
+}
+`
+
+const separableFragmentShaderSrc = `
+#version 330 core
+
+uniform sampler2D tex;
+uniform sampler2D target;
+uniform vec4 tint;
+in vec2 interpolatedST;
+in vec2 interpolatedTargetST;
+out vec4 color;
+
+// d and s are not premultiplied target and source color components
+float blendFunction(float d, float s) {
+	%s
+}
+
+void main() {
+	vec4 s = texture(tex, interpolatedST) * tint;
+	vec4 d = texture(target, interpolatedTargetST);
+	vec3 blended = vec3(0.0);
+	if (s.a > 0.0 && d.a > 0.0) {
+		vec3 ds = d.rgb / d.a;
+		vec3 ss = s.rgb / s.a;
+		blended = s.a * d.a * vec3(
+			blendFunction(ds.r, ss.r),
+			blendFunction(ds.g, ss.g),
+			blendFunction(ds.b, ss.b));
+	}
+	color = vec4(
+		s.rgb * (1.0 - d.a) + d.rgb * (1.0 - s.a) + blended,
+		s.a + d.a - s.a * d.a);
+}
+`
+
+func newSeparableTool(context *gl.Context, blendFunctionSrc string) (*Tool, error) {
+	if context == nil {
+		panic("nil context")
+	}
+	vertexShader, err := context.CompileVertexShader(separableVertexShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	fragmentShaderSrc := fmt.Sprintf(separableFragmentShaderSrc, blendFunctionSrc)
+	fragmentShader, err := context.CompileFragmentShader(fragmentShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	program, err := context.LinkProgram(vertexShader, fragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	copyCommand, err := newBlendCommand(context, gl.SourceBlendFactors, newOptions())
+	if err != nil {
+		return nil, err
+	}
+	vertexBuffer := context.NewFloatVertexBuffer(24, gl.DynamicDraw)
+	vertexArray := context.NewVertexArray(gl.VertexLayout{gl.Vec2, gl.Vec2, gl.Vec2})
+	vertexArray.Set(0, gl.VertexBufferPointer{Offset: 0, Stride: 6, Buffer: vertexBuffer})
+	vertexArray.Set(1, gl.VertexBufferPointer{Offset: 2, Stride: 6, Buffer: vertexBuffer})
+	vertexArray.Set(2, gl.VertexBufferPointer{Offset: 4, Stride: 6, Buffer: vertexBuffer})
+	options := newOptions()
+	command := program.AcceleratedCommand(
+		&separableCommand{
+			vertexBuffer: vertexBuffer,
+			vertexArray:  vertexArray,
+			options:      options,
+		})
+	return &Tool{
+		command: command,
+		options: options,
+		targetCopy: &targetCopy{
+			context: context,
+			command: copyCommand,
+		},
+	}, nil
+}
+
+type separableCommand struct {
+	vertexBuffer *gl.FloatVertexBuffer
+	vertexArray  *gl.VertexArray
+	options      *options
+}
+
+func (c *separableCommand) RunGL(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+	source := selections[0]
+	target := selections[1]
+	renderer.BindTexture(0, "tex", source.Image)
+	renderer.BindTexture(1, "target", target.Image)
+	c.options.setUniforms(renderer)
+	st := c.options.sourceCorners(source)
+	targetLeft, targetRight, targetTop, targetBottom := textureCoordinates(target)
+	// xy -> st -> targetST
+	vertices := []float32{
+		-1, 1, st[0][0], st[0][1], targetLeft, targetTop,
+		1, 1, st[1][0], st[1][1], targetRight, targetTop,
+		1, -1, st[2][0], st[2][1], targetRight, targetBottom,
+		-1, -1, st[3][0], st[3][1], targetLeft, targetBottom,
+	}
+	c.vertexBuffer.Upload(0, vertices)
+	renderer.DrawArrays(c.vertexArray, gl.TriangleFan, 0, 4)
+}
+
+// targetCopy copies target pixels into a temporary image, which is reused
+// between calls and grows when needed.
+type targetCopy struct {
+	context *gl.Context
+	command *gl.AcceleratedCommand
+	image   *image.Image
+}
+
+// copy returns selection of the temporary image with pixels copied from
+// the target
+func (c *targetCopy) copy(target image.Selection) image.Selection {
+	width, height := target.Width(), target.Height()
+	if c.image == nil || c.image.Width() < width || c.image.Height() < height {
+		if c.image != nil {
+			width = max(width, c.image.Width())
+			height = max(height, c.image.Height())
+			c.image.Delete()
+		}
+		c.image = image.New(c.context.NewAcceleratedImage(width, height))
+	}
+	selection := c.image.Selection(0, 0).WithSize(target.Width(), target.Height())
+	selection.Modify(c.command, target)
+	return selection
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
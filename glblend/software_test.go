@@ -0,0 +1,106 @@
+package glblend_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/blend"
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/gl/software"
+	"github.com/elgopher/pixiq/glblend"
+	"github.com/elgopher/pixiq/glblend/internal/blendmodes"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/internal/imagetest"
+)
+
+func TestSource_BlendSourceToTarget(t *testing.T) {
+	context := gl.NewContext(software.NewAPI())
+	tool, err := glblend.NewSource(context)
+	require.NoError(t, err)
+
+	t.Run("should give the same results as blend.Source", func(t *testing.T) {
+		source := imagetest.NewPatternImage(context, 16, 16, 7)
+		cpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+		gpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+		// when
+		blend.NewSource().BlendSourceToTarget(source.Selection(2, 1), cpuTarget.Selection(-1, 3))
+		tool.BlendSourceToTarget(source.Selection(2, 1), gpuTarget.Selection(-1, 3))
+		// then
+		imagetest.AssertSimilarColors(t, cpuTarget, gpuTarget, 0)
+	})
+}
+
+func TestSourceOver_BlendSourceToTarget(t *testing.T) {
+	context := gl.NewContext(software.NewAPI())
+	tool, err := glblend.NewSourceOver(context)
+	require.NoError(t, err)
+
+	t.Run("should give the same results as blend.SourceOver", func(t *testing.T) {
+		source := imagetest.NewPatternImage(context, 16, 16, 7)
+		cpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+		gpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+		// when
+		blend.NewSourceOver().BlendSourceToTarget(source.Selection(2, 1), cpuTarget.Selection(-1, 3))
+		tool.BlendSourceToTarget(source.Selection(2, 1), gpuTarget.Selection(-1, 3))
+		// then
+		imagetest.AssertSimilarColors(t, cpuTarget, gpuTarget, 1)
+	})
+}
+
+func TestTool_BlendSourceToTarget(t *testing.T) {
+	context := gl.NewContext(software.NewAPI())
+
+	for name, mode := range blendmodes.All {
+		t.Run(name, func(t *testing.T) {
+			gpuTool, err := mode.GPUTool(context)
+			require.NoError(t, err)
+			cpuTool := mode.CPUTool()
+
+			t.Run("should give the same results as CPU tool", func(t *testing.T) {
+				tests := map[string]struct {
+					sourceX, sourceY int
+					targetX, targetY int
+				}{
+					"whole images":           {},
+					"source outside image":   {sourceX: -2, sourceY: 3},
+					"target outside image":   {targetX: -3, targetY: -1},
+					"target at bottom right": {targetX: 10, targetY: 12},
+				}
+				for name, test := range tests {
+					t.Run(name, func(t *testing.T) {
+						source := imagetest.NewPatternImage(context, 16, 16, 7)
+						cpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+						gpuTarget := imagetest.NewPatternImage(context, 16, 16, 13)
+						sourceSelection := source.Selection(test.sourceX, test.sourceY).WithSize(16, 16)
+						// when
+						cpuTool.BlendSourceToTarget(sourceSelection, cpuTarget.Selection(test.targetX, test.targetY))
+						gpuTool.BlendSourceToTarget(sourceSelection, gpuTarget.Selection(test.targetX, test.targetY))
+						// then
+						imagetest.AssertSimilarColors(t, cpuTarget, gpuTarget, 1)
+					})
+				}
+			})
+
+			t.Run("should give the same results as CPU tool with opacity, tint and transform", func(t *testing.T) {
+				cpuTool := mode.CPUTool()
+				cpuTool.SetOpacity(0.6)
+				cpuTool.SetTint(image.RGBA(200, 100, 50, 255))
+				cpuTool.SetTransform(blend.Rotate90)
+				gpuTool, err := mode.GPUTool(context)
+				require.NoError(t, err)
+				gpuTool.SetOpacity(0.6)
+				gpuTool.SetTint(image.RGBA(200, 100, 50, 255))
+				gpuTool.SetTransform(blend.Rotate90)
+				source := imagetest.NewPatternImage(context, 5, 3, 7)
+				cpuTarget := imagetest.NewPatternImage(context, 8, 8, 13)
+				gpuTarget := imagetest.NewPatternImage(context, 8, 8, 13)
+				// when
+				cpuTool.BlendSourceToTarget(source.WholeImageSelection(), cpuTarget.Selection(1, 2))
+				gpuTool.BlendSourceToTarget(source.WholeImageSelection(), gpuTarget.Selection(1, 2))
+				// then
+				imagetest.AssertSimilarColors(t, cpuTarget, gpuTarget, 1)
+			})
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Package gldither provides dithering tools using video card
+package gldither
+
+import (
+	"github.com/elgopher/pixiq/dither"
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/internal/clip"
+	"github.com/elgopher/pixiq/internal/glquad"
+	"github.com/elgopher/pixiq/palette"
+)
+
+// NewOrdered creates a new ordered dithering tool using Bayer matrix with given
+// size. It is a GPU version of dither.Ordered.
+//
+// Will panic when context is nil, palette is empty or has more colors than
+// MAX_TEXTURE_SIZE, or matrixSize is not 2, 4 or 8.
+func NewOrdered(context *gl.Context, pal palette.Palette, matrixSize int) (*Ordered, error) {
+	if context == nil {
+		panic("nil context")
+	}
+	if len(pal) == 0 {
+		panic("empty palette")
+	}
+	dither.Bayer(matrixSize) // validates matrix size
+	vertexShader, err := context.CompileVertexShader(glquad.VertexShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	fragmentShader, err := context.CompileFragmentShader(fragmentShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	program, err := context.LinkProgram(vertexShader, fragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	paletteImage := context.NewAcceleratedImage(len(pal), 1)
+	paletteImage.Upload(pal)
+	vertexBuffer := context.NewFloatVertexBuffer(16, gl.DynamicDraw)
+	vertexArray := glquad.NewVertexArray(context, vertexBuffer)
+	command := &orderedCommand{
+		vertexBuffer: vertexBuffer,
+		vertexArray:  vertexArray,
+		palette:      paletteImage,
+		paletteSize:  len(pal),
+		matrixSize:   matrixSize,
+		spread:       dither.DefaultSpread,
+	}
+	return &Ordered{
+		command:        program.AcceleratedCommand(command),
+		orderedCommand: command,
+	}, nil
+}
+
+const fragmentShaderSrc = `
+#version 330 core
+
+uniform sampler2D tex;
+uniform sampler2D palette;
+uniform int paletteSize;
+uniform int matrixSize;
+uniform float spread;
+// position of the top-left corner of source selection in texels
+uniform ivec2 sourceOrigin;
+in vec2 interpolatedST;
+out vec4 color;
+
+// bayer calculates the value of recursively defined Bayer matrix
+int bayer(ivec2 position) {
+	int value = 0;
+	int multiplier = 1;
+	for (int n = matrixSize / 2; n >= 1; n /= 2) {
+		int bx = (position.x / n) % 2;
+		int by = (position.y / n) % 2;
+		value += multiplier * (2 * (bx ^ by) + by);
+		multiplier *= 4;
+	}
+	return value;
+}
+
+void main() {
+	// color components are converted to integers in range 0-255, so results
+	// are exactly the same as in dither package. GLSL round() may round halfway
+	// cases to even, therefore floor(v + 0.5) is used instead. It gives the same
+	// results as math.Round for non-negative values.
+	vec4 sourceColor = floor(texture(tex, interpolatedST) * 255.0 + 0.5);
+	ivec2 texel = ivec2(floor(interpolatedST * vec2(textureSize(tex, 0))));
+	// texture rows are stored bottom-up
+	ivec2 position = ivec2(texel.x - sourceOrigin.x, sourceOrigin.y - texel.y);
+	position = ivec2(position.x % matrixSize, position.y % matrixSize);
+	float threshold = (float(bayer(position)) + 0.5) / float(matrixSize * matrixSize) - 0.5;
+	// offset is premultiplied by alpha
+	float offset = spread * threshold * sourceColor.a / 255.0;
+	vec4 adjusted = clamp(floor(sourceColor + vec4(offset, offset, offset, 0.0) + 0.5), 0.0, 255.0);
+	float nearestDistance = 5.0 * 255.0 * 255.0;
+	for (int i = 0; i < paletteSize; i++) {
+		vec4 paletteColor = texelFetch(palette, ivec2(i, 0), 0);
+		vec4 diff = floor(paletteColor * 255.0 + 0.5) - adjusted;
+		float distance = dot(diff, diff);
+		if (distance < nearestDistance) {
+			nearestDistance = distance;
+			color = paletteColor;
+		}
+	}
+}
+`
+
+type orderedCommand struct {
+	vertexBuffer *gl.FloatVertexBuffer
+	vertexArray  *gl.VertexArray
+	palette      *gl.AcceleratedImage
+	paletteSize  int
+	matrixSize   int
+	spread       int
+}
+
+func (c *orderedCommand) RunGL(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+	source := selections[0]
+	renderer.BindTexture(0, "tex", source.Image)
+	renderer.BindTexture(1, "palette", c.palette)
+	renderer.SetInt("paletteSize", int32(c.paletteSize))
+	renderer.SetInt("matrixSize", int32(c.matrixSize))
+	renderer.SetFloat("spread", float32(c.spread))
+	renderer.SetIVec2("sourceOrigin",
+		int32(source.Location.X),
+		int32(source.Image.Height()-1-source.Location.Y))
+	var (
+		imageWidth  = float32(source.Image.Width())
+		left        = float32(source.Location.X) / imageWidth
+		right       = float32(source.Location.X+source.Location.Width) / imageWidth
+		imageHeight = float32(source.Image.Height())
+		top         = (imageHeight - float32(source.Location.Y)) / imageHeight
+		bottom      = (imageHeight - float32(source.Location.Y) - float32(source.Location.Height)) / imageHeight
+	)
+	// xy -> st
+	vertices := []float32{
+		-1, 1, left, top,
+		1, 1, right, top,
+		1, -1, right, bottom,
+		-1, -1, left, bottom,
+	}
+	c.vertexBuffer.Upload(0, vertices)
+	renderer.DrawArrays(c.vertexArray, gl.TriangleFan, 0, 4)
+}
+
+// Ordered is a tool for ordered dithering (aka Bayer dithering) using video
+// card.
+type Ordered struct {
+	command        *gl.AcceleratedCommand
+	orderedCommand *orderedCommand
+}
+
+// SetSpread sets how much colors are changed by the threshold matrix before
+// the nearest palette color is found. See dither.Ordered.SetSpread.
+// Will panic when spread is negative.
+func (o *Ordered) SetSpread(spread int) {
+	if spread < 0 {
+		panic("negative spread")
+	}
+	o.orderedCommand.spread = spread
+}
+
+// DitherSourceToTarget replaces source colors with palette colors and puts
+// the result into the target selection. Pattern is aligned to the top-left
+// corner of the source selection.
+//
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+func (o *Ordered) DitherSourceToTarget(source, target image.Selection) {
+	source = clip.SourceToTargetImage(source, target)
+	target = target.WithSize(source.Width(), source.Height())
+	target.Modify(o.command, source)
+}
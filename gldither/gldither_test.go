@@ -0,0 +1,19 @@
+package gldither_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/gldither"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/palette"
+)
+
+func TestNewOrdered(t *testing.T) {
+	t.Run("should panic when context is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = gldither.NewOrdered(nil, palette.Palette{image.Transparent}, 2)
+		})
+	})
+}
@@ -0,0 +1,193 @@
+package glfw_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/dither"
+	"github.com/elgopher/pixiq/gldither"
+	"github.com/elgopher/pixiq/glfw"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/internal/imagetest"
+	"github.com/elgopher/pixiq/palette"
+)
+
+var mainThreadLoop *glfw.MainThreadLoop
+
+func TestMain(m *testing.M) {
+	var exit int
+	glfw.StartMainThreadLoop(func(main *glfw.MainThreadLoop) {
+		mainThreadLoop = main
+		exit = m.Run()
+	})
+	os.Exit(exit)
+}
+
+var (
+	black      = image.RGB(0, 0, 0)
+	white      = image.RGB(255, 255, 255)
+	gray       = image.RGB(128, 128, 128)
+	blackWhite = palette.Palette{black, white}
+)
+
+func TestNewOrdered(t *testing.T) {
+	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+	defer openGL.Destroy()
+	context := openGL.Context()
+
+	t.Run("should panic", func(t *testing.T) {
+		tests := map[string]struct {
+			palette    palette.Palette
+			matrixSize int
+		}{
+			"empty palette":       {matrixSize: 2},
+			"invalid matrix size": {palette: blackWhite, matrixSize: 3},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				assert.Panics(t, func() {
+					_, _ = gldither.NewOrdered(context, test.palette, test.matrixSize)
+				})
+			})
+		}
+	})
+
+	t.Run("should return ordered dithering tool", func(t *testing.T) {
+		// when
+		tool, err := gldither.NewOrdered(context, blackWhite, 4)
+		// then
+		assert.NotNil(t, tool)
+		assert.NoError(t, err)
+	})
+}
+
+func TestOrdered_SetSpread(t *testing.T) {
+	t.Run("should panic for negative spread", func(t *testing.T) {
+		openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+		defer openGL.Destroy()
+		tool, err := gldither.NewOrdered(openGL.Context(), blackWhite, 2)
+		require.NoError(t, err)
+		assert.Panics(t, func() {
+			tool.SetSpread(-1)
+		})
+	})
+}
+
+func TestOrdered_DitherSourceToTarget(t *testing.T) {
+	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+	defer openGL.Destroy()
+	context := openGL.Context()
+
+	t.Run("should dither gray color", func(t *testing.T) {
+		tool, err := gldither.NewOrdered(context, blackWhite, 2)
+		require.NoError(t, err)
+		source := newFilledImage(openGL, 4, 2, gray)
+		target := openGL.NewImage(4, 2)
+		// when
+		tool.DitherSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+		// then
+		assertColors(t, target, [][]image.Color{
+			{black, white, black, white},
+			{white, black, white, black},
+		})
+	})
+
+	t.Run("should align pattern to source selection", func(t *testing.T) {
+		tool, err := gldither.NewOrdered(context, blackWhite, 2)
+		require.NoError(t, err)
+		source := newFilledImage(openGL, 3, 3, gray)
+		target := newFilledImage(openGL, 3, 2, gray)
+		// when
+		tool.DitherSourceToTarget(source.Selection(1, 1).WithSize(2, 2), target.Selection(1, 0))
+		// then
+		assertColors(t, target, [][]image.Color{
+			{gray, black, white},
+			{gray, white, black},
+		})
+	})
+
+	t.Run("should give the same results as dither.Ordered", func(t *testing.T) {
+		pal := palette.Palette{
+			black, white, image.RGB(255, 0, 0), image.RGB(0, 255, 0), image.RGB(0, 0, 255),
+		}
+		for _, matrixSize := range []int{2, 4, 8} {
+			tool, err := gldither.NewOrdered(context, pal, matrixSize)
+			require.NoError(t, err)
+			source := imagetest.NewGradientImage(context, 32, 32)
+			target := openGL.NewImage(32, 32)
+			expected := imagetest.NewGradientImage(context, 32, 32)
+			dither.NewOrdered(pal, matrixSize).Dither(expected.WholeImageSelection())
+			// when
+			tool.DitherSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+			// then
+			imagetest.AssertEqualImages(t, expected, target)
+		}
+	})
+
+	t.Run("should round halfway cases the same way as dither.Ordered", func(t *testing.T) {
+		// with default spread and 8x8 matrix every offset is a halfway case
+		// and the palette with all grays shows how adjusted colors are rounded
+		pal := make(palette.Palette, 256)
+		for i := range pal {
+			pal[i] = image.RGB(uint8(i), uint8(i), uint8(i))
+		}
+		tool, err := gldither.NewOrdered(context, pal, 8)
+		require.NoError(t, err)
+		source := newGrayRampImage(openGL, 32, 8)
+		target := openGL.NewImage(32, 8)
+		expected := newGrayRampImage(openGL, 32, 8)
+		cpuTool := dither.NewOrdered(pal, 8)
+		cpuTool.SetSpread(dither.DefaultSpread)
+		cpuTool.Dither(expected.WholeImageSelection())
+		// when
+		tool.DitherSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+		// then
+		expectedSelection := expected.WholeImageSelection()
+		targetSelection := target.WholeImageSelection()
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 32; x++ {
+				assert.Equal(t, expectedSelection.Color(x, y), targetSelection.Color(x, y),
+					"position (%d,%d)", x, y)
+			}
+		}
+	})
+}
+
+func assertColors(t *testing.T, img *image.Image, expectedColorLines [][]image.Color) {
+	selection := img.WholeImageSelection()
+	for y := 0; y < selection.Height(); y++ {
+		expectedColorLine := expectedColorLines[y]
+		for x := 0; x < selection.Width(); x++ {
+			color := selection.Color(x, y)
+			assert.Equal(t, expectedColorLine[x], color, "position (%d,%d)", x, y)
+		}
+	}
+}
+
+func newFilledImage(gl *glfw.OpenGL, width, height int, color image.Color) *image.Image {
+	img := gl.NewImage(width, height)
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, color)
+		}
+	}
+	return img
+}
+
+// newGrayRampImage creates image with all 256 shades of gray when
+// width*height is 256
+func newGrayRampImage(gl *glfw.OpenGL, width, height int) *image.Image {
+	img := gl.NewImage(width, height)
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8(y*width + x)
+			selection.SetColor(x, y, image.RGB(v, v, v))
+		}
+	}
+	return img
+}
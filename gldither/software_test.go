@@ -0,0 +1,38 @@
+package gldither_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/dither"
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/gl/software"
+	"github.com/elgopher/pixiq/gldither"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/internal/imagetest"
+	"github.com/elgopher/pixiq/palette"
+)
+
+func TestOrdered_DitherSourceToTarget(t *testing.T) {
+	context := gl.NewContext(software.NewAPI())
+
+	t.Run("should give the same results as dither.Ordered", func(t *testing.T) {
+		pal := palette.Palette{
+			image.RGB(0, 0, 0), image.RGB(255, 255, 255),
+			image.RGB(255, 0, 0), image.RGB(0, 255, 0), image.RGB(0, 0, 255),
+		}
+		for _, matrixSize := range []int{2, 4, 8} {
+			tool, err := gldither.NewOrdered(context, pal, matrixSize)
+			require.NoError(t, err)
+			source := imagetest.NewGradientImage(context, 32, 32)
+			target := image.New(context.NewAcceleratedImage(32, 32))
+			expected := imagetest.NewGradientImage(context, 32, 32)
+			dither.NewOrdered(pal, matrixSize).Dither(expected.WholeImageSelection())
+			// when
+			tool.DitherSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+			// then
+			imagetest.AssertEqualImages(t, expected, target)
+		}
+	})
+}
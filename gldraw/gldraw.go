@@ -0,0 +1,389 @@
+// Package gldraw provides GPU tools for drawing primitives such as lines,
+// rectangles and triangles.
+//
+// Drawn pixels are exactly the same as the ones drawn by the CPU draw.Tool.
+// Lines are not rasterized using GL_LINES, because rasterization rules for lines
+// differ between drivers. Instead, each line is drawn as a thin quad and
+// the fragment shader decides which pixels belong to the line.
+package gldraw
+
+import (
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/image"
+)
+
+// New returns a new instance of *gldraw.Tool. By default the tool draws with
+// transparent color and lines are 1 pixel thick.
+func New(context *gl.Context) (*Tool, error) {
+	if context == nil {
+		panic("nil context")
+	}
+	vertexShader, err := context.CompileVertexShader(vertexShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	fragmentShader, err := context.CompileFragmentShader(fragmentShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	program, err := context.LinkProgram(vertexShader, fragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	vertexBuffer := context.NewFloatVertexBuffer(initialBufferSize, gl.DynamicDraw)
+	command := &drawCommand{
+		vertexBuffer: vertexBuffer,
+		vertexArray:  makeVertexArray(context, vertexBuffer),
+	}
+	return &Tool{
+		context:     context,
+		command:     program.AcceleratedCommand(command),
+		drawCommand: command,
+		thickness:   1,
+	}, nil
+}
+
+const (
+	// x, y, lineX1, lineY1, lineX2, lineY2
+	floatsPerVertex   = 6
+	verticesPerQuad   = 6
+	initialBufferSize = 64 * verticesPerQuad * floatsPerVertex
+)
+
+const vertexShaderSrc = `
+#version 330 core
+
+layout(location = 0) in vec2 xy;
+layout(location = 1) in vec4 line;
+uniform vec2 size;
+out vec2 pixel;
+flat out ivec4 lineEnds;
+
+void main() {
+	gl_Position = vec4(2*xy.x/size.x - 1, 1 - 2*xy.y/size.y, 0.0, 1.0);
+	pixel = xy;
+	lineEnds = ivec4(round(line));
+}
+`
+
+const fragmentShaderSrc = `
+#version 330 core
+
+uniform vec4 color;
+// 0 means that primitive is filled
+uniform int thickness;
+in vec2 pixel;
+flat in ivec4 lineEnds;
+out vec4 outputColor;
+
+// minorOffset returns step*minor/major rounded half away from zero
+int minorOffset(int step, int major, int minor) {
+	if (major == 0) {
+		return 0;
+	}
+	int offset = (2*step*abs(minor) + major) / (2*major);
+	return minor < 0 ? -offset : offset;
+}
+
+// onLine returns true when pixel p is drawn by draw.Tool.Line with the same
+// thickness. Start of the line must be before the end on the major axis.
+bool onLine(ivec2 p) {
+	ivec2 start = lineEnds.xy;
+	ivec2 end = lineEnds.zw;
+	ivec2 delta = end - start;
+	if (abs(delta.y) > abs(delta.x)) {
+		start = start.yx;
+		end = end.yx;
+		delta = delta.yx;
+		p = p.yx;
+	}
+	int before = (thickness - 1) / 2;
+	int after = thickness - 1 - before;
+	// line pixels which brush covers p
+	int first = max(p.x - after, start.x);
+	int last = min(p.x + before, end.x);
+	if (first > last) {
+		return false;
+	}
+	int minor1 = start.y + minorOffset(first - start.x, delta.x, delta.y);
+	int minor2 = start.y + minorOffset(last - start.x, delta.x, delta.y);
+	return p.y >= min(minor1, minor2) - before && p.y <= max(minor1, minor2) + after;
+}
+
+void main() {
+	if (thickness > 0 && !onLine(ivec2(floor(pixel)))) {
+		discard;
+	}
+	outputColor = color;
+}
+`
+
+func makeVertexArray(context *gl.Context, buffer *gl.FloatVertexBuffer) *gl.VertexArray {
+	array := context.NewVertexArray(gl.VertexLayout{gl.Vec2, gl.Vec4})
+	setVertexBuffer(array, buffer)
+	return array
+}
+
+func setVertexBuffer(array *gl.VertexArray, buffer *gl.FloatVertexBuffer) {
+	xy := gl.VertexBufferPointer{Offset: 0, Stride: floatsPerVertex, Buffer: buffer}
+	array.Set(0, xy)
+	line := gl.VertexBufferPointer{Offset: 2, Stride: floatsPerVertex, Buffer: buffer}
+	array.Set(1, line)
+}
+
+// Tool is a drawing tool. It draws primitives into the image.Selection using
+// previously set color. Drawn pixels replace the original ones - there is no
+// blending and no anti-aliasing.
+//
+// Tool uses GPU through use of *gl.AcceleratedCommand.
+type Tool struct {
+	context     *gl.Context
+	command     *gl.AcceleratedCommand
+	drawCommand *drawCommand
+	thickness   int
+}
+
+// Line is a line segment from (X1,Y1) to (X2,Y2).
+type Line struct {
+	X1, Y1, X2, Y2 int
+}
+
+// SetColor sets color which will be used by drawing methods
+func (t *Tool) SetColor(color image.Color) {
+	t.drawCommand.color = color
+}
+
+// SetThickness sets the thickness of lines in pixels. It is used by Line, Lines,
+// Rectangle and Triangle. Thickness lower than 1 is treated as 1.
+func (t *Tool) SetThickness(thickness int) {
+	if thickness < 1 {
+		thickness = 1
+	}
+	t.thickness = thickness
+}
+
+// Line draws a line from (x1,y1) to (x2,y2). Drawn pixels are the same as
+// the ones drawn by draw.Tool.Line.
+func (t *Tool) Line(selection image.Selection, x1, y1, x2, y2 int) {
+	t.drawCommand.appendLine(x1, y1, x2, y2, t.thickness)
+	t.draw(selection, t.thickness)
+}
+
+// Lines draws all lines at once. It is much faster than calling Line
+// for each line separately.
+func (t *Tool) Lines(selection image.Selection, lines []Line) {
+	for _, line := range lines {
+		t.drawCommand.appendLine(line.X1, line.Y1, line.X2, line.Y2, t.thickness)
+	}
+	t.draw(selection, t.thickness)
+}
+
+// Rectangle draws an outline of the rectangle with top-left corner at (x,y).
+// Thick outline is drawn inside the rectangle.
+func (t *Tool) Rectangle(selection image.Selection, x, y, width, height int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	c := t.drawCommand
+	thickness := t.thickness
+	if 2*thickness >= width || 2*thickness >= height {
+		c.appendRectangle(x, y, width, height)
+	} else {
+		c.appendRectangle(x, y, width, thickness)
+		c.appendRectangle(x, y+height-thickness, width, thickness)
+		c.appendRectangle(x, y+thickness, thickness, height-2*thickness)
+		c.appendRectangle(x+width-thickness, y+thickness, thickness, height-2*thickness)
+	}
+	t.draw(selection, thickness)
+}
+
+// FilledRectangle draws a filled rectangle with top-left corner at (x,y).
+func (t *Tool) FilledRectangle(selection image.Selection, x, y, width, height int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	t.drawCommand.appendRectangle(x, y, width, height)
+	t.draw(selection, t.thickness)
+}
+
+// Triangle draws an outline of the triangle.
+func (t *Tool) Triangle(selection image.Selection, x1, y1, x2, y2, x3, y3 int) {
+	c := t.drawCommand
+	c.appendLine(x1, y1, x2, y2, t.thickness)
+	c.appendLine(x2, y2, x3, y3, t.thickness)
+	c.appendLine(x3, y3, x1, y1, t.thickness)
+	t.draw(selection, t.thickness)
+}
+
+// FilledTriangle draws a filled triangle. Drawn pixels are the same as the ones
+// drawn by draw.Tool.FilledPolygon for three points.
+func (t *Tool) FilledTriangle(selection image.Selection, x1, y1, x2, y2, x3, y3 int) {
+	c := t.drawCommand
+	c.appendTriangle(x1, y1, x2, y2, x3, y3)
+	c.appendLine(x1, y1, x2, y2, 1)
+	c.appendLine(x2, y2, x3, y3, 1)
+	c.appendLine(x3, y3, x1, y1, 1)
+	t.draw(selection, 1)
+}
+
+func (t *Tool) draw(selection image.Selection, thickness int) {
+	c := t.drawCommand
+	defer c.reset()
+	selection = clampToImage(selection)
+	if selection.Width() <= 0 || selection.Height() <= 0 {
+		return
+	}
+	size := len(c.fill) + len(c.lines)
+	if size == 0 {
+		return
+	}
+	if size > c.vertexBuffer.Size() {
+		newSize := max(size, 2*c.vertexBuffer.Size())
+		c.vertexBuffer.Delete()
+		c.vertexBuffer = t.context.NewFloatVertexBuffer(newSize, gl.DynamicDraw)
+		setVertexBuffer(c.vertexArray, c.vertexBuffer)
+	}
+	c.thickness = thickness
+	c.width = selection.Width()
+	c.height = selection.Height()
+	selection.Modify(t.command)
+}
+
+// clampToImage makes sure that selection does not exceed the right and bottom
+// border of the image. Thanks to that the OpenGL viewport has the size
+// of selection.
+func clampToImage(selection image.Selection) image.Selection {
+	width := selection.Width()
+	if selection.ImageX()+width > selection.Image().Width() {
+		width = selection.Image().Width() - selection.ImageX()
+	}
+	height := selection.Height()
+	if selection.ImageY()+height > selection.Image().Height() {
+		height = selection.Image().Height() - selection.ImageY()
+	}
+	return selection.WithSize(width, height)
+}
+
+type drawCommand struct {
+	vertexBuffer *gl.FloatVertexBuffer
+	vertexArray  *gl.VertexArray
+	color        image.Color
+	thickness    int
+	width        int
+	height       int
+	// vertices of filled primitives
+	fill []float32
+	// vertices of quads covering lines
+	lines []float32
+}
+
+func (c *drawCommand) RunGL(renderer *gl.Renderer, _ []image.AcceleratedImageSelection) {
+	r, g, b, a := c.color.RGBAf()
+	renderer.SetVec4("color", r, g, b, a)
+	renderer.SetVec2("size", float32(c.width), float32(c.height))
+	fillVertices := len(c.fill) / floatsPerVertex
+	if fillVertices > 0 {
+		c.vertexBuffer.Upload(0, c.fill)
+		renderer.SetInt("thickness", 0)
+		renderer.DrawArrays(c.vertexArray, gl.Triangles, 0, fillVertices)
+	}
+	lineVertices := len(c.lines) / floatsPerVertex
+	if lineVertices > 0 {
+		c.vertexBuffer.Upload(len(c.fill), c.lines)
+		renderer.SetInt("thickness", int32(c.thickness))
+		renderer.DrawArrays(c.vertexArray, gl.Triangles, fillVertices, lineVertices)
+	}
+}
+
+func (c *drawCommand) reset() {
+	c.fill = c.fill[:0]
+	c.lines = c.lines[:0]
+}
+
+func (c *drawCommand) appendRectangle(x, y, width, height int) {
+	var (
+		left   = float32(x)
+		top    = float32(y)
+		right  = float32(x + width)
+		bottom = float32(y + height)
+	)
+	c.fill = append(c.fill,
+		left, top, 0, 0, 0, 0,
+		right, top, 0, 0, 0, 0,
+		right, bottom, 0, 0, 0, 0,
+		left, top, 0, 0, 0, 0,
+		right, bottom, 0, 0, 0, 0,
+		left, bottom, 0, 0, 0, 0,
+	)
+}
+
+// appendTriangle appends a triangle with vertices in pixel centers.
+func (c *drawCommand) appendTriangle(x1, y1, x2, y2, x3, y3 int) {
+	c.fill = append(c.fill,
+		float32(x1)+0.5, float32(y1)+0.5, 0, 0, 0, 0,
+		float32(x2)+0.5, float32(y2)+0.5, 0, 0, 0, 0,
+		float32(x3)+0.5, float32(y3)+0.5, 0, 0, 0, 0,
+	)
+}
+
+// appendLine appends a parallelogram covering all pixels of the line. Which
+// pixels are really drawn is decided by fragment shader.
+func (c *drawCommand) appendLine(x1, y1, x2, y2, thickness int) {
+	dx, dy := x2-x1, y2-y1
+	yMajor := abs(dy) > abs(dx)
+	// ends are sorted by major axis, the same way as in draw.Tool.Line
+	if (!yMajor && x1 > x2) || (yMajor && y1 > y2) {
+		x1, y1, x2, y2, dx, dy = x2, y2, x1, y1, -dx, -dy
+	}
+	var (
+		// margins are big enough to cover the brush and rounding
+		majorMargin = float32(thickness)
+		minorMargin = float32(2*thickness + 1)
+		major1      = float32(x1)
+		minor1      = float32(y1)
+		major2      = float32(x2)
+		minor2      = float32(y2)
+		slope       = float32(0)
+	)
+	if yMajor {
+		major1, minor1, major2, minor2 = minor1, major1, minor2, major2
+		slope = float32(dx) / float32(dy)
+	} else if dx != 0 {
+		slope = float32(dy) / float32(dx)
+	}
+	var (
+		start      = major1 - majorMargin
+		end        = major2 + 1 + majorMargin
+		startMinor = minor1 + 0.5 + slope*(start-major1-0.5)
+		endMinor   = minor1 + 0.5 + slope*(end-major1-0.5)
+		corners    = [4][2]float32{
+			{start, startMinor - minorMargin},
+			{end, endMinor - minorMargin},
+			{end, endMinor + minorMargin},
+			{start, startMinor + minorMargin},
+		}
+	)
+	if yMajor {
+		for i := range corners {
+			corners[i][0], corners[i][1] = corners[i][1], corners[i][0]
+		}
+	}
+	lx1, ly1, lx2, ly2 := float32(x1), float32(y1), float32(x2), float32(y2)
+	for _, i := range [verticesPerQuad]int{0, 1, 2, 0, 2, 3} {
+		c.lines = append(c.lines, corners[i][0], corners[i][1], lx1, ly1, lx2, ly2)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
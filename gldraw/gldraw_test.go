@@ -0,0 +1,477 @@
+package gldraw_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/gldraw"
+	"github.com/elgopher/pixiq/image"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should panic when context is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = gldraw.New(nil)
+		})
+	})
+	t.Run("should return error when shader cannot be compiled", func(t *testing.T) {
+		api := newFakeAPI()
+		api.compileFails = true
+		// when
+		tool, err := gldraw.New(gl.NewContext(api))
+		// then
+		assert.Error(t, err)
+		assert.Nil(t, tool)
+	})
+	t.Run("should create tool", func(t *testing.T) {
+		tool, err := gldraw.New(gl.NewContext(newFakeAPI()))
+		require.NoError(t, err)
+		assert.NotNil(t, tool)
+	})
+}
+
+var color = image.RGBA(10, 20, 30, 40)
+
+func TestTool_Line(t *testing.T) {
+	t.Run("should draw quad covering line", func(t *testing.T) {
+		tests := map[string]struct {
+			x1, y1, x2, y2   int
+			thickness        int
+			expectedVertices []float32
+		}{
+			"horizontal": {
+				x1: 1, y1: 2, x2: 3, y2: 2, thickness: 1,
+				expectedVertices: lineQuad(
+					[4][2]float32{{0, -0.5}, {5, -0.5}, {5, 5.5}, {0, 5.5}},
+					1, 2, 3, 2),
+			},
+			"reversed horizontal": {
+				x1: 3, y1: 2, x2: 1, y2: 2, thickness: 1,
+				expectedVertices: lineQuad(
+					[4][2]float32{{0, -0.5}, {5, -0.5}, {5, 5.5}, {0, 5.5}},
+					1, 2, 3, 2),
+			},
+			"thick horizontal": {
+				x1: 1, y1: 2, x2: 3, y2: 2, thickness: 2,
+				expectedVertices: lineQuad(
+					[4][2]float32{{-1, -2.5}, {6, -2.5}, {6, 7.5}, {-1, 7.5}},
+					1, 2, 3, 2),
+			},
+			"reversed vertical": {
+				x1: 2, y1: 3, x2: 2, y2: 1, thickness: 1,
+				expectedVertices: lineQuad(
+					[4][2]float32{{-0.5, 0}, {-0.5, 5}, {5.5, 5}, {5.5, 0}},
+					2, 1, 2, 3),
+			},
+			"diagonal": {
+				x1: 0, y1: 0, x2: 2, y2: 2, thickness: 1,
+				expectedVertices: lineQuad(
+					[4][2]float32{{-1, -4}, {4, 1}, {4, 7}, {-1, 2}},
+					0, 0, 2, 2),
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				api := newFakeAPI()
+				tool, img := newTool(t, api, 8, 8)
+				tool.SetThickness(test.thickness)
+				// when
+				tool.Line(img.WholeImageSelection(), test.x1, test.y1, test.x2, test.y2)
+				// then
+				require.Len(t, api.drawCalls, 1)
+				drawCall := api.drawCalls[0]
+				assert.Equal(t, test.expectedVertices, drawCall.vertices)
+				assert.Equal(t, int32(test.thickness), drawCall.thickness)
+			})
+		}
+	})
+	t.Run("should set uniforms", func(t *testing.T) {
+		api := newFakeAPI()
+		tool, img := newTool(t, api, 8, 8)
+		// when
+		tool.Line(img.Selection(1, 2).WithSize(3, 4), 0, 0, 1, 1)
+		// then
+		require.Len(t, api.drawCalls, 1)
+		drawCall := api.drawCalls[0]
+		assert.Equal(t, int32(triangles), drawCall.mode)
+		assert.Equal(t, rgbaf(color), drawCall.color)
+		assert.Equal(t, [2]float32{3, 4}, drawCall.size)
+	})
+	t.Run("should clamp selection to image", func(t *testing.T) {
+		api := newFakeAPI()
+		tool, img := newTool(t, api, 8, 8)
+		// when
+		tool.Line(img.Selection(5, 6).WithSize(10, 10), 0, 0, 1, 1)
+		// then
+		require.Len(t, api.drawCalls, 1)
+		assert.Equal(t, [2]float32{3, 2}, api.drawCalls[0].size)
+	})
+	t.Run("should not draw when selection is outside image", func(t *testing.T) {
+		api := newFakeAPI()
+		tool, img := newTool(t, api, 8, 8)
+		// when
+		tool.Line(img.Selection(8, 0).WithSize(1, 1), 0, 0, 1, 1)
+		// then
+		assert.Empty(t, api.drawCalls)
+	})
+	t.Run("should treat thickness lower than 1 as 1", func(t *testing.T) {
+		api := newFakeAPI()
+		tool, img := newTool(t, api, 8, 8)
+		tool.SetThickness(0)
+		// when
+		tool.Line(img.WholeImageSelection(), 0, 0, 1, 1)
+		// then
+		require.Len(t, api.drawCalls, 1)
+		assert.Equal(t, int32(1), api.drawCalls[0].thickness)
+	})
+}
+
+func TestTool_Lines(t *testing.T) {
+	t.Run("should not draw anything for empty slice", func(t *testing.T) {
+		api := newFakeAPI()
+		tool, img := newTool(t, api, 8, 8)
+		// when
+		tool.Lines(img.WholeImageSelection(), nil)
+		// then
+		assert.Empty(t, api.drawCalls)
+	})
+	t.Run("should draw all lines using one draw call", func(t *testing.T) {
+		for _, count := range []int{1, 2, 1000} {
+			api := newFakeAPI()
+			tool, img := newTool(t, api, 8, 8)
+			lines := make([]gldraw.Line, count)
+			// when
+			tool.Lines(img.WholeImageSelection(), lines)
+			// then
+			require.Len(t, api.drawCalls, 1)
+			assert.Equal(t, int32(0), api.drawCalls[0].first)
+			assert.Equal(t, int32(6*count), api.drawCalls[0].count)
+		}
+	})
+}
+
+func TestTool_FilledRectangle(t *testing.T) {
+	t.Run("should not draw anything for empty rectangle", func(t *testing.T) {
+		api := newFakeAPI()
+		tool, img := newTool(t, api, 8, 8)
+		// when
+		tool.FilledRectangle(img.WholeImageSelection(), 1, 1, 0, 1)
+		// then
+		assert.Empty(t, api.drawCalls)
+	})
+	t.Run("should draw rectangle using pixel edges", func(t *testing.T) {
+		api := newFakeAPI()
+		tool, img := newTool(t, api, 8, 8)
+		// when
+		tool.FilledRectangle(img.WholeImageSelection(), 1, 2, 3, 4)
+		// then
+		require.Len(t, api.drawCalls, 1)
+		drawCall := api.drawCalls[0]
+		assert.Equal(t, int32(0), drawCall.thickness)
+		assert.Equal(t, fillQuad(1, 2, 4, 6), drawCall.vertices)
+	})
+}
+
+func TestTool_Rectangle(t *testing.T) {
+	t.Run("should draw 4 rectangles", func(t *testing.T) {
+		api := newFakeAPI()
+		tool, img := newTool(t, api, 8, 8)
+		// when
+		tool.Rectangle(img.WholeImageSelection(), 1, 2, 3, 4)
+		// then
+		require.Len(t, api.drawCalls, 1)
+		var expected []float32
+		expected = append(expected, fillQuad(1, 2, 4, 3)...)
+		expected = append(expected, fillQuad(1, 5, 4, 6)...)
+		expected = append(expected, fillQuad(1, 3, 2, 5)...)
+		expected = append(expected, fillQuad(3, 3, 4, 5)...)
+		assert.Equal(t, expected, api.drawCalls[0].vertices)
+	})
+	t.Run("should draw one rectangle when outline is too thick", func(t *testing.T) {
+		api := newFakeAPI()
+		tool, img := newTool(t, api, 8, 8)
+		tool.SetThickness(2)
+		// when
+		tool.Rectangle(img.WholeImageSelection(), 1, 2, 3, 4)
+		// then
+		require.Len(t, api.drawCalls, 1)
+		assert.Equal(t, fillQuad(1, 2, 4, 6), api.drawCalls[0].vertices)
+	})
+}
+
+func TestTool_Triangle(t *testing.T) {
+	t.Run("should draw 3 lines", func(t *testing.T) {
+		api := newFakeAPI()
+		tool, img := newTool(t, api, 8, 8)
+		tool.SetThickness(3)
+		// when
+		tool.Triangle(img.WholeImageSelection(), 0, 0, 4, 0, 0, 4)
+		// then
+		require.Len(t, api.drawCalls, 1)
+		drawCall := api.drawCalls[0]
+		assert.Equal(t, int32(18), drawCall.count)
+		assert.Equal(t, int32(3), drawCall.thickness)
+	})
+}
+
+func TestTool_FilledTriangle(t *testing.T) {
+	t.Run("should draw triangle and its outline", func(t *testing.T) {
+		api := newFakeAPI()
+		tool, img := newTool(t, api, 8, 8)
+		tool.SetThickness(3)
+		// when
+		tool.FilledTriangle(img.WholeImageSelection(), 0, 0, 4, 0, 0, 4)
+		// then
+		require.Len(t, api.drawCalls, 2)
+		fill := api.drawCalls[0]
+		assert.Equal(t, int32(0), fill.thickness)
+		assert.Equal(t, []float32{
+			0.5, 0.5, 0, 0, 0, 0,
+			4.5, 0.5, 0, 0, 0, 0,
+			0.5, 4.5, 0, 0, 0, 0,
+		}, fill.vertices)
+		outline := api.drawCalls[1]
+		assert.Equal(t, int32(3), outline.first)
+		assert.Equal(t, int32(18), outline.count)
+		assert.Equal(t, int32(1), outline.thickness, "outline is always 1 pixel thick")
+	})
+}
+
+func newTool(t *testing.T, api *fakeAPI, width, height int) (*gldraw.Tool, *image.Image) {
+	context := gl.NewContext(api)
+	tool, err := gldraw.New(context)
+	require.NoError(t, err)
+	tool.SetColor(color)
+	img := image.New(context.NewAcceleratedImage(width, height))
+	return tool, img
+}
+
+func lineQuad(corners [4][2]float32, x1, y1, x2, y2 float32) []float32 {
+	var vertices []float32
+	for _, i := range []int{0, 1, 2, 0, 2, 3} {
+		vertices = append(vertices, corners[i][0], corners[i][1], x1, y1, x2, y2)
+	}
+	return vertices
+}
+
+func fillQuad(left, top, right, bottom float32) []float32 {
+	return []float32{
+		left, top, 0, 0, 0, 0,
+		right, top, 0, 0, 0, 0,
+		right, bottom, 0, 0, 0, 0,
+		left, top, 0, 0, 0, 0,
+		right, bottom, 0, 0, 0, 0,
+		left, bottom, 0, 0, 0, 0,
+	}
+}
+
+func rgbaf(color image.Color) [4]float32 {
+	r, g, b, a := color.RGBAf()
+	return [4]float32{r, g, b, a}
+}
+
+const (
+	triangles              = 0x0004
+	arrayBuffer            = 0x8892
+	floatVec2              = 0x8B50
+	floatVec4              = 0x8B52
+	compileStatus          = 0x8B81
+	linkStatus             = 0x8B82
+	activeUniforms         = 0x8B86
+	activeUniformMaxLength = 0x8B87
+	activeAttributes       = 0x8B89
+	activeAttributeMaxLen  = 0x8B8A
+	maxTextureSize         = 0x0D33
+)
+
+var (
+	uniforms   = []string{"color", "size", "thickness"}
+	attributes = []struct {
+		name  string
+		xtype uint32
+	}{
+		{name: "xy", xtype: floatVec2},
+		{name: "line", xtype: floatVec4},
+	}
+)
+
+type drawCall struct {
+	mode, first, count int32
+	vertices           []float32
+	color              [4]float32
+	size               [2]float32
+	thickness          int32
+}
+
+// fakeAPI simulates OpenGL driver used by the tool. It records draw calls
+// together with vertices and uniforms. Methods not used by the tool are not
+// implemented.
+type fakeAPI struct {
+	gl.API
+	compileFails     bool
+	lastID           uint32
+	buffers          map[uint32][]float32
+	vertexArrays     map[uint32]uint32
+	boundArrayBuffer uint32
+	boundVertexArray uint32
+	uniformFloats    map[string][]float32
+	uniformInts      map[string]int32
+	drawCalls        []drawCall
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		buffers:       map[uint32][]float32{},
+		vertexArrays:  map[uint32]uint32{},
+		uniformFloats: map[string][]float32{},
+		uniformInts:   map[string]int32{},
+	}
+}
+
+func (a *fakeAPI) nextID() uint32 {
+	a.lastID++
+	return a.lastID
+}
+
+func (a *fakeAPI) GetIntegerv(pname uint32, data *int32) {
+	if pname == maxTextureSize {
+		*data = 4096
+	}
+}
+
+func (a *fakeAPI) CreateShader(xtype uint32) uint32 { return a.nextID() }
+func (a *fakeAPI) ShaderSource(shader uint32, count int32, xstring **uint8, length *int32) {
+}
+func (a *fakeAPI) CompileShader(shader uint32) {}
+func (a *fakeAPI) GetShaderiv(shader uint32, pname uint32, params *int32) {
+	if pname == compileStatus && !a.compileFails {
+		*params = 1
+	}
+}
+func (a *fakeAPI) Strs(strs ...string) (cstrs **uint8, free func()) {
+	return nil, func() {}
+}
+
+func (a *fakeAPI) CreateProgram() uint32                      { return a.nextID() }
+func (a *fakeAPI) AttachShader(program uint32, shader uint32) {}
+func (a *fakeAPI) LinkProgram(program uint32)                 {}
+func (a *fakeAPI) UseProgram(program uint32)                  {}
+func (a *fakeAPI) GetProgramiv(program uint32, pname uint32, params *int32) {
+	switch pname {
+	case linkStatus:
+		*params = 1
+	case activeUniforms:
+		*params = int32(len(uniforms))
+	case activeAttributes:
+		*params = int32(len(attributes))
+	case activeUniformMaxLength, activeAttributeMaxLen:
+		*params = 32
+	}
+}
+func (a *fakeAPI) GetActiveUniform(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	writeString(name, bufSize, uniforms[index])
+}
+func (a *fakeAPI) GetActiveAttrib(program uint32, index uint32, bufSize int32, length *int32, size *int32, xtype *uint32, name *uint8) {
+	writeString(name, bufSize, attributes[index].name)
+	*xtype = attributes[index].xtype
+}
+func (a *fakeAPI) GetAttribLocation(program uint32, name *uint8) int32 {
+	goName := a.GoStr(name)
+	for i, attribute := range attributes {
+		if attribute.name == goName {
+			return int32(i)
+		}
+	}
+	return -1
+}
+func (a *fakeAPI) GoStr(cstr *uint8) string {
+	bytes := (*[1 << 10]byte)(unsafe.Pointer(cstr))
+	length := 0
+	for bytes[length] != 0 {
+		length++
+	}
+	return string(bytes[:length])
+}
+
+func writeString(dst *uint8, bufSize int32, s string) {
+	bytes := (*[1 << 10]byte)(unsafe.Pointer(dst))[:bufSize:bufSize]
+	copy(bytes, s)
+	bytes[len(s)] = 0
+}
+
+func (a *fakeAPI) GenBuffers(n int32, buffers *uint32) { *buffers = a.nextID() }
+func (a *fakeAPI) BindBuffer(target uint32, buffer uint32) {
+	if target == arrayBuffer {
+		a.boundArrayBuffer = buffer
+	}
+}
+func (a *fakeAPI) BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
+	a.buffers[a.boundArrayBuffer] = make([]float32, size/4)
+}
+func (a *fakeAPI) BufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+	floats := (*[1 << 24]float32)(data)[: size/4 : size/4]
+	copy(a.buffers[a.boundArrayBuffer][offset/4:], floats)
+}
+func (a *fakeAPI) DeleteBuffers(n int32, buffers *uint32) {
+	delete(a.buffers, *buffers)
+}
+func (a *fakeAPI) GenVertexArrays(n int32, arrays *uint32) { *arrays = a.nextID() }
+func (a *fakeAPI) BindVertexArray(array uint32)            { a.boundVertexArray = array }
+func (a *fakeAPI) EnableVertexAttribArray(index uint32)    {}
+func (a *fakeAPI) VertexAttribPointer(index uint32, size int32, xtype uint32, normalized bool, stride int32, pointer unsafe.Pointer) {
+	a.vertexArrays[a.boundVertexArray] = a.boundArrayBuffer
+}
+func (a *fakeAPI) Ptr(data interface{}) unsafe.Pointer {
+	if floats, ok := data.([]float32); ok && len(floats) > 0 {
+		return unsafe.Pointer(&floats[0])
+	}
+	return nil
+}
+func (a *fakeAPI) PtrOffset(offset int) unsafe.Pointer { return nil }
+
+func (a *fakeAPI) GenTextures(n int32, textures *uint32)     { *textures = a.nextID() }
+func (a *fakeAPI) BindTexture(target uint32, texture uint32) {}
+func (a *fakeAPI) TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+}
+func (a *fakeAPI) TexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+}
+func (a *fakeAPI) TexParameteri(target uint32, pname uint32, param int32) {}
+func (a *fakeAPI) GenFramebuffers(n int32, framebuffers *uint32)          { *framebuffers = a.nextID() }
+func (a *fakeAPI) BindFramebuffer(target uint32, framebuffer uint32)      {}
+func (a *fakeAPI) FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
+}
+func (a *fakeAPI) Enable(cap uint32)                                                  {}
+func (a *fakeAPI) Scissor(x int32, y int32, width int32, height int32)                {}
+func (a *fakeAPI) Viewport(x int32, y int32, width int32, height int32)               {}
+func (a *fakeAPI) ClearColor(red float32, green float32, blue float32, alpha float32) {}
+func (a *fakeAPI) Clear(mask uint32)                                                  {}
+func (a *fakeAPI) BlendFunc(sfactor uint32, dfactor uint32)                           {}
+
+func (a *fakeAPI) Uniform1i(location int32, v0 int32) {
+	a.uniformInts[uniforms[location]] = v0
+}
+func (a *fakeAPI) Uniform2f(location int32, v0 float32, v1 float32) {
+	a.uniformFloats[uniforms[location]] = []float32{v0, v1}
+}
+func (a *fakeAPI) Uniform4f(location int32, v0 float32, v1 float32, v2 float32, v3 float32) {
+	a.uniformFloats[uniforms[location]] = []float32{v0, v1, v2, v3}
+}
+
+func (a *fakeAPI) DrawArrays(mode uint32, first int32, count int32) {
+	buffer := a.buffers[a.vertexArrays[a.boundVertexArray]]
+	vertices := buffer[first*6 : (first+count)*6]
+	call := drawCall{
+		mode:      int32(mode),
+		first:     first,
+		count:     count,
+		vertices:  append([]float32(nil), vertices...),
+		thickness: a.uniformInts["thickness"],
+	}
+	copy(call.color[:], a.uniformFloats["color"])
+	copy(call.size[:], a.uniformFloats["size"])
+	a.drawCalls = append(a.drawCalls, call)
+}
@@ -0,0 +1,168 @@
+package glfw_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/draw"
+	"github.com/elgopher/pixiq/gldraw"
+	"github.com/elgopher/pixiq/glfw"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+)
+
+var mainThreadLoop *glfw.MainThreadLoop
+
+func TestMain(m *testing.M) {
+	var exit int
+	glfw.StartMainThreadLoop(func(main *glfw.MainThreadLoop) {
+		mainThreadLoop = main
+		exit = m.Run()
+	})
+	os.Exit(exit)
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should create tool", func(t *testing.T) {
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		// when
+		tool, err := gldraw.New(openGL.Context())
+		// then
+		require.NoError(t, err)
+		assert.NotNil(t, tool)
+	})
+}
+
+const (
+	width  = 24
+	height = 20
+)
+
+var color = image.RGBA(10, 20, 30, 40)
+
+func TestTool_Line(t *testing.T) {
+	openGL, err := glfw.NewOpenGL(mainThreadLoop)
+	require.NoError(t, err)
+	defer openGL.Destroy()
+	gpuTool, err := gldraw.New(openGL.Context())
+	require.NoError(t, err)
+	gpuTool.SetColor(color)
+	cpuTool := draw.New()
+	cpuTool.SetColor(color)
+
+	t.Run("should draw the same pixels as draw.Tool", func(t *testing.T) {
+		ends := []int{-3, 0, 1, 4, 7, 11, 19, 26}
+		for _, thickness := range []int{1, 2, 3} {
+			for _, x1 := range ends {
+				for _, y2 := range ends {
+					x2, y1 := 9, 5
+					name := fmt.Sprintf("(%d,%d)-(%d,%d) thickness %d", x1, y1, x2, y2, thickness)
+					t.Run(name, func(t *testing.T) {
+						gpuImage := image.New(openGL.Context().NewAcceleratedImage(width, height))
+						cpuImage := image.New(fake.NewAcceleratedImage(width, height))
+						gpuTool.SetThickness(thickness)
+						cpuTool.SetThickness(thickness)
+						// when
+						gpuTool.Line(gpuImage.WholeImageSelection(), x1, y1, x2, y2)
+						// then
+						cpuTool.Line(cpuImage.WholeImageSelection(), x1, y1, x2, y2)
+						assertSameImages(t, cpuImage, gpuImage)
+					})
+				}
+			}
+		}
+	})
+	t.Run("should draw line in selection", func(t *testing.T) {
+		gpuImage := image.New(openGL.Context().NewAcceleratedImage(width, height))
+		cpuImage := image.New(fake.NewAcceleratedImage(width, height))
+		gpuTool.SetThickness(1)
+		cpuTool.SetThickness(1)
+		gpuSelection := gpuImage.Selection(3, 4).WithSize(30, 6)
+		cpuSelection := cpuImage.Selection(3, 4).WithSize(30, 6)
+		// when
+		gpuTool.Line(gpuSelection, -2, -1, 25, 7)
+		// then
+		cpuTool.Line(cpuSelection, -2, -1, 25, 7)
+		assertSameImages(t, cpuImage, gpuImage)
+	})
+}
+
+func TestTool_Rectangle(t *testing.T) {
+	openGL, err := glfw.NewOpenGL(mainThreadLoop)
+	require.NoError(t, err)
+	defer openGL.Destroy()
+	gpuTool, err := gldraw.New(openGL.Context())
+	require.NoError(t, err)
+	gpuTool.SetColor(color)
+	cpuTool := draw.New()
+	cpuTool.SetColor(color)
+
+	t.Run("should draw the same pixels as draw.Tool", func(t *testing.T) {
+		for _, thickness := range []int{1, 2, 5} {
+			name := fmt.Sprintf("thickness %d", thickness)
+			t.Run(name, func(t *testing.T) {
+				gpuImage := image.New(openGL.Context().NewAcceleratedImage(width, height))
+				cpuImage := image.New(fake.NewAcceleratedImage(width, height))
+				gpuTool.SetThickness(thickness)
+				cpuTool.SetThickness(thickness)
+				// when
+				gpuTool.Rectangle(gpuImage.WholeImageSelection(), 2, 3, 15, 12)
+				gpuTool.FilledRectangle(gpuImage.WholeImageSelection(), -1, 17, 30, 5)
+				// then
+				cpuTool.Rectangle(cpuImage.WholeImageSelection(), 2, 3, 15, 12)
+				cpuTool.FilledRectangle(cpuImage.WholeImageSelection(), -1, 17, 30, 5)
+				assertSameImages(t, cpuImage, gpuImage)
+			})
+		}
+	})
+}
+
+func TestTool_FilledTriangle(t *testing.T) {
+	openGL, err := glfw.NewOpenGL(mainThreadLoop)
+	require.NoError(t, err)
+	defer openGL.Destroy()
+	gpuTool, err := gldraw.New(openGL.Context())
+	require.NoError(t, err)
+	gpuTool.SetColor(color)
+	cpuTool := draw.New()
+	cpuTool.SetColor(color)
+
+	t.Run("should draw the same pixels as draw.Tool.FilledPolygon", func(t *testing.T) {
+		triangles := [][3]draw.Point{
+			{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}},
+			{{X: 12, Y: 1}, {X: 23, Y: 7}, {X: 3, Y: 19}},
+			{{X: 5, Y: 5}, {X: 6, Y: 18}, {X: 7, Y: 4}},
+			{{X: -5, Y: 2}, {X: 30, Y: 9}, {X: 11, Y: 25}},
+			{{X: 1, Y: 1}, {X: 5, Y: 5}, {X: 9, Y: 9}},
+		}
+		for _, triangle := range triangles {
+			name := fmt.Sprintf("%v", triangle)
+			t.Run(name, func(t *testing.T) {
+				gpuImage := image.New(openGL.Context().NewAcceleratedImage(width, height))
+				cpuImage := image.New(fake.NewAcceleratedImage(width, height))
+				p := triangle
+				// when
+				gpuTool.FilledTriangle(gpuImage.WholeImageSelection(), p[0].X, p[0].Y, p[1].X, p[1].Y, p[2].X, p[2].Y)
+				// then
+				cpuTool.FilledPolygon(cpuImage.WholeImageSelection(), p[:])
+				assertSameImages(t, cpuImage, gpuImage)
+			})
+		}
+	})
+}
+
+func assertSameImages(t *testing.T, expected, actual *image.Image) {
+	expectedSelection := expected.WholeImageSelection()
+	actualSelection := actual.WholeImageSelection()
+	for y := 0; y < expected.Height(); y++ {
+		for x := 0; x < expected.Width(); x++ {
+			assert.Equal(t, expectedSelection.Color(x, y), actualSelection.Color(x, y), "position(%d,%d)", x, y)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package gldraw_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/draw"
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/gl/software"
+	"github.com/elgopher/pixiq/gldraw"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/internal/imagetest"
+)
+
+var drawColor = image.RGBA(200, 100, 50, 255)
+
+func TestTool_Line_Software(t *testing.T) {
+	t.Run("should give the same results as draw.Tool", func(t *testing.T) {
+		tests := map[string]struct {
+			x1, y1, x2, y2 int
+		}{
+			"single pixel":     {x1: 3, y1: 4, x2: 3, y2: 4},
+			"horizontal":       {x1: 1, y1: 2, x2: 9, y2: 2},
+			"vertical":         {x1: 5, y1: 9, x2: 5, y2: 1},
+			"diagonal":         {x1: 0, y1: 0, x2: 9, y2: 9},
+			"gentle slope":     {x1: 1, y1: 1, x2: 9, y2: 4},
+			"reversed gentle":  {x1: 9, y1: 4, x2: 1, y2: 1},
+			"steep slope":      {x1: 2, y1: 0, x2: 3, y2: 8},
+			"reversed steep":   {x1: 3, y1: 8, x2: 2, y2: 0},
+			"steep going left": {x1: 7, y1: 1, x2: 4, y2: 9},
+			"clipped":          {x1: -5, y1: -3, x2: 15, y2: 12},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				for thickness := 1; thickness <= 3; thickness++ {
+					cpuTool, gpuTool, context := newTools(t, thickness)
+					cpuTarget := imagetest.NewPatternImage(context, 12, 10, 1)
+					gpuTarget := imagetest.NewPatternImage(context, 12, 10, 1)
+					// when
+					cpuTool.Line(cpuTarget.Selection(1, 0), test.x1, test.y1, test.x2, test.y2)
+					gpuTool.Line(gpuTarget.Selection(1, 0), test.x1, test.y1, test.x2, test.y2)
+					// then
+					imagetest.AssertEqualImages(t, cpuTarget, gpuTarget)
+				}
+			})
+		}
+	})
+}
+
+func TestTool_Rectangle_Software(t *testing.T) {
+	t.Run("should give the same results as draw.Tool", func(t *testing.T) {
+		tests := map[string]struct {
+			x, y, width, height int
+		}{
+			"inside":         {x: 1, y: 2, width: 7, height: 5},
+			"clipped":        {x: -2, y: -1, width: 8, height: 20},
+			"negative width": {x: 5, y: 2, width: -3, height: 4},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				for thickness := 1; thickness <= 3; thickness++ {
+					cpuTool, gpuTool, context := newTools(t, thickness)
+					cpuTarget := imagetest.NewPatternImage(context, 10, 10, 1)
+					gpuTarget := imagetest.NewPatternImage(context, 10, 10, 1)
+					// when
+					cpuTool.Rectangle(cpuTarget.WholeImageSelection(), test.x, test.y, test.width, test.height)
+					gpuTool.Rectangle(gpuTarget.WholeImageSelection(), test.x, test.y, test.width, test.height)
+					cpuTool.FilledRectangle(cpuTarget.Selection(2, 2), test.x, test.y, test.height, test.width)
+					gpuTool.FilledRectangle(gpuTarget.Selection(2, 2), test.x, test.y, test.height, test.width)
+					// then
+					imagetest.AssertEqualImages(t, cpuTarget, gpuTarget)
+				}
+			})
+		}
+	})
+}
+
+func TestTool_Triangle_Software(t *testing.T) {
+	t.Run("should give the same results as draw.Tool", func(t *testing.T) {
+		tests := map[string][3]draw.Point{
+			"clockwise":        {{X: 1, Y: 1}, {X: 9, Y: 3}, {X: 4, Y: 9}},
+			"counterclockwise": {{X: 1, Y: 1}, {X: 4, Y: 9}, {X: 9, Y: 3}},
+			"thin":             {{X: 0, Y: 0}, {X: 2, Y: 5}, {X: 1, Y: 5}},
+			"flat":             {{X: 1, Y: 8}, {X: 8, Y: 8}, {X: 5, Y: 2}},
+			"clipped":          {{X: -4, Y: 3}, {X: 6, Y: -5}, {X: 13, Y: 11}},
+		}
+		for name, points := range tests {
+			t.Run(name, func(t *testing.T) {
+				p1, p2, p3 := points[0], points[1], points[2]
+				for thickness := 1; thickness <= 2; thickness++ {
+					cpuTool, gpuTool, context := newTools(t, thickness)
+					cpuTarget := imagetest.NewPatternImage(context, 10, 10, 1)
+					gpuTarget := imagetest.NewPatternImage(context, 10, 10, 1)
+					// when
+					cpuTool.Polygon(cpuTarget.WholeImageSelection(), points[:])
+					gpuTool.Triangle(gpuTarget.WholeImageSelection(), p1.X, p1.Y, p2.X, p2.Y, p3.X, p3.Y)
+					// then
+					imagetest.AssertEqualImages(t, cpuTarget, gpuTarget)
+				}
+				cpuTool, gpuTool, context := newTools(t, 1)
+				cpuTarget := imagetest.NewPatternImage(context, 10, 10, 1)
+				gpuTarget := imagetest.NewPatternImage(context, 10, 10, 1)
+				// when
+				cpuTool.FilledPolygon(cpuTarget.WholeImageSelection(), points[:])
+				gpuTool.FilledTriangle(gpuTarget.WholeImageSelection(), p1.X, p1.Y, p2.X, p2.Y, p3.X, p3.Y)
+				// then
+				imagetest.AssertEqualImages(t, cpuTarget, gpuTarget)
+			})
+		}
+	})
+}
+
+func newTools(t *testing.T, thickness int) (*draw.Tool, *gldraw.Tool, *gl.Context) {
+	context := gl.NewContext(software.NewAPI())
+	gpuTool, err := gldraw.New(context)
+	require.NoError(t, err)
+	gpuTool.SetColor(drawColor)
+	gpuTool.SetThickness(thickness)
+	cpuTool := draw.New()
+	cpuTool.SetColor(drawColor)
+	cpuTool.SetThickness(thickness)
+	return cpuTool, gpuTool, context
+}
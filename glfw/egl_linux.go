@@ -0,0 +1,324 @@
+package glfw
+
+/*
+#cgo LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+
+// Types and constants from EGL/egl.h and EGL/eglext.h. libEGL is loaded
+// dynamically, therefore neither EGL headers nor libEGL are needed to build
+// the package.
+typedef int32_t EGLint;
+typedef unsigned int EGLBoolean;
+typedef unsigned int EGLenum;
+typedef void *EGLDisplay;
+typedef void *EGLConfig;
+typedef void *EGLSurface;
+typedef void *EGLContext;
+typedef void (*EGLProc)(void);
+
+#define EGL_FALSE                           0
+#define EGL_EXTENSIONS                      0x3055
+#define EGL_ALPHA_SIZE                      0x3021
+#define EGL_BLUE_SIZE                       0x3022
+#define EGL_GREEN_SIZE                      0x3023
+#define EGL_RED_SIZE                        0x3024
+#define EGL_SURFACE_TYPE                    0x3033
+#define EGL_NONE                            0x3038
+#define EGL_RENDERABLE_TYPE                 0x3040
+#define EGL_HEIGHT                          0x3056
+#define EGL_WIDTH                           0x3057
+#define EGL_PBUFFER_BIT                     0x0001
+#define EGL_OPENGL_BIT                      0x0008
+#define EGL_OPENGL_API                      0x30A2
+#define EGL_CONTEXT_MAJOR_VERSION           0x3098
+#define EGL_CONTEXT_MINOR_VERSION           0x30FB
+#define EGL_CONTEXT_OPENGL_PROFILE_MASK     0x30FD
+#define EGL_CONTEXT_OPENGL_CORE_PROFILE_BIT 0x0001
+#define EGL_PLATFORM_SURFACELESS_MESA       0x31DD
+
+static struct {
+	EGLProc (*getProcAddress)(const char *);
+	const char *(*queryString)(EGLDisplay, EGLint);
+	EGLDisplay (*getDisplay)(void *);
+	EGLBoolean (*initialize)(EGLDisplay, EGLint *, EGLint *);
+	EGLBoolean (*terminate)(EGLDisplay);
+	EGLBoolean (*bindAPI)(EGLenum);
+	EGLBoolean (*chooseConfig)(EGLDisplay, const EGLint *, EGLConfig *, EGLint, EGLint *);
+	EGLSurface (*createPbufferSurface)(EGLDisplay, EGLConfig, const EGLint *);
+	EGLContext (*createContext)(EGLDisplay, EGLConfig, EGLContext, const EGLint *);
+	EGLBoolean (*makeCurrent)(EGLDisplay, EGLSurface, EGLSurface, EGLContext);
+	EGLContext (*getCurrentContext)(void);
+	EGLBoolean (*destroyContext)(EGLDisplay, EGLContext);
+	EGLBoolean (*destroySurface)(EGLDisplay, EGLSurface);
+	EGLint (*getError)(void);
+} egl;
+
+// loadEGL loads libEGL and looks up its functions. Returns 0 on failure.
+static int loadEGL() {
+	if (egl.getProcAddress != NULL) {
+		return 1;
+	}
+	void *lib = dlopen("libEGL.so.1", RTLD_NOW | RTLD_GLOBAL);
+	if (lib == NULL) {
+		lib = dlopen("libEGL.so", RTLD_NOW | RTLD_GLOBAL);
+	}
+	if (lib == NULL) {
+		return 0;
+	}
+	egl.queryString = dlsym(lib, "eglQueryString");
+	egl.getDisplay = dlsym(lib, "eglGetDisplay");
+	egl.initialize = dlsym(lib, "eglInitialize");
+	egl.terminate = dlsym(lib, "eglTerminate");
+	egl.bindAPI = dlsym(lib, "eglBindAPI");
+	egl.chooseConfig = dlsym(lib, "eglChooseConfig");
+	egl.createPbufferSurface = dlsym(lib, "eglCreatePbufferSurface");
+	egl.createContext = dlsym(lib, "eglCreateContext");
+	egl.makeCurrent = dlsym(lib, "eglMakeCurrent");
+	egl.getCurrentContext = dlsym(lib, "eglGetCurrentContext");
+	egl.destroyContext = dlsym(lib, "eglDestroyContext");
+	egl.destroySurface = dlsym(lib, "eglDestroySurface");
+	egl.getError = dlsym(lib, "eglGetError");
+	egl.getProcAddress = dlsym(lib, "eglGetProcAddress");
+	return egl.getProcAddress != NULL;
+}
+
+static int hasExtension(const char *extensions, const char *name) {
+	size_t length = strlen(name);
+	const char *found = extensions;
+	while (extensions != NULL && (found = strstr(found, name)) != NULL) {
+		int atStart = found == extensions || found[-1] == ' ';
+		int atEnd = found[length] == ' ' || found[length] == '\0';
+		if (atStart && atEnd) {
+			return 1;
+		}
+		found += length;
+	}
+	return 0;
+}
+
+// getDisplay returns surfaceless Mesa display if available, which does not
+// need any display server nor GPU. Otherwise it returns the default display.
+static EGLDisplay getDisplay() {
+	const char *clientExtensions = egl.queryString(NULL, EGL_EXTENSIONS);
+	if (hasExtension(clientExtensions, "EGL_MESA_platform_surfaceless")) {
+		EGLDisplay (*getPlatformDisplay)(EGLenum, void *, const EGLint *) =
+			(void *) egl.getProcAddress("eglGetPlatformDisplayEXT");
+		if (getPlatformDisplay != NULL) {
+			EGLDisplay display = getPlatformDisplay(EGL_PLATFORM_SURFACELESS_MESA, NULL, NULL);
+			if (display != NULL) {
+				return display;
+			}
+		}
+	}
+	return egl.getDisplay(NULL);
+}
+
+static int displaySupports(EGLDisplay display, const char *extension) {
+	return hasExtension(egl.queryString(display, EGL_EXTENSIONS), extension);
+}
+
+static EGLBoolean initialize(EGLDisplay display) {
+	return egl.initialize(display, NULL, NULL);
+}
+
+static void terminate(EGLDisplay display) {
+	egl.terminate(display);
+}
+
+static EGLBoolean bindAPI(EGLenum api) {
+	return egl.bindAPI(api);
+}
+
+static EGLBoolean chooseConfig(EGLDisplay display, const EGLint *attributes, EGLConfig *config, EGLint *numConfigs) {
+	return egl.chooseConfig(display, attributes, config, 1, numConfigs);
+}
+
+static EGLSurface createPbufferSurface(EGLDisplay display, EGLConfig config, const EGLint *attributes) {
+	return egl.createPbufferSurface(display, config, attributes);
+}
+
+static EGLContext createContext(EGLDisplay display, EGLConfig config, const EGLint *attributes) {
+	return egl.createContext(display, config, NULL, attributes);
+}
+
+static EGLBoolean makeCurrent(EGLDisplay display, EGLSurface surface, EGLContext context) {
+	return egl.makeCurrent(display, surface, surface, context);
+}
+
+static EGLContext getCurrentContext() {
+	return egl.getCurrentContext();
+}
+
+static void destroyContext(EGLDisplay display, EGLContext context) {
+	egl.destroyContext(display, context);
+}
+
+static void destroySurface(EGLDisplay display, EGLSurface surface) {
+	egl.destroySurface(display, surface);
+}
+
+static EGLint getError() {
+	return egl.getError();
+}
+
+static void *getProcAddress(const char *name) {
+	return (void *) egl.getProcAddress(name);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// eglContext is an offscreen OpenGL 3.3 core context created using EGL.
+// All methods must be executed in the main thread.
+type eglContext struct {
+	display C.EGLDisplay
+	surface C.EGLSurface
+	context C.EGLContext
+}
+
+func newEGLContext() (*eglContext, error) {
+	if C.loadEGL() == 0 {
+		return nil, errors.New("libEGL.so.1 can't be loaded")
+	}
+	display := C.getDisplay()
+	if display == 0 {
+		return nil, errors.New("no EGL display")
+	}
+	if err := initializeDisplay(display); err != nil {
+		return nil, err
+	}
+	ctx := &eglContext{display: display}
+	if C.bindAPI(C.EGL_OPENGL_API) == C.EGL_FALSE {
+		err := eglError("eglBindAPI")
+		ctx.destroy()
+		return nil, err
+	}
+	surfaceless := displaySupports(display, "EGL_KHR_surfaceless_context")
+	var surfaceType C.EGLint = C.EGL_PBUFFER_BIT
+	if surfaceless {
+		surfaceType = 0 // any config can be used
+	}
+	configAttributes := []C.EGLint{
+		C.EGL_RENDERABLE_TYPE, C.EGL_OPENGL_BIT,
+		C.EGL_SURFACE_TYPE, surfaceType,
+		C.EGL_RED_SIZE, 8,
+		C.EGL_GREEN_SIZE, 8,
+		C.EGL_BLUE_SIZE, 8,
+		C.EGL_ALPHA_SIZE, 8,
+		C.EGL_NONE,
+	}
+	var (
+		config     C.EGLConfig
+		numConfigs C.EGLint
+	)
+	if C.chooseConfig(display, &configAttributes[0], &config, &numConfigs) == C.EGL_FALSE {
+		err := eglError("eglChooseConfig")
+		ctx.destroy()
+		return nil, err
+	}
+	if numConfigs == 0 {
+		ctx.destroy()
+		return nil, errors.New("no EGL config supporting OpenGL")
+	}
+	if !surfaceless {
+		surfaceAttributes := []C.EGLint{
+			C.EGL_WIDTH, 1,
+			C.EGL_HEIGHT, 1,
+			C.EGL_NONE,
+		}
+		ctx.surface = C.createPbufferSurface(display, config, &surfaceAttributes[0])
+		if ctx.surface == nil {
+			err := eglError("eglCreatePbufferSurface")
+			ctx.destroy()
+			return nil, err
+		}
+	}
+	contextAttributes := []C.EGLint{
+		C.EGL_CONTEXT_MAJOR_VERSION, 3,
+		C.EGL_CONTEXT_MINOR_VERSION, 3,
+		C.EGL_CONTEXT_OPENGL_PROFILE_MASK, C.EGL_CONTEXT_OPENGL_CORE_PROFILE_BIT,
+		C.EGL_NONE,
+	}
+	ctx.context = C.createContext(display, config, &contextAttributes[0])
+	if ctx.context == nil {
+		err := eglError("eglCreateContext")
+		ctx.destroy()
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// displayReferences counts contexts created for each initialized display.
+// Display is shared by the whole process and eglTerminate invalidates all its
+// contexts, therefore the display is terminated when the last context using it
+// is destroyed. Accessed from the main thread only.
+var displayReferences = map[C.EGLDisplay]int{}
+
+func initializeDisplay(display C.EGLDisplay) error {
+	if displayReferences[display] == 0 && C.initialize(display) == C.EGL_FALSE {
+		return eglError("eglInitialize")
+	}
+	displayReferences[display]++
+	return nil
+}
+
+func releaseDisplay(display C.EGLDisplay) {
+	displayReferences[display]--
+	if displayReferences[display] == 0 {
+		delete(displayReferences, display)
+		C.terminate(display)
+	}
+}
+
+func displaySupports(display C.EGLDisplay, extension string) bool {
+	cextension := C.CString(extension)
+	defer C.free(unsafe.Pointer(cextension))
+	return C.displaySupports(display, cextension) == 1
+}
+
+func eglError(function string) error {
+	return fmt.Errorf("%s failed with EGL error 0x%X", function, int(C.getError()))
+}
+
+// MakeContextCurrent makes the context current in the calling thread.
+func (c *eglContext) MakeContextCurrent() {
+	if C.makeCurrent(c.display, c.surface, c.context) == C.EGL_FALSE {
+		panic(eglError("eglMakeCurrent"))
+	}
+}
+
+// destroy releases the context, the surface and the display. Can be used for
+// partially created contexts too.
+func (c *eglContext) destroy() {
+	if c.display == 0 {
+		return
+	}
+	if c.context != nil && C.getCurrentContext() == c.context {
+		C.makeCurrent(c.display, nil, nil)
+	}
+	if c.context != nil {
+		C.destroyContext(c.display, c.context)
+		c.context = nil
+	}
+	if c.surface != nil {
+		C.destroySurface(c.display, c.surface)
+		c.surface = nil
+	}
+	releaseDisplay(c.display)
+	c.display = 0
+}
+
+// eglProcAddress returns the address of OpenGL function
+func eglProcAddress(name string) unsafe.Pointer {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return C.getProcAddress(cname)
+}
@@ -0,0 +1,25 @@
+//go:build !linux
+// +build !linux
+
+package glfw
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// eglContext is not supported on this platform
+type eglContext struct{}
+
+func newEGLContext() (*eglContext, error) {
+	return nil, errors.New("headless OpenGL is supported only on Linux")
+}
+
+// MakeContextCurrent does nothing
+func (c *eglContext) MakeContextCurrent() {}
+
+func (c *eglContext) destroy() {}
+
+func eglProcAddress(string) unsafe.Pointer {
+	return nil
+}
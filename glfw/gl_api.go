@@ -4,7 +4,6 @@ import (
 	"unsafe"
 
 	"github.com/go-gl/gl/v3.3-core/gl"
-	"github.com/go-gl/glfw/v3.3/glfw"
 )
 
 type context struct {
@@ -12,18 +11,18 @@ type context struct {
 	runAsync func(func())
 }
 
-func newContext(mainThreadLoop *MainThreadLoop, window *glfw.Window) *context {
+func newContext(mainThreadLoop *MainThreadLoop, glContext openGLContext) *context {
 	return &context{
 		run: func(f func()) {
 			mainThreadLoop.executeCommand(command{
-				window:  window,
-				execute: f,
+				glContext: glContext,
+				execute:   f,
 			})
 		},
 		runAsync: func(f func()) {
 			mainThreadLoop.executeAsyncCommand(command{
-				window:  window,
-				execute: f,
+				glContext: glContext,
+				execute:   f,
 			})
 		},
 	}
@@ -71,6 +70,49 @@ func (g *context) DeleteBuffers(n int32, buffers *uint32) {
 	})
 }
 
+// MapBufferRange maps all or part of a buffer object's data store into the client's address space
+func (g *context) MapBufferRange(target uint32, offset int, length int, access uint32) unsafe.Pointer {
+	var ptr unsafe.Pointer
+	g.run(func() {
+		ptr = gl.MapBufferRange(target, offset, length, access)
+	})
+	return ptr
+}
+
+// UnmapBuffer releases the mapping of a buffer object's data store into the client's address space
+func (g *context) UnmapBuffer(target uint32) bool {
+	var success bool
+	g.run(func() {
+		success = gl.UnmapBuffer(target)
+	})
+	return success
+}
+
+// FenceSync creates a new sync object and inserts it into the GL command stream
+func (g *context) FenceSync(condition uint32, flags uint32) uintptr {
+	var sync uintptr
+	g.run(func() {
+		sync = gl.FenceSync(condition, flags)
+	})
+	return sync
+}
+
+// ClientWaitSync blocks and waits for a sync object to become signaled
+func (g *context) ClientWaitSync(sync uintptr, flags uint32, timeout uint64) uint32 {
+	var result uint32
+	g.run(func() {
+		result = gl.ClientWaitSync(sync, flags, timeout)
+	})
+	return result
+}
+
+// DeleteSync deletes a sync object
+func (g *context) DeleteSync(sync uintptr) {
+	g.runAsync(func() {
+		gl.DeleteSync(sync)
+	})
+}
+
 // GenVertexArrays generates vertex array object names
 func (g *context) GenVertexArrays(n int32, arrays *uint32) {
 	g.run(func() {
@@ -99,6 +141,13 @@ func (g *context) VertexAttribPointer(index uint32, size int32, xtype uint32, no
 	})
 }
 
+// VertexAttribDivisor modifies the rate at which generic vertex attributes advance during instanced rendering
+func (g *context) VertexAttribDivisor(index uint32, divisor uint32) {
+	g.runAsync(func() {
+		gl.VertexAttribDivisor(index, divisor)
+	})
+}
+
 // EnableVertexAttribArray enables a generic vertex attribute array
 func (g *context) EnableVertexAttribArray(index uint32) {
 	g.runAsync(func() {
@@ -280,6 +329,27 @@ func (g *context) DrawArrays(mode uint32, first int32, count int32) {
 	})
 }
 
+// DrawElements render primitives from array data using indices stored in element array buffer
+func (g *context) DrawElements(mode uint32, count int32, xtype uint32, indices unsafe.Pointer) {
+	g.runAsync(func() {
+		gl.DrawElements(mode, count, xtype, indices)
+	})
+}
+
+// DrawArraysInstanced draws multiple instances of a range of elements
+func (g *context) DrawArraysInstanced(mode uint32, first int32, count int32, instancecount int32) {
+	g.runAsync(func() {
+		gl.DrawArraysInstanced(mode, first, count, instancecount)
+	})
+}
+
+// DrawElementsInstanced draws multiple instances of a set of elements
+func (g *context) DrawElementsInstanced(mode uint32, count int32, xtype uint32, indices unsafe.Pointer, instancecount int32) {
+	g.runAsync(func() {
+		gl.DrawElementsInstanced(mode, count, xtype, indices, instancecount)
+	})
+}
+
 // Uniform1f specifies the value of a uniform variable for the current program object
 func (g *context) Uniform1f(location int32, v0 float32) {
 	g.runAsync(func() {
@@ -0,0 +1,97 @@
+package glfw
+
+import (
+	gl33 "github.com/go-gl/gl/v3.3-core/gl"
+
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/image"
+)
+
+// NewHeadlessOpenGL creates OpenGL instance which does not open any window
+// and does not need a display server. It can be used by batch tools, such as
+// sprite converters, running on servers without a display. Under the hood it
+// is using an offscreen EGL context. On Linux machines without GPU the context
+// can be provided by Mesa's llvmpipe software driver.
+//
+// Just like NewOpenGL, all OpenGL calls are executed in the main thread using
+// given MainThreadLoop. HeadlessOpenGL and OpenGL instances can be used at the
+// same time, although this requires the driver to support both GLX and EGL
+// (as for example libglvnd does).
+//
+// libEGL.so.1 is loaded at runtime, so programs which do not use
+// NewHeadlessOpenGL do not depend on it. NewHeadlessOpenGL returns error when
+// libEGL can't be loaded or OpenGL 3.3 is not available.
+// At the moment it is supported only on Linux.
+//
+// NewHeadlessOpenGL will panic if mainThreadLoop is nil.
+func NewHeadlessOpenGL(mainThreadLoop *MainThreadLoop) (*HeadlessOpenGL, error) {
+	if mainThreadLoop == nil {
+		panic("nil MainThreadLoop")
+	}
+	var (
+		eglCtx *eglContext
+		err    error
+	)
+	mainThreadLoop.Execute(func() {
+		eglCtx, err = newEGLContext()
+		if err != nil {
+			return
+		}
+		mainThreadLoop.bind(eglCtx)
+		err = gl33.InitWithProcAddrFunc(eglProcAddress)
+		if err != nil {
+			mainThreadLoop.unbind(eglCtx)
+			eglCtx.destroy()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &HeadlessOpenGL{
+		mainThreadLoop: mainThreadLoop,
+		eglContext:     eglCtx,
+		context:        gl.NewContext(newContext(mainThreadLoop, eglCtx)),
+	}, nil
+}
+
+// HeadlessOpenGL provides method for creating OpenGL-accelerated image.Image
+// without opening a window.
+type HeadlessOpenGL struct {
+	mainThreadLoop *MainThreadLoop
+	eglContext     *eglContext
+	context        *gl.Context
+}
+
+// Destroy cleans all the OpenGL resources associated with this instance.
+func (h *HeadlessOpenGL) Destroy() {
+	h.mainThreadLoop.Execute(func() {
+		h.mainThreadLoop.unbind(h.eglContext)
+		h.eglContext.destroy()
+	})
+}
+
+// NewImage creates an *image.Image which is using OpenGL acceleration
+// under-the-hood.
+//
+// Will panic if width or height are negative or higher than MAX_TEXTURE_SIZE
+func (h *HeadlessOpenGL) NewImage(width, height int) *image.Image {
+	if width < 0 {
+		panic("negative width")
+	}
+	if height < 0 {
+		panic("negative height")
+	}
+	return image.New(h.context.NewAcceleratedImage(width, height))
+}
+
+// Context returns OpenGL's context. It's methods can be invoked from any goroutine.
+// Each invocation will return the same instance.
+func (h *HeadlessOpenGL) Context() *gl.Context {
+	return h.context
+}
+
+// ContextAPI returns gl.API, which can be used to OpenGL direct access.
+// It's methods can be invoked from any goroutine.
+func (h *HeadlessOpenGL) ContextAPI() gl.API {
+	return h.context.API()
+}
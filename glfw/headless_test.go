@@ -0,0 +1,102 @@
+//go:build egl
+// +build egl
+
+package glfw_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/glfw"
+	"github.com/elgopher/pixiq/image"
+)
+
+func TestNewHeadlessOpenGL(t *testing.T) {
+	t.Run("should panic when MainThreadLoop is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = glfw.NewHeadlessOpenGL(nil)
+		})
+	})
+	t.Run("should create HeadlessOpenGL using supplied MainThreadLoop", func(t *testing.T) {
+		// when
+		openGL, err := glfw.NewHeadlessOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		// then
+		assert.NotNil(t, openGL)
+		assert.NotNil(t, openGL.Context())
+		assert.NotNil(t, openGL.ContextAPI())
+	})
+	t.Run("should create 2 objects working at the same time", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			openGL, err := glfw.NewHeadlessOpenGL(mainThreadLoop)
+			require.NoError(t, err)
+			defer openGL.Destroy()
+		}
+	})
+	t.Run("should work together with OpenGL", func(t *testing.T) {
+		headless, err := glfw.NewHeadlessOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer headless.Destroy()
+		openGL, err := glfw.NewOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		colors := []image.Color{image.RGBA(10, 20, 30, 40)}
+		headlessImage := headless.Context().NewAcceleratedImage(1, 1)
+		openGLImage := openGL.Context().NewAcceleratedImage(1, 1)
+		// when
+		headlessImage.Upload(colors)
+		openGLImage.Upload(colors)
+		// then
+		headlessOutput := make([]image.Color, 1)
+		headlessImage.Download(headlessOutput)
+		assert.Equal(t, colors, headlessOutput)
+		openGLOutput := make([]image.Color, 1)
+		openGLImage.Download(openGLOutput)
+		assert.Equal(t, colors, openGLOutput)
+	})
+}
+
+func TestHeadlessOpenGL_NewImage(t *testing.T) {
+	openGL, err := glfw.NewHeadlessOpenGL(mainThreadLoop)
+	require.NoError(t, err)
+	defer openGL.Destroy()
+
+	t.Run("should panic for negative width", func(t *testing.T) {
+		assert.Panics(t, func() {
+			// when
+			openGL.NewImage(-1, 0)
+		})
+	})
+	t.Run("should panic for negative height", func(t *testing.T) {
+		assert.Panics(t, func() {
+			// when
+			openGL.NewImage(0, -1)
+		})
+	})
+	t.Run("should create Image", func(t *testing.T) {
+		// when
+		img := openGL.NewImage(1, 2)
+		// then
+		assert.Equal(t, 1, img.Width())
+		assert.Equal(t, 2, img.Height())
+	})
+}
+
+func TestHeadlessOpenGL_Context(t *testing.T) {
+	t.Run("should create AcceleratedImage which can be uploaded and downloaded", func(t *testing.T) {
+		openGL, err := glfw.NewHeadlessOpenGL(mainThreadLoop)
+		require.NoError(t, err)
+		defer openGL.Destroy()
+		img := openGL.Context().NewAcceleratedImage(2, 1)
+		colors := []image.Color{image.RGBA(10, 20, 30, 40), image.RGBA(50, 60, 70, 80)}
+		img.Upload(colors)
+		output := make([]image.Color, 2)
+		// when
+		img.Download(output)
+		// then
+		assert.Equal(t, colors, output)
+	})
+}
@@ -5,8 +5,6 @@ import (
 	"log"
 	"runtime"
 	"strconv"
-
-	"github.com/go-gl/glfw/v3.3/glfw"
 )
 
 // StartMainThreadLoop starts a loop assigned to main thread. It has to be
@@ -41,8 +39,14 @@ func isMainGoroutine() bool {
 
 // MainThreadLoop is a loop for executing jobs in main thread.
 type MainThreadLoop struct {
-	commands    chan command
-	boundWindow *glfw.Window
+	commands     chan command
+	boundContext openGLContext
+}
+
+// openGLContext is an OpenGL context which can be made current in the main
+// thread. It is implemented by *glfw.Window and headless eglContext.
+type openGLContext interface {
+	MakeContextCurrent()
 }
 
 func (g *MainThreadLoop) run() {
@@ -52,8 +56,8 @@ func (g *MainThreadLoop) run() {
 		if !ok {
 			return
 		}
-		if cmd.window != nil {
-			g.bind(cmd.window)
+		if cmd.glContext != nil {
+			g.bind(cmd.glContext)
 		}
 		cmd.execute()
 	}
@@ -73,17 +77,25 @@ func (g *MainThreadLoop) Execute(job func()) {
 	g.executeCommand(command{execute: job})
 }
 
-func (g *MainThreadLoop) bind(window *glfw.Window) {
-	if g.boundWindow != window {
-		window.MakeContextCurrent()
-		g.boundWindow = window
+func (g *MainThreadLoop) bind(glContext openGLContext) {
+	if g.boundContext != glContext {
+		glContext.MakeContextCurrent()
+		g.boundContext = glContext
+	}
+}
+
+// unbind forgets that glContext is current. Must be executed in the main thread
+// when glContext is destroyed.
+func (g *MainThreadLoop) unbind(glContext openGLContext) {
+	if g.boundContext == glContext {
+		g.boundContext = nil
 	}
 }
 
 // better use an array and create a function which will decode arguments
 type command struct {
-	window  *glfw.Window
-	execute func()
+	glContext openGLContext
+	execute   func()
 }
 
 func (g *MainThreadLoop) executeAsyncCommand(command command) {
@@ -93,7 +105,7 @@ func (g *MainThreadLoop) executeAsyncCommand(command command) {
 func (g *MainThreadLoop) executeCommand(cmd command) {
 	done := make(chan struct{})
 	g.commands <- command{
-		window: cmd.window,
+		glContext: cmd.glContext,
 		execute: func() {
 			cmd.execute()
 			done <- struct{}{}
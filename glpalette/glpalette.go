@@ -0,0 +1,165 @@
+// Package glpalette provides palette swapping tools using video card
+package glpalette
+
+import (
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/internal/clip"
+	"github.com/elgopher/pixiq/internal/glquad"
+	"github.com/elgopher/pixiq/palette"
+)
+
+// NewSwap creates a new tool replacing colors using video card. It is a faster
+// version of palette.Swap, which can be used for every frame, for example
+// for flash-on-hit effects or team colors.
+func NewSwap(context *gl.Context) (*Swap, error) {
+	if context == nil {
+		panic("nil context")
+	}
+	vertexShader, err := context.CompileVertexShader(glquad.VertexShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	fragmentShader, err := context.CompileFragmentShader(fragmentShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	program, err := context.LinkProgram(vertexShader, fragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	vertexBuffer := context.NewFloatVertexBuffer(16, gl.DynamicDraw)
+	vertexArray := glquad.NewVertexArray(context, vertexBuffer)
+	command := program.AcceleratedCommand(
+		&swapCommand{
+			vertexBuffer: vertexBuffer,
+			vertexArray:  vertexArray,
+		})
+	return &Swap{command: command}, nil
+}
+
+const fragmentShaderSrc = `
+#version 330 core
+
+uniform sampler2D tex;
+uniform sampler2D lookup;
+// texel position of the first "from" color
+uniform ivec2 lookupFrom;
+// texel position of the first "to" color
+uniform ivec2 lookupTo;
+uniform int lookupSize;
+in vec2 interpolatedST;
+out vec4 color;
+
+const vec4 epsilon = vec4(0.5 / 255.0);
+
+void main() {
+	vec4 sourceColor = texture(tex, interpolatedST);
+	for (int i = 0; i < lookupSize; i++) {
+		vec4 from = texelFetch(lookup, lookupFrom + ivec2(i, 0), 0);
+		if (all(lessThan(abs(from - sourceColor), epsilon))) {
+			color = texelFetch(lookup, lookupTo + ivec2(i, 0), 0);
+			return;
+		}
+	}
+	color = sourceColor;
+}
+`
+
+type swapCommand struct {
+	vertexBuffer *gl.FloatVertexBuffer
+	vertexArray  *gl.VertexArray
+}
+
+func (c *swapCommand) RunGL(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+	source := selections[0]
+	lookup := selections[1]
+	renderer.BindTexture(0, "tex", source.Image)
+	renderer.BindTexture(1, "lookup", lookup.Image)
+	// texture rows are stored bottom-up
+	lookupHeight := int32(lookup.Image.Height())
+	lookupX := int32(lookup.Location.X)
+	lookupY := int32(lookup.Location.Y)
+	renderer.SetIVec2("lookupFrom", lookupX, lookupHeight-1-lookupY)
+	renderer.SetIVec2("lookupTo", lookupX, lookupHeight-2-lookupY)
+	renderer.SetInt("lookupSize", int32(lookup.Location.Width))
+	var (
+		imageWidth  = float32(source.Image.Width())
+		left        = float32(source.Location.X) / imageWidth
+		right       = float32(source.Location.X+source.Location.Width) / imageWidth
+		imageHeight = float32(source.Image.Height())
+		top         = (imageHeight - float32(source.Location.Y)) / imageHeight
+		bottom      = (imageHeight - float32(source.Location.Y) - float32(source.Location.Height)) / imageHeight
+	)
+	// xy -> st
+	vertices := []float32{
+		-1, 1, left, top,
+		1, 1, right, top,
+		1, -1, right, bottom,
+		-1, -1, left, bottom,
+	}
+	c.vertexBuffer.Upload(0, vertices)
+	renderer.DrawArrays(c.vertexArray, gl.TriangleFan, 0, 4)
+}
+
+// Swap is a tool replacing colors using video card.
+type Swap struct {
+	command *gl.AcceleratedCommand
+}
+
+// SwapSourceToTarget replaces colors of source selection and puts the result
+// into the target selection. Colors are replaced using the lookup selection,
+// which must have exactly 2 rows: the first row contains colors which will be
+// replaced ("from" colors) and the second row contains replacements
+// ("to" colors). Each source pixel equal to the "from" color in column i
+// is replaced by the "to" color in column i. Other pixels are copied without
+// any change. The lookup selection can be created using NewLookup.
+//
+// Only position of the target Selection is used and the source is not clamped by
+// the target size.
+//
+// Will panic when lookup selection does not have 2 rows or is not entirely
+// inside the image.
+func (s *Swap) SwapSourceToTarget(source, lookup, target image.Selection) {
+	if lookup.Height() != 2 {
+		panic("lookup selection must have 2 rows")
+	}
+	if lookup.ImageX() < 0 || lookup.ImageY() < 0 ||
+		lookup.ImageX()+lookup.Width() > lookup.Image().Width() ||
+		lookup.ImageY()+lookup.Height() > lookup.Image().Height() {
+		panic("lookup selection is outside the image")
+	}
+	source = clip.SourceToTargetImage(source, target)
+	target = target.WithSize(source.Width(), source.Height())
+	target.Modify(s.command, source, lookup)
+}
+
+// ImageFactory creates a new image with given dimensions.
+//
+// *glfw.OpenGL instance can be used as an ImageFactory implementation.
+type ImageFactory interface {
+	NewImage(width, height int) *image.Image
+}
+
+// NewLookup creates a lookup image which can be used by Swap. The image has
+// 2 rows: the first row contains from colors and the second row contains to
+// colors. Will panic when factory is nil, palettes are empty or have
+// different lengths.
+func NewLookup(factory ImageFactory, from, to palette.Palette) *image.Image {
+	if factory == nil {
+		panic("nil factory")
+	}
+	if len(from) != len(to) {
+		panic("palettes have different lengths")
+	}
+	if len(from) == 0 {
+		panic("empty palettes")
+	}
+	img := factory.NewImage(len(from), 2)
+	selection := img.WholeImageSelection()
+	for i := range from {
+		selection.SetColor(i, 0, from[i])
+		selection.SetColor(i, 1, to[i])
+	}
+	return img
+}
@@ -0,0 +1,68 @@
+package glpalette_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/glpalette"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+	"github.com/elgopher/pixiq/palette"
+)
+
+func TestNewSwap(t *testing.T) {
+	t.Run("should panic when context is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = glpalette.NewSwap(nil)
+		})
+	})
+}
+
+func TestNewLookup(t *testing.T) {
+	var (
+		red   = image.RGB(255, 0, 0)
+		green = image.RGB(0, 255, 0)
+		blue  = image.RGB(0, 0, 255)
+		white = image.RGB(255, 255, 255)
+	)
+
+	t.Run("should panic", func(t *testing.T) {
+		tests := map[string]struct {
+			factory  glpalette.ImageFactory
+			from, to palette.Palette
+		}{
+			"nil factory":        {from: palette.Palette{red}, to: palette.Palette{green}},
+			"empty palettes":     {factory: fakeImageFactory{}},
+			"different lengths":  {factory: fakeImageFactory{}, from: palette.Palette{red}, to: palette.Palette{green, blue}},
+			"empty to palette":   {factory: fakeImageFactory{}, from: palette.Palette{red}},
+			"empty from palette": {factory: fakeImageFactory{}, to: palette.Palette{red}},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				assert.Panics(t, func() {
+					glpalette.NewLookup(test.factory, test.from, test.to)
+				})
+			})
+		}
+	})
+
+	t.Run("should create lookup image", func(t *testing.T) {
+		// when
+		img := glpalette.NewLookup(fakeImageFactory{}, palette.Palette{red, green}, palette.Palette{blue, white})
+		// then
+		assert.Equal(t, 2, img.Width())
+		assert.Equal(t, 2, img.Height())
+		selection := img.WholeImageSelection()
+		assert.Equal(t, red, selection.Color(0, 0))
+		assert.Equal(t, green, selection.Color(1, 0))
+		assert.Equal(t, blue, selection.Color(0, 1))
+		assert.Equal(t, white, selection.Color(1, 1))
+	})
+}
+
+type fakeImageFactory struct{}
+
+func (i fakeImageFactory) NewImage(width, height int) *image.Image {
+	return image.New(fake.NewAcceleratedImage(width, height))
+}
@@ -0,0 +1,149 @@
+package glfw_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/glfw"
+	"github.com/elgopher/pixiq/glpalette"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/palette"
+)
+
+var mainThreadLoop *glfw.MainThreadLoop
+
+func TestMain(m *testing.M) {
+	var exit int
+	glfw.StartMainThreadLoop(func(main *glfw.MainThreadLoop) {
+		mainThreadLoop = main
+		exit = m.Run()
+	})
+	os.Exit(exit)
+}
+
+var (
+	red   = image.RGB(255, 0, 0)
+	green = image.RGB(0, 255, 0)
+	blue  = image.RGB(0, 0, 255)
+	white = image.RGB(255, 255, 255)
+)
+
+func TestNewSwap(t *testing.T) {
+	t.Run("should return swap tool", func(t *testing.T) {
+		openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+		defer openGL.Destroy()
+		context := openGL.Context()
+		// when
+		swap, err := glpalette.NewSwap(context)
+		// then
+		assert.NotNil(t, swap)
+		assert.NoError(t, err)
+	})
+}
+
+func TestSwap_SwapSourceToTarget(t *testing.T) {
+	openGL, _ := glfw.NewOpenGL(mainThreadLoop)
+	defer openGL.Destroy()
+	swap, err := glpalette.NewSwap(openGL.Context())
+	require.NoError(t, err)
+
+	t.Run("should panic for invalid lookup", func(t *testing.T) {
+		source := openGL.NewImage(1, 1).WholeImageSelection()
+		target := openGL.NewImage(1, 1).WholeImageSelection()
+		lookup := openGL.NewImage(2, 3)
+		tests := map[string]image.Selection{
+			"one row":           lookup.Selection(0, 0).WithSize(2, 1),
+			"three rows":        lookup.WholeImageSelection(),
+			"outside on left":   lookup.Selection(-1, 0).WithSize(2, 2),
+			"outside on top":    lookup.Selection(0, -1).WithSize(2, 2),
+			"outside on right":  lookup.Selection(1, 0).WithSize(2, 2),
+			"outside on bottom": lookup.Selection(0, 2).WithSize(2, 2),
+		}
+		for name, lookupSelection := range tests {
+			t.Run(name, func(t *testing.T) {
+				assert.Panics(t, func() {
+					swap.SwapSourceToTarget(source, lookupSelection, target)
+				})
+			})
+		}
+	})
+
+	t.Run("should replace colors", func(t *testing.T) {
+		source := newImage(openGL, [][]image.Color{
+			{red, green},
+			{blue, white},
+		})
+		target := openGL.NewImage(2, 2)
+		lookup := glpalette.NewLookup(openGL,
+			palette.Palette{red, blue},
+			palette.Palette{white, green})
+		// when
+		swap.SwapSourceToTarget(source.WholeImageSelection(), lookup.WholeImageSelection(), target.WholeImageSelection())
+		// then
+		assertColors(t, target, [][]image.Color{
+			{white, green},
+			{green, white},
+		})
+	})
+
+	t.Run("should use lookup selection", func(t *testing.T) {
+		source := newImage(openGL, [][]image.Color{
+			{red, green, blue},
+		})
+		target := openGL.NewImage(3, 1)
+		lookup := newImage(openGL, [][]image.Color{
+			{red, red, red},
+			{red, green, blue},
+			{white, white, white},
+			{red, red, red},
+		})
+		// when
+		swap.SwapSourceToTarget(source.WholeImageSelection(), lookup.Selection(1, 1).WithSize(2, 2), target.WholeImageSelection())
+		// then
+		assertColors(t, target, [][]image.Color{
+			{red, white, white},
+		})
+	})
+
+	t.Run("should draw into target selection", func(t *testing.T) {
+		source := newImage(openGL, [][]image.Color{
+			{image.Transparent, red, green},
+		})
+		target := openGL.NewImage(3, 2)
+		lookup := glpalette.NewLookup(openGL, palette.Palette{red}, palette.Palette{blue})
+		// when
+		swap.SwapSourceToTarget(source.Selection(1, 0).WithSize(2, 1), lookup.WholeImageSelection(), target.Selection(1, 1))
+		// then
+		assertColors(t, target, [][]image.Color{
+			{image.Transparent, image.Transparent, image.Transparent},
+			{image.Transparent, blue, green},
+		})
+	})
+}
+
+func assertColors(t *testing.T, img *image.Image, expectedColorLines [][]image.Color) {
+	selection := img.WholeImageSelection()
+	for y := 0; y < selection.Height(); y++ {
+		expectedColorLine := expectedColorLines[y]
+		for x := 0; x < selection.Width(); x++ {
+			color := selection.Color(x, y)
+			assert.Equal(t, expectedColorLine[x], color, "position (%d,%d)", x, y)
+		}
+	}
+}
+
+func newImage(gl *glfw.OpenGL, pixels [][]image.Color) *image.Image {
+	width := len(pixels[0])
+	height := len(pixels)
+	img := gl.NewImage(width, height)
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, pixels[y][x])
+		}
+	}
+	return img
+}
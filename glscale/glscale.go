@@ -0,0 +1,231 @@
+// Package glscale provides tools for upscaling selections by integer factors
+// using video card. Results are the same as for tools in scale package.
+// Smooth2x is available in scale package only.
+package glscale
+
+import (
+	"fmt"
+
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/image"
+)
+
+// NewNearest creates a tool which scales selection using nearest-neighbour
+// algorithm. Each source pixel becomes a square of factor x factor pixels.
+// Will panic when context is nil or factor is lower than 1.
+func NewNearest(context *gl.Context, factor int) (*Tool, error) {
+	if factor < 1 {
+		panic("factor lower than 1")
+	}
+	return newTool(context, factor, `return E;`)
+}
+
+// NewScale2x creates a tool which scales selection 2 times using Scale2x
+// algorithm (aka EPX or AdvMAME2x). See scale.NewScale2x.
+func NewScale2x(context *gl.Context) (*Tool, error) {
+	return newTool(context, 2, `
+		if (B != H && D != F) {
+			if (block == ivec2(0, 0) && D == B) return D;
+			if (block == ivec2(1, 0) && B == F) return F;
+			if (block == ivec2(0, 1) && D == H) return D;
+			if (block == ivec2(1, 1) && H == F) return F;
+		}
+		return E;`)
+}
+
+// NewScale3x creates a tool which scales selection 3 times using Scale3x
+// algorithm (aka AdvMAME3x). See scale.NewScale3x.
+func NewScale3x(context *gl.Context) (*Tool, error) {
+	return newTool(context, 3, `
+		if (B == H || D == F) {
+			return E;
+		}
+		switch (block.y * 3 + block.x) {
+		case 0:
+			return D == B ? D : E;
+		case 1:
+			return (D == B && E != C) || (B == F && E != A) ? B : E;
+		case 2:
+			return B == F ? F : E;
+		case 3:
+			return (D == B && E != G) || (D == H && E != A) ? D : E;
+		case 5:
+			return (B == F && E != I) || (H == F && E != C) ? F : E;
+		case 6:
+			return D == H ? D : E;
+		case 7:
+			return (D == H && E != I) || (H == F && E != G) ? H : E;
+		case 8:
+			return H == F ? F : E;
+		}
+		return E;`)
+}
+
+const vertexShaderSrc = `
+#version 330 core
+	
+layout(location = 0) in vec2 xy;
+layout(location = 1) in vec2 st;
+out vec2 interpolatedST;
+
+void main() {
+	gl_Position = vec4(xy, 0.0, 1.0);
+	interpolatedST = st;
+}
+`
+
+const fragmentShaderSrc = `
+#version 330 core
+
+uniform sampler2D tex;
+// factor is a constant, not a uniform, because drivers remove uniforms which
+// are not used and scale function may not use block at all
+const int factor = %d;
+// source selection edges in texels (inclusive)
+uniform ivec2 sourceMin;
+uniform ivec2 sourceMax;
+in vec2 interpolatedST;
+out vec4 color;
+
+// fetch returns color of the texel. Neighbours outside the source selection
+// are copies of the edge pixels. Texels outside the image are transparent.
+vec4 fetch(ivec2 position) {
+	position = clamp(position, sourceMin, sourceMax);
+	ivec2 size = textureSize(tex, 0);
+	if (position.x < 0 || position.y < 0 || position.x >= size.x || position.y >= size.y) {
+		return vec4(0.0);
+	}
+	return texelFetch(tex, position, 0);
+}
+
+// scale returns color of the pixel at position block (counted from top-left)
+// inside the square of factor x factor pixels created from source pixel E:
+//
+//	A B C
+//	D E F
+//	G H I
+vec4 scale(ivec2 block, vec4 A, vec4 B, vec4 C, vec4 D, vec4 E, vec4 F, vec4 G, vec4 H, vec4 I) {
+	%s
+}
+
+void main() {
+	vec2 position = interpolatedST * vec2(textureSize(tex, 0));
+	ivec2 texel = ivec2(floor(position));
+	ivec2 block = ivec2(floor(fract(position) * float(factor)));
+	// texture rows are stored bottom-up
+	block.y = factor - 1 - block.y;
+	color = scale(block,
+		fetch(texel + ivec2(-1, 1)), fetch(texel + ivec2(0, 1)), fetch(texel + ivec2(1, 1)),
+		fetch(texel + ivec2(-1, 0)), fetch(texel), fetch(texel + ivec2(1, 0)),
+		fetch(texel + ivec2(-1, -1)), fetch(texel + ivec2(0, -1)), fetch(texel + ivec2(1, -1)));
+}
+`
+
+func newTool(context *gl.Context, factor int, scaleSrc string) (*Tool, error) {
+	if context == nil {
+		panic("nil context")
+	}
+	vertexShader, err := context.CompileVertexShader(vertexShaderSrc)
+	if err != nil {
+		return nil, err
+	}
+	fragmentShader, err := context.CompileFragmentShader(fmt.Sprintf(fragmentShaderSrc, factor, scaleSrc))
+	if err != nil {
+		return nil, err
+	}
+	program, err := context.LinkProgram(vertexShader, fragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	vertexBuffer := context.NewFloatVertexBuffer(16, gl.DynamicDraw)
+	vertexArray := context.NewVertexArray(gl.VertexLayout{gl.Vec2, gl.Vec2})
+	vertexArray.Set(0, gl.VertexBufferPointer{Offset: 0, Stride: 4, Buffer: vertexBuffer})
+	vertexArray.Set(1, gl.VertexBufferPointer{Offset: 2, Stride: 4, Buffer: vertexBuffer})
+	command := &scaleCommand{
+		vertexBuffer: vertexBuffer,
+		vertexArray:  vertexArray,
+		factor:       factor,
+	}
+	return &Tool{
+		command:      program.AcceleratedCommand(command),
+		scaleCommand: command,
+	}, nil
+}
+
+type scaleCommand struct {
+	vertexBuffer *gl.FloatVertexBuffer
+	vertexArray  *gl.VertexArray
+	factor       int
+	// visibleWidth and visibleHeight are fractions of the scaled source
+	// visible in the target image
+	visibleWidth, visibleHeight float32
+}
+
+func (c *scaleCommand) RunGL(renderer *gl.Renderer, selections []image.AcceleratedImageSelection) {
+	source := selections[0]
+	renderer.BindTexture(0, "tex", source.Image)
+	var (
+		location    = source.Location
+		imageWidth  = float32(source.Image.Width())
+		imageHeight = float32(source.Image.Height())
+		bottomY     = source.Image.Height() - location.Y - location.Height
+	)
+	renderer.SetIVec2("sourceMin", int32(location.X), int32(bottomY))
+	renderer.SetIVec2("sourceMax",
+		int32(location.X+location.Width-1),
+		int32(bottomY+location.Height-1))
+	left := float32(location.X) / imageWidth
+	right := left + float32(location.Width)*c.visibleWidth/imageWidth
+	top := (imageHeight - float32(location.Y)) / imageHeight
+	bottom := top - float32(location.Height)*c.visibleHeight/imageHeight
+	// xy -> st
+	vertices := []float32{
+		-1, 1, left, top,
+		1, 1, right, top,
+		1, -1, right, bottom,
+		-1, -1, left, bottom,
+	}
+	c.vertexBuffer.Upload(0, vertices)
+	renderer.DrawArrays(c.vertexArray, gl.TriangleFan, 0, 4)
+}
+
+// Tool upscales source selection using video card and puts the result into
+// the target. Use constructors such as NewNearest or NewScale2x to create it.
+type Tool struct {
+	command      *gl.AcceleratedCommand
+	scaleCommand *scaleCommand
+}
+
+// Factor returns how many times the source is scaled in each direction.
+func (t *Tool) Factor() int {
+	return t.scaleCommand.factor
+}
+
+// ScaleSourceToTarget scales source and puts the result into the target
+// selection. Target pixels are replaced without blending. Pixels outside the
+// source image are treated as transparent.
+//
+// Only position of the target Selection is used. Target size is the source
+// size multiplied by Factor.
+func (t *Tool) ScaleSourceToTarget(source, target image.Selection) {
+	factor := t.scaleCommand.factor
+	width, height := source.Width()*factor, source.Height()*factor
+	if width <= 0 || height <= 0 {
+		return
+	}
+	// viewport is clamped to the target image by gl package, therefore
+	// the scaled source must be clamped too
+	visibleWidth, visibleHeight := width, height
+	if visibleWidth+target.ImageX() > target.Image().Width() {
+		visibleWidth = target.Image().Width() - target.ImageX()
+	}
+	if visibleHeight+target.ImageY() > target.Image().Height() {
+		visibleHeight = target.Image().Height() - target.ImageY()
+	}
+	if visibleWidth <= 0 || visibleHeight <= 0 {
+		return
+	}
+	t.scaleCommand.visibleWidth = float32(visibleWidth) / float32(width)
+	t.scaleCommand.visibleHeight = float32(visibleHeight) / float32(height)
+	target.WithSize(visibleWidth, visibleHeight).Modify(t.command, source)
+}
@@ -0,0 +1,39 @@
+package glscale_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/glscale"
+)
+
+func TestNewNearest(t *testing.T) {
+	t.Run("should panic when context is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = glscale.NewNearest(nil, 2)
+		})
+	})
+	t.Run("should panic when factor is lower than 1", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = glscale.NewNearest(&gl.Context{}, 0)
+		})
+	})
+}
+
+func TestNewScale2x(t *testing.T) {
+	t.Run("should panic when context is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = glscale.NewScale2x(nil)
+		})
+	})
+}
+
+func TestNewScale3x(t *testing.T) {
+	t.Run("should panic when context is nil", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = glscale.NewScale3x(nil)
+		})
+	})
+}
@@ -0,0 +1,33 @@
+// Package scaletools lists scaling tools available both in scale and glscale
+// packages. It is used by tests comparing GPU tools with their CPU twins.
+package scaletools
+
+import (
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/glscale"
+	"github.com/elgopher/pixiq/scale"
+)
+
+// Tool creates CPU and GPU tools scaling the same way.
+type Tool struct {
+	CPUTool func() *scale.Tool
+	GPUTool func(context *gl.Context) (*glscale.Tool, error)
+}
+
+// All contains tools implemented by scale.Tool and glscale.Tool, by name.
+var All = map[string]Tool{
+	"Nearest 1": {
+		CPUTool: func() *scale.Tool { return scale.NewNearest(1) },
+		GPUTool: func(context *gl.Context) (*glscale.Tool, error) {
+			return glscale.NewNearest(context, 1)
+		},
+	},
+	"Nearest 3": {
+		CPUTool: func() *scale.Tool { return scale.NewNearest(3) },
+		GPUTool: func(context *gl.Context) (*glscale.Tool, error) {
+			return glscale.NewNearest(context, 3)
+		},
+	},
+	"Scale2x": {CPUTool: scale.NewScale2x, GPUTool: glscale.NewScale2x},
+	"Scale3x": {CPUTool: scale.NewScale3x, GPUTool: glscale.NewScale3x},
+}
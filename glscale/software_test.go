@@ -0,0 +1,52 @@
+package glscale_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/gl/software"
+	"github.com/elgopher/pixiq/glscale/internal/scaletools"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/internal/imagetest"
+)
+
+func TestTool_ScaleSourceToTarget(t *testing.T) {
+	context := gl.NewContext(software.NewAPI())
+
+	for name, tool := range scaletools.All {
+		t.Run(name, func(t *testing.T) {
+			gpuTool, err := tool.GPUTool(context)
+			require.NoError(t, err)
+			cpuTool := tool.CPUTool()
+			require.Equal(t, cpuTool.Factor(), gpuTool.Factor())
+
+			t.Run("should give the same results as CPU tool", func(t *testing.T) {
+				tests := map[string]struct {
+					sourceX, sourceY int
+					targetX, targetY int
+				}{
+					"whole images":                   {},
+					"source outside image":           {sourceX: -2, sourceY: 3},
+					"target outside top left":        {targetX: -5, targetY: -4},
+					"target outside bottom right":    {targetX: 7, targetY: 11},
+					"target partially outside right": {targetX: 19, targetY: 1},
+				}
+				for name, test := range tests {
+					t.Run(name, func(t *testing.T) {
+						source := imagetest.NewFewColorsImage(context, 8, 7)
+						cpuTarget := image.New(context.NewAcceleratedImage(24, 24))
+						gpuTarget := image.New(context.NewAcceleratedImage(24, 24))
+						sourceSelection := source.Selection(test.sourceX, test.sourceY).WithSize(8, 7)
+						// when
+						cpuTool.ScaleSourceToTarget(sourceSelection, cpuTarget.Selection(test.targetX, test.targetY))
+						gpuTool.ScaleSourceToTarget(sourceSelection, gpuTarget.Selection(test.targetX, test.targetY))
+						// then
+						imagetest.AssertEqualImages(t, cpuTarget, gpuTarget)
+					})
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package image
+
+import "math"
+
+// Lerp linearly interpolates between colors a and b. When t is 0 the color a
+// is returned, when t is 1 the color b is returned. t is clamped to range
+// 0.0 to 1.0. Interpolation is done on premultiplied components, therefore
+// transparent colors do not darken the result.
+func Lerp(a, b Color, t float64) Color {
+	t = clamp(t)
+	return Color{
+		r: lerp(a.r, b.r, t),
+		g: lerp(a.g, b.g, t),
+		b: lerp(a.b, b.b, t),
+		a: lerp(a.a, b.a, t),
+	}
+}
+
+func lerp(a, b byte, t float64) byte {
+	return byte(math.Round(float64(a) + (float64(b)-float64(a))*t))
+}
+
+// Brighten returns a color with amount added to each RGB component (not
+// premultiplied by alpha). Amount is in range -1.0 (black) to 1.0 (white).
+// Alpha is not changed.
+func (c Color) Brighten(amount float64) Color {
+	r, g, b := c.straight()
+	return fromStraight(r+amount, g+amount, b+amount, c.a)
+}
+
+// Contrast returns a color with contrast changed by a given factor. Factor 1.0
+// does not change the color, factor 0.0 returns gray, factors higher than 1.0
+// increase the contrast. Alpha is not changed.
+func (c Color) Contrast(factor float64) Color {
+	r, g, b := c.straight()
+	contrast := func(v float64) float64 {
+		return (v-0.5)*factor + 0.5
+	}
+	return fromStraight(contrast(r), contrast(g), contrast(b), c.a)
+}
+
+// Saturate returns a color with saturation changed by a given factor. Factor
+// 1.0 does not change the color, factor 0.0 returns grayscale color, factors
+// higher than 1.0 increase the saturation. Alpha is not changed.
+func (c Color) Saturate(factor float64) Color {
+	r, g, b := c.straight()
+	// Rec. 709 luma
+	luma := 0.2126*r + 0.7152*g + 0.0722*b
+	saturate := func(v float64) float64 {
+		return luma + (v-luma)*factor
+	}
+	return fromStraight(saturate(r), saturate(g), saturate(b), c.a)
+}
+
+// DeltaE returns perceptual distance between colors using CIEDE2000 formula.
+// Value 0 means that colors are the same, value around 1.0 is the smallest
+// difference perceptible by human eye, value 100 is a difference between
+// black and white. Alpha is ignored.
+func DeltaE(c1, c2 Color) float64 {
+	l1, a1, b1 := c1.Lab()
+	l2, a2, b2 := c2.Lab()
+
+	cAvg := (math.Hypot(a1, b1) + math.Hypot(a2, b2)) / 2
+	cAvg7 := math.Pow(cAvg, 7)
+	g := 0.5 * (1 - math.Sqrt(cAvg7/(cAvg7+math.Pow(25, 7))))
+	a1p, a2p := a1*(1+g), a2*(1+g)
+	c1p, c2p := math.Hypot(a1p, b1), math.Hypot(a2p, b2)
+	h1p, h2p := hueAngle(b1, a1p), hueAngle(b2, a2p)
+
+	deltaLp := l2 - l1
+	deltaCp := c2p - c1p
+	var deltahp float64
+	if c1p*c2p != 0 {
+		deltahp = h2p - h1p
+		if deltahp > 180 {
+			deltahp -= 360
+		} else if deltahp < -180 {
+			deltahp += 360
+		}
+	}
+	deltaHp := 2 * math.Sqrt(c1p*c2p) * math.Sin(radians(deltahp/2))
+
+	lpAvg := (l1 + l2) / 2
+	cpAvg := (c1p + c2p) / 2
+	hpAvg := h1p + h2p
+	if c1p*c2p != 0 {
+		switch {
+		case math.Abs(h1p-h2p) <= 180:
+			hpAvg /= 2
+		case h1p+h2p < 360:
+			hpAvg = (hpAvg + 360) / 2
+		default:
+			hpAvg = (hpAvg - 360) / 2
+		}
+	}
+
+	t := 1 - 0.17*math.Cos(radians(hpAvg-30)) +
+		0.24*math.Cos(radians(2*hpAvg)) +
+		0.32*math.Cos(radians(3*hpAvg+6)) -
+		0.20*math.Cos(radians(4*hpAvg-63))
+	deltaTheta := 30 * math.Exp(-math.Pow((hpAvg-275)/25, 2))
+	cpAvg7 := math.Pow(cpAvg, 7)
+	rc := 2 * math.Sqrt(cpAvg7/(cpAvg7+math.Pow(25, 7)))
+	lpAvg50 := (lpAvg - 50) * (lpAvg - 50)
+	sl := 1 + 0.015*lpAvg50/math.Sqrt(20+lpAvg50)
+	sc := 1 + 0.045*cpAvg
+	sh := 1 + 0.015*cpAvg*t
+	rt := -math.Sin(radians(2*deltaTheta)) * rc
+
+	dl, dc, dh := deltaLp/sl, deltaCp/sc, deltaHp/sh
+	return math.Sqrt(dl*dl + dc*dc + dh*dh + rt*dc*dh)
+}
+
+// hueAngle returns angle in degrees in range 0.0 to 360.0
+func hueAngle(b, a float64) float64 {
+	if a == 0 && b == 0 {
+		return 0
+	}
+	h := math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
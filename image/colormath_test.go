@@ -0,0 +1,121 @@
+package image_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+func TestLerp(t *testing.T) {
+	var (
+		from = image.RGBA(0, 100, 200, 255)
+		to   = image.RGBA(100, 0, 100, 155)
+	)
+	tests := map[string]struct {
+		t        float64
+		expected image.Color
+	}{
+		"t=0":    {t: 0, expected: from},
+		"t=1":    {t: 1, expected: to},
+		"t=0.5":  {t: 0.5, expected: image.RGBA(50, 50, 150, 205)},
+		"t=0.25": {t: 0.25, expected: image.RGBA(25, 75, 175, 230)},
+		"t<0":    {t: -1, expected: from},
+		"t>1":    {t: 2, expected: to},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, image.Lerp(from, to, test.t))
+		})
+	}
+
+	t.Run("should not darken when interpolating with transparent", func(t *testing.T) {
+		// when
+		color := image.Lerp(image.RGB(255, 0, 0), image.Transparent, 0.5)
+		// then
+		r, g, b, _ := color.NRGBA()
+		assert.Equal(t, [3]byte{255, 0, 0}, [3]byte{r, g, b})
+	})
+}
+
+func TestColor_Brighten(t *testing.T) {
+	tests := map[string]struct {
+		color    image.Color
+		amount   float64
+		expected image.Color
+	}{
+		"zero":            {color: image.RGB(10, 20, 30), amount: 0, expected: image.RGB(10, 20, 30)},
+		"brighter":        {color: image.RGB(10, 20, 30), amount: 0.2, expected: image.RGB(61, 71, 81)},
+		"darker":          {color: image.RGB(100, 20, 30), amount: -0.2, expected: image.RGB(49, 0, 0)},
+		"white":           {color: image.RGB(10, 20, 30), amount: 1, expected: image.RGB(255, 255, 255)},
+		"black":           {color: image.RGB(10, 20, 30), amount: -1, expected: image.RGB(0, 0, 0)},
+		"semitransparent": {color: image.NRGBA(0, 0, 0, 128), amount: 1, expected: image.RGBA(128, 128, 128, 128)},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.color.Brighten(test.amount))
+		})
+	}
+}
+
+func TestColor_Contrast(t *testing.T) {
+	tests := map[string]struct {
+		color    image.Color
+		factor   float64
+		expected image.Color
+	}{
+		"unchanged":     {color: image.RGB(10, 128, 200), factor: 1, expected: image.RGB(10, 128, 200)},
+		"gray":          {color: image.RGB(10, 128, 200), factor: 0, expected: image.RGB(128, 128, 128)},
+		"more contrast": {color: image.RGB(64, 128, 191), factor: 2, expected: image.RGB(0, 129, 255)},
+		"less contrast": {color: image.RGB(0, 128, 255), factor: 0.5, expected: image.RGB(64, 128, 191)},
+		"keeps alpha":   {color: image.RGBA(0, 0, 0, 128), factor: 0, expected: image.RGBA(64, 64, 64, 128)},
+		"transparent":   {color: image.Transparent, factor: 2, expected: image.Transparent},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.color.Contrast(test.factor))
+		})
+	}
+}
+
+func TestColor_Saturate(t *testing.T) {
+	tests := map[string]struct {
+		color    image.Color
+		factor   float64
+		expected image.Color
+	}{
+		"unchanged":      {color: image.RGB(10, 128, 200), factor: 1, expected: image.RGB(10, 128, 200)},
+		"grayscale":      {color: image.RGB(255, 0, 0), factor: 0, expected: image.RGB(54, 54, 54)},
+		"gray":           {color: image.RGB(100, 100, 100), factor: 3, expected: image.RGB(100, 100, 100)},
+		"more saturated": {color: image.RGB(150, 100, 100), factor: 2, expected: image.RGB(189, 89, 89)},
+		"keeps alpha":    {color: image.NRGBA(255, 0, 0, 128), factor: 0, expected: image.NRGBA(54, 54, 54, 128)},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.color.Saturate(test.factor))
+		})
+	}
+}
+
+func TestDeltaE(t *testing.T) {
+	tests := map[string]struct {
+		c1, c2   image.Color
+		expected float64
+	}{
+		"same colors":     {c1: image.RGB(10, 20, 30), c2: image.RGB(10, 20, 30), expected: 0},
+		"black and white": {c1: image.RGB(0, 0, 0), c2: image.RGB(255, 255, 255), expected: 100},
+		"red and blue":    {c1: image.RGB(255, 0, 0), c2: image.RGB(0, 0, 255), expected: 52.8814},
+		"red and green":   {c1: image.RGB(255, 0, 0), c2: image.RGB(0, 255, 0), expected: 86.6082},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, test.expected, image.DeltaE(test.c1, test.c2), 0.001)
+			assert.InDelta(t, test.expected, image.DeltaE(test.c2, test.c1), 0.001)
+		})
+	}
+
+	t.Run("should ignore alpha", func(t *testing.T) {
+		assert.InDelta(t, 0, image.DeltaE(image.RGB(255, 0, 0), image.NRGBA(255, 0, 0, 128)), 0.001)
+	})
+}
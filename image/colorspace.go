@@ -0,0 +1,236 @@
+package image
+
+import "math"
+
+// NRGBA returns color components not premultiplied by alpha (aka straight
+// alpha). RGB components of fully transparent color are zero.
+func (c Color) NRGBA() (byte, byte, byte, byte) {
+	return div(c.r, c.a), div(c.g, c.a), div(c.b, c.a), c.a
+}
+
+// div is round(a * 255 / b) clamped to 255. It is an inverse of mul.
+func div(a, b byte) byte {
+	if b == 0 {
+		return 0
+	}
+	v := (int(a)*255 + int(b)/2) / int(b)
+	if v > 255 {
+		return 255
+	}
+	return byte(v)
+}
+
+// straight returns not premultiplied components in range 0.0 to 1.0
+func (c Color) straight() (r, g, b float64) {
+	if c.a == 0 {
+		return 0, 0, 0
+	}
+	a := float64(c.a)
+	return float64(c.r) / a, float64(c.g) / a, float64(c.b) / a
+}
+
+// fromStraight creates Color from not premultiplied components in range 0.0
+// to 1.0. Components are clamped.
+func fromStraight(r, g, b float64, a byte) Color {
+	return NRGBA(toByte(r), toByte(g), toByte(b), a)
+}
+
+func toByte(v float64) byte {
+	return byte(math.Round(clamp(v) * 255))
+}
+
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// HSV returns hue, saturation and value of the color. Hue is in degrees in
+// range 0.0 to 360.0 (exclusive), saturation and value are in range
+// 0.0 to 1.0. Hue of gray colors is 0. Alpha is ignored.
+func (c Color) HSV() (h, s, v float64) {
+	r, g, b := c.straight()
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	v = max
+	if max > 0 {
+		s = (max - min) / max
+	}
+	h = hue(r, g, b, max, min)
+	return
+}
+
+// HSVA creates Color using hue (in degrees), saturation, value and alpha.
+// Hue is wrapped to range 0.0 to 360.0, saturation and value are clamped to
+// range 0.0 to 1.0.
+func HSVA(h, s, v float64, a byte) Color {
+	s, v = clamp(s), clamp(v)
+	chroma := v * s
+	r, g, b := hueToRGB(h, chroma)
+	m := v - chroma
+	return fromStraight(r+m, g+m, b+m, a)
+}
+
+// HSL returns hue, saturation and lightness of the color. Hue is in degrees in
+// range 0.0 to 360.0 (exclusive), saturation and lightness are in range
+// 0.0 to 1.0. Hue of gray colors is 0. Alpha is ignored.
+func (c Color) HSL() (h, s, l float64) {
+	r, g, b := c.straight()
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	l = (max + min) / 2
+	if max != min {
+		s = (max - min) / (1 - math.Abs(2*l-1))
+	}
+	h = hue(r, g, b, max, min)
+	return
+}
+
+// HSLA creates Color using hue (in degrees), saturation, lightness and alpha.
+// Hue is wrapped to range 0.0 to 360.0, saturation and lightness are clamped
+// to range 0.0 to 1.0.
+func HSLA(h, s, l float64, a byte) Color {
+	s, l = clamp(s), clamp(l)
+	chroma := (1 - math.Abs(2*l-1)) * s
+	r, g, b := hueToRGB(h, chroma)
+	m := l - chroma/2
+	return fromStraight(r+m, g+m, b+m, a)
+}
+
+func hue(r, g, b, max, min float64) float64 {
+	delta := max - min
+	if delta == 0 {
+		return 0
+	}
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/delta, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// hueToRGB returns RGB components of the color with given hue and chroma
+// without lightness
+func hueToRGB(h, chroma float64) (r, g, b float64) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	sector := h / 60
+	x := chroma * (1 - math.Abs(math.Mod(sector, 2)-1))
+	switch int(sector) {
+	case 0:
+		return chroma, x, 0
+	case 1:
+		return x, chroma, 0
+	case 2:
+		return 0, chroma, x
+	case 3:
+		return 0, x, chroma
+	case 4:
+		return x, 0, chroma
+	default:
+		return chroma, 0, x
+	}
+}
+
+// LinearRGB returns color components in linear RGB color space (not
+// premultiplied by alpha). Components are in range 0.0 to 1.0. Image colors
+// are stored in sRGB color space, which is not linear: doubling the component
+// value does not double the light intensity. Linear RGB should be used for
+// physically correct mixing of colors. Alpha is ignored.
+func (c Color) LinearRGB() (r, g, b float64) {
+	r, g, b = c.straight()
+	return toLinear(r), toLinear(g), toLinear(b)
+}
+
+// LinearRGBA creates Color using components in linear RGB color space (not
+// premultiplied by alpha) and alpha. Components are clamped to range
+// 0.0 to 1.0.
+func LinearRGBA(r, g, b float64, a byte) Color {
+	return fromStraight(toSRGB(r), toSRGB(g), toSRGB(b), a)
+}
+
+func toLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func toSRGB(v float64) float64 {
+	v = clamp(v)
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// D65 white point
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+// Lab returns color in CIELAB color space (using D65 white point). Lightness
+// is in range 0.0 to 100.0, a and b are roughly in range -128.0 to 128.0.
+// CIELAB is designed to be perceptually uniform, that is the same distance
+// between colors is perceived as the same difference. Alpha is ignored.
+func (c Color) Lab() (l, a, b float64) {
+	x, y, z := c.xyz()
+	fx, fy, fz := labF(x/whiteX), labF(y/whiteY), labF(z/whiteZ)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+// LabA creates Color using components in CIELAB color space (using D65 white
+// point) and alpha. Colors outside the sRGB gamut are clamped.
+func LabA(l, a, b float64, alpha byte) Color {
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - b/200
+	x, y, z := labFInv(fx)*whiteX, labFInv(fy)*whiteY, labFInv(fz)*whiteZ
+	return LinearRGBA(
+		3.2404542*x-1.5371385*y-0.4985314*z,
+		-0.9692660*x+1.8760108*y+0.0415560*z,
+		0.0556434*x-0.2040259*y+1.0572252*z,
+		alpha)
+}
+
+// xyz returns color in CIE XYZ color space
+func (c Color) xyz() (x, y, z float64) {
+	r, g, b := c.LinearRGB()
+	x = 0.4124564*r + 0.3575761*g + 0.1804375*b
+	y = 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z = 0.0193339*r + 0.1191920*g + 0.9503041*b
+	return
+}
+
+const labEpsilon = 216.0 / 24389.0
+
+func labF(t float64) float64 {
+	if t > labEpsilon {
+		return math.Cbrt(t)
+	}
+	return (24389.0/27.0*t + 16) / 116
+}
+
+func labFInv(t float64) float64 {
+	if t3 := t * t * t; t3 > labEpsilon {
+		return t3
+	}
+	return (116*t - 16) * 27.0 / 24389.0
+}
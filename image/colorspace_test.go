@@ -0,0 +1,216 @@
+package image_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+// colors used for round-trip tests
+var roundTripColors = []image.Color{
+	image.Transparent,
+	image.RGB(0, 0, 0),
+	image.RGB(255, 255, 255),
+	image.RGB(255, 0, 0),
+	image.RGB(12, 200, 77),
+	image.RGB(128, 128, 128),
+	image.NRGBA(100, 150, 200, 128),
+	image.NRGBA(255, 255, 0, 255),
+}
+
+func TestColor_NRGBA(t *testing.T) {
+	t.Run("should return components not premultiplied by alpha", func(t *testing.T) {
+		tests := map[image.Color][4]byte{
+			image.Transparent:          {0, 0, 0, 0},
+			image.RGBA(0, 0, 0, 0):     {0, 0, 0, 0},
+			image.RGB(10, 20, 30):      {10, 20, 30, 255},
+			image.RGBA(50, 0, 10, 100): {128, 0, 26, 100},
+			image.RGBA(1, 1, 1, 2):     {128, 128, 128, 2},
+			image.RGBA(200, 0, 0, 100): {255, 0, 0, 100},
+		}
+		for color, expected := range tests {
+			t.Run(color.String(), func(t *testing.T) {
+				// when
+				r, g, b, a := color.NRGBA()
+				// then
+				assert.Equal(t, expected, [4]byte{r, g, b, a})
+			})
+		}
+	})
+
+	t.Run("should be an inverse of NRGBA", func(t *testing.T) {
+		for a := 1; a < 256; a += 7 {
+			for v := 0; v < 256; v += 5 {
+				color := image.NRGBA(byte(v), byte(v), byte(v), byte(a))
+				r, g, b, alpha := color.NRGBA()
+				assert.Equal(t, color, image.NRGBA(r, g, b, alpha))
+			}
+		}
+	})
+}
+
+func TestColor_HSV(t *testing.T) {
+	t.Run("should convert color to HSV", func(t *testing.T) {
+		tests := map[image.Color][3]float64{
+			image.RGB(0, 0, 0):         {0, 0, 0},
+			image.RGB(255, 255, 255):   {0, 0, 1},
+			image.RGB(255, 0, 0):       {0, 1, 1},
+			image.RGB(0, 255, 0):       {120, 1, 1},
+			image.RGB(0, 0, 255):       {240, 1, 1},
+			image.RGB(255, 0, 255):     {300, 1, 1},
+			image.RGB(0, 0, 128):       {240, 1, 128.0 / 255},
+			image.RGBA(0, 0, 128, 128): {240, 1, 1},
+		}
+		for color, expected := range tests {
+			t.Run(color.String(), func(t *testing.T) {
+				// when
+				h, s, v := color.HSV()
+				// then
+				assert.InDeltaSlice(t, expected[:], []float64{h, s, v}, 0.0001)
+			})
+		}
+	})
+}
+
+func TestHSVA(t *testing.T) {
+	t.Run("should create color", func(t *testing.T) {
+		tests := map[string]struct {
+			h, s, v  float64
+			a        byte
+			expected image.Color
+		}{
+			"red":             {h: 0, s: 1, v: 1, a: 255, expected: image.RGB(255, 0, 0)},
+			"yellow":          {h: 60, s: 1, v: 1, a: 255, expected: image.RGB(255, 255, 0)},
+			"wrapped hue":     {h: 480, s: 1, v: 1, a: 255, expected: image.RGB(0, 255, 0)},
+			"negative hue":    {h: -120, s: 1, v: 1, a: 255, expected: image.RGB(0, 0, 255)},
+			"gray":            {h: 100, s: 0, v: 0.5, a: 255, expected: image.RGB(128, 128, 128)},
+			"clamped":         {h: 0, s: 2, v: -1, a: 255, expected: image.RGB(0, 0, 0)},
+			"semitransparent": {h: 0, s: 1, v: 1, a: 128, expected: image.RGBA(128, 0, 0, 128)},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				assert.Equal(t, test.expected, image.HSVA(test.h, test.s, test.v, test.a))
+			})
+		}
+	})
+
+	t.Run("should be an inverse of Color.HSV", func(t *testing.T) {
+		for _, color := range roundTripColors {
+			h, s, v := color.HSV()
+			assert.Equal(t, color, image.HSVA(h, s, v, color.A()))
+		}
+	})
+}
+
+func TestColor_HSL(t *testing.T) {
+	t.Run("should convert color to HSL", func(t *testing.T) {
+		tests := map[image.Color][3]float64{
+			image.RGB(0, 0, 0):       {0, 0, 0},
+			image.RGB(255, 255, 255): {0, 0, 1},
+			image.RGB(255, 0, 0):     {0, 1, 0.5},
+			image.RGB(0, 255, 0):     {120, 1, 0.5},
+			image.RGB(255, 128, 128): {0, 1, 383.0 / 510},
+		}
+		for color, expected := range tests {
+			t.Run(color.String(), func(t *testing.T) {
+				// when
+				h, s, l := color.HSL()
+				// then
+				assert.InDeltaSlice(t, expected[:], []float64{h, s, l}, 0.0001)
+			})
+		}
+	})
+}
+
+func TestHSLA(t *testing.T) {
+	t.Run("should create color", func(t *testing.T) {
+		tests := map[string]struct {
+			h, s, l  float64
+			a        byte
+			expected image.Color
+		}{
+			"red":     {h: 0, s: 1, l: 0.5, a: 255, expected: image.RGB(255, 0, 0)},
+			"cyan":    {h: 180, s: 1, l: 0.5, a: 255, expected: image.RGB(0, 255, 255)},
+			"white":   {h: 180, s: 1, l: 1, a: 255, expected: image.RGB(255, 255, 255)},
+			"pink":    {h: 0, s: 1, l: 0.75, a: 255, expected: image.RGB(255, 128, 128)},
+			"clamped": {h: 0, s: -1, l: 2, a: 255, expected: image.RGB(255, 255, 255)},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				assert.Equal(t, test.expected, image.HSLA(test.h, test.s, test.l, test.a))
+			})
+		}
+	})
+
+	t.Run("should be an inverse of Color.HSL", func(t *testing.T) {
+		for _, color := range roundTripColors {
+			h, s, l := color.HSL()
+			assert.Equal(t, color, image.HSLA(h, s, l, color.A()))
+		}
+	})
+}
+
+func TestColor_LinearRGB(t *testing.T) {
+	t.Run("should convert color to linear RGB", func(t *testing.T) {
+		tests := map[image.Color][3]float64{
+			image.RGB(0, 0, 0):       {0, 0, 0},
+			image.RGB(255, 255, 255): {1, 1, 1},
+			image.RGB(128, 10, 0):    {0.2158605, 0.0030353, 0},
+		}
+		for color, expected := range tests {
+			t.Run(color.String(), func(t *testing.T) {
+				// when
+				r, g, b := color.LinearRGB()
+				// then
+				assert.InDeltaSlice(t, expected[:], []float64{r, g, b}, 0.0001)
+			})
+		}
+	})
+}
+
+func TestLinearRGBA(t *testing.T) {
+	t.Run("should clamp components", func(t *testing.T) {
+		assert.Equal(t, image.RGB(255, 0, 255), image.LinearRGBA(2, -1, 1, 255))
+	})
+
+	t.Run("should be an inverse of Color.LinearRGB", func(t *testing.T) {
+		for _, color := range roundTripColors {
+			r, g, b := color.LinearRGB()
+			assert.Equal(t, color, image.LinearRGBA(r, g, b, color.A()))
+		}
+	})
+}
+
+func TestColor_Lab(t *testing.T) {
+	t.Run("should convert color to CIELAB", func(t *testing.T) {
+		tests := map[image.Color][3]float64{
+			image.RGB(0, 0, 0):       {0, 0, 0},
+			image.RGB(255, 255, 255): {100, 0, 0},
+			image.RGB(255, 0, 0):     {53.2408, 80.0925, 67.2032},
+			image.RGB(0, 0, 255):     {32.2970, 79.1875, -107.8602},
+		}
+		for color, expected := range tests {
+			t.Run(color.String(), func(t *testing.T) {
+				// when
+				l, a, b := color.Lab()
+				// then
+				assert.InDeltaSlice(t, expected[:], []float64{l, a, b}, 0.01)
+			})
+		}
+	})
+}
+
+func TestLabA(t *testing.T) {
+	t.Run("should clamp colors outside the gamut", func(t *testing.T) {
+		assert.Equal(t, image.RGB(255, 255, 255), image.LabA(200, 0, 0, 255))
+	})
+
+	t.Run("should be an inverse of Color.Lab", func(t *testing.T) {
+		for _, color := range roundTripColors {
+			l, a, b := color.Lab()
+			assert.Equal(t, color, image.LabA(l, a, b, color.A()))
+		}
+	})
+}
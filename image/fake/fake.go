@@ -43,6 +43,28 @@ func (i *AcceleratedImage) Upload(pixels []image.Color) {
 	copy(i.pixels, pixels)
 }
 
+// UploadRegion send pixels of a given region to a container in RAM
+func (i *AcceleratedImage) UploadRegion(location image.AcceleratedImageLocation, pixels []image.Color) {
+	if !i.containsLocation(location) {
+		panic("location out of image bounds")
+	}
+	if len(pixels) != location.Width*location.Height {
+		panic("pixels slice is not of length location.Width*location.Height")
+	}
+	firstLine := i.height - location.Y - location.Height
+	for line := 0; line < location.Height; line++ {
+		start := (firstLine+line)*i.width + location.X
+		copy(i.pixels[start:start+location.Width], pixels[line*location.Width:])
+	}
+}
+
+func (i *AcceleratedImage) containsLocation(location image.AcceleratedImageLocation) bool {
+	return location.X >= 0 && location.Y >= 0 &&
+		location.Width >= 0 && location.Height >= 0 &&
+		location.X+location.Width <= i.width &&
+		location.Y+location.Height <= i.height
+}
+
 // Download fills output slice with image colors
 func (i *AcceleratedImage) Download(output []image.Color) {
 	if len(output) != i.width*i.height {
@@ -53,6 +75,21 @@ func (i *AcceleratedImage) Download(output []image.Color) {
 	}
 }
 
+// DownloadRegion fills output slice with colors of a given region
+func (i *AcceleratedImage) DownloadRegion(location image.AcceleratedImageLocation, output []image.Color) {
+	if !i.containsLocation(location) {
+		panic("location out of image bounds")
+	}
+	if len(output) != location.Width*location.Height {
+		panic("output slice is not of length location.Width*location.Height")
+	}
+	firstLine := i.height - location.Y - location.Height
+	for line := 0; line < location.Height; line++ {
+		start := (firstLine+line)*i.width + location.X
+		copy(output[line*location.Width:], i.pixels[start:start+location.Width])
+	}
+}
+
 // Width returns the number of pixels in a row.
 func (i *AcceleratedImage) Width() int {
 	return i.width
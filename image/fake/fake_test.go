@@ -176,6 +176,143 @@ func TestAcceleratedImage_Upload(t *testing.T) {
 	})
 }
 
+func TestAcceleratedImage_UploadRegion(t *testing.T) {
+	t.Run("should panic when location is out of image bounds", func(t *testing.T) {
+		tests := map[string]image.AcceleratedImageLocation{
+			"negative X":    {X: -1, Width: 1, Height: 1},
+			"negative Y":    {Y: -1, Width: 1, Height: 1},
+			"too big width": {Width: 3, Height: 1},
+			"too big X":     {X: 2, Width: 1, Height: 1},
+			"too big Y":     {Y: 1, Width: 1, Height: 1},
+		}
+		for name, location := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := fake.NewAcceleratedImage(2, 1)
+				input := make([]image.Color, location.Width*location.Height)
+				assert.Panics(t, func() {
+					img.UploadRegion(location, input)
+				})
+			})
+		}
+	})
+	t.Run("should panic when input slice is not of location width*height length", func(t *testing.T) {
+		img := fake.NewAcceleratedImage(2, 2)
+		input := make([]image.Color, 3)
+		assert.Panics(t, func() {
+			img.UploadRegion(image.AcceleratedImageLocation{Width: 2, Height: 2}, input)
+		})
+	})
+	t.Run("should upload colors of region", func(t *testing.T) {
+		var (
+			color0 = image.RGB(0, 0, 0)
+			color1 = image.RGB(1, 1, 1)
+			color2 = image.RGB(2, 2, 2)
+		)
+		tests := map[string]struct {
+			location      image.AcceleratedImageLocation
+			colors        []image.Color
+			expectedTable [][]image.Color
+		}{
+			"top-left pixel": {
+				location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+				colors:   []image.Color{color0},
+				expectedTable: [][]image.Color{
+					{image.Transparent, image.Transparent, image.Transparent},
+					{color0, image.Transparent, image.Transparent},
+				},
+			},
+			"bottom-right 2x1": {
+				location: image.AcceleratedImageLocation{X: 1, Y: 1, Width: 2, Height: 1},
+				colors:   []image.Color{color0, color1},
+				expectedTable: [][]image.Color{
+					{image.Transparent, color0, color1},
+					{image.Transparent, image.Transparent, image.Transparent},
+				},
+			},
+			"right 1x2": {
+				location: image.AcceleratedImageLocation{X: 2, Width: 1, Height: 2},
+				colors:   []image.Color{color1, color2},
+				expectedTable: [][]image.Color{
+					{image.Transparent, image.Transparent, color1},
+					{image.Transparent, image.Transparent, color2},
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := fake.NewAcceleratedImage(3, 2)
+				// when
+				img.UploadRegion(test.location, test.colors)
+				// then
+				assert.Equal(t, test.expectedTable, img.PixelsTable())
+			})
+		}
+	})
+}
+
+func TestAcceleratedImage_DownloadRegion(t *testing.T) {
+	t.Run("should panic when location is out of image bounds", func(t *testing.T) {
+		tests := map[string]image.AcceleratedImageLocation{
+			"negative X":     {X: -1, Width: 1, Height: 1},
+			"negative Y":     {Y: -1, Width: 1, Height: 1},
+			"too big height": {Width: 1, Height: 2},
+			"too big X":      {X: 2, Width: 1, Height: 1},
+		}
+		for name, location := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := fake.NewAcceleratedImage(2, 1)
+				output := make([]image.Color, location.Width*location.Height)
+				assert.Panics(t, func() {
+					img.DownloadRegion(location, output)
+				})
+			})
+		}
+	})
+	t.Run("should panic when output slice is not of location width*height length", func(t *testing.T) {
+		img := fake.NewAcceleratedImage(2, 2)
+		output := make([]image.Color, 1)
+		assert.Panics(t, func() {
+			img.DownloadRegion(image.AcceleratedImageLocation{Width: 2, Height: 1}, output)
+		})
+	})
+	t.Run("should download colors of region", func(t *testing.T) {
+		var (
+			color0 = image.RGB(0, 0, 0)
+			color1 = image.RGB(1, 1, 1)
+			color2 = image.RGB(2, 2, 2)
+			color3 = image.RGB(3, 3, 3)
+		)
+		tests := map[string]struct {
+			location image.AcceleratedImageLocation
+			expected []image.Color
+		}{
+			"top-left pixel": {
+				location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+				expected: []image.Color{color2},
+			},
+			"bottom line": {
+				location: image.AcceleratedImageLocation{Y: 1, Width: 2, Height: 1},
+				expected: []image.Color{color0, color1},
+			},
+			"right column": {
+				location: image.AcceleratedImageLocation{X: 1, Width: 1, Height: 2},
+				expected: []image.Color{color1, color3},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := fake.NewAcceleratedImage(2, 2)
+				img.Upload([]image.Color{color0, color1, color2, color3})
+				output := make([]image.Color, len(test.expected))
+				// when
+				img.DownloadRegion(test.location, output)
+				// then
+				assert.Equal(t, test.expected, output)
+			})
+		}
+	})
+}
+
 func TestAcceleratedImage_PixelsTable(t *testing.T) {
 	t.Run("should return 2d slice", func(t *testing.T) {
 		color0 := image.RGB(0, 0, 0)
@@ -31,6 +31,43 @@ type AcceleratedImage interface {
 	Delete()
 }
 
+// RegionUploader is an optional interface which can be implemented by
+// AcceleratedImage. Image uses it to upload only pixels modified in RAM instead
+// of all the pixels.
+type RegionUploader interface {
+	// UploadRegion transfers pixels of a given region from RAM to external memory
+	// (such as VRAM).
+	//
+	// Location is given in image coordinates, where (0,0) is the top-left corner
+	// of the image. Location is always within image boundaries.
+	//
+	// Pixels must have pixel colors of the region sorted by coordinates.
+	// Pixels are send for last line first, from left to right.
+	// Pixels slice holds all region pixels and therefore must have size
+	// location.Width*location.Height
+	//
+	// Implementations must not retain pixels slice and make a copy instead.
+	UploadRegion(location AcceleratedImageLocation, pixels []Color)
+}
+
+// RegionDownloader is an optional interface which can be implemented by
+// AcceleratedImage. Image uses it to download only pixels modified by
+// AcceleratedCommand instead of all the pixels.
+type RegionDownloader interface {
+	// DownloadRegion transfers pixels of a given region from external memory
+	// (such as VRAM) to RAM.
+	//
+	// Location is given in image coordinates, where (0,0) is the top-left corner
+	// of the image. Location is always within image boundaries.
+	//
+	// Output will have pixel colors of the region sorted by coordinates.
+	// Pixels are send for last line first, from left to right.
+	// Output must be of size location.Width*location.Height.
+	//
+	// Implementations must not retain output.
+	DownloadRegion(location AcceleratedImageLocation, output []Color)
+}
+
 // New creates an Image with same size as provided AcceleratedImage.
 // Will panic if AcceleratedImage is nil or width and height of
 // AcceleratedImage are negative
@@ -67,11 +104,14 @@ type Image struct {
 	height         int
 	heightMinusOne int
 	// pixel colors line by line, starting from the bottom
-	pixels                   []Color
-	acceleratedImage         AcceleratedImage
-	selectionsCache          []AcceleratedImageSelection
-	acceleratedImageModified bool
-	ramModified              bool
+	pixels           []Color
+	acceleratedImage AcceleratedImage
+	selectionsCache  []AcceleratedImageSelection
+	// regions modified by AcceleratedCommand since last download
+	acceleratedImageModifiedRegions regions
+	// regions modified in RAM since last upload
+	ramModifiedRegions regions
+	regionPixels       []Color
 }
 
 // Width returns the number of pixels in a row.
@@ -100,16 +140,69 @@ func (i *Image) WholeImageSelection() Selection {
 	return i.Selection(0, 0).WithSize(i.width, i.height)
 }
 
-// Upload uploads all image pixels to associated AcceleratedImage.
+// Upload uploads all pixels modified in RAM to associated AcceleratedImage.
 // This method should be called rarely. Image pixels are uploaded automatically
 // when needed.
 //
+// If AcceleratedImage implements RegionUploader only modified regions are uploaded.
+//
 // DEPRECATED - this method will be removed in next release
 func (i *Image) Upload() {
-	if i.ramModified {
+	if i.ramModifiedRegions.empty() {
+		return
+	}
+	uploader, ok := i.acceleratedImage.(RegionUploader)
+	if !ok || i.ramModifiedRegions.coversWholeImage(i.width, i.height) {
 		i.acceleratedImage.Upload(i.pixels)
-		i.ramModified = false
+		i.ramModifiedRegions.clear()
+		return
+	}
+	for _, region := range i.ramModifiedRegions {
+		uploader.UploadRegion(region, i.copyRegionPixels(region))
+	}
+	i.ramModifiedRegions.clear()
+}
+
+// copyRegionPixels copies pixels of a given region into a reusable slice. Lines
+// are copied starting from the last one.
+func (i *Image) copyRegionPixels(region AcceleratedImageLocation) []Color {
+	size := region.Width * region.Height
+	if cap(i.regionPixels) < size {
+		i.regionPixels = make([]Color, size)
+	}
+	output := i.regionPixels[:size]
+	firstLine := i.height - region.Y - region.Height
+	for line := 0; line < region.Height; line++ {
+		start := (firstLine+line)*i.width + region.X
+		copy(output[line*region.Width:], i.pixels[start:start+region.Width])
+	}
+	return output
+}
+
+// download downloads all pixels modified by AcceleratedCommand from associated
+// AcceleratedImage. If AcceleratedImage implements RegionDownloader only modified
+// regions are downloaded.
+func (i *Image) download() {
+	downloader, ok := i.acceleratedImage.(RegionDownloader)
+	if !ok || i.acceleratedImageModifiedRegions.coversWholeImage(i.width, i.height) {
+		i.acceleratedImage.Download(i.pixels)
+		i.acceleratedImageModifiedRegions.clear()
+		return
+	}
+	for _, region := range i.acceleratedImageModifiedRegions {
+		size := region.Width * region.Height
+		if cap(i.regionPixels) < size {
+			i.regionPixels = make([]Color, size)
+		}
+		regionPixels := i.regionPixels[:size]
+		downloader.DownloadRegion(region, regionPixels)
+		firstLine := i.height - region.Y - region.Height
+		for line := 0; line < region.Height; line++ {
+			start := (firstLine+line)*i.width + region.X
+			copy(i.pixels[start:start+region.Width], regionPixels[line*region.Width:])
+		}
 	}
+	i.acceleratedImageModifiedRegions.clear()
 }
 
 // Delete cleans resources allocated outside the Go heap. This method must be
@@ -187,9 +280,8 @@ func (s Selection) Selection(localX, localY int) Selection {
 // If pixel is outside the image boundaries then transparent color is returned.
 // It is also possible to get the color outside the selection.
 func (s Selection) Color(localX, localY int) Color {
-	if s.image.acceleratedImageModified {
-		s.image.acceleratedImage.Download(s.image.pixels)
-		s.image.acceleratedImageModified = false
+	if !s.image.acceleratedImageModifiedRegions.empty() {
+		s.image.download()
 	}
 	x := localX + s.x
 	if x < 0 {
@@ -215,9 +307,8 @@ func (s Selection) Color(localX, localY int) Color {
 // If pixel is outside the image boundaries then nothing happens.
 // It is possible to set the color outside the selection.
 func (s Selection) SetColor(localX, localY int, color Color) {
-	if s.image.acceleratedImageModified {
-		s.image.acceleratedImage.Download(s.image.pixels)
-		s.image.acceleratedImageModified = false
+	if !s.image.acceleratedImageModifiedRegions.empty() {
+		s.image.download()
 	}
 	x := localX + s.x
 	if x < 0 {
@@ -234,7 +325,7 @@ func (s Selection) SetColor(localX, localY int, color Color) {
 	if index >= len(s.image.pixels) {
 		return
 	}
-	s.image.ramModified = true
+	s.image.ramModifiedRegions.add(x, localY+s.y, 1, 1)
 	s.image.pixels[index] = color
 }
 
@@ -253,7 +344,8 @@ type AcceleratedImageSelection struct {
 type AcceleratedCommand interface {
 	// Run should put the results into the output selection of AcceleratedImage,
 	// so that next time AcceleratedImage.Download is called modified pixels are
-	// downloaded.
+	// downloaded. Pixels outside the output selection must not be modified,
+	// because Image may download only the output selection.
 	//
 	// Run may return error when output or selections cannot be used. Usually
 	// the reason for that is they were not created in a given context (such
@@ -267,6 +359,9 @@ type AcceleratedCommand interface {
 // This method ensures that all passed selections are uploaded before the command
 // is called. Selections get converted into AcceleratedImageSelection and
 // passed to the command.Run.
+//
+// Only pixels inside the Selection are downloaded from the AcceleratedImage
+// afterwards, provided that AcceleratedImage implements RegionDownloader.
 func (s Selection) Modify(command AcceleratedCommand, selections ...Selection) {
 	if command == nil {
 		return
@@ -278,7 +373,15 @@ func (s Selection) Modify(command AcceleratedCommand, selections ...Selection) {
 	}
 	s.image.Upload()
 	command.Run(s.toAcceleratedImageSelection(), convertedSelections)
-	s.image.acceleratedImageModified = true
+	s.markAcceleratedImageModified()
+}
+
+func (s Selection) markAcceleratedImageModified() {
+	left := max(s.x, 0)
+	top := max(s.y, 0)
+	right := min(s.x+s.width, s.image.width)
+	bottom := min(s.y+s.height, s.image.height)
+	s.image.acceleratedImageModifiedRegions.add(left, top, right-left, bottom-top)
 }
 
 func (s Selection) toAcceleratedImageSelection() AcceleratedImageSelection {
@@ -374,7 +477,7 @@ func (l Lines) YOffset() int {
 // AcceleratedCommand and changes will not be immediately reflected in a slice.
 func (l Lines) LineForWrite(line int) []Color {
 	pixels := l.line(line)
-	l.image.ramModified = true
+	l.image.ramModifiedRegions.add(l.startX+l.xOffset, l.startY+l.yOffset+line, len(pixels), 1)
 	return pixels
 }
 
@@ -418,9 +521,8 @@ func (l Lines) line(line int) []Color {
 	if stop < 0 {
 		return []Color{}
 	}
-	if l.image.acceleratedImageModified {
-		l.image.acceleratedImage.Download(l.image.pixels)
-		l.image.acceleratedImageModified = false
+	if !l.image.acceleratedImageModifiedRegions.empty() {
+		l.image.download()
 	}
 	return l.image.pixels[start:stop]
 }
@@ -443,7 +443,7 @@ func TestSelection_SetColor(t *testing.T) {
 			commandColor = image.RGBA(50, 60, 70, 80)
 			accImg       = fake.NewAcceleratedImage(2, 1)
 			img          = image.New(accImg)
-			selection    = img.Selection(0, 0)
+			selection    = img.Selection(0, 0).WithSize(2, 1)
 		)
 		selection.Modify(&acceleratedCommandMock{
 			command: func(image.AcceleratedImageSelection, []image.AcceleratedImageSelection) {
@@ -519,6 +519,175 @@ func TestImage_Upload(t *testing.T) {
 		// then
 		assert.Equal(t, [][]image.Color{{color}}, acceleratedImage.PixelsTable())
 	})
+	t.Run("should upload only modified regions", func(t *testing.T) {
+		var (
+			color1 = image.RGBA(10, 20, 30, 40)
+			color2 = image.RGBA(50, 60, 70, 80)
+		)
+		tests := map[string]struct {
+			modify          func(selection image.Selection)
+			expectedUploads []regionUpload
+		}{
+			"nothing modified": {
+				modify: func(image.Selection) {},
+			},
+			"one pixel": {
+				modify: func(selection image.Selection) {
+					selection.SetColor(1, 2, color1)
+				},
+				expectedUploads: []regionUpload{
+					{
+						location: image.AcceleratedImageLocation{X: 1, Y: 2, Width: 1, Height: 1},
+						pixels:   []image.Color{color1},
+					},
+				},
+			},
+			"two adjacent pixels": {
+				modify: func(selection image.Selection) {
+					selection.SetColor(1, 2, color1)
+					selection.SetColor(2, 2, color2)
+				},
+				expectedUploads: []regionUpload{
+					{
+						location: image.AcceleratedImageLocation{X: 1, Y: 2, Width: 2, Height: 1},
+						pixels:   []image.Color{color1, color2},
+					},
+				},
+			},
+			"two pixels in a column": {
+				modify: func(selection image.Selection) {
+					selection.SetColor(0, 0, color1)
+					selection.SetColor(0, 1, color2)
+				},
+				expectedUploads: []regionUpload{
+					{
+						location: image.AcceleratedImageLocation{Width: 1, Height: 2},
+						pixels:   []image.Color{color2, color1},
+					},
+				},
+			},
+			"two distant pixels": {
+				modify: func(selection image.Selection) {
+					selection.SetColor(0, 0, color1)
+					selection.SetColor(3, 3, color2)
+				},
+				expectedUploads: []regionUpload{
+					{
+						location: image.AcceleratedImageLocation{Width: 1, Height: 1},
+						pixels:   []image.Color{color1},
+					},
+					{
+						location: image.AcceleratedImageLocation{X: 3, Y: 3, Width: 1, Height: 1},
+						pixels:   []image.Color{color2},
+					},
+				},
+			},
+			"line for write": {
+				modify: func(selection image.Selection) {
+					lines := selection.Selection(1, 1).WithSize(2, 2).Lines()
+					lines.LineForWrite(1)[0] = color1
+				},
+				expectedUploads: []regionUpload{
+					{
+						location: image.AcceleratedImageLocation{X: 1, Y: 2, Width: 2, Height: 1},
+						pixels:   []image.Color{color1, transparent},
+					},
+				},
+			},
+			"line for write of selection partially outside the image": {
+				modify: func(selection image.Selection) {
+					lines := selection.Selection(-1, 3).WithSize(3, 2).Lines()
+					lines.LineForWrite(0)[0] = color1
+				},
+				expectedUploads: []regionUpload{
+					{
+						location: image.AcceleratedImageLocation{X: 0, Y: 3, Width: 2, Height: 1},
+						pixels:   []image.Color{color1, transparent},
+					},
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				acceleratedImage := &regionUploaderMock{width: 4, height: 4}
+				img := image.New(acceleratedImage)
+				test.modify(img.WholeImageSelection())
+				// when
+				img.Upload()
+				// then
+				assert.Equal(t, test.expectedUploads, acceleratedImage.uploads)
+				assert.Equal(t, 0, acceleratedImage.wholeImageUploads)
+			})
+		}
+	})
+	t.Run("should upload whole image when all pixels were modified", func(t *testing.T) {
+		acceleratedImage := &regionUploaderMock{width: 2, height: 2}
+		img := image.New(acceleratedImage)
+		lines := img.WholeImageSelection().Lines()
+		lines.LineForWrite(0)
+		lines.LineForWrite(1)
+		// when
+		img.Upload()
+		// then
+		assert.Empty(t, acceleratedImage.uploads)
+		assert.Equal(t, 1, acceleratedImage.wholeImageUploads)
+	})
+	t.Run("should not upload regions second time", func(t *testing.T) {
+		acceleratedImage := &regionUploaderMock{width: 2, height: 2}
+		img := image.New(acceleratedImage)
+		img.WholeImageSelection().SetColor(0, 0, image.RGB(1, 2, 3))
+		img.Upload()
+		acceleratedImage.uploads = nil
+		// when
+		img.Upload()
+		// then
+		assert.Empty(t, acceleratedImage.uploads)
+	})
+	t.Run("should upload region to fake AcceleratedImage", func(t *testing.T) {
+		var (
+			color            = image.RGBA(10, 20, 30, 40)
+			acceleratedImage = fake.NewAcceleratedImage(2, 2)
+			img              = image.New(acceleratedImage)
+		)
+		img.WholeImageSelection().SetColor(1, 0, color)
+		// when
+		img.Upload()
+		// then
+		assert.Equal(t, [][]image.Color{
+			{transparent, transparent},
+			{transparent, color},
+		}, acceleratedImage.PixelsTable())
+	})
+}
+
+type regionUpload struct {
+	location image.AcceleratedImageLocation
+	pixels   []image.Color
+}
+
+type regionUploaderMock struct {
+	acceleratedImageStub
+	width, height     int
+	uploads           []regionUpload
+	wholeImageUploads int
+}
+
+func (i *regionUploaderMock) Width() int {
+	return i.width
+}
+
+func (i *regionUploaderMock) Height() int {
+	return i.height
+}
+
+func (i *regionUploaderMock) Upload([]image.Color) {
+	i.wholeImageUploads++
+}
+
+func (i *regionUploaderMock) UploadRegion(location image.AcceleratedImageLocation, pixels []image.Color) {
+	pixelsCopy := make([]image.Color, len(pixels))
+	copy(pixelsCopy, pixels)
+	i.uploads = append(i.uploads, regionUpload{location: location, pixels: pixelsCopy})
 }
 
 func TestImage_Delete(t *testing.T) {
@@ -887,6 +1056,132 @@ func TestSelection_Modify(t *testing.T) {
 		// then
 		assert.Equal(t, color, selection.Color(0, 0))
 	})
+
+	t.Run("should download only modified regions", func(t *testing.T) {
+		tests := map[string]struct {
+			modify            func(img *image.Image)
+			expectedDownloads []image.AcceleratedImageLocation
+		}{
+			"selection inside the image": {
+				modify: func(img *image.Image) {
+					img.Selection(1, 2).WithSize(2, 1).Modify(&acceleratedCommandStub{})
+				},
+				expectedDownloads: []image.AcceleratedImageLocation{
+					{X: 1, Y: 2, Width: 2, Height: 1},
+				},
+			},
+			"selection partially outside the image": {
+				modify: func(img *image.Image) {
+					img.Selection(-1, 3).WithSize(3, 2).Modify(&acceleratedCommandStub{})
+				},
+				expectedDownloads: []image.AcceleratedImageLocation{
+					{X: 0, Y: 3, Width: 2, Height: 1},
+				},
+			},
+			"selection outside the image": {
+				modify: func(img *image.Image) {
+					img.Selection(4, 0).WithSize(1, 1).Modify(&acceleratedCommandStub{})
+				},
+			},
+			"two overlapping selections": {
+				modify: func(img *image.Image) {
+					img.Selection(0, 0).WithSize(2, 2).Modify(&acceleratedCommandStub{})
+					img.Selection(1, 1).WithSize(2, 2).Modify(&acceleratedCommandStub{})
+				},
+				expectedDownloads: []image.AcceleratedImageLocation{
+					{X: 0, Y: 0, Width: 3, Height: 3},
+				},
+			},
+			"two distant selections": {
+				modify: func(img *image.Image) {
+					img.Selection(0, 0).WithSize(1, 1).Modify(&acceleratedCommandStub{})
+					img.Selection(3, 3).WithSize(1, 1).Modify(&acceleratedCommandStub{})
+				},
+				expectedDownloads: []image.AcceleratedImageLocation{
+					{X: 0, Y: 0, Width: 1, Height: 1},
+					{X: 3, Y: 3, Width: 1, Height: 1},
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				acceleratedImage := &regionDownloaderMock{width: 4, height: 4}
+				img := image.New(acceleratedImage)
+				test.modify(img)
+				// when
+				img.WholeImageSelection().Color(0, 0)
+				// then
+				assert.Equal(t, test.expectedDownloads, acceleratedImage.downloads)
+				assert.Equal(t, 0, acceleratedImage.wholeImageDownloads)
+			})
+		}
+	})
+
+	t.Run("should download whole image when whole image was modified", func(t *testing.T) {
+		acceleratedImage := &regionDownloaderMock{width: 2, height: 2}
+		img := image.New(acceleratedImage)
+		img.WholeImageSelection().Modify(&acceleratedCommandStub{})
+		// when
+		img.WholeImageSelection().Color(0, 0)
+		// then
+		assert.Empty(t, acceleratedImage.downloads)
+		assert.Equal(t, 1, acceleratedImage.wholeImageDownloads)
+	})
+
+	t.Run("should download region only once", func(t *testing.T) {
+		acceleratedImage := &regionDownloaderMock{width: 2, height: 2}
+		img := image.New(acceleratedImage)
+		selection := img.Selection(0, 0).WithSize(1, 1)
+		selection.Modify(&acceleratedCommandStub{})
+		selection.Color(0, 0)
+		acceleratedImage.downloads = nil
+		// when
+		selection.Color(0, 0)
+		// then
+		assert.Empty(t, acceleratedImage.downloads)
+	})
+
+	t.Run("should not override pixels outside the modified region", func(t *testing.T) {
+		var (
+			color        = image.RGBA(10, 20, 30, 40)
+			commandColor = image.RGBA(50, 60, 70, 80)
+			accImg       = fake.NewAcceleratedImage(2, 1)
+			img          = image.New(accImg)
+		)
+		img.Selection(0, 0).SetColor(0, 0, color)
+		target := img.Selection(1, 0).WithSize(1, 1)
+		// when
+		target.Modify(&acceleratedCommandMock{
+			command: func(image.AcceleratedImageSelection, []image.AcceleratedImageSelection) {
+				accImg.UploadRegion(image.AcceleratedImageLocation{X: 1, Width: 1, Height: 1}, []image.Color{commandColor})
+			},
+		})
+		// then
+		assertColors(t, img.WholeImageSelection(), [][]image.Color{{color, commandColor}})
+	})
+}
+
+type regionDownloaderMock struct {
+	acceleratedImageStub
+	width, height       int
+	downloads           []image.AcceleratedImageLocation
+	wholeImageDownloads int
+}
+
+func (i *regionDownloaderMock) Width() int {
+	return i.width
+}
+
+func (i *regionDownloaderMock) Height() int {
+	return i.height
+}
+
+func (i *regionDownloaderMock) Download([]image.Color) {
+	i.wholeImageDownloads++
+}
+
+func (i *regionDownloaderMock) DownloadRegion(location image.AcceleratedImageLocation, _ []image.Color) {
+	i.downloads = append(i.downloads, location)
 }
 
 func TestLines_Length(t *testing.T) {
@@ -0,0 +1,102 @@
+package image
+
+// maxRegions limits the number of rectangles tracked by regions. When the limit
+// is exceeded all rectangles are merged into a single bounding rectangle.
+const maxRegions = 16
+
+// regions is a set of rectangles given in image coordinates. Rectangles which
+// overlap or touch each other are merged, therefore regions never contain
+// intersecting rectangles.
+type regions []AcceleratedImageLocation
+
+// add adds rectangle to the set. Rectangle must be already clamped to image
+// boundaries.
+func (r *regions) add(x, y, width, height int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	rects := *r
+	if len(rects) > 0 && contains(rects[len(rects)-1], x, y, width, height) {
+		// fast path for sequential modifications, such as setting pixel colors
+		// in a loop
+		return
+	}
+	added := AcceleratedImageLocation{X: x, Y: y, Width: width, Height: height}
+	for i := 0; i < len(rects); {
+		if !touches(rects[i], added) {
+			i++
+			continue
+		}
+		added = union(rects[i], added)
+		rects = append(rects[:i], rects[i+1:]...)
+		i = 0
+	}
+	rects = append(rects, added)
+	if len(rects) > maxRegions {
+		bounds := rects[0]
+		for _, rect := range rects[1:] {
+			bounds = union(bounds, rect)
+		}
+		rects = append(rects[:0], bounds)
+	}
+	*r = rects
+}
+
+func (r *regions) clear() {
+	*r = (*r)[:0]
+}
+
+func (r regions) empty() bool {
+	return len(r) == 0
+}
+
+// coversWholeImage returns true if regions contain exactly one rectangle with
+// the size of the image.
+func (r regions) coversWholeImage(width, height int) bool {
+	if len(r) != 1 {
+		return false
+	}
+	rect := r[0]
+	return rect.X == 0 && rect.Y == 0 && rect.Width == width && rect.Height == height
+}
+
+func contains(rect AcceleratedImageLocation, x, y, width, height int) bool {
+	return x >= rect.X && y >= rect.Y &&
+		x+width <= rect.X+rect.Width &&
+		y+height <= rect.Y+rect.Height
+}
+
+// touches returns true if rectangles overlap or share an edge.
+func touches(a, b AcceleratedImageLocation) bool {
+	aRight, aBottom := a.X+a.Width, a.Y+a.Height
+	bRight, bBottom := b.X+b.Width, b.Y+b.Height
+	if a.X > bRight || b.X > aRight || a.Y > bBottom || b.Y > aBottom {
+		return false
+	}
+	touchingHorizontally := a.X == bRight || b.X == aRight
+	touchingVertically := a.Y == bBottom || b.Y == aBottom
+	// rectangles touching only by corners are not merged
+	return !(touchingHorizontally && touchingVertically)
+}
+
+func union(a, b AcceleratedImageLocation) AcceleratedImageLocation {
+	left := min(a.X, b.X)
+	top := min(a.Y, b.Y)
+	right := max(a.X+a.Width, b.X+b.Width)
+	bottom := max(a.Y+a.Height, b.Y+b.Height)
+	return AcceleratedImageLocation{X: left, Y: top, Width: right - left, Height: bottom - top}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
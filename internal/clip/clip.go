@@ -0,0 +1,19 @@
+// Package clip provides clipping of selections shared by CPU and GPU tools.
+package clip
+
+import "github.com/elgopher/pixiq/image"
+
+// SourceToTargetImage shrinks the source selection, so that after putting it
+// at the position of the target selection it does not exceed the right and
+// bottom border of the target image.
+func SourceToTargetImage(source image.Selection, target image.Selection) image.Selection {
+	width := source.Width()
+	if width+target.ImageX() > target.Image().Width() {
+		width = target.Image().Width() - target.ImageX()
+	}
+	height := source.Height()
+	if height+target.ImageY() > target.Image().Height() {
+		height = target.Image().Height() - target.ImageY()
+	}
+	return source.WithSize(width, height)
+}
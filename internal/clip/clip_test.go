@@ -0,0 +1,50 @@
+package clip_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+	"github.com/elgopher/pixiq/internal/clip"
+)
+
+func TestSourceToTargetImage(t *testing.T) {
+	t.Run("should clip source to target image", func(t *testing.T) {
+		source := image.New(fake.NewAcceleratedImage(4, 3)).WholeImageSelection()
+		targetImage := image.New(fake.NewAcceleratedImage(5, 5))
+		tests := map[string]struct {
+			target                        image.Selection
+			expectedWidth, expectedHeight int
+		}{
+			"inside": {
+				target:        targetImage.Selection(1, 1),
+				expectedWidth: 4, expectedHeight: 3,
+			},
+			"exceeding right border": {
+				target:        targetImage.Selection(3, 0),
+				expectedWidth: 2, expectedHeight: 3,
+			},
+			"exceeding bottom border": {
+				target:        targetImage.Selection(0, 4),
+				expectedWidth: 4, expectedHeight: 1,
+			},
+			"left top outside image": {
+				target:        targetImage.Selection(-2, -2),
+				expectedWidth: 4, expectedHeight: 3,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				// when
+				clipped := clip.SourceToTargetImage(source, test.target)
+				// then
+				assert.Equal(t, test.expectedWidth, clipped.Width())
+				assert.Equal(t, test.expectedHeight, clipped.Height())
+				assert.Equal(t, source.ImageX(), clipped.ImageX())
+				assert.Equal(t, source.ImageY(), clipped.ImageY())
+			})
+		}
+	})
+}
@@ -0,0 +1,31 @@
+// Package glquad provides a vertex shader and a vertex array shared by GPU
+// tools which draw a textured quad. Each vertex has xy position in clip space
+// followed by st texture coordinates.
+package glquad
+
+import "github.com/elgopher/pixiq/gl"
+
+// VertexShaderSrc passes st texture coordinates to the fragment shader as
+// interpolatedST.
+const VertexShaderSrc = `
+#version 330 core
+	
+layout(location = 0) in vec2 xy;
+layout(location = 1) in vec2 st;
+out vec2 interpolatedST;
+
+void main() {
+	gl_Position = vec4(xy, 0.0, 1.0);
+	interpolatedST = st;
+}
+`
+
+// NewVertexArray creates a vertex array reading xy and st from the buffer.
+func NewVertexArray(context *gl.Context, buffer *gl.FloatVertexBuffer) *gl.VertexArray {
+	array := context.NewVertexArray(gl.VertexLayout{gl.Vec2, gl.Vec2})
+	xy := gl.VertexBufferPointer{Offset: 0, Stride: 4, Buffer: buffer}
+	array.Set(0, xy)
+	st := gl.VertexBufferPointer{Offset: 2, Stride: 4, Buffer: buffer}
+	array.Set(1, st)
+	return array
+}
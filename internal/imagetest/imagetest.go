@@ -0,0 +1,86 @@
+// Package imagetest provides images and assertions shared by tests comparing
+// GPU tools with their CPU twins. Tests run either on a real video card
+// or on the software gl.API.
+package imagetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/gl"
+	"github.com/elgopher/pixiq/image"
+)
+
+// NewPatternImage creates image with various colors, including transparent
+// and semitransparent ones. Images with different seeds have different colors.
+func NewPatternImage(context *gl.Context, width, height, seed int) *image.Image {
+	img := image.New(context.NewAcceleratedImage(width, height))
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := (x*31 + y*17) * seed
+			selection.SetColor(x, y, image.NRGBA(byte(v), byte(v*3), byte(v*7), byte(v*5)))
+		}
+	}
+	return img
+}
+
+// NewFewColorsImage creates image with a few colors repeated, so pixel-art
+// upscalers find edges
+func NewFewColorsImage(context *gl.Context, width, height int) *image.Image {
+	colors := []image.Color{
+		image.Transparent,
+		image.RGB(200, 30, 40),
+		image.RGBA(10, 20, 30, 40),
+	}
+	img := image.New(context.NewAcceleratedImage(width, height))
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, colors[(x*x+3*y+x*y)%len(colors)])
+		}
+	}
+	return img
+}
+
+// NewGradientImage creates opaque image with colors changing smoothly in both
+// directions
+func NewGradientImage(context *gl.Context, width, height int) *image.Image {
+	img := image.New(context.NewAcceleratedImage(width, height))
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, image.RGB(uint8(x*8), uint8(y*8), uint8(x*4+y*4)))
+		}
+	}
+	return img
+}
+
+// AssertSimilarColors asserts that color components differ at most by delta.
+// Shaders calculate using floats, so results may be rounded differently than
+// in CPU tools.
+func AssertSimilarColors(t *testing.T, expected, actual *image.Image, delta float64) {
+	expectedSelection := expected.WholeImageSelection()
+	actualSelection := actual.WholeImageSelection()
+	for y := 0; y < expectedSelection.Height(); y++ {
+		for x := 0; x < expectedSelection.Width(); x++ {
+			er, eg, eb, ea := expectedSelection.Color(x, y).RGBAi()
+			ar, ag, ab, aa := actualSelection.Color(x, y).RGBAi()
+			assert.InDeltaSlice(t, []int{er, eg, eb, ea}, []int{ar, ag, ab, aa}, delta,
+				"position (%d,%d)", x, y)
+		}
+	}
+}
+
+// AssertEqualImages asserts that both images have exactly the same colors.
+func AssertEqualImages(t *testing.T, expected, actual *image.Image) {
+	expectedSelection := expected.WholeImageSelection()
+	actualSelection := actual.WholeImageSelection()
+	for y := 0; y < expectedSelection.Height(); y++ {
+		for x := 0; x < expectedSelection.Width(); x++ {
+			assert.Equal(t, expectedSelection.Color(x, y), actualSelection.Color(x, y),
+				"position (%d,%d)", x, y)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package palette
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+// Decode decodes palette in one of supported formats. The format is detected
+// automatically:
+//
+//   - GIMP palette (.gpl) starting with "GIMP Palette" line
+//   - JASC-PAL (.pal) used by Paint Shop Pro and Aseprite
+//   - Lospec JSON, an object with "colors" array of hex strings
+//   - HEX (.hex) with one RRGGBB or RRGGBBAA color per line, optionally
+//     prefixed with #
+func Decode(reader io.Reader) (Palette, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("GIMP Palette")):
+		return DecodeGPL(bytes.NewReader(data))
+	case bytes.HasPrefix(trimmed, []byte("JASC-PAL")):
+		return DecodeJASC(bytes.NewReader(data))
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return DecodeLospecJSON(bytes.NewReader(data))
+	default:
+		return DecodeHex(bytes.NewReader(data))
+	}
+}
+
+// DecodeFile decodes palette file. See Decode.
+func DecodeFile(fileName string) (Palette, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Decode(file)
+}
+
+// DecodeGPL decodes GIMP palette. Blank lines before the header are skipped.
+func DecodeGPL(reader io.Reader) (Palette, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	scanner := bufio.NewScanner(reader)
+	header, lineNumber := scanHeader(scanner)
+	if header != "GIMP Palette" {
+		return nil, errorOrDefault(scanner.Err(), "not a GIMP palette: missing header")
+	}
+	var palette Palette
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") ||
+			strings.HasPrefix(line, "Name:") || strings.HasPrefix(line, "Columns:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("line %d: expected 3 color components", lineNumber)
+		}
+		color, err := parseRGB(fields[:3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNumber, err)
+		}
+		palette = append(palette, color)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return palette, nil
+}
+
+// DecodeJASC decodes JASC-PAL palette. Blank lines before the header are skipped.
+func DecodeJASC(reader io.Reader) (Palette, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	scanner := bufio.NewScanner(reader)
+	if header, _ := scanHeader(scanner); header != "JASC-PAL" {
+		return nil, errorOrDefault(scanner.Err(), "not a JASC palette: missing header")
+	}
+	if !scanner.Scan() {
+		return nil, errorOrDefault(scanner.Err(), "missing version")
+	}
+	if !scanner.Scan() {
+		return nil, errorOrDefault(scanner.Err(), "missing number of colors")
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid number of colors: %s", scanner.Text())
+	}
+	palette := make(Palette, 0, count)
+	for len(palette) < count && scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("color %d: expected 3 color components", len(palette))
+		}
+		color, err := parseRGB(fields[:3])
+		if err != nil {
+			return nil, fmt.Errorf("color %d: %s", len(palette), err)
+		}
+		palette = append(palette, color)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(palette) < count {
+		return nil, fmt.Errorf("expected %d colors, got %d", count, len(palette))
+	}
+	return palette, nil
+}
+
+// DecodeHex decodes palette with one hex color per line. Colors can be in
+// RRGGBB or RRGGBBAA format (not premultiplied), optionally prefixed with #.
+// Empty lines and lines starting with ; are ignored.
+func DecodeHex(reader io.Reader) (Palette, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	var palette Palette
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(trimBOM(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		color, err := ParseHex(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNumber, err)
+		}
+		palette = append(palette, color)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return palette, nil
+}
+
+// DecodeLospecJSON decodes palette in JSON format returned by Lospec palette
+// list, for example https://lospec.com/palette-list/pico-8.json
+func DecodeLospecJSON(reader io.Reader) (Palette, error) {
+	if reader == nil {
+		panic("nil reader")
+	}
+	var lospec struct {
+		Colors []string `json:"colors"`
+	}
+	if err := json.NewDecoder(reader).Decode(&lospec); err != nil {
+		return nil, err
+	}
+	if lospec.Colors == nil {
+		return nil, errors.New("missing colors")
+	}
+	palette := make(Palette, 0, len(lospec.Colors))
+	for i, hexColor := range lospec.Colors {
+		color, err := ParseHex(hexColor)
+		if err != nil {
+			return nil, fmt.Errorf("color %d: %s", i, err)
+		}
+		palette = append(palette, color)
+	}
+	return palette, nil
+}
+
+// ParseHex parses color in RRGGBB or RRGGBBAA format (not premultiplied),
+// optionally prefixed with #.
+func ParseHex(s string) (image.Color, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 && len(s) != 8 {
+		return image.Transparent, fmt.Errorf("invalid hex color %q", s)
+	}
+	components, err := hex.DecodeString(s)
+	if err != nil {
+		return image.Transparent, fmt.Errorf("invalid hex color %q", s)
+	}
+	if len(components) == 3 {
+		return image.RGB(components[0], components[1], components[2]), nil
+	}
+	return image.NRGBA(components[0], components[1], components[2], components[3]), nil
+}
+
+func parseRGB(fields []string) (image.Color, error) {
+	var rgb [3]byte
+	for i, field := range fields {
+		v, err := strconv.ParseUint(field, 10, 8)
+		if err != nil {
+			return image.Transparent, fmt.Errorf("invalid color component %q", field)
+		}
+		rgb[i] = byte(v)
+	}
+	return image.RGB(rgb[0], rgb[1], rgb[2]), nil
+}
+
+// scanHeader skips leading blank lines and returns the first non-blank line
+// without BOM and surrounding spaces, together with its line number. Empty
+// string is returned when there are no more lines.
+func scanHeader(scanner *bufio.Scanner) (header string, lineNumber int) {
+	for scanner.Scan() {
+		lineNumber++
+		header = strings.TrimSpace(trimBOM(scanner.Text()))
+		if header != "" {
+			return header, lineNumber
+		}
+	}
+	return "", lineNumber
+}
+
+func trimBOM(s string) string {
+	return strings.TrimPrefix(s, "\ufeff")
+}
+
+func errorOrDefault(err error, message string) error {
+	if err != nil {
+		return err
+	}
+	return errors.New(message)
+}
@@ -0,0 +1,268 @@
+package palette_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/palette"
+)
+
+const gpl = `GIMP Palette
+Name: Test
+Columns: 2
+#
+  0   0   0	Black
+255 255 255	White
+255   0   0
+`
+
+const jasc = "JASC-PAL\r\n0100\r\n3\r\n0 0 0\r\n255 255 255\r\n255 0 0\r\n"
+
+const hex = `000000
+#ffffff
+; comment
+
+FF0000
+`
+
+const lospec = `{"name":"Test","author":"","colors":["000000","ffffff","ff0000"]}`
+
+var expectedPalette = palette.Palette{black, white, red}
+
+type erroneousReader struct{}
+
+func (erroneousReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = palette.Decode(nil)
+		})
+	})
+
+	t.Run("should return error when reader returns error", func(t *testing.T) {
+		pal, err := palette.Decode(erroneousReader{})
+		assert.Error(t, err)
+		assert.Nil(t, pal)
+	})
+
+	t.Run("should detect format", func(t *testing.T) {
+		tests := map[string]string{
+			"GPL":    gpl,
+			"JASC":   jasc,
+			"HEX":    hex,
+			"Lospec": lospec,
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				// when
+				pal, err := palette.Decode(strings.NewReader(data))
+				// then
+				require.NoError(t, err)
+				assert.Equal(t, expectedPalette, pal)
+			})
+		}
+	})
+
+	t.Run("should detect format of data starting with blank lines", func(t *testing.T) {
+		tests := map[string]string{
+			"GPL":    gpl,
+			"JASC":   jasc,
+			"HEX":    hex,
+			"Lospec": lospec,
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				// when
+				pal, err := palette.Decode(strings.NewReader("\n \n" + data))
+				// then
+				require.NoError(t, err)
+				assert.Equal(t, expectedPalette, pal)
+			})
+		}
+	})
+}
+
+func TestDecodeFile(t *testing.T) {
+	t.Run("should return error when file does not exist", func(t *testing.T) {
+		_, err := palette.DecodeFile("missing.gpl")
+		assert.Error(t, err)
+	})
+
+	t.Run("should decode file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "palette")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		fileName := filepath.Join(dir, "test.gpl")
+		require.NoError(t, ioutil.WriteFile(fileName, []byte(gpl), 0644))
+		// when
+		pal, err := palette.DecodeFile(fileName)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, expectedPalette, pal)
+	})
+}
+
+func TestDecodeGPL(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = palette.DecodeGPL(nil)
+		})
+	})
+
+	t.Run("should return error for invalid file", func(t *testing.T) {
+		tests := map[string]string{
+			"empty":             "",
+			"missing header":    "0 0 0\n",
+			"missing component": "GIMP Palette\n0 0\n",
+			"not a number":      "GIMP Palette\n0 x 0\n",
+			"out of range":      "GIMP Palette\n0 256 0\n",
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				pal, err := palette.DecodeGPL(strings.NewReader(data))
+				assert.Error(t, err)
+				assert.Nil(t, pal)
+			})
+		}
+	})
+
+	t.Run("should decode", func(t *testing.T) {
+		pal, err := palette.DecodeGPL(strings.NewReader(gpl))
+		require.NoError(t, err)
+		assert.Equal(t, expectedPalette, pal)
+	})
+
+	t.Run("should skip blank lines before header", func(t *testing.T) {
+		pal, err := palette.DecodeGPL(strings.NewReader("\n  \n" + gpl))
+		require.NoError(t, err)
+		assert.Equal(t, expectedPalette, pal)
+	})
+}
+
+func TestDecodeJASC(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = palette.DecodeJASC(nil)
+		})
+	})
+
+	t.Run("should return error for invalid file", func(t *testing.T) {
+		tests := map[string]string{
+			"empty":             "",
+			"missing header":    "0100\n1\n0 0 0\n",
+			"missing version":   "JASC-PAL\n",
+			"missing count":     "JASC-PAL\n0100\n",
+			"invalid count":     "JASC-PAL\n0100\nx\n",
+			"missing colors":    "JASC-PAL\n0100\n2\n0 0 0\n",
+			"missing component": "JASC-PAL\n0100\n1\n0 0\n",
+			"out of range":      "JASC-PAL\n0100\n1\n0 0 300\n",
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				pal, err := palette.DecodeJASC(strings.NewReader(data))
+				assert.Error(t, err)
+				assert.Nil(t, pal)
+			})
+		}
+	})
+
+	t.Run("should decode", func(t *testing.T) {
+		pal, err := palette.DecodeJASC(strings.NewReader(jasc))
+		require.NoError(t, err)
+		assert.Equal(t, expectedPalette, pal)
+	})
+
+	t.Run("should skip blank lines before header", func(t *testing.T) {
+		pal, err := palette.DecodeJASC(strings.NewReader("\r\n" + jasc))
+		require.NoError(t, err)
+		assert.Equal(t, expectedPalette, pal)
+	})
+}
+
+func TestDecodeHex(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = palette.DecodeHex(nil)
+		})
+	})
+
+	t.Run("should return error for invalid color", func(t *testing.T) {
+		pal, err := palette.DecodeHex(strings.NewReader("000000\nxyz\n"))
+		assert.Error(t, err)
+		assert.Nil(t, pal)
+	})
+
+	t.Run("should decode", func(t *testing.T) {
+		pal, err := palette.DecodeHex(strings.NewReader(hex))
+		require.NoError(t, err)
+		assert.Equal(t, expectedPalette, pal)
+	})
+}
+
+func TestDecodeLospecJSON(t *testing.T) {
+	t.Run("should panic for nil reader", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = palette.DecodeLospecJSON(nil)
+		})
+	})
+
+	t.Run("should return error for invalid file", func(t *testing.T) {
+		tests := map[string]string{
+			"invalid JSON":   "{",
+			"missing colors": `{"name":"Test"}`,
+			"invalid color":  `{"colors":["12345"]}`,
+		}
+		for name, data := range tests {
+			t.Run(name, func(t *testing.T) {
+				pal, err := palette.DecodeLospecJSON(strings.NewReader(data))
+				assert.Error(t, err)
+				assert.Nil(t, pal)
+			})
+		}
+	})
+
+	t.Run("should decode", func(t *testing.T) {
+		pal, err := palette.DecodeLospecJSON(strings.NewReader(lospec))
+		require.NoError(t, err)
+		assert.Equal(t, expectedPalette, pal)
+	})
+}
+
+func TestParseHex(t *testing.T) {
+	t.Run("should return error for invalid color", func(t *testing.T) {
+		tests := []string{"", "#", "fff", "fffffff", "gggggg", "#ffffffffff"}
+		for _, s := range tests {
+			t.Run(s, func(t *testing.T) {
+				_, err := palette.ParseHex(s)
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("should parse color", func(t *testing.T) {
+		tests := map[string]image.Color{
+			"0a141e":    image.RGB(10, 20, 30),
+			"#0A141E":   image.RGB(10, 20, 30),
+			"ff000080":  image.NRGBA(255, 0, 0, 128),
+			"#00000000": image.Transparent,
+		}
+		for s, expected := range tests {
+			t.Run(s, func(t *testing.T) {
+				color, err := palette.ParseHex(s)
+				require.NoError(t, err)
+				assert.Equal(t, expected, color)
+			})
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package palette
+
+import (
+	"github.com/elgopher/pixiq/image"
+)
+
+// NewIndexedImage creates an image which pixels are indexes of palette colors.
+// All pixels have index 0. Will panic when width or height is negative or
+// palette has more than 256 colors.
+func NewIndexedImage(width, height int, palette Palette) *IndexedImage {
+	if width < 0 {
+		panic("negative width")
+	}
+	if height < 0 {
+		panic("negative height")
+	}
+	img := &IndexedImage{
+		width:  width,
+		height: height,
+		pixels: make([]uint8, width*height),
+	}
+	img.SetPalette(palette)
+	return img
+}
+
+// FromSelection creates IndexedImage from selection. Each pixel is replaced by
+// the index of the nearest palette color. Pixels outside the image are
+// treated as transparent. Will panic when palette is empty or has more than
+// 256 colors.
+func FromSelection(selection image.Selection, palette Palette) *IndexedImage {
+	if len(palette) == 0 {
+		panic("empty palette")
+	}
+	width, height := selection.Width(), selection.Height()
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	img := NewIndexedImage(width, height, palette)
+	cache := map[image.Color]uint8{}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			color := selection.Color(x, y)
+			index, ok := cache[color]
+			if !ok {
+				index = uint8(palette.Nearest(color))
+				cache[color] = index
+			}
+			img.pixels[y*width+x] = index
+		}
+	}
+	return img
+}
+
+// IndexedImage is an image which pixels are indexes of palette colors. Changing
+// the palette changes colors of all pixels at once, which can be used for
+// palette swapping or effects like day/night cycle.
+//
+// IndexedImage is stored in RAM only. Use Draw to draw it into image.Selection.
+type IndexedImage struct {
+	width, height int
+	pixels        []uint8
+	palette       Palette
+}
+
+// Width returns the width of the image.
+func (i *IndexedImage) Width() int {
+	return i.width
+}
+
+// Height returns the height of the image.
+func (i *IndexedImage) Height() int {
+	return i.height
+}
+
+// Palette returns the palette of the image.
+func (i *IndexedImage) Palette() Palette {
+	return i.palette
+}
+
+// SetPalette sets the palette of the image. Pixels with indexes not available
+// in the palette will be drawn as transparent. Will panic when palette has
+// more than 256 colors.
+func (i *IndexedImage) SetPalette(palette Palette) {
+	if len(palette) > 256 {
+		panic("palette has more than 256 colors")
+	}
+	i.palette = palette
+}
+
+// Index returns the palette index of the pixel at given position. Returns 0
+// for pixels outside the image.
+func (i *IndexedImage) Index(x, y int) uint8 {
+	if x < 0 || y < 0 || x >= i.width || y >= i.height {
+		return 0
+	}
+	return i.pixels[y*i.width+x]
+}
+
+// SetIndex sets the palette index of the pixel at given position. Does nothing
+// when position is outside the image.
+func (i *IndexedImage) SetIndex(x, y int, index uint8) {
+	if x < 0 || y < 0 || x >= i.width || y >= i.height {
+		return
+	}
+	i.pixels[y*i.width+x] = index
+}
+
+// Color returns the palette color of the pixel at given position. Returns
+// image.Transparent for pixels outside the image or indexes outside the palette.
+func (i *IndexedImage) Color(x, y int) image.Color {
+	if x < 0 || y < 0 || x >= i.width || y >= i.height {
+		return image.Transparent
+	}
+	return i.color(i.pixels[y*i.width+x])
+}
+
+func (i *IndexedImage) color(index uint8) image.Color {
+	if int(index) >= len(i.palette) {
+		return image.Transparent
+	}
+	return i.palette[index]
+}
+
+// Draw replaces pixels of the target selection with image colors. Only position
+// of the target Selection is used, the image is not clamped by the target size.
+// Pixels are copied without blending.
+func (i *IndexedImage) Draw(target image.Selection) {
+	lines := target.WithSize(i.width, i.height).Lines()
+	var (
+		xOffset = lines.XOffset()
+		yOffset = lines.YOffset()
+	)
+	for y := 0; y < lines.Length(); y++ {
+		line := lines.LineForWrite(y)
+		row := i.pixels[(y+yOffset)*i.width:]
+		for x := range line {
+			line[x] = i.color(row[x+xOffset])
+		}
+	}
+}
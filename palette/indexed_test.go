@@ -0,0 +1,170 @@
+package palette_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+	"github.com/elgopher/pixiq/palette"
+)
+
+func TestNewIndexedImage(t *testing.T) {
+	t.Run("should panic", func(t *testing.T) {
+		tests := map[string]struct {
+			width, height int
+			palette       palette.Palette
+		}{
+			"negative width":  {width: -1, height: 1},
+			"negative height": {width: 1, height: -1},
+			"too big palette": {width: 1, height: 1, palette: make(palette.Palette, 257)},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				assert.Panics(t, func() {
+					palette.NewIndexedImage(test.width, test.height, test.palette)
+				})
+			})
+		}
+	})
+
+	t.Run("should create image", func(t *testing.T) {
+		pal := palette.Palette{red, green}
+		// when
+		img := palette.NewIndexedImage(2, 3, pal)
+		// then
+		assert.Equal(t, 2, img.Width())
+		assert.Equal(t, 3, img.Height())
+		assert.Equal(t, pal, img.Palette())
+		assert.Equal(t, uint8(0), img.Index(1, 2))
+		assert.Equal(t, red, img.Color(1, 2))
+	})
+}
+
+func TestIndexedImage_SetIndex(t *testing.T) {
+	t.Run("should set index", func(t *testing.T) {
+		img := palette.NewIndexedImage(2, 2, palette.Palette{red, green})
+		// when
+		img.SetIndex(1, 1, 1)
+		// then
+		assert.Equal(t, uint8(1), img.Index(1, 1))
+		assert.Equal(t, green, img.Color(1, 1))
+		assert.Equal(t, uint8(0), img.Index(0, 1))
+	})
+
+	t.Run("should ignore pixels outside the image", func(t *testing.T) {
+		img := palette.NewIndexedImage(1, 1, palette.Palette{red, green})
+		positions := [][2]int{{-1, 0}, {0, -1}, {1, 0}, {0, 1}}
+		for _, pos := range positions {
+			// when
+			img.SetIndex(pos[0], pos[1], 1)
+			// then
+			assert.Equal(t, uint8(0), img.Index(pos[0], pos[1]))
+			assert.Equal(t, image.Transparent, img.Color(pos[0], pos[1]))
+		}
+		assert.Equal(t, uint8(0), img.Index(0, 0))
+	})
+}
+
+func TestIndexedImage_SetPalette(t *testing.T) {
+	t.Run("should panic for too big palette", func(t *testing.T) {
+		img := palette.NewIndexedImage(1, 1, nil)
+		assert.Panics(t, func() {
+			img.SetPalette(make(palette.Palette, 257))
+		})
+	})
+
+	t.Run("should change colors of pixels", func(t *testing.T) {
+		img := palette.NewIndexedImage(1, 1, palette.Palette{red})
+		// when
+		img.SetPalette(palette.Palette{blue})
+		// then
+		assert.Equal(t, blue, img.Color(0, 0))
+	})
+
+	t.Run("should return transparent for index outside the palette", func(t *testing.T) {
+		img := palette.NewIndexedImage(1, 1, palette.Palette{red, green})
+		img.SetIndex(0, 0, 1)
+		// when
+		img.SetPalette(palette.Palette{blue})
+		// then
+		assert.Equal(t, image.Transparent, img.Color(0, 0))
+	})
+}
+
+func TestIndexedImage_Draw(t *testing.T) {
+	t.Run("should draw image", func(t *testing.T) {
+		indexed := palette.NewIndexedImage(2, 2, palette.Palette{red, green, blue, white})
+		indexed.SetIndex(1, 0, 1)
+		indexed.SetIndex(0, 1, 2)
+		indexed.SetIndex(1, 1, 3)
+		img := image.New(fake.NewAcceleratedImage(3, 3))
+		target := img.Selection(1, 1)
+		// when
+		indexed.Draw(target)
+		// then
+		assert.Equal(t, red, target.Color(0, 0))
+		assert.Equal(t, green, target.Color(1, 0))
+		assert.Equal(t, blue, target.Color(0, 1))
+		assert.Equal(t, white, target.Color(1, 1))
+		assert.Equal(t, image.Transparent, img.WholeImageSelection().Color(0, 0))
+	})
+
+	t.Run("should clip image", func(t *testing.T) {
+		indexed := palette.NewIndexedImage(2, 2, palette.Palette{red, green, blue, white})
+		indexed.SetIndex(1, 0, 1)
+		indexed.SetIndex(0, 1, 2)
+		indexed.SetIndex(1, 1, 3)
+		img := image.New(fake.NewAcceleratedImage(2, 2))
+		tests := map[string]struct {
+			x, y          int
+			expectedColor image.Color
+		}{
+			"top left":     {x: -1, y: -1, expectedColor: white},
+			"bottom right": {x: 1, y: 1, expectedColor: red},
+			"top right":    {x: 1, y: -1, expectedColor: blue},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				// when
+				indexed.Draw(img.Selection(test.x, test.y))
+				// then
+				x, y := test.x, test.y
+				if x < 0 {
+					x = 0
+				}
+				if y < 0 {
+					y = 0
+				}
+				assert.Equal(t, test.expectedColor, img.WholeImageSelection().Color(x, y))
+			})
+		}
+	})
+}
+
+func TestFromSelection(t *testing.T) {
+	t.Run("should panic for empty palette", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(1, 1))
+		assert.Panics(t, func() {
+			palette.FromSelection(img.WholeImageSelection(), palette.Palette{})
+		})
+	})
+
+	t.Run("should map pixels to nearest colors", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(3, 1))
+		selection := img.WholeImageSelection()
+		selection.SetColor(0, 0, image.RGB(250, 5, 5))
+		selection.SetColor(1, 0, image.RGB(10, 10, 10))
+		selection.SetColor(2, 0, blue)
+		pal := palette.Palette{black, red, blue}
+		// when
+		indexed := palette.FromSelection(selection, pal)
+		// then
+		assert.Equal(t, 3, indexed.Width())
+		assert.Equal(t, 1, indexed.Height())
+		assert.Equal(t, uint8(1), indexed.Index(0, 0))
+		assert.Equal(t, uint8(0), indexed.Index(1, 0))
+		assert.Equal(t, uint8(2), indexed.Index(2, 0))
+	})
+}
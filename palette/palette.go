@@ -0,0 +1,86 @@
+// Package palette provides color palettes and indexed images. Palettes can be
+// loaded from files in popular formats (GPL, JASC-PAL, HEX and Lospec JSON):
+//
+//	pal, err := palette.DecodeFile("pico-8.hex")
+//	...
+//	nearest := pal.Nearest(color)
+//
+// Palettes are useful for palette swapping of sprites (see Swap) and drawing
+// indexed images with different palettes (see IndexedImage).
+package palette
+
+import (
+	"github.com/elgopher/pixiq/image"
+)
+
+// Palette is an ordered list of colors.
+type Palette []image.Color
+
+// Index returns the index of the first color in the palette exactly equal to
+// given color. False is returned when palette does not contain the color.
+func (p Palette) Index(color image.Color) (int, bool) {
+	for i, c := range p {
+		if c == color {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// Nearest returns the index of the palette color closest to given color.
+// Distance is calculated in RGBA space. When many colors have the same
+// distance the first one is returned. Will panic when palette is empty.
+func (p Palette) Nearest(color image.Color) int {
+	if len(p) == 0 {
+		panic("empty palette")
+	}
+	nearest, nearestDistance := 0, -1
+	for i, c := range p {
+		distance := Distance(c, color)
+		if distance == 0 {
+			return i
+		}
+		if nearestDistance == -1 || distance < nearestDistance {
+			nearest, nearestDistance = i, distance
+		}
+	}
+	return nearest
+}
+
+// NearestColor returns the palette color closest to given color. See Nearest.
+func (p Palette) NearestColor(color image.Color) image.Color {
+	return p[p.Nearest(color)]
+}
+
+// Distance returns the squared Euclidean distance between two colors in RGBA
+// space.
+func Distance(a, b image.Color) int {
+	r1, g1, b1, a1 := a.RGBAi()
+	r2, g2, b2, a2 := b.RGBAi()
+	dr, dg, db, da := r1-r2, g1-g2, b1-b2, a1-a2
+	return dr*dr + dg*dg + db*db + da*da
+}
+
+// Swap replaces colors in the selection. Each pixel equal to from[i] is replaced
+// by to[i]. Other pixels are not modified. It can be used for creating
+// variations of the same sprite, for example enemies with different colors.
+//
+// Will panic when palettes have different lengths.
+func Swap(selection image.Selection, from, to Palette) {
+	if len(from) != len(to) {
+		panic("palettes have different lengths")
+	}
+	replacements := make(map[image.Color]image.Color, len(from))
+	for i := len(from) - 1; i >= 0; i-- {
+		replacements[from[i]] = to[i]
+	}
+	lines := selection.Lines()
+	for y := 0; y < lines.Length(); y++ {
+		line := lines.LineForWrite(y)
+		for x, c := range line {
+			if replacement, ok := replacements[c]; ok {
+				line[x] = replacement
+			}
+		}
+	}
+}
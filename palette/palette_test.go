@@ -0,0 +1,127 @@
+package palette_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+	"github.com/elgopher/pixiq/palette"
+)
+
+var (
+	black = image.RGB(0, 0, 0)
+	white = image.RGB(255, 255, 255)
+	red   = image.RGB(255, 0, 0)
+	green = image.RGB(0, 255, 0)
+	blue  = image.RGB(0, 0, 255)
+)
+
+func TestPalette_Index(t *testing.T) {
+	pal := palette.Palette{black, white, red, white}
+
+	t.Run("should return index of the first matching color", func(t *testing.T) {
+		// when
+		index, ok := pal.Index(white)
+		// then
+		assert.True(t, ok)
+		assert.Equal(t, 1, index)
+	})
+
+	t.Run("should return false when color is missing", func(t *testing.T) {
+		// when
+		index, ok := pal.Index(blue)
+		// then
+		assert.False(t, ok)
+		assert.Equal(t, -1, index)
+	})
+}
+
+func TestPalette_Nearest(t *testing.T) {
+	t.Run("should panic for empty palette", func(t *testing.T) {
+		assert.Panics(t, func() {
+			palette.Palette{}.Nearest(black)
+		})
+	})
+
+	t.Run("should return index of nearest color", func(t *testing.T) {
+		pal := palette.Palette{black, white, red, green}
+		tests := map[string]struct {
+			color         image.Color
+			expectedIndex int
+		}{
+			"exact match":          {color: red, expectedIndex: 2},
+			"dark gray":            {color: image.RGB(50, 50, 50), expectedIndex: 0},
+			"light gray":           {color: image.RGB(200, 200, 200), expectedIndex: 1},
+			"dark red":             {color: image.RGB(200, 10, 10), expectedIndex: 2},
+			"first when ambiguous": {color: image.RGB(128, 128, 0), expectedIndex: 2},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				// when
+				index := pal.Nearest(test.color)
+				// then
+				assert.Equal(t, test.expectedIndex, index)
+				assert.Equal(t, pal[test.expectedIndex], pal.NearestColor(test.color))
+			})
+		}
+	})
+}
+
+func TestDistance(t *testing.T) {
+	t.Run("should return squared distance", func(t *testing.T) {
+		assert.Equal(t, 0, palette.Distance(red, red))
+		assert.Equal(t, 2*255*255, palette.Distance(red, green))
+		assert.Equal(t, 4*255*255, palette.Distance(image.Transparent, white))
+	})
+}
+
+func TestSwap(t *testing.T) {
+	t.Run("should panic when palettes have different lengths", func(t *testing.T) {
+		selection := image.New(fake.NewAcceleratedImage(1, 1)).WholeImageSelection()
+		assert.Panics(t, func() {
+			palette.Swap(selection, palette.Palette{red}, palette.Palette{})
+		})
+	})
+
+	t.Run("should replace colors", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(4, 1))
+		selection := img.WholeImageSelection()
+		selection.SetColor(0, 0, red)
+		selection.SetColor(1, 0, green)
+		selection.SetColor(2, 0, blue)
+		selection.SetColor(3, 0, red)
+		// when
+		palette.Swap(selection, palette.Palette{red, green}, palette.Palette{green, white})
+		// then
+		assert.Equal(t, green, selection.Color(0, 0))
+		assert.Equal(t, white, selection.Color(1, 0))
+		assert.Equal(t, blue, selection.Color(2, 0))
+		assert.Equal(t, green, selection.Color(3, 0))
+	})
+
+	t.Run("should use first occurrence of duplicated color", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(1, 1))
+		selection := img.WholeImageSelection()
+		selection.SetColor(0, 0, red)
+		// when
+		palette.Swap(selection, palette.Palette{red, red}, palette.Palette{green, blue})
+		// then
+		assert.Equal(t, green, selection.Color(0, 0))
+	})
+
+	t.Run("should replace colors only inside selection", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(3, 1))
+		whole := img.WholeImageSelection()
+		whole.SetColor(0, 0, red)
+		whole.SetColor(1, 0, red)
+		whole.SetColor(2, 0, red)
+		// when
+		palette.Swap(img.Selection(1, 0).WithSize(1, 1), palette.Palette{red}, palette.Palette{blue})
+		// then
+		assert.Equal(t, red, whole.Color(0, 0))
+		assert.Equal(t, blue, whole.Color(1, 0))
+		assert.Equal(t, red, whole.Color(2, 0))
+	})
+}
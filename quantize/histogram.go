@@ -0,0 +1,58 @@
+// Package quantize provides algorithms for reducing number of colors in the
+// image. They can be used to generate a palette from the image:
+//
+//	pal := quantize.MedianCut(selection, 16)
+//
+// Histogram can be used to find out how many distinct colors the image has.
+package quantize
+
+import (
+	"sort"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+// ColorCount is a color and number of pixels having this color.
+type ColorCount struct {
+	Color image.Color
+	Count int
+}
+
+// Histogram returns distinct colors of the selection with number of pixels
+// for each color. Colors are sorted by count in descending order. Colors with
+// the same count are sorted by the position of the first pixel (from left to
+// right, from top to bottom). Pixels outside the image are ignored.
+func Histogram(selection image.Selection) []ColorCount {
+	var (
+		histogram []ColorCount
+		indexes   = map[image.Color]int{}
+		lines     = selection.Lines()
+	)
+	for y := 0; y < lines.Length(); y++ {
+		for _, color := range lines.LineForRead(y) {
+			index, ok := indexes[color]
+			if !ok {
+				index = len(histogram)
+				indexes[color] = index
+				histogram = append(histogram, ColorCount{Color: color})
+			}
+			histogram[index].Count++
+		}
+	}
+	sortByCount(histogram)
+	return histogram
+}
+
+func sortByCount(histogram []ColorCount) {
+	sort.SliceStable(histogram, func(i, j int) bool {
+		return histogram[i].Count > histogram[j].Count
+	})
+}
+
+func colors(histogram []ColorCount) []image.Color {
+	colors := make([]image.Color, len(histogram))
+	for i, c := range histogram {
+		colors[i] = c.Color
+	}
+	return colors
+}
@@ -0,0 +1,86 @@
+package quantize_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+	"github.com/elgopher/pixiq/quantize"
+)
+
+var (
+	black = image.RGB(0, 0, 0)
+	white = image.RGB(255, 255, 255)
+	red   = image.RGB(255, 0, 0)
+	green = image.RGB(0, 255, 0)
+	blue  = image.RGB(0, 0, 255)
+)
+
+func TestHistogram(t *testing.T) {
+	t.Run("should return empty histogram for empty selection", func(t *testing.T) {
+		img := newImage([][]image.Color{{red}})
+		// when
+		histogram := quantize.Histogram(img.Selection(0, 0))
+		// then
+		assert.Empty(t, histogram)
+	})
+
+	t.Run("should return colors sorted by count", func(t *testing.T) {
+		img := newImage([][]image.Color{
+			{red, green, blue},
+			{blue, green, blue},
+		})
+		// when
+		histogram := quantize.Histogram(img.WholeImageSelection())
+		// then
+		expected := []quantize.ColorCount{
+			{Color: blue, Count: 3},
+			{Color: green, Count: 2},
+			{Color: red, Count: 1},
+		}
+		assert.Equal(t, expected, histogram)
+	})
+
+	t.Run("should sort colors with the same count by position", func(t *testing.T) {
+		img := newImage([][]image.Color{
+			{green, red},
+			{blue, white},
+		})
+		// when
+		histogram := quantize.Histogram(img.WholeImageSelection())
+		// then
+		expected := []quantize.ColorCount{
+			{Color: green, Count: 1},
+			{Color: red, Count: 1},
+			{Color: blue, Count: 1},
+			{Color: white, Count: 1},
+		}
+		assert.Equal(t, expected, histogram)
+	})
+
+	t.Run("should ignore pixels outside the image", func(t *testing.T) {
+		img := newImage([][]image.Color{{red}})
+		// when
+		histogram := quantize.Histogram(img.Selection(-1, -1).WithSize(3, 3))
+		// then
+		expected := []quantize.ColorCount{
+			{Color: red, Count: 1},
+		}
+		assert.Equal(t, expected, histogram)
+	})
+}
+
+func newImage(pixels [][]image.Color) *image.Image {
+	width := len(pixels[0])
+	height := len(pixels)
+	img := image.New(fake.NewAcceleratedImage(width, height))
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, pixels[y][x])
+		}
+	}
+	return img
+}
@@ -0,0 +1,102 @@
+package quantize
+
+import (
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/palette"
+)
+
+const kMeansMaxIterations = 32
+
+// KMeans returns palette with at most n colors representing colors of the
+// selection. The algorithm groups colors into n clusters, so that the
+// distance between each color and the center of its cluster is minimal.
+// Distances are calculated in the perceptual Oklab color space, which gives
+// better results than MedianCut but is slower. Initial clusters are found
+// using MedianCut, therefore the result is deterministic. Colors are sorted
+// by number of pixels they represent in descending order.
+//
+// When the selection has no more than n distinct colors, these colors are
+// returned. Will panic when n is not positive.
+func KMeans(selection image.Selection, n int) palette.Palette {
+	if n <= 0 {
+		panic("n must be positive")
+	}
+	histogram := Histogram(selection)
+	if len(histogram) <= n {
+		return colors(histogram)
+	}
+	points := make([]oklab, len(histogram))
+	for i, c := range histogram {
+		points[i] = toOklab(c.Color)
+	}
+	initial := medianCut(histogram, n)
+	centroids := make([]oklab, len(initial))
+	for i, c := range initial {
+		centroids[i] = toOklab(c)
+	}
+	assignments := make([]int, len(points))
+	counts := make([]int, len(centroids))
+	for iteration := 0; iteration < kMeansMaxIterations; iteration++ {
+		changed := assign(points, centroids, assignments)
+		if !changed && iteration > 0 {
+			break
+		}
+		sums := make([]oklab, len(centroids))
+		for i := range counts {
+			counts[i] = 0
+		}
+		for i, p := range points {
+			cluster := assignments[i]
+			weight := float64(histogram[i].Count)
+			sums[cluster].l += p.l * weight
+			sums[cluster].a += p.a * weight
+			sums[cluster].b += p.b * weight
+			sums[cluster].alpha += p.alpha * weight
+			counts[cluster] += histogram[i].Count
+		}
+		for i, sum := range sums {
+			if counts[i] == 0 {
+				// empty cluster keeps the previous centroid
+				continue
+			}
+			count := float64(counts[i])
+			centroids[i] = oklab{
+				l:     sum.l / count,
+				a:     sum.a / count,
+				b:     sum.b / count,
+				alpha: sum.alpha / count,
+			}
+		}
+	}
+	return sortedCentroids(centroids, counts)
+}
+
+// assign assigns each point to the nearest centroid. Returns true when any
+// assignment has changed.
+func assign(points, centroids []oklab, assignments []int) bool {
+	changed := false
+	for i, p := range points {
+		nearest, nearestDistance := 0, p.distance(centroids[0])
+		for j := 1; j < len(centroids); j++ {
+			if d := p.distance(centroids[j]); d < nearestDistance {
+				nearest, nearestDistance = j, d
+			}
+		}
+		if assignments[i] != nearest {
+			assignments[i] = nearest
+			changed = true
+		}
+	}
+	return changed
+}
+
+func sortedCentroids(centroids []oklab, counts []int) palette.Palette {
+	clusters := make([]ColorCount, 0, len(centroids))
+	for i, c := range centroids {
+		if counts[i] > 0 {
+			clusters = append(clusters, ColorCount{Color: c.color(), Count: counts[i]})
+		}
+	}
+	sortByCount(clusters)
+	return colors(clusters)
+}
@@ -0,0 +1,135 @@
+package quantize
+
+import (
+	"sort"
+
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/palette"
+)
+
+// MedianCut returns palette with at most n colors representing colors of the
+// selection. The algorithm recursively splits the RGBA color space into
+// boxes at the median of the longest box side. Each palette color is an
+// average of colors inside a box. Colors are sorted by number of pixels they
+// represent in descending order.
+//
+// When the selection has no more than n distinct colors, these colors are
+// returned. Will panic when n is not positive.
+func MedianCut(selection image.Selection, n int) palette.Palette {
+	if n <= 0 {
+		panic("n must be positive")
+	}
+	histogram := Histogram(selection)
+	if len(histogram) <= n {
+		return colors(histogram)
+	}
+	return medianCut(histogram, n)
+}
+
+func medianCut(histogram []ColorCount, n int) palette.Palette {
+	boxes := []box{newBox(histogram)}
+	for len(boxes) < n {
+		i := boxToSplit(boxes)
+		if i == -1 {
+			break
+		}
+		first, second := boxes[i].split()
+		boxes[i] = first
+		boxes = append(boxes, second)
+	}
+	sort.SliceStable(boxes, func(i, j int) bool {
+		return boxes[i].count > boxes[j].count
+	})
+	pal := make(palette.Palette, len(boxes))
+	for i, b := range boxes {
+		pal[i] = b.average()
+	}
+	return pal
+}
+
+// box is a set of histogram colors
+type box struct {
+	colors []ColorCount
+	count  int
+	// channel with the longest side: 0 - red, 1 - green, 2 - blue, 3 - alpha
+	channel int
+	length  int
+}
+
+func newBox(colors []ColorCount) box {
+	var minimum, maximum [4]int
+	for i := range minimum {
+		minimum[i] = 255
+	}
+	count := 0
+	for _, c := range colors {
+		count += c.Count
+		components := channels(c.Color)
+		for i, v := range components {
+			if v < minimum[i] {
+				minimum[i] = v
+			}
+			if v > maximum[i] {
+				maximum[i] = v
+			}
+		}
+	}
+	b := box{colors: colors, count: count}
+	for i := range minimum {
+		if length := maximum[i] - minimum[i]; length > b.length {
+			b.channel, b.length = i, length
+		}
+	}
+	return b
+}
+
+func channels(c image.Color) [4]int {
+	r, g, b, a := c.RGBAi()
+	return [4]int{r, g, b, a}
+}
+
+// boxToSplit returns the index of the box with the longest side or -1 when
+// no box can be split
+func boxToSplit(boxes []box) int {
+	index, length := -1, 0
+	for i, b := range boxes {
+		if len(b.colors) > 1 && b.length > length {
+			index, length = i, b.length
+		}
+	}
+	return index
+}
+
+// split splits the box at the median of the longest side
+func (b box) split() (box, box) {
+	channel := b.channel
+	sort.SliceStable(b.colors, func(i, j int) bool {
+		return channels(b.colors[i].Color)[channel] < channels(b.colors[j].Color)[channel]
+	})
+	median := 1
+	sum := b.colors[0].Count
+	for ; median < len(b.colors)-1; median++ {
+		if sum >= b.count/2 {
+			break
+		}
+		sum += b.colors[median].Count
+	}
+	return newBox(b.colors[:median]), newBox(b.colors[median:])
+}
+
+// average returns average color weighted by pixel count
+func (b box) average() image.Color {
+	var sum [4]int
+	for _, c := range b.colors {
+		components := channels(c.Color)
+		for i, v := range components {
+			sum[i] += v * c.Count
+		}
+	}
+	half := b.count / 2
+	return image.RGBAi(
+		(sum[0]+half)/b.count,
+		(sum[1]+half)/b.count,
+		(sum[2]+half)/b.count,
+		(sum[3]+half)/b.count)
+}
@@ -0,0 +1,52 @@
+package quantize
+
+import (
+	"math"
+
+	"github.com/elgopher/pixiq/image"
+)
+
+// oklab is a color in perceptual Oklab color space with alpha channel.
+// See https://bottosson.github.io/posts/oklab/
+type oklab struct {
+	l, a, b, alpha float64
+}
+
+func toOklab(c image.Color) oklab {
+	if c.A() == 0 {
+		return oklab{}
+	}
+	r, g, b := c.LinearRGB()
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	return oklab{
+		l:     0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		a:     1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		b:     0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+		alpha: float64(c.A()) / 255,
+	}
+}
+
+func (c oklab) color() image.Color {
+	l := c.l + 0.3963377774*c.a + 0.2158037573*c.b
+	m := c.l - 0.1055613458*c.a - 0.0638541728*c.b
+	s := c.l - 0.0894841775*c.a - 1.2914855480*c.b
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	r := +4.0767416621*l - 3.3077115913*m + 0.2309699292*s
+	g := -1.2684380046*l + 2.6097574011*m - 0.3413193965*s
+	b := -0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+
+	alpha := math.Round(math.Max(0, math.Min(1, c.alpha)) * 255)
+	return image.LinearRGBA(r, g, b, byte(alpha))
+}
+
+// distance returns squared distance between colors. Alpha difference is
+// treated the same as lightness difference.
+func (c oklab) distance(o oklab) float64 {
+	dl, da, db, dalpha := c.l-o.l, c.a-o.a, c.b-o.b, c.alpha-o.alpha
+	return dl*dl + da*da + db*db + dalpha*dalpha
+}
@@ -0,0 +1,94 @@
+package quantize_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/palette"
+	"github.com/elgopher/pixiq/quantize"
+)
+
+var algorithms = map[string]func(selection image.Selection, n int) palette.Palette{
+	"MedianCut": quantize.MedianCut,
+	"KMeans":    quantize.KMeans,
+}
+
+func TestQuantize(t *testing.T) {
+	for name, quantizeFunc := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should panic when n is not positive", func(t *testing.T) {
+				img := newImage([][]image.Color{{red}})
+				for _, n := range []int{-1, 0} {
+					assert.Panics(t, func() {
+						quantizeFunc(img.WholeImageSelection(), n)
+					})
+				}
+			})
+
+			t.Run("should return empty palette for empty selection", func(t *testing.T) {
+				img := newImage([][]image.Color{{red}})
+				// when
+				pal := quantizeFunc(img.Selection(0, 0), 2)
+				// then
+				assert.Empty(t, pal)
+			})
+
+			t.Run("should return all colors when selection has no more than n colors", func(t *testing.T) {
+				img := newImage([][]image.Color{
+					{red, green, green},
+				})
+				// when
+				pal := quantizeFunc(img.WholeImageSelection(), 2)
+				// then
+				assert.Equal(t, palette.Palette{green, red}, pal)
+			})
+
+			t.Run("should merge similar colors", func(t *testing.T) {
+				img := newImage([][]image.Color{
+					{image.RGB(250, 0, 0), image.RGB(254, 0, 0), image.RGB(252, 0, 0)},
+					{image.RGB(0, 0, 250), image.RGB(0, 0, 254), black},
+				})
+				// when
+				pal := quantizeFunc(img.WholeImageSelection(), 3)
+				// then
+				assert.Len(t, pal, 3)
+				assert.Equal(t, image.RGB(252, 0, 0), pal[0])
+				assert.Contains(t, pal, black)
+				nearestBlue := pal.NearestColor(blue)
+				assert.InDelta(t, 252, int(nearestBlue.B()), 1)
+			})
+
+			t.Run("should return n colors", func(t *testing.T) {
+				img := newGradientImage()
+				for _, n := range []int{1, 2, 8, 16} {
+					// when
+					pal := quantizeFunc(img.WholeImageSelection(), n)
+					// then
+					assert.Len(t, pal, n)
+				}
+			})
+
+			t.Run("should return deterministic results", func(t *testing.T) {
+				img := newGradientImage()
+				// when
+				pal1 := quantizeFunc(img.WholeImageSelection(), 8)
+				pal2 := quantizeFunc(img.WholeImageSelection(), 8)
+				// then
+				assert.Equal(t, pal1, pal2)
+			})
+		})
+	}
+}
+
+func newGradientImage() *image.Image {
+	pixels := make([][]image.Color, 32)
+	for y := range pixels {
+		pixels[y] = make([]image.Color, 32)
+		for x := range pixels[y] {
+			pixels[y][x] = image.RGB(uint8(x*8), uint8(y*8), uint8(255-x*4-y*4))
+		}
+	}
+	return newImage(pixels)
+}
@@ -0,0 +1,74 @@
+package scale
+
+import (
+	"github.com/elgopher/pixiq/image"
+)
+
+// NewScale2x creates a tool which scales selection 2 times using Scale2x
+// algorithm (aka EPX or AdvMAME2x). Pixels on diagonal edges are replaced by
+// neighbours, which makes edges smoother without introducing new colors.
+//
+// See https://www.scale2x.it/algorithm
+func NewScale2x() *Tool {
+	return &Tool{factor: 2, scale: scale2x}
+}
+
+func scale2x(n *neighbourhood, block []image.Color) {
+	block[0], block[1], block[2], block[3] = n.e, n.e, n.e, n.e
+	if n.b == n.h || n.d == n.f {
+		return
+	}
+	if n.d == n.b {
+		block[0] = n.d
+	}
+	if n.b == n.f {
+		block[1] = n.f
+	}
+	if n.d == n.h {
+		block[2] = n.d
+	}
+	if n.h == n.f {
+		block[3] = n.f
+	}
+}
+
+// NewScale3x creates a tool which scales selection 3 times using Scale3x
+// algorithm (aka AdvMAME3x). It is similar to Scale2x.
+//
+// See https://www.scale2x.it/algorithm
+func NewScale3x() *Tool {
+	return &Tool{factor: 3, scale: scale3x}
+}
+
+func scale3x(n *neighbourhood, block []image.Color) {
+	for i := range block {
+		block[i] = n.e
+	}
+	if n.b == n.h || n.d == n.f {
+		return
+	}
+	if n.d == n.b {
+		block[0] = n.d
+	}
+	if (n.d == n.b && n.e != n.c) || (n.b == n.f && n.e != n.a) {
+		block[1] = n.b
+	}
+	if n.b == n.f {
+		block[2] = n.f
+	}
+	if (n.d == n.b && n.e != n.g) || (n.d == n.h && n.e != n.a) {
+		block[3] = n.d
+	}
+	if (n.b == n.f && n.e != n.i) || (n.h == n.f && n.e != n.c) {
+		block[5] = n.f
+	}
+	if n.d == n.h {
+		block[6] = n.d
+	}
+	if (n.d == n.h && n.e != n.i) || (n.h == n.f && n.e != n.g) {
+		block[7] = n.h
+	}
+	if n.h == n.f {
+		block[8] = n.f
+	}
+}
@@ -0,0 +1,120 @@
+package scale_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/scale"
+)
+
+func TestPixelArtUpscalers(t *testing.T) {
+	g := image.RGB(128, 128, 128) // blend of a and b
+	tests := map[string]struct {
+		tool                 *scale.Tool
+		expectedFactor       int
+		expectedSinglePixel  [][]image.Color
+		expectedDiagonalLine [][]image.Color
+	}{
+		"Scale2x": {
+			tool:           scale.NewScale2x(),
+			expectedFactor: 2,
+			expectedDiagonalLine: [][]image.Color{
+				{a, a, b, b},
+				{a, b, a, b},
+				{b, a, b, a},
+				{b, b, a, a},
+			},
+		},
+		"Scale3x": {
+			tool:           scale.NewScale3x(),
+			expectedFactor: 3,
+			expectedDiagonalLine: [][]image.Color{
+				{a, a, a, b, b, b},
+				{a, a, b, a, b, b},
+				{a, b, b, a, a, b},
+				{b, a, a, b, b, a},
+				{b, b, a, b, a, a},
+				{b, b, b, a, a, a},
+			},
+		},
+		"Smooth2x": {
+			tool:           scale.NewSmooth2x(),
+			expectedFactor: 2,
+			expectedDiagonalLine: [][]image.Color{
+				{a, a, b, b},
+				{a, g, g, b},
+				{b, g, g, a},
+				{b, b, a, a},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should return factor", func(t *testing.T) {
+				assert.Equal(t, test.expectedFactor, test.tool.Factor())
+			})
+
+			t.Run("should scale single pixel", func(t *testing.T) {
+				source := newImage([][]image.Color{{c}})
+				target := image.New(newAcceleratedImage(test.expectedFactor+1, test.expectedFactor+1))
+				// when
+				test.tool.ScaleSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+				// then
+				selection := target.WholeImageSelection()
+				for y := 0; y <= test.expectedFactor; y++ {
+					for x := 0; x <= test.expectedFactor; x++ {
+						expected := c
+						if x == test.expectedFactor || y == test.expectedFactor {
+							expected = image.Transparent
+						}
+						assert.Equal(t, expected, selection.Color(x, y), "position (%d,%d)", x, y)
+					}
+				}
+			})
+
+			t.Run("should smooth diagonal line", func(t *testing.T) {
+				source := newImage([][]image.Color{
+					{a, b},
+					{b, a},
+				})
+				size := 2 * test.expectedFactor
+				target := image.New(newAcceleratedImage(size, size))
+				// when
+				test.tool.ScaleSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+				// then
+				assertColors(t, target, test.expectedDiagonalLine)
+			})
+		})
+	}
+}
+
+func TestNewSmooth2x(t *testing.T) {
+	t.Run("should treat similar colors as equal", func(t *testing.T) {
+		a2 := image.RGB(10, 10, 10) // similar to a
+		source := newImage([][]image.Color{
+			{a, b},
+			{b, a2},
+		})
+		target := image.New(newAcceleratedImage(4, 4))
+		// when
+		scale.NewSmooth2x().ScaleSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+		// then
+		assert.Equal(t, image.RGB(130, 130, 130), target.WholeImageSelection().Color(2, 1))
+		assert.Equal(t, image.RGB(133, 133, 133), target.WholeImageSelection().Color(2, 2))
+		assert.Equal(t, a2, target.WholeImageSelection().Color(3, 3))
+	})
+
+	t.Run("should not treat transparent and black colors as similar", func(t *testing.T) {
+		source := newImage([][]image.Color{
+			{a, o},
+			{o, a},
+		})
+		target := image.New(newAcceleratedImage(4, 4))
+		// when
+		scale.NewSmooth2x().ScaleSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+		// then
+		assert.Equal(t, image.RGBA(0, 0, 0, 128), target.WholeImageSelection().Color(1, 1))
+	})
+}
@@ -0,0 +1,130 @@
+// Package scale provides tools for upscaling selections by integer factors
+// using CPU. Nearest-neighbour scaling keeps pixels sharp and square:
+//
+//	tool := scale.NewNearest(4)
+//	tool.ScaleSourceToTarget(sprite, preview.Selection(0, 0))
+//
+// Pixel-art upscalers (Scale2x, Scale3x and Smooth2x) additionally smooth
+// diagonal edges. They can be used for screenshots or "smooth" display modes.
+package scale
+
+import (
+	"github.com/elgopher/pixiq/image"
+)
+
+// Tool upscales source selection and puts the result into the target. Use
+// constructors such as NewNearest or NewScale2x to create it.
+type Tool struct {
+	factor int
+	scale  scaleFunction
+}
+
+// scaleFunction fills block of factor*factor pixels (row by row) using
+// neighbourhood of the source pixel
+type scaleFunction func(n *neighbourhood, block []image.Color)
+
+// neighbourhood contains the source pixel E with its neighbours:
+//
+//	A B C
+//	D E F
+//	G H I
+//
+// Neighbours outside the source selection are copies of the edge pixels.
+type neighbourhood struct {
+	a, b, c, d, e, f, g, h, i image.Color
+}
+
+// NewNearest creates a tool which scales selection using nearest-neighbour
+// algorithm. Each source pixel becomes a square of factor x factor pixels.
+// Will panic when factor is lower than 1.
+func NewNearest(factor int) *Tool {
+	if factor < 1 {
+		panic("factor lower than 1")
+	}
+	return &Tool{
+		factor: factor,
+		scale: func(n *neighbourhood, block []image.Color) {
+			for i := range block {
+				block[i] = n.e
+			}
+		},
+	}
+}
+
+// Factor returns how many times the source is scaled in each direction.
+func (t *Tool) Factor() int {
+	return t.factor
+}
+
+// ScaleSourceToTarget scales source and puts the result into the target
+// selection. Target pixels are replaced without blending. Pixels outside the
+// source image are treated as transparent.
+//
+// Only position of the target Selection is used. Target size is the source
+// size multiplied by Factor.
+func (t *Tool) ScaleSourceToTarget(source, target image.Selection) {
+	width, height := source.Width(), source.Height()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	factor := t.factor
+	lines := target.WithSize(width*factor, height*factor).Lines()
+	if lines.Length() == 0 {
+		return
+	}
+	var (
+		xOffset = lines.XOffset()
+		yOffset = lines.YOffset()
+		pixels  = make([]image.Color, width*height)
+		block   = make([]image.Color, factor*factor)
+		rows    = make([][]image.Color, factor)
+	)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixels[y*width+x] = source.Color(x, y)
+		}
+	}
+	for i := range rows {
+		rows[i] = make([]image.Color, width*factor)
+	}
+	firstRow := yOffset / factor
+	lastRow := (yOffset + lines.Length() - 1) / factor
+	for y := firstRow; y <= lastRow; y++ {
+		for x := 0; x < width; x++ {
+			n := neighbourhoodOf(pixels, width, height, x, y)
+			t.scale(&n, block)
+			for blockY := 0; blockY < factor; blockY++ {
+				copy(rows[blockY][x*factor:], block[blockY*factor:(blockY+1)*factor])
+			}
+		}
+		for blockY := 0; blockY < factor; blockY++ {
+			line := y*factor + blockY - yOffset
+			if line < 0 || line >= lines.Length() {
+				continue
+			}
+			copy(lines.LineForWrite(line), rows[blockY][xOffset:])
+		}
+	}
+}
+
+func neighbourhoodOf(pixels []image.Color, width, height, x, y int) neighbourhood {
+	left, right := clamp(x-1, width), clamp(x+1, width)
+	top, bottom := clamp(y-1, height)*width, clamp(y+1, height)*width
+	row := y * width
+	return neighbourhood{
+		a: pixels[top+left], b: pixels[top+x], c: pixels[top+right],
+		d: pixels[row+left], e: pixels[row+x], f: pixels[row+right],
+		g: pixels[bottom+left], h: pixels[bottom+x], i: pixels[bottom+right],
+	}
+}
+
+// clamp clamps v to range [0,length)
+func clamp(v, length int) int {
+	if v < 0 {
+		return 0
+	}
+	if v >= length {
+		return length - 1
+	}
+	return v
+}
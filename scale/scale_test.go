@@ -0,0 +1,163 @@
+package scale_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+	"github.com/elgopher/pixiq/scale"
+)
+
+var (
+	a = image.RGB(0, 0, 0)
+	b = image.RGB(255, 255, 255)
+	c = image.RGBA(10, 20, 30, 40)
+	x = image.RGB(1, 2, 3) // target color
+	o = image.Transparent
+)
+
+func TestNewNearest(t *testing.T) {
+	t.Run("should panic when factor is lower than 1", func(t *testing.T) {
+		for _, factor := range []int{-1, 0} {
+			assert.Panics(t, func() {
+				scale.NewNearest(factor)
+			})
+		}
+	})
+
+	t.Run("should create tool", func(t *testing.T) {
+		tool := scale.NewNearest(3)
+		assert.Equal(t, 3, tool.Factor())
+	})
+}
+
+func TestTool_ScaleSourceToTarget(t *testing.T) {
+	t.Run("should scale using nearest neighbour", func(t *testing.T) {
+		tests := map[string]struct {
+			factor   int
+			expected [][]image.Color
+		}{
+			"factor 1": {
+				factor: 1,
+				expected: [][]image.Color{
+					{a, b, x},
+					{c, a, x},
+					{x, x, x},
+				},
+			},
+			"factor 2": {
+				factor: 2,
+				expected: [][]image.Color{
+					{a, a, b},
+					{a, a, b},
+					{c, c, a},
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				source := newImage([][]image.Color{
+					{a, b},
+					{c, a},
+				})
+				target := newImage([][]image.Color{
+					{x, x, x},
+					{x, x, x},
+					{x, x, x},
+				})
+				tool := scale.NewNearest(test.factor)
+				// when
+				tool.ScaleSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+				// then
+				assertColors(t, target, test.expected)
+			})
+		}
+	})
+
+	t.Run("should scale into target partially outside image", func(t *testing.T) {
+		source := newImage([][]image.Color{
+			{a, b},
+			{c, a},
+		})
+		target := newImage([][]image.Color{
+			{x, x, x},
+			{x, x, x},
+		})
+		tool := scale.NewNearest(3)
+		// when
+		tool.ScaleSourceToTarget(source.WholeImageSelection(), target.Selection(-2, -4))
+		// then
+		assertColors(t, target, [][]image.Color{
+			{c, a, a},
+			{c, a, a},
+		})
+	})
+
+	t.Run("should scale source partially outside image", func(t *testing.T) {
+		source := newImage([][]image.Color{
+			{a, b},
+		})
+		target := newImage([][]image.Color{
+			{x, x, x, x},
+			{x, x, x, x},
+		})
+		tool := scale.NewNearest(2)
+		// when
+		tool.ScaleSourceToTarget(source.Selection(1, 0).WithSize(2, 1), target.WholeImageSelection())
+		// then
+		assertColors(t, target, [][]image.Color{
+			{b, b, o, o},
+			{b, b, o, o},
+		})
+	})
+
+	t.Run("should not modify target when source has zero size", func(t *testing.T) {
+		source := newImage([][]image.Color{{a}})
+		target := newImage([][]image.Color{{x}})
+		tool := scale.NewNearest(2)
+		// when
+		tool.ScaleSourceToTarget(source.Selection(0, 0), target.WholeImageSelection())
+		// then
+		assertColors(t, target, [][]image.Color{{x}})
+	})
+
+	t.Run("should not modify source", func(t *testing.T) {
+		source := newImage([][]image.Color{{a, b}})
+		target := newImage([][]image.Color{{x, x}})
+		tool := scale.NewScale2x()
+		// when
+		tool.ScaleSourceToTarget(source.WholeImageSelection(), target.WholeImageSelection())
+		// then
+		assertColors(t, source, [][]image.Color{{a, b}})
+	})
+}
+
+func assertColors(t *testing.T, img *image.Image, expectedColorLines [][]image.Color) {
+	selection := img.WholeImageSelection()
+	for y := 0; y < selection.Height(); y++ {
+		expectedColorLine := expectedColorLines[y]
+		for x := 0; x < selection.Width(); x++ {
+			color := selection.Color(x, y)
+			assert.Equal(t, expectedColorLine[x], color, "position (%d,%d)", x, y)
+		}
+	}
+}
+
+func newAcceleratedImage(width, height int) image.AcceleratedImage {
+	return fake.NewAcceleratedImage(width, height)
+}
+
+func newImage(pixels [][]image.Color) *image.Image {
+	width := len(pixels[0])
+	height := len(pixels)
+	img := image.New(newAcceleratedImage(width, height))
+	selection := img.WholeImageSelection()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			selection.SetColor(x, y, pixels[y][x])
+		}
+	}
+	return img
+}
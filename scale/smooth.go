@@ -0,0 +1,78 @@
+package scale
+
+import (
+	"github.com/elgopher/pixiq/image"
+)
+
+// NewSmooth2x creates a tool which scales selection 2 times using hqx-style
+// algorithm. It works like Scale2x, but colors are compared in YUV color space
+// with tolerance (the same thresholds as in hqx), and corners of diagonal
+// edges are blended with neighbours instead of being replaced. The result is
+// smoother than Scale2x, but new colors are introduced.
+func NewSmooth2x() *Tool {
+	return &Tool{factor: 2, scale: smooth2x}
+}
+
+func smooth2x(n *neighbourhood, block []image.Color) {
+	block[0], block[1], block[2], block[3] = n.e, n.e, n.e, n.e
+	if similar(n.b, n.h) || similar(n.d, n.f) {
+		return
+	}
+	if similar(n.d, n.b) {
+		block[0] = interpolate(n.e, n.d, n.b)
+	}
+	if similar(n.b, n.f) {
+		block[1] = interpolate(n.e, n.b, n.f)
+	}
+	if similar(n.d, n.h) {
+		block[2] = interpolate(n.e, n.d, n.h)
+	}
+	if similar(n.h, n.f) {
+		block[3] = interpolate(n.e, n.h, n.f)
+	}
+}
+
+// thresholds used by hqx
+const (
+	thresholdY     = 48
+	thresholdU     = 7
+	thresholdV     = 6
+	thresholdAlpha = 48
+)
+
+// similar returns true when colors differ less than thresholds in YUV space.
+// Premultiplied components are used, so transparent and opaque colors are
+// never similar.
+func similar(c1, c2 image.Color) bool {
+	if c1 == c2 {
+		return true
+	}
+	r1, g1, b1, a1 := c1.RGBAi()
+	r2, g2, b2, a2 := c2.RGBAi()
+	r, g, b := float64(r1-r2), float64(g1-g2), float64(b1-b2)
+	y := 0.299*r + 0.587*g + 0.114*b
+	u := -0.169*r - 0.331*g + 0.5*b
+	v := 0.5*r - 0.419*g - 0.081*b
+	return abs(y) <= thresholdY && abs(u) <= thresholdU && abs(v) <= thresholdV &&
+		abs(float64(a1-a2)) <= thresholdAlpha
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// interpolate mixes colors using weights 2:1:1
+func interpolate(e, c1, c2 image.Color) image.Color {
+	er, eg, eb, ea := e.RGBAi()
+	r1, g1, b1, a1 := c1.RGBAi()
+	r2, g2, b2, a2 := c2.RGBAi()
+	return image.RGBAi(
+		(2*er+r1+r2+2)/4,
+		(2*eg+g1+g2+2)/4,
+		(2*eb+b1+b2+2)/4,
+		(2*ea+a1+a2+2)/4,
+	)
+}
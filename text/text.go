@@ -0,0 +1,290 @@
+// Package text provides a CPU tool for drawing text using bitmap fonts:
+//
+//	tool := text.New(f)
+//	tool.SetColor(colornames.Yellow)
+//	tool.Draw(screen.Selection(10, 10), "Score: 100")
+package text
+
+import (
+	"github.com/elgopher/pixiq/blend"
+	"github.com/elgopher/pixiq/font"
+	"github.com/elgopher/pixiq/image"
+)
+
+// Alignment is a horizontal alignment of text lines.
+type Alignment int
+
+const (
+	// Left aligns lines to the left edge of the target selection.
+	Left Alignment = iota
+	// Center centers lines within the target selection.
+	Center
+	// Right aligns lines to the right edge of the target selection.
+	Right
+)
+
+// Span is a part of the text drawn with given color.
+type Span struct {
+	Text  string
+	Color image.Color
+}
+
+// New creates a text Tool using given font. By default text is white, left
+// aligned and not wrapped.
+func New(f *font.Font) *Tool {
+	if f == nil {
+		panic("nil font")
+	}
+	tinter := &tinter{}
+	return &Tool{
+		font:    f,
+		color:   image.RGB(255, 255, 255),
+		tinter:  tinter,
+		blender: blend.New(tinter),
+	}
+}
+
+// Tool draws text using a bitmap font. Glyphs are tinted with the text color
+// and blended with the target using the source-over mode.
+//
+// Tool uses CPU.
+type Tool struct {
+	font        *font.Font
+	color       image.Color
+	alignment   Alignment
+	wrapWidth   int
+	lineSpacing int
+	tinter      *tinter
+	blender     *blend.Tool
+	// reused between calls
+	chars []char
+	lines []line
+}
+
+type char struct {
+	r     rune
+	color image.Color
+}
+
+// line is a range of chars [start,end)
+type line struct {
+	start, end int
+	width      int
+}
+
+// SetFont sets the font used for measuring and drawing. Will panic when font is nil.
+func (t *Tool) SetFont(f *font.Font) {
+	if f == nil {
+		panic("nil font")
+	}
+	t.font = f
+}
+
+// SetColor sets the color of text drawn by Draw. Glyph colors are multiplied by
+// this color, so for white glyphs the text will have exactly this color.
+func (t *Tool) SetColor(color image.Color) {
+	t.color = color
+}
+
+// SetAlignment sets horizontal alignment of lines within the target selection.
+// Center and Right alignment use the width of the target selection,
+// therefore zero-width selection can be used to center text around a point
+// or align it to the left of a point.
+func (t *Tool) SetAlignment(alignment Alignment) {
+	if alignment != Left && alignment != Center && alignment != Right {
+		panic("invalid alignment")
+	}
+	t.alignment = alignment
+}
+
+// SetWrapWidth sets the maximum width of a line in pixels. Longer lines are
+// broken at spaces. Words longer than the width are broken between
+// characters. 0 disables wrapping, which is the default. Will panic when width
+// is negative.
+func (t *Tool) SetWrapWidth(width int) {
+	if width < 0 {
+		panic("negative wrap width")
+	}
+	t.wrapWidth = width
+}
+
+// SetLineSpacing sets the additional space in pixels between lines. Can be negative.
+func (t *Tool) SetLineSpacing(spacing int) {
+	t.lineSpacing = spacing
+}
+
+// Measure returns the size in pixels of the text drawn by Draw.
+func (t *Tool) Measure(text string) (width, height int) {
+	return t.MeasureSpans(Span{Text: text, Color: t.color})
+}
+
+// MeasureSpans returns the size in pixels of the text drawn by DrawSpans.
+func (t *Tool) MeasureSpans(spans ...Span) (width, height int) {
+	t.layout(spans)
+	for _, l := range t.lines {
+		if l.width > width {
+			width = l.width
+		}
+	}
+	if len(t.lines) > 0 {
+		height = len(t.lines)*t.font.LineHeight() + (len(t.lines)-1)*t.lineSpacing
+	}
+	return
+}
+
+// Draw draws the text starting at the top-left corner of the target selection.
+// New line characters ('\n') start a new line. Text is not clipped to the
+// target selection, only to the target image. Runes not available in the font
+// are skipped.
+func (t *Tool) Draw(target image.Selection, text string) {
+	t.DrawSpans(target, Span{Text: text, Color: t.color})
+}
+
+// DrawSpans draws multi-color text. Spans are drawn one after another as if
+// they were a single text. See Draw.
+func (t *Tool) DrawSpans(target image.Selection, spans ...Span) {
+	t.layout(spans)
+	lineHeight := t.font.LineHeight()
+	y := 0
+	for _, l := range t.lines {
+		x := 0
+		switch t.alignment {
+		case Center:
+			x = (target.Width() - l.width) / 2
+		case Right:
+			x = target.Width() - l.width
+		}
+		for i := l.start; i < l.end; i++ {
+			c := t.chars[i]
+			glyph, ok := t.font.Glyph(c.r)
+			if ok {
+				t.tinter.color = c.color
+				glyphTarget := target.Selection(x+glyph.OffsetX, y+glyph.OffsetY)
+				t.blender.BlendSourceToTarget(glyph.Selection, glyphTarget)
+			}
+			x += t.advance(i, l.end)
+		}
+		y += lineHeight + t.lineSpacing
+	}
+}
+
+// advance returns the advance of char i, which is in the line ending at end
+func (t *Tool) advance(i, end int) int {
+	next := rune(-1)
+	if i+1 < end {
+		next = t.chars[i+1].r
+	}
+	return t.font.Advance(t.chars[i].r, next)
+}
+
+func (t *Tool) layout(spans []Span) {
+	t.chars = t.chars[:0]
+	t.lines = t.lines[:0]
+	for _, span := range spans {
+		for _, r := range span.Text {
+			t.chars = append(t.chars, char{r: r, color: span.Color})
+		}
+	}
+	if len(t.chars) == 0 {
+		return
+	}
+	start := 0
+	for i, c := range t.chars {
+		if c.r == '\n' {
+			t.layoutParagraph(start, i)
+			start = i + 1
+		}
+	}
+	t.layoutParagraph(start, len(t.chars))
+}
+
+// layoutParagraph breaks chars [start,end) into lines
+func (t *Tool) layoutParagraph(start, end int) {
+	for {
+		lineEnd, next := t.breakLine(start, end)
+		t.lines = append(t.lines, line{
+			start: start,
+			end:   lineEnd,
+			width: t.width(start, lineEnd),
+		})
+		if next >= end {
+			return
+		}
+		start = next
+	}
+}
+
+// breakLine returns the end of the line starting at start and the start of the
+// next line.
+func (t *Tool) breakLine(start, end int) (lineEnd, next int) {
+	if t.wrapWidth == 0 {
+		return end, end
+	}
+	var (
+		lastSpace = -1
+		pen       = 0
+	)
+	for i := start; i < end; i++ {
+		if i > start {
+			pen += t.font.Advance(t.chars[i-1].r, t.chars[i].r)
+		}
+		if t.chars[i].r == ' ' {
+			lastSpace = i
+			continue
+		}
+		width := pen + t.font.Advance(t.chars[i].r, -1)
+		if width <= t.wrapWidth || i == start {
+			continue
+		}
+		if lastSpace <= start {
+			// word longer than the line
+			return i, i
+		}
+		lineEnd, next = lastSpace, lastSpace
+		for lineEnd > start && t.chars[lineEnd-1].r == ' ' {
+			lineEnd--
+		}
+		for next < end && t.chars[next].r == ' ' {
+			next++
+		}
+		return lineEnd, next
+	}
+	return end, end
+}
+
+func (t *Tool) width(start, end int) int {
+	width := 0
+	for i := start; i < end; i++ {
+		width += t.advance(i, end)
+	}
+	return width
+}
+
+// tinter multiplies source color by the text color and blends the result
+// with the target using source-over.
+type tinter struct {
+	color image.Color
+}
+
+func (t *tinter) BlendSourceToTargetColor(source, target image.Color) image.Color {
+	srcR, srcG, srcB, srcA := source.RGBAi()
+	colorR, colorG, colorB, colorA := t.color.RGBAi()
+	srcR = mul(srcR, colorR)
+	srcG = mul(srcG, colorG)
+	srcB = mul(srcB, colorB)
+	srcA = mul(srcA, colorA)
+	dstR, dstG, dstB, dstA := target.RGBAi()
+	dstFactor := 255 - srcA
+	return image.RGBAi(
+		srcR+mul(dstR, dstFactor),
+		srcG+mul(dstG, dstFactor),
+		srcB+mul(dstB, dstFactor),
+		srcA+mul(dstA, dstFactor),
+	)
+}
+
+// mul is an optimized version of round(a * b / 255)
+func mul(a, b int) int {
+	t := a*b + 0x80
+	return ((t >> 8) + t) >> 8
+}
@@ -0,0 +1,357 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elgopher/pixiq/font"
+	"github.com/elgopher/pixiq/image"
+	"github.com/elgopher/pixiq/image/fake"
+	"github.com/elgopher/pixiq/text"
+)
+
+var (
+	white = image.RGB(255, 255, 255)
+	red   = image.RGB(255, 0, 0)
+	green = image.RGB(0, 255, 0)
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should panic for nil font", func(t *testing.T) {
+		assert.Panics(t, func() {
+			text.New(nil)
+		})
+	})
+	t.Run("should create tool", func(t *testing.T) {
+		tool := text.New(newFont())
+		assert.NotNil(t, tool)
+	})
+}
+
+func TestTool_SetFont(t *testing.T) {
+	t.Run("should panic for nil font", func(t *testing.T) {
+		tool := text.New(newFont())
+		assert.Panics(t, func() {
+			tool.SetFont(nil)
+		})
+	})
+}
+
+func TestTool_SetAlignment(t *testing.T) {
+	t.Run("should panic for invalid alignment", func(t *testing.T) {
+		tool := text.New(newFont())
+		assert.Panics(t, func() {
+			tool.SetAlignment(-1)
+		})
+	})
+}
+
+func TestTool_SetWrapWidth(t *testing.T) {
+	t.Run("should panic for negative width", func(t *testing.T) {
+		tool := text.New(newFont())
+		assert.Panics(t, func() {
+			tool.SetWrapWidth(-1)
+		})
+	})
+}
+
+func TestTool_Measure(t *testing.T) {
+	tests := map[string]struct {
+		text           string
+		wrapWidth      int
+		lineSpacing    int
+		expectedWidth  int
+		expectedHeight int
+	}{
+		"empty": {
+			text: "",
+		},
+		"single char": {
+			text:           "i",
+			expectedWidth:  2,
+			expectedHeight: 2,
+		},
+		"two chars": {
+			text:           "iw",
+			expectedWidth:  6,
+			expectedHeight: 2,
+		},
+		"kerning": {
+			text:           "wi",
+			expectedWidth:  5,
+			expectedHeight: 2,
+		},
+		"two lines": {
+			text:           "i\nww",
+			expectedWidth:  8,
+			expectedHeight: 4,
+		},
+		"empty line": {
+			text:           "\n",
+			expectedWidth:  0,
+			expectedHeight: 4,
+		},
+		"line spacing": {
+			text:           "i\ni\ni",
+			lineSpacing:    1,
+			expectedWidth:  2,
+			expectedHeight: 8,
+		},
+		"wrapped at space": {
+			text:           "ii ii",
+			wrapWidth:      6,
+			expectedWidth:  4,
+			expectedHeight: 4,
+		},
+		"wrapped long word": {
+			text:           "iiiii",
+			wrapWidth:      6,
+			expectedWidth:  6,
+			expectedHeight: 4,
+		},
+		"not wrapped": {
+			text:           "ii ii",
+			wrapWidth:      10,
+			expectedWidth:  10,
+			expectedHeight: 2,
+		},
+		"missing glyph": {
+			text:           "ixi",
+			expectedWidth:  4,
+			expectedHeight: 2,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tool := text.New(newFont())
+			tool.SetWrapWidth(test.wrapWidth)
+			tool.SetLineSpacing(test.lineSpacing)
+			// when
+			width, height := tool.Measure(test.text)
+			// then
+			assert.Equal(t, test.expectedWidth, width)
+			assert.Equal(t, test.expectedHeight, height)
+		})
+	}
+}
+
+func TestTool_Draw(t *testing.T) {
+	t.Run("should draw text", func(t *testing.T) {
+		tests := map[string]struct {
+			text      string
+			x, y      int
+			width     int
+			alignment text.Alignment
+			wrapWidth int
+			expected  []string
+		}{
+			"single char": {
+				text: "i",
+				expected: []string{
+					"#.........",
+					"#.........",
+					"..........",
+					"..........",
+				},
+			},
+			"chars": {
+				text: "iwi",
+				expected: []string{
+					"#.####....",
+					"#.#..#....",
+					"..........",
+					"..........",
+				},
+			},
+			"kerning": {
+				text: "wi",
+				expected: []string{
+					"####......",
+					"#..#......",
+					"..........",
+					"..........",
+				},
+			},
+			"glyph offset": {
+				text: ".i",
+				expected: []string{
+					"..#.......",
+					"#.#.......",
+					"..........",
+					"..........",
+				},
+			},
+			"new line": {
+				text: "i\nwi",
+				expected: []string{
+					"#.........",
+					"#.........",
+					"####......",
+					"#..#......",
+				},
+			},
+			"position": {
+				text: "i",
+				x:    1, y: 1,
+				expected: []string{
+					"..........",
+					".#........",
+					".#........",
+					"..........",
+				},
+			},
+			"wrapped": {
+				text:      "i i i",
+				wrapWidth: 6,
+				expected: []string{
+					"#...#.....",
+					"#...#.....",
+					"#.........",
+					"#.........",
+				},
+			},
+			"center": {
+				text:      "ii",
+				width:     10,
+				alignment: text.Center,
+				expected: []string{
+					"...#.#....",
+					"...#.#....",
+					"..........",
+					"..........",
+				},
+			},
+			"center around point": {
+				text:      "ii",
+				x:         5,
+				alignment: text.Center,
+				expected: []string{
+					"...#.#....",
+					"...#.#....",
+					"..........",
+					"..........",
+				},
+			},
+			"right": {
+				text:      "i\nii",
+				width:     10,
+				alignment: text.Right,
+				expected: []string{
+					"........#.",
+					"........#.",
+					"......#.#.",
+					"......#.#.",
+				},
+			},
+			"clipped": {
+				text: "iiiiiii",
+				x:    -1,
+				expected: []string{
+					".#.#.#.#.#",
+					".#.#.#.#.#",
+					"..........",
+					"..........",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				img := image.New(fake.NewAcceleratedImage(10, 4))
+				tool := text.New(newFont())
+				tool.SetAlignment(test.alignment)
+				tool.SetWrapWidth(test.wrapWidth)
+				target := img.Selection(test.x, test.y).WithSize(test.width, 0)
+				// when
+				tool.Draw(target, test.text)
+				// then
+				assertPixels(t, img, test.expected)
+			})
+		}
+	})
+	t.Run("should draw text with color", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(1, 1))
+		tool := text.New(newFont())
+		tool.SetColor(image.RGBA(10, 20, 30, 40))
+		// when
+		tool.Draw(img.WholeImageSelection(), "i")
+		// then
+		assert.Equal(t, image.RGBA(10, 20, 30, 40), img.WholeImageSelection().Color(0, 0))
+	})
+	t.Run("should blend text with target", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(1, 1))
+		img.WholeImageSelection().SetColor(0, 0, image.RGBA(0, 0, 100, 255))
+		tool := text.New(newFont())
+		tool.SetColor(image.RGBA(100, 0, 0, 128))
+		// when
+		tool.Draw(img.WholeImageSelection(), "i")
+		// then
+		assert.Equal(t, image.RGBA(100, 0, 50, 255), img.WholeImageSelection().Color(0, 0))
+	})
+}
+
+func TestTool_DrawSpans(t *testing.T) {
+	t.Run("should draw text with many colors", func(t *testing.T) {
+		img := image.New(fake.NewAcceleratedImage(6, 1))
+		tool := text.New(newFont())
+		// when
+		tool.DrawSpans(img.WholeImageSelection(),
+			text.Span{Text: "i", Color: red},
+			text.Span{Text: "ii", Color: green},
+		)
+		// then
+		selection := img.WholeImageSelection()
+		assert.Equal(t, red, selection.Color(0, 0))
+		assert.Equal(t, green, selection.Color(2, 0))
+		assert.Equal(t, green, selection.Color(4, 0))
+	})
+}
+
+// newFont creates a font with line height 2 and glyphs:
+//
+//	'i' - 1x2 pixels, advance 2
+//	'w' - 3x2 pixels (#.. below ###), advance 4, kerning with 'i' is -1
+//	'.' - 1x1 pixel placed at the bottom, advance 2
+//	' ' - no pixels, advance 2
+func newFont() *font.Font {
+	sheet := image.New(fake.NewAcceleratedImage(5, 2))
+	selection := sheet.WholeImageSelection()
+	pixels := []string{
+		"####.",
+		"##..#",
+	}
+	for y, line := range pixels {
+		for x := 0; x < len(line); x++ {
+			if line[x] == '#' {
+				selection.SetColor(x, y, white)
+			}
+		}
+	}
+	f := font.New(2)
+	f.SetGlyph('i', font.Glyph{Selection: selection.WithSize(1, 2), Advance: 2})
+	f.SetGlyph('w', font.Glyph{Selection: selection.Selection(1, 0).WithSize(3, 2), Advance: 4})
+	f.SetGlyph('.', font.Glyph{Selection: selection.Selection(4, 1).WithSize(1, 1), Advance: 2, OffsetY: 1})
+	f.SetGlyph(' ', font.Glyph{Advance: 2})
+	f.SetKerning('w', 'i', -1)
+	return f
+}
+
+func assertPixels(t *testing.T, img *image.Image, expected []string) {
+	selection := img.WholeImageSelection()
+	actual := make([]string, img.Height())
+	for y := 0; y < img.Height(); y++ {
+		line := make([]byte, img.Width())
+		for x := 0; x < img.Width(); x++ {
+			switch selection.Color(x, y) {
+			case white:
+				line[x] = '#'
+			case image.Transparent:
+				line[x] = '.'
+			default:
+				line[x] = '?'
+			}
+		}
+		actual[y] = string(line)
+	}
+	assert.Equal(t, expected, actual)
+}